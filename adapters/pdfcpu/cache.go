@@ -0,0 +1,129 @@
+package pdfcpu
+
+import (
+	"sync"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// FontCache shares parsed fonts between the pages of one document: a font
+// dictionary referenced from several pages is parsed, and its ToUnicode
+// CMap decoded, only once. Streams shared between font dictionaries
+// (ToUnicode CMaps, encoding CMaps, /CIDToGIDMap streams and font
+// programs) are likewise decoded and parsed once per object. It is safe
+// for concurrent use.
+type FontCache struct {
+	mu      sync.Mutex
+	fonts   map[types.IndirectRef]*font.Font
+	streams map[types.IndirectRef]any
+
+	maxCMapMappings int // see SetMaxCMapMappings
+}
+
+// NewFontCache creates an empty font cache.
+func NewFontCache() *FontCache {
+	return &FontCache{
+		fonts:   make(map[types.IndirectRef]*font.Font),
+		streams: make(map[types.IndirectRef]any),
+	}
+}
+
+// SetMaxCMapMappings caps the entries of the ToUnicode CMaps the cache
+// parses (see font.ParseToUnicodeCMapLimit): loading a font whose CMap
+// has more fails with a *parser.LimitError. Zero, the default, means no
+// limit. Call it before loading fonts.
+func (c *FontCache) SetMaxCMapMappings(n int) {
+	c.maxCMapMappings = n
+}
+
+// Len returns the number of cached fonts.
+func (c *FontCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.fonts)
+}
+
+// FontRegistry is like the package-level FontRegistry but takes fonts
+// from the cache where possible and adds newly parsed ones to it.
+func (c *FontCache) FontRegistry(ctx *model.Context, resources types.Dict) (*font.FontRegistry, error) {
+	return buildRegistry(ctx, resources, c)
+}
+
+// PageFontRegistry is like the package-level PageFontRegistry but uses the
+// cache.
+func (c *FontCache) PageFontRegistry(ctx *model.Context, pageNr int) (*font.FontRegistry, error) {
+	return pageFontRegistry(ctx, pageNr, c)
+}
+
+// LoadPage is like the package-level LoadPage but uses the cache.
+func (c *FontCache) LoadPage(ctx *model.Context, pageNr int) ([]byte, *font.FontRegistry, error) {
+	return loadPage(ctx, pageNr, c)
+}
+
+// lookup returns the cached font for ref, registered under name. Pages
+// may refer to the same font by different resource names, so a font
+// cached under another name is returned as a renamed copy.
+func (c *FontCache) lookup(ref types.IndirectRef, name string) (*font.Font, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.fonts[ref]
+	if !ok {
+		return nil, false
+	}
+	if f.Name != name {
+		renamed := *f
+		renamed.Name = name
+		return &renamed, true
+	}
+	return f, true
+}
+
+func (c *FontCache) store(ref types.IndirectRef, f *font.Font) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fonts[ref] = f
+}
+
+// stream returns the cached result of parsing the stream object ref.
+func (c *FontCache) stream(ref types.IndirectRef) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.streams[ref]
+	return v, ok
+}
+
+func (c *FontCache) storeStream(ref types.IndirectRef, v any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.streams[ref] = v
+}
+
+// loadStream returns parse's result for the stream obj refers to, or the
+// zero value if the stream cannot be decoded. With a cache, a stream given
+// by indirect reference is decoded and parsed only once; one that cannot
+// be dereferenced is an error and is not cached.
+func loadStream[T any](ctx *model.Context, cache *FontCache, obj types.Object, parse func(sd *types.StreamDict) T) (T, error) {
+	ref, isRef := obj.(types.IndirectRef)
+	if cache != nil && isRef {
+		if v, ok := cache.stream(ref); ok {
+			if t, ok := v.(T); ok {
+				return t, nil
+			}
+		}
+	}
+	var v T
+	sd, _, err := ctx.DereferenceStreamDict(obj)
+	if err != nil || sd == nil {
+		return v, err
+	}
+	if sd.Decode() == nil {
+		v = parse(sd)
+	}
+	if cache != nil && isRef {
+		cache.storeStream(ref, v)
+	}
+	return v, nil
+}
@@ -0,0 +1,75 @@
+package pdfcpu
+
+import (
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// DocumentLayers returns the layer visibility of the document's default
+// optional content configuration (the /D entry of /OCProperties), for
+// interpreter.Options.Layers: the layers its /OFF array lists are hidden,
+// or all but those of its /ON array if its /BaseState is /OFF. Layers are
+// matched by name, so groups sharing a name share the visibility of the
+// last one listed. It returns nil if the document has no optional
+// content.
+func DocumentLayers(ctx *model.Context) (interpreter.LayerFunc, error) {
+	catalog, err := ctx.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+	properties, err := ctx.DereferenceDict(catalog["OCProperties"])
+	if err != nil {
+		return nil, fmt.Errorf("OCProperties: %w", err)
+	}
+	if properties == nil {
+		return nil, nil
+	}
+	config, err := ctx.DereferenceDict(properties["D"])
+	if err != nil {
+		return nil, fmt.Errorf("OCProperties default configuration: %w", err)
+	}
+	if config == nil {
+		return nil, nil
+	}
+
+	baseOn := true
+	if base, _ := toGo(ctx, config["BaseState"], 0); base == "OFF" {
+		baseOn = false
+	}
+	visible := make(map[string]bool)
+	for _, list := range []struct {
+		key string
+		on  bool
+	}{{"OFF", false}, {"ON", true}} {
+		groups, err := ctx.DereferenceArray(config[list.key])
+		if err != nil {
+			return nil, fmt.Errorf("OCProperties /%s: %w", list.key, err)
+		}
+		for _, group := range groups {
+			if name, ok := groupName(ctx, group); ok {
+				visible[name] = list.on
+			}
+		}
+	}
+	return func(name string) bool {
+		if on, ok := visible[name]; ok {
+			return on
+		}
+		return baseOn
+	}, nil
+}
+
+// groupName returns the /Name of an optional content group.
+func groupName(ctx *model.Context, group types.Object) (string, bool) {
+	d, err := ctx.DereferenceDict(group)
+	if err != nil || d == nil {
+		return "", false
+	}
+	name, _ := toGo(ctx, d["Name"], 0)
+	s, ok := name.(string)
+	return s, ok
+}
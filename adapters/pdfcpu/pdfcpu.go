@@ -0,0 +1,417 @@
+// Package pdfcpu connects the stream engine to documents loaded with
+// github.com/pdfcpu/pdfcpu: it resolves a page's content streams and font
+// resources (ToUnicode CMaps, base encodings, widths) and runs the
+// interpreter over them.
+package pdfcpu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// ExtractPageText extracts the text of page pageNr (1-based) of ctx,
+// decoding each font through its ToUnicode CMap or base encoding.
+func ExtractPageText(ctx *model.Context, pageNr int) (string, error) {
+	return ExtractPageTextWithOptions(ctx, pageNr, interpreter.Options{})
+}
+
+// ExtractPageTextWithOptions is like ExtractPageText but also accepts
+// interpreter options controlling what is extracted.
+func ExtractPageTextWithOptions(ctx *model.Context, pageNr int, opts interpreter.Options) (string, error) {
+	content, fonts, err := LoadPage(ctx, pageNr)
+	if err != nil {
+		return "", err
+	}
+	resources, err := PageResources(ctx, pageNr)
+	if err != nil {
+		return "", err
+	}
+	interp := interpreter.NewInterpreterWithOptions(fonts, opts)
+	interp.SetResources(resources)
+	if err := interp.ProcessStream(bytes.NewReader(content)); err != nil {
+		// Keep what was extracted before the error
+		return interp.GetText(), fmt.Errorf("page %d: %w", pageNr, err)
+	}
+	return interp.GetText(), nil
+}
+
+// LoadPage returns the decoded content of page pageNr (all content streams
+// concatenated) and a font registry built from its resources. A page
+// without content yields empty content and no error.
+func LoadPage(ctx *model.Context, pageNr int) ([]byte, *font.FontRegistry, error) {
+	return loadPage(ctx, pageNr, nil)
+}
+
+// loadPage implements LoadPage, reusing fonts from cache if it is not nil.
+func loadPage(ctx *model.Context, pageNr int, cache *FontCache) ([]byte, *font.FontRegistry, error) {
+	pageDict, _, inherited, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("page %d: %w", pageNr, err)
+	}
+
+	var resources types.Dict
+	if inherited != nil {
+		resources = inherited.Resources
+	}
+	fonts, err := buildRegistry(ctx, resources, cache)
+	if err != nil {
+		return nil, nil, fmt.Errorf("page %d: %w", pageNr, err)
+	}
+
+	content, err := ctx.PageContent(pageDict, pageNr)
+	if err != nil && !errors.Is(err, model.ErrNoContent) {
+		return nil, nil, fmt.Errorf("page %d: %w", pageNr, err)
+	}
+	return content, fonts, nil
+}
+
+// PageFontRegistry builds a font registry from the Font resources of page
+// pageNr, including resources inherited from the page tree.
+func PageFontRegistry(ctx *model.Context, pageNr int) (*font.FontRegistry, error) {
+	return pageFontRegistry(ctx, pageNr, nil)
+}
+
+// pageFontRegistry implements PageFontRegistry, reusing fonts from cache if
+// it is not nil.
+func pageFontRegistry(ctx *model.Context, pageNr int, cache *FontCache) (*font.FontRegistry, error) {
+	_, _, inherited, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", pageNr, err)
+	}
+	if inherited == nil {
+		return font.NewFontRegistry(), nil
+	}
+	return buildRegistry(ctx, inherited.Resources, cache)
+}
+
+// FontRegistry builds a font registry from a Resources dictionary,
+// registering each entry of its /Font subdictionary under its resource
+// name. A font whose ToUnicode stream is missing or unparsable falls back
+// to its base encoding; only unresolvable references are errors.
+func FontRegistry(ctx *model.Context, resources types.Dict) (*font.FontRegistry, error) {
+	return buildRegistry(ctx, resources, nil)
+}
+
+// buildRegistry implements FontRegistry, reusing fonts from cache if it is
+// not nil.
+func buildRegistry(ctx *model.Context, resources types.Dict, cache *FontCache) (*font.FontRegistry, error) {
+	registry := font.NewFontRegistry()
+	if resources == nil {
+		return registry, nil
+	}
+	fontsObj, ok := resources.Find("Font")
+	if !ok {
+		return registry, nil
+	}
+	fonts, err := ctx.DereferenceDict(fontsObj)
+	if err != nil {
+		return nil, fmt.Errorf("font resources: %w", err)
+	}
+	for name, obj := range fonts {
+		f, err := loadCachedFont(ctx, name, obj, cache)
+		if err != nil {
+			return nil, fmt.Errorf("font %s: %w", name, err)
+		}
+		if f != nil {
+			registry.Register(f)
+		}
+	}
+	return registry, nil
+}
+
+// loadCachedFont loads the font dictionary obj under the resource name
+// name, taking it from cache if it is not nil and obj is a reference it
+// holds. It returns nil if obj is null.
+func loadCachedFont(ctx *model.Context, name string, obj types.Object, cache *FontCache) (*font.Font, error) {
+	ref, isRef := obj.(types.IndirectRef)
+	if cache != nil && isRef {
+		if f, ok := cache.lookup(ref, name); ok {
+			return f, nil
+		}
+	}
+	fontDict, err := ctx.DereferenceDict(obj)
+	if err != nil || fontDict == nil {
+		return nil, err
+	}
+	f, err := loadFont(ctx, name, fontDict, cache)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil && isRef {
+		cache.store(ref, f)
+	}
+	return f, nil
+}
+
+// fontDictKeys are the font dictionary entries font.FromPDFDict reads.
+var fontDictKeys = []string{"Subtype", "BaseFont", "Encoding", "FirstChar", "Widths", "FontDescriptor", "DescendantFonts"}
+
+// loadFont builds a Font from a font dictionary: font.FromPDFDict sets
+// what the dictionary itself gives, and the streams (ToUnicode, embedded
+// encoding CMaps, /CIDToGIDMap, font programs) are read here, through
+// cache if it is not nil.
+func loadFont(ctx *model.Context, name string, d types.Dict, cache *FontCache) (*font.Font, error) {
+	fd := make(parser.Dict, len(fontDictKeys))
+	for _, key := range fontDictKeys {
+		v, err := toGo(ctx, d[key], 0)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			fd[key] = v
+		}
+	}
+	f, err := font.FromPDFDict(name, fd)
+	if err != nil {
+		return nil, err
+	}
+
+	subtype := ""
+	if s := d.Subtype(); s != nil {
+		subtype = *s
+	}
+	if subtype == "Type0" {
+		if err := loadType0(ctx, f, d, cache); err != nil {
+			return nil, err
+		}
+	}
+	if err := loadFontProgram(ctx, f, d, subtype, cache); err != nil {
+		return nil, err
+	}
+
+	if obj, ok := d.Find("ToUnicode"); ok {
+		cmap, err := loadToUnicode(ctx, obj, cache)
+		if err != nil {
+			return nil, fmt.Errorf("ToUnicode: %w", err)
+		}
+		if cmap != nil {
+			f.ToUnicode = cmap
+		}
+	}
+	return f, nil
+}
+
+// loadType0 reads the streams of a Type0 font: an embedded encoding CMap,
+// and the /CIDToGIDMap of the descendant CIDFont. An encoding CMap that
+// cannot be parsed is left unset, and the font decodes two-byte codes.
+func loadType0(ctx *model.Context, f *font.Font, d types.Dict, cache *FontCache) error {
+	encoding, err := ctx.Dereference(d["Encoding"])
+	if err != nil {
+		return err
+	}
+	if _, ok := encoding.(types.StreamDict); ok {
+		cmap, err := loadStream(ctx, cache, d["Encoding"], func(sd *types.StreamDict) *font.EncodingCMap {
+			cmap, _ := font.ParseEncodingCMap(bytes.NewReader(sd.Content))
+			return cmap
+		})
+		if err != nil {
+			return err
+		}
+		if cmap != nil {
+			f.EncodingCMap = cmap
+			f.Vertical = cmap.Vertical
+		}
+	}
+
+	descendants, err := ctx.DereferenceArray(d["DescendantFonts"])
+	if err != nil || len(descendants) == 0 || f.CIDFont == nil {
+		return err
+	}
+	cidFont, err := ctx.DereferenceDict(descendants[0])
+	if err != nil || cidFont == nil {
+		return err
+	}
+	if obj, ok := cidFont.Find("CIDToGIDMap"); ok {
+		gids, err := loadStream(ctx, cache, obj, func(sd *types.StreamDict) []uint16 {
+			return font.ParseCIDToGIDMap(sd.Content)
+		})
+		if err == nil && gids != nil {
+			f.CIDFont.CIDToGID = gids
+		}
+	}
+	return nil
+}
+
+// loadFontProgram reads the embedded font program from the font
+// descriptor of a simple font or of a Type0 font's descendant: the cmap
+// table of a TrueType (/FontFile2) or OpenType (/FontFile3 with /Subtype
+// /OpenType) program, or the built-in encoding and charset of a Type 1
+// (/FontFile) or CFF (/FontFile3 with /Subtype /Type1C) program. A
+// program that cannot be decoded or parsed is ignored.
+func loadFontProgram(ctx *model.Context, f *font.Font, d types.Dict, subtype string, cache *FontCache) error {
+	if subtype == "Type0" {
+		descendants, err := ctx.DereferenceArray(d["DescendantFonts"])
+		if err != nil || len(descendants) == 0 {
+			return err
+		}
+		if d, err = ctx.DereferenceDict(descendants[0]); err != nil || d == nil {
+			return err
+		}
+	}
+	descriptor, err := ctx.DereferenceDict(d["FontDescriptor"])
+	if err != nil || descriptor == nil {
+		return err
+	}
+	for _, key := range []string{"FontFile2", "FontFile3", "FontFile"} {
+		obj, ok := descriptor.Find(key)
+		if !ok {
+			continue
+		}
+		p, err := loadStream(ctx, cache, obj, func(sd *types.StreamDict) fontProgram {
+			return parseFontProgram(key, sd)
+		})
+		if err != nil {
+			return err
+		}
+		if p.cmap != nil {
+			f.EmbeddedCmap = p.cmap
+		}
+		if p.enc != nil || p.charset != nil {
+			f.BuiltinEncoding, f.Charset = p.enc, p.charset
+		}
+		return nil
+	}
+	return nil
+}
+
+// fontProgram is what an embedded font program gives a font: a cmap
+// table, or a built-in encoding and charset.
+type fontProgram struct {
+	cmap    *font.TrueTypeCmap
+	enc     *font.GlyphEncoding
+	charset []string
+}
+
+// parseFontProgram parses the decoded font program sd, found under the
+// descriptor entry key.
+func parseFontProgram(key string, sd *types.StreamDict) fontProgram {
+	program := key
+	if key == "FontFile3" {
+		if s := sd.Subtype(); s != nil {
+			program = *s
+		}
+	}
+	var p fontProgram
+	switch program {
+	case "FontFile2", "OpenType":
+		if cmap, err := font.ParseTrueTypeCmap(sd.Content); err == nil {
+			p.cmap = cmap
+		}
+	case "FontFile":
+		if enc, err := font.ParseType1Encoding(sd.Content); err == nil {
+			p.enc = enc
+		}
+	case "Type1C", "CIDFontType0C":
+		if enc, charset, err := font.ParseCFFEncoding(sd.Content); err == nil {
+			p.enc, p.charset = enc, charset
+		}
+	}
+	return p
+}
+
+// loadToUnicode parses a ToUnicode stream, returning nil if it cannot be
+// decoded or parsed: the font then falls back to its base encoding. It
+// fails only if the CMap exceeds the cache's limit on mappings.
+func loadToUnicode(ctx *model.Context, obj types.Object, cache *FontCache) (*font.CMap, error) {
+	maxMappings := 0
+	if cache != nil {
+		maxMappings = cache.maxCMapMappings
+	}
+	parsed, _ := loadStream(ctx, cache, obj, func(sd *types.StreamDict) parsedCMap {
+		cmap, err := font.ParseToUnicodeCMapLimit(bytes.NewReader(sd.Content), maxMappings)
+		return parsedCMap{cmap, err}
+	})
+	if errors.Is(parsed.err, parser.ErrLimitExceeded) {
+		return nil, parsed.err
+	}
+	return parsed.cmap, nil
+}
+
+// parsedCMap is a parsed ToUnicode stream, cached with the error so that
+// a CMap over the limit fails every font using it.
+type parsedCMap struct {
+	cmap *font.CMap
+	err  error
+}
+
+// maxDepth bounds the nesting toGo follows, guarding against reference
+// cycles in malformed files.
+const maxDepth = 32
+
+// toGo converts obj to the operand types produced by the parser: float64
+// for numbers, string for names and literal strings, []byte for hex
+// strings, bool, []any for arrays and parser.Dict for dictionaries, with
+// streams converted to their dictionaries. Indirect objects are
+// dereferenced; other objects, and array elements and dictionary entries
+// that fail to convert, become nil.
+func toGo(ctx *model.Context, obj types.Object, depth int) (any, error) {
+	if obj == nil || depth > maxDepth {
+		return nil, nil
+	}
+	obj, err := ctx.Dereference(obj)
+	if err != nil {
+		return nil, err
+	}
+	switch v := obj.(type) {
+	case types.Integer:
+		return float64(v), nil
+	case types.Float:
+		return float64(v), nil
+	case types.Name:
+		return string(v), nil
+	case types.StringLiteral:
+		s, err := types.StringLiteralToString(v)
+		if err != nil {
+			return nil, nil
+		}
+		return s, nil
+	case types.HexLiteral:
+		b, err := v.Bytes()
+		if err != nil {
+			return nil, nil
+		}
+		return b, nil
+	case types.Boolean:
+		return bool(v), nil
+	case types.Array:
+		out := make([]any, 0, len(v))
+		for _, elem := range v {
+			g, err := toGo(ctx, elem, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if g != nil {
+				out = append(out, g)
+			}
+		}
+		return out, nil
+	case types.Dict:
+		return toGoDict(ctx, v, depth)
+	case types.StreamDict:
+		return toGoDict(ctx, v.Dict, depth)
+	default:
+		return nil, nil
+	}
+}
+
+func toGoDict(ctx *model.Context, d types.Dict, depth int) (parser.Dict, error) {
+	out := make(parser.Dict, len(d))
+	for key, elem := range d {
+		g, err := toGo(ctx, elem, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if g != nil {
+			out[key] = g
+		}
+	}
+	return out, nil
+}
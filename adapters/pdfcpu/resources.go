@@ -0,0 +1,270 @@
+package pdfcpu
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/apex-woot/pdf-stream-engine/images"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// Resources returns a resource provider for a Resources dictionary, which
+// resolves the graphics state parameter dictionaries of its /ExtGState
+// subdictionary for gs, loading their /Font entries like FontRegistry,
+// the optional content of its /Properties subdictionary for BDC and the
+// color spaces of its /ColorSpace subdictionary for cs. The provider also
+// implements images.ResourceProvider over its /XObject subdictionary.
+func Resources(ctx *model.Context, resources types.Dict) (interpreter.ResourceProvider, error) {
+	return newResourceProvider(ctx, resources, nil)
+}
+
+// PageResources returns a resource provider for the resources of page
+// pageNr, including resources inherited from the page tree.
+func PageResources(ctx *model.Context, pageNr int) (interpreter.ResourceProvider, error) {
+	return pageResources(ctx, pageNr, nil)
+}
+
+// Resources is like the package-level Resources but takes fonts from the
+// cache.
+func (c *FontCache) Resources(ctx *model.Context, resources types.Dict) (interpreter.ResourceProvider, error) {
+	return newResourceProvider(ctx, resources, c)
+}
+
+// PageResources is like the package-level PageResources but takes fonts
+// from the cache.
+func (c *FontCache) PageResources(ctx *model.Context, pageNr int) (interpreter.ResourceProvider, error) {
+	return pageResources(ctx, pageNr, c)
+}
+
+// pageResources implements PageResources, reusing fonts from cache if it
+// is not nil.
+func pageResources(ctx *model.Context, pageNr int, cache *FontCache) (interpreter.ResourceProvider, error) {
+	_, _, inherited, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", pageNr, err)
+	}
+	var resources types.Dict
+	if inherited != nil {
+		resources = inherited.Resources
+	}
+	return newResourceProvider(ctx, resources, cache)
+}
+
+// resourceProvider implements interpreter.ResourceProvider over the
+// /ExtGState, /Properties and /ColorSpace resources of a pdfcpu document,
+// and images.ResourceProvider over its /XObject resources.
+type resourceProvider struct {
+	ctx         *model.Context
+	extGState   types.Dict
+	properties  types.Dict
+	colorSpaces types.Dict
+	xObjects    types.Dict
+	cache       *FontCache
+}
+
+func newResourceProvider(ctx *model.Context, resources types.Dict, cache *FontCache) (*resourceProvider, error) {
+	p := &resourceProvider{ctx: ctx, cache: cache}
+	if resources == nil {
+		return p, nil
+	}
+	if obj, ok := resources.Find("ExtGState"); ok {
+		extGState, err := ctx.DereferenceDict(obj)
+		if err != nil {
+			return nil, fmt.Errorf("ExtGState resources: %w", err)
+		}
+		p.extGState = extGState
+	}
+	if obj, ok := resources.Find("Properties"); ok {
+		properties, err := ctx.DereferenceDict(obj)
+		if err != nil {
+			return nil, fmt.Errorf("Properties resources: %w", err)
+		}
+		p.properties = properties
+	}
+	if obj, ok := resources.Find("ColorSpace"); ok {
+		colorSpaces, err := ctx.DereferenceDict(obj)
+		if err != nil {
+			return nil, fmt.Errorf("ColorSpace resources: %w", err)
+		}
+		p.colorSpaces = colorSpaces
+	}
+	if obj, ok := resources.Find("XObject"); ok {
+		xObjects, err := ctx.DereferenceDict(obj)
+		if err != nil {
+			return nil, fmt.Errorf("XObject resources: %w", err)
+		}
+		p.xObjects = xObjects
+	}
+	return p, nil
+}
+
+// ExtGState resolves the graphics state parameter dictionary name. A
+// /Font entry whose font cannot be loaded is left out.
+func (p *resourceProvider) ExtGState(name string) (interpreter.ExtGState, bool) {
+	var gs interpreter.ExtGState
+	obj, ok := p.extGState.Find(name)
+	if !ok {
+		return gs, false
+	}
+	d, err := p.ctx.DereferenceDict(obj)
+	if err != nil || d == nil {
+		return gs, false
+	}
+
+	if arr, err := p.ctx.DereferenceArray(d["Font"]); err == nil && len(arr) == 2 {
+		size, _ := toGo(p.ctx, arr[1], 0)
+		if size, ok := size.(float64); ok {
+			// The font has no resource name; it goes by the dictionary's
+			if f, err := loadCachedFont(p.ctx, name, arr[0], p.cache); err == nil && f != nil {
+				gs.Font, gs.FontSize = f, size
+			}
+		}
+	}
+	if alpha, ok := p.number(d["CA"]); ok {
+		gs.StrokeAlpha = &alpha
+	}
+	if alpha, ok := p.number(d["ca"]); ok {
+		gs.FillAlpha = &alpha
+	}
+	switch bm, _ := toGo(p.ctx, d["BM"], 0); bm := bm.(type) {
+	case string:
+		gs.BlendMode = bm
+	case []any:
+		if len(bm) > 0 {
+			gs.BlendMode, _ = bm[0].(string)
+		}
+	}
+	return gs, true
+}
+
+// OptionalContent resolves the property list name as an optional content
+// group or membership dictionary.
+func (p *resourceProvider) OptionalContent(name string) (interpreter.OptionalContent, bool) {
+	obj, ok := p.properties.Find(name)
+	if !ok {
+		return interpreter.OptionalContent{}, false
+	}
+	d, err := p.ctx.DereferenceDict(obj)
+	if err != nil || d == nil {
+		return interpreter.OptionalContent{}, false
+	}
+	props, err := toGoDict(p.ctx, d, 0)
+	if err != nil {
+		return interpreter.OptionalContent{}, false
+	}
+	return interpreter.ParseOptionalContent(props)
+}
+
+// ColorSpace resolves the color space name to its family and number of
+// components.
+func (p *resourceProvider) ColorSpace(name string) (string, int, bool) {
+	obj, ok := p.colorSpaces.Find(name)
+	if !ok {
+		return "", 0, false
+	}
+	obj, err := p.ctx.Dereference(obj)
+	if err != nil {
+		return "", 0, false
+	}
+	switch cs := obj.(type) {
+	case types.Name:
+		n, ok := colorComponents[string(cs)]
+		return string(cs), n, ok
+	case types.Array:
+		if len(cs) == 0 {
+			return "", 0, false
+		}
+		family, ok := cs[0].(types.Name)
+		if !ok {
+			return "", 0, false
+		}
+		switch family {
+		case "ICCBased":
+			if len(cs) < 2 {
+				return "", 0, false
+			}
+			sd, _, err := p.ctx.DereferenceStreamDict(cs[1])
+			if err != nil || sd == nil {
+				return "", 0, false
+			}
+			n, ok := p.number(sd.Dict["N"])
+			return string(family), int(n), ok
+		case "DeviceN":
+			if len(cs) < 2 {
+				return "", 0, false
+			}
+			names, err := p.ctx.DereferenceArray(cs[1])
+			return string(family), len(names), err == nil
+		}
+		n, ok := colorComponents[string(family)]
+		return string(family), n, ok
+	}
+	return "", 0, false
+}
+
+// XObject resolves the XObject name to its stream dictionary and raw
+// data, for images.Extract.
+func (p *resourceProvider) XObject(name string) (*images.XObject, error) {
+	obj, ok := p.xObjects.Find(name)
+	if !ok {
+		return nil, errors.New("not in the resources")
+	}
+	sd, _, err := p.ctx.DereferenceStreamDict(obj)
+	if err != nil {
+		return nil, err
+	}
+	if sd == nil {
+		return nil, errors.New("not a stream")
+	}
+	dict, err := toGoDict(p.ctx, sd.Dict, 0)
+	if err != nil {
+		return nil, err
+	}
+	subtype, _ := dict["Subtype"].(string)
+	return &images.XObject{Subtype: subtype, Dict: dict, Data: sd.Raw}, nil
+}
+
+// colorComponents maps the color space families with a fixed number of
+// color components to it. Pattern colors have none unless uncolored.
+var colorComponents = map[string]int{
+	"DeviceGray": 1, "DeviceRGB": 3, "DeviceCMYK": 4,
+	"CalGray": 1, "CalRGB": 3, "Lab": 3,
+	"Indexed": 1, "Separation": 1, "Pattern": 0,
+}
+
+// number returns obj as a number, dereferencing it if needed.
+func (p *resourceProvider) number(obj types.Object) (float64, bool) {
+	v, _ := toGo(p.ctx, obj, 0)
+	n, ok := v.(float64)
+	return n, ok
+}
+
+// PageResourceNames returns the names the resources of page pageNr define
+// (including inherited ones), by category: the keys of the Resources
+// dictionary, such as Font and XObject. It is the form lint.Resources
+// takes.
+func PageResourceNames(ctx *model.Context, pageNr int) (map[string][]string, error) {
+	_, _, inherited, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", pageNr, err)
+	}
+	names := make(map[string][]string)
+	if inherited == nil || inherited.Resources == nil {
+		return names, nil
+	}
+	for category, obj := range inherited.Resources {
+		d, err := ctx.DereferenceDict(obj)
+		if err != nil || d == nil {
+			continue // ProcSet is an array
+		}
+		for name := range d {
+			names[category] = append(names[category], name)
+		}
+		slices.Sort(names[category])
+	}
+	return names, nil
+}
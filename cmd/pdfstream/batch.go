@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+// batchOptions configure extractTree.
+type batchOptions struct {
+	out     string // root of the sidecar tree, or "" to write next to the PDFs
+	json    bool   // write .json instead of .txt
+	jobs    int
+	extract []streamengine.Option
+}
+
+// fileStatus is the outcome of extracting one file of a batch.
+type fileStatus struct {
+	path        string
+	pages       int
+	failedPages int
+	err         error // the file could not be read or its sidecar written
+}
+
+// extractTree extracts every PDF under root, jobs at a time, writes one
+// sidecar file per PDF, and prints a summary with the status of each file.
+// It returns an error if any file failed; files with some failed pages
+// count as extracted.
+func extractTree(root string, opts batchOptions) error {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".pdf") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	statuses := make([]fileStatus, len(paths))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range max(opts.jobs, 1) {
+		wg.Go(func() {
+			for i := range work {
+				statuses[i] = extractToSidecar(root, paths[i], opts)
+			}
+		})
+	}
+	for i := range paths {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	failed := 0
+	for _, s := range statuses {
+		switch {
+		case s.err != nil:
+			failed++
+			fmt.Printf("failed   %s: %v\n", s.path, s.err)
+		case s.failedPages > 0:
+			fmt.Printf("partial  %s: %d of %d pages failed\n", s.path, s.failedPages, s.pages)
+		default:
+			fmt.Printf("ok       %s (%d pages)\n", s.path, s.pages)
+		}
+	}
+	fmt.Printf("%d files, %d extracted, %d failed\n", len(statuses), len(statuses)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(statuses))
+	}
+	return nil
+}
+
+// extractToSidecar extracts the PDF at path, under root, to its sidecar
+// file.
+func extractToSidecar(root, path string, opts batchOptions) fileStatus {
+	status := fileStatus{path: path}
+	doc, err := streamengine.ExtractDocument(path, opts.extract...)
+	if err != nil {
+		status.err = err
+		return status
+	}
+	status.pages = len(doc.Pages)
+	for _, page := range doc.Pages {
+		if page.Err != nil {
+			status.failedPages++
+		}
+	}
+
+	sidecar, err := sidecarPath(root, path, opts)
+	if err != nil {
+		status.err = err
+		return status
+	}
+	if err := os.MkdirAll(filepath.Dir(sidecar), 0o755); err != nil {
+		status.err = err
+		return status
+	}
+	f, err := os.Create(sidecar)
+	if err != nil {
+		status.err = err
+		return status
+	}
+	if err := writeDocument(f, doc, opts.json); err != nil {
+		f.Close()
+		status.err = fmt.Errorf("writing %s: %w", sidecar, err)
+		return status
+	}
+	if err := f.Close(); err != nil {
+		status.err = fmt.Errorf("writing %s: %w", sidecar, err)
+	}
+	return status
+}
+
+// sidecarPath returns the file the text of the PDF at path is written to:
+// the PDF's path with the extension replaced, under opts.out if set.
+func sidecarPath(root, path string, opts batchOptions) (string, error) {
+	ext := ".txt"
+	if opts.json {
+		ext = ".json"
+	}
+	name := strings.TrimSuffix(path, filepath.Ext(path)) + ext
+	if opts.out == "" {
+		return name, nil
+	}
+	rel, err := filepath.Rel(root, name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(opts.out, rel), nil
+}
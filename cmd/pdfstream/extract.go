@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/apex-woot/pdf-stream-engine/formats"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+func runExtract(fs *flag.FlagSet, args []string) error {
+	layout := fs.Bool("layout", false, "rebuild the text from the positioned runs, in reading order")
+	asJSON := fs.Bool("json", false, "print the pages, blocks, lines and runs as JSON")
+	recursive := fs.Bool("recursive", false, "extract every PDF in the directory tree given instead of a file, to sidecar .txt (or .json) files")
+	out := fs.String("out", "", "with --recursive, write the sidecar files under this directory, mirroring the tree, instead of next to the PDFs")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "with --recursive, how many files to extract at once")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	opts := []streamengine.Option{streamengine.WithLayout(*layout)}
+	if *recursive {
+		return extractTree(args[0], batchOptions{out: *out, json: *asJSON, jobs: *jobs, extract: opts})
+	}
+
+	doc, err := streamengine.ExtractDocument(args[0], opts...)
+	if err != nil {
+		return err
+	}
+	// Pages that failed are reported but do not stop the others
+	for _, page := range doc.Pages {
+		if page.Err != nil {
+			fmt.Fprintf(os.Stderr, "page %d: %v\n", page.Number, page.Err)
+		}
+	}
+	return writeDocument(os.Stdout, doc, *asJSON)
+}
+
+// writeDocument writes the text of doc, or its document model as JSON.
+func writeDocument(w io.Writer, doc streamengine.DocumentText, asJSON bool) error {
+	if asJSON {
+		return formats.WriteJSON(w, doc)
+	}
+	_, err := fmt.Fprintln(w, doc.Text())
+	return err
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+
+	"github.com/apex-woot/pdf-stream-engine/adapters/pdfcpu"
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+func runFonts(fs *flag.FlagSet, args []string) error {
+	page := fs.Int("page", 0, "list only this page (1-based)")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := api.ReadContextFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", args[0], err)
+	}
+	first, last, err := pageRange(*page, ctx.PageCount)
+	if err != nil {
+		return err
+	}
+	cache := pdfcpu.NewFontCache() // Pages share most of their fonts
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PAGE\tNAME\tBASE FONT\tTYPE\tENCODING\tTOUNICODE\tDECODING")
+	for n := first; n <= last; n++ {
+		fonts, err := cache.PageFontRegistry(ctx, n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "page %d: %v\n", n, err)
+			continue
+		}
+		fonts.Each(func(name string, f *font.Font) bool {
+			toUnicode := "no"
+			if f.ToUnicode != nil {
+				toUnicode = "yes"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", n, name, f.BaseFont, fontType(f), encoding(f), toUnicode, f.Capability())
+			return true
+		})
+	}
+	return w.Flush()
+}
+
+// fontType tells composite (Type0) fonts from simple ones.
+func fontType(f *font.Font) string {
+	if f.CIDFont != nil || f.EncodingCMap != nil {
+		return "composite"
+	}
+	return "simple"
+}
+
+// encoding describes how f maps codes to glyphs: the encoding CMap of a
+// composite font, or the base encoding of a simple one and whether a
+// Differences array modifies it.
+func encoding(f *font.Font) string {
+	if f.EncodingCMap != nil {
+		return f.EncodingCMap.Name
+	}
+	if f.Differences != nil {
+		return f.Encoding.String() + "+Differences"
+	}
+	return f.Encoding.String()
+}
@@ -0,0 +1,141 @@
+// Command pdfstream extracts and inspects the text of PDF files with the
+// stream engine, for trying it out without writing Go code.
+//
+// Usage:
+//
+//	pdfstream extract [--layout] [--json] file.pdf
+//	pdfstream extract --recursive [--out dir] [--jobs n] [--layout] [--json] dir
+//	pdfstream ops [--page n] file.pdf
+//	pdfstream fonts [--page n] file.pdf
+//	pdfstream search [--case-sensitive] [--whole-word] [--regexp] query file.pdf
+//	pdfstream serve [--addr host:port] [limits]
+//
+// extract prints the text of every page, pages separated by a blank line,
+// or with --json the document model of the formats package; with
+// --recursive it extracts every PDF of a directory tree to sidecar files
+// and prints the status of each file. ops lists the
+// operators of the pages' content streams, indented by nesting. fonts
+// lists the fonts of each page with their encodings and how their codes
+// map to Unicode. search prints the matches of a query with their pages
+// and positions. serve runs the extraction service of the server
+// package.
+//
+// Flags may come before or after the arguments; a "--" ends them.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// errUsage reports a command line that does not parse; the flag set has
+// printed the problem and the usage already.
+var errUsage = errors.New("usage")
+
+// command is a subcommand.
+type command struct {
+	name  string
+	args  string // the arguments after the flags, for the usage line
+	brief string
+	run   func(fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"extract", "file.pdf | --recursive dir", "print the text of every page", runExtract},
+	{"ops", "file.pdf", "list the content stream operators of each page", runOps},
+	{"fonts", "file.pdf", "list the fonts of each page and their encodings", runFonts},
+	{"search", "query file.pdf", "find text and print where it is", runSearch},
+	{"serve", "", "serve POST /extract over HTTP", runServe},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "usage: pdfstream %s [flags] %s\n\nFlags:\n", cmd.name, cmd.args)
+			fs.PrintDefaults()
+		}
+		err := cmd.run(fs, os.Args[2:])
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return
+		case errors.Is(err, errUsage):
+			os.Exit(2)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "pdfstream %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+	fmt.Fprintf(os.Stderr, "pdfstream: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: pdfstream <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.brief)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "pdfstream <command> -h" for the flags of a command.`)
+}
+
+// parseArgs parses the flags of fs, which may come before, between or
+// after the arguments, and checks that there are n arguments. A "--"
+// ends the flags.
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		// Parsing stopped at an argument: take it and go on with the flags
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+	if len(positional) != n {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// pageRange returns the pages to visit: page if it is set, else all of
+// the count pages.
+func pageRange(page, count int) (first, last int, err error) {
+	if page == 0 {
+		return 1, count, nil
+	}
+	if page < 1 || page > count {
+		return 0, 0, fmt.Errorf("page %d out of range [1, %d]", page, count)
+	}
+	return page, page, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+
+	"github.com/apex-woot/pdf-stream-engine/adapters/pdfcpu"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+func runOps(fs *flag.FlagSet, args []string) error {
+	page := fs.Int("page", 0, "list only this page (1-based)")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := api.ReadContextFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", args[0], err)
+	}
+	first, last, err := pageRange(*page, ctx.PageCount)
+	if err != nil {
+		return err
+	}
+	for n := first; n <= last; n++ {
+		if n > first {
+			fmt.Println()
+		}
+		fmt.Printf("%% page %d\n", n)
+		content, _, err := pdfcpu.LoadPage(ctx, n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "page %d: %v\n", n, err)
+			continue
+		}
+		p := parser.NewParserBytes(content)
+		ops, err := p.Parse()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "page %d: %v\n", n, err)
+		}
+		for _, w := range p.Warnings() {
+			fmt.Fprintf(os.Stderr, "page %d: %v\n", n, w)
+		}
+		if err := parser.Dump(os.Stdout, ops); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+
+	"github.com/apex-woot/pdf-stream-engine/search"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+func runSearch(fs *flag.FlagSet, args []string) error {
+	var opts search.Options
+	fs.BoolVar(&opts.CaseSensitive, "case-sensitive", false, "match letter case exactly")
+	fs.BoolVar(&opts.WholeWord, "whole-word", false, "match whole words only")
+	fs.IntVar(&opts.MaxMatches, "max", 0, "stop after this many matches (0 for all)")
+	isRegexp := fs.Bool("regexp", false, "read the query as a regular expression (RE2 syntax)")
+	args, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	query, path := args[0], args[1]
+
+	var re *regexp.Regexp
+	if *isRegexp {
+		if re, err = regexp.Compile(query); err != nil {
+			return err
+		}
+	}
+	doc, err := streamengine.ExtractDocument(path)
+	if err != nil {
+		return err
+	}
+	var matches []search.Match
+	if re != nil {
+		matches = search.FindRegexp(doc, re, opts)
+	} else {
+		matches = search.Find(doc, query, opts)
+	}
+	// One line per match: the page, the box of its first line and the
+	// matched text
+	for _, m := range matches {
+		fmt.Printf("page %d", m.Page)
+		if len(m.Rects) > 0 {
+			r := m.Rects[0]
+			fmt.Printf(" [%.1f %.1f %.1f %.1f]", r.X0, r.Y0, r.X1, r.Y1)
+		}
+		fmt.Printf(": %q\n", m.Text)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/apex-woot/pdf-stream-engine/server"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+func runServe(fs *flag.FlagSet, args []string) error {
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	var cfg server.Config
+	fs.Int64Var(&cfg.MaxUploadBytes, "max-upload", 32<<20, "largest upload accepted, in bytes")
+	fs.IntVar(&cfg.MaxPages, "max-pages", 0, "most pages a PDF may have (0 for no limit)")
+	fs.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "time limit for extracting one request")
+	fs.IntVar(&cfg.MaxConcurrent, "max-concurrent", 0, "most requests extracted at once (0 for no limit)")
+	pageTimeout := fs.Duration("page-timeout", 0, "time limit per page, after which the page's text is returned as is (0 for no limit)")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *pageTimeout > 0 {
+		cfg.Options = append(cfg.Options, streamengine.WithPageTimeout(*pageTimeout))
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(cfg),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "pdfstream: listening on %s\n", *addr)
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,200 @@
+// Package diff compares two content streams operation by operation and by
+// their extracted text, so that regression tests of PDF generators can
+// explain what changed between two builds.
+package diff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+	"github.com/apex-woot/pdf-stream-engine/textdiff"
+)
+
+// Kind is the kind of a change.
+type Kind int
+
+const (
+	Added   Kind = iota // operation only in the new stream
+	Removed             // operation only in the old stream
+	Changed             // same operator with different operands
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Change is one difference between the operations of the streams.
+type Change struct {
+	Kind Kind
+
+	// OldIndex and NewIndex are the indexes of the operations in the old
+	// and new stream, and Old and New the operations. OldIndex is -1 for
+	// Added changes and NewIndex for Removed ones.
+	OldIndex, NewIndex int
+	Old, New           parser.Operation
+}
+
+// String formats the change as its operations, with their positions.
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.New.Position, formatOperation(c.New))
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Old.Position, formatOperation(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s\n  %s: %s", c.Old.Position, formatOperation(c.Old), c.New.Position, formatOperation(c.New))
+	}
+}
+
+// Report is the outcome of Compare.
+type Report struct {
+	// Changes are the operation-level differences, in stream order.
+	Changes []Change
+
+	// OldText and NewText are the texts extracted from the streams, and
+	// Text the line-level edit script between them.
+	OldText, NewText string
+	Text             []textdiff.Edit
+}
+
+// Equal reports whether the streams hold the same operations.
+func (r Report) Equal() bool {
+	return len(r.Changes) == 0
+}
+
+// TextChanged reports whether the streams extract to different text.
+// Operations can change without the text changing, e.g. a color or a
+// position.
+func (r Report) TextChanged() bool {
+	return r.OldText != r.NewText
+}
+
+// String renders the report: the operation changes, one per line (two
+// for changed operations), followed by a unified diff of the text.
+func (r Report) String() string {
+	var b strings.Builder
+	for _, c := range r.Changes {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	b.WriteString(textdiff.Unified(r.OldText, r.NewText, "old", "new", 3))
+	return b.String()
+}
+
+// Compare parses the content streams a (old) and b (new) and reports how
+// they differ: operations added and removed, found as a minimal edit
+// script, and operations whose operator stayed but whose operands
+// changed, as well as the differences in their text extracted with opts.
+//
+// Operations are compared by operator and operands, not by position, so
+// moving unchanged operations to other offsets is not a change.
+func Compare(a, b []byte, opts ...streamengine.Option) (Report, error) {
+	oldOps, err := parser.NewParserBytes(a).Parse()
+	if err != nil {
+		return Report{}, fmt.Errorf("parsing old stream: %w", err)
+	}
+	newOps, err := parser.NewParserBytes(b).Parse()
+	if err != nil {
+		return Report{}, fmt.Errorf("parsing new stream: %w", err)
+	}
+
+	r := Report{
+		Changes: compareOperations(oldOps, newOps),
+		OldText: streamengine.ExtractText(a, opts...),
+		NewText: streamengine.ExtractText(b, opts...),
+	}
+	r.Text = textdiff.Lines(r.OldText, r.NewText)
+	return r, nil
+}
+
+// compareOperations diffs the operations line by line, as one line each,
+// and pairs the removed and added operations of each hunk with the same
+// operator as changes.
+func compareOperations(oldOps, newOps []parser.Operation) []Change {
+	edits := textdiff.Lines(operationLines(oldOps), operationLines(newOps))
+
+	var changes []Change
+	var removed, added []Change // the current hunk
+	flush := func() {
+		// Pair the k-th removed and added operations of each operator;
+		// the removals left over come first, then the additions and
+		// changes in new stream order
+		var unpaired []Change
+		for _, r := range removed {
+			j := slices.IndexFunc(added, func(c Change) bool { return c.Kind == Added && c.New.Name == r.Old.Name })
+			if j < 0 {
+				unpaired = append(unpaired, r)
+				continue
+			}
+			added[j].Kind, added[j].OldIndex, added[j].Old = Changed, r.OldIndex, r.Old
+		}
+		changes = append(changes, unpaired...)
+		changes = append(changes, added...)
+		removed, added = removed[:0], added[:0]
+	}
+	i, j := 0, 0
+	for _, e := range edits {
+		switch e.Op {
+		case textdiff.Equal:
+			flush()
+			i, j = i+1, j+1
+		case textdiff.Delete:
+			removed = append(removed, Change{Kind: Removed, OldIndex: i, NewIndex: -1, Old: oldOps[i]})
+			i++
+		case textdiff.Insert:
+			added = append(added, Change{Kind: Added, OldIndex: -1, NewIndex: j, New: newOps[j]})
+			j++
+		}
+	}
+	flush()
+	return changes
+}
+
+// operationLines returns the operations as text, one line per operation.
+func operationLines(ops []parser.Operation) string {
+	var b strings.Builder
+	for _, op := range ops {
+		b.WriteString(operationKey(op))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// operationKey returns op as a single line that is equal for equal
+// operations: its serialization, with inline image data hashed.
+func operationKey(op parser.Operation) string {
+	if op.Name == "EI" && len(op.Operands) == 1 {
+		if data, ok := op.Operands[0].([]byte); ok {
+			return fmt.Sprintf("%x EI", sha256.Sum256(data))
+		}
+	}
+	return formatOperation(op)
+}
+
+// formatOperation returns op in content stream syntax, on one line.
+func formatOperation(op parser.Operation) string {
+	if op.Name == "EI" && len(op.Operands) == 1 {
+		if data, ok := op.Operands[0].([]byte); ok {
+			return fmt.Sprintf("EI (%d bytes of image data)", len(data))
+		}
+	}
+	s, err := parser.Serialize([]parser.Operation{op})
+	if err != nil {
+		return fmt.Sprintf("%s %v", op.Name, op.Operands)
+	}
+	return string(bytes.TrimSuffix(s, []byte("\n")))
+}
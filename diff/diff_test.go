@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// changedStreams returns two streams of n operations that differ in one
+// operand of every operation.
+func changedStreams(n int) (old, new []byte) {
+	var a, b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&a, "%d %d m\n", i, i%50)
+		fmt.Fprintf(&b, "%d %d m\n", i, i%50+1)
+	}
+	return []byte(a.String()), []byte(b.String())
+}
+
+func TestCompareAllChanged(t *testing.T) {
+	old, new := changedStreams(4000)
+	r, err := Compare(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Changes) != 4000 {
+		t.Fatalf("%d changes, want 4000", len(r.Changes))
+	}
+	for i, c := range r.Changes {
+		if c.Kind != Changed || c.OldIndex != i || c.NewIndex != i {
+			t.Fatalf("change %d = %v %d→%d, want changed %d→%d", i, c.Kind, c.OldIndex, c.NewIndex, i, i)
+		}
+	}
+}
+
+// BenchmarkCompare measures comparing two large streams that differ in
+// every operation, the worst case of the edit script.
+func BenchmarkCompare(b *testing.B) {
+	old, new := changedStreams(4000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Compare(old, new); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package filters
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// FlateDecode inflates zlib-wrapped deflate data. Streams with a damaged
+// zlib header are retried as raw deflate, and truncated streams, which
+// are common, yield whatever could be inflated.
+func FlateDecode(data []byte) ([]byte, error) {
+	var r io.Reader
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		r = flate.NewReader(bytes.NewReader(data))
+	} else {
+		r = zr
+	}
+	out, err := io.ReadAll(r)
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("%w: FlateDecode: %w", ErrInvalidData, err)
+	}
+	return out, nil
+}
+
+// LZW codes with special meaning.
+const (
+	lzwClear = 256
+	lzwEOD   = 257
+)
+
+// LZWDecode decodes LZW data with the PDF variant's conventions: codes are
+// read most significant bit first, start at 9 bits, and grow up to 12
+// bits. earlyChange is the EarlyChange parameter (default 1): code widths
+// grow one code early.
+func LZWDecode(data []byte, earlyChange int) ([]byte, error) {
+	var out bytes.Buffer
+	var table [][]byte
+	reset := func() {
+		table = table[:0]
+		for i := 0; i < 256; i++ {
+			table = append(table, []byte{byte(i)})
+		}
+		table = append(table, nil, nil) // Clear and EOD
+	}
+	reset()
+
+	width := 9
+	var bitBuf uint32
+	bitCount := 0
+	var prev []byte
+	for pos := 0; ; {
+		for bitCount < width && pos < len(data) {
+			bitBuf = bitBuf<<8 | uint32(data[pos])
+			bitCount += 8
+			pos++
+		}
+		if bitCount < width {
+			break // Missing EOD
+		}
+		code := int(bitBuf>>(bitCount-width)) & (1<<width - 1)
+		bitCount -= width
+
+		switch {
+		case code == lzwClear:
+			reset()
+			width = 9
+			prev = nil
+			continue
+		case code == lzwEOD:
+			return out.Bytes(), nil
+		}
+
+		var entry []byte
+		switch {
+		case code < len(table):
+			entry = table[code]
+		case code == len(table) && prev != nil:
+			entry = append(append([]byte(nil), prev...), prev[0])
+		default:
+			return nil, fmt.Errorf("%w: LZWDecode: bad code %d", ErrInvalidData, code)
+		}
+		out.Write(entry)
+		if prev != nil && len(table) < 4096 {
+			table = append(table, append(append([]byte(nil), prev...), entry[0]))
+		}
+		prev = entry
+
+		if len(table)+earlyChange >= 1<<width && width < 12 {
+			width++
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// ASCIIHexDecode decodes hexadecimal digits up to '>', ignoring
+// whitespace. An odd final digit is padded with 0.
+func ASCIIHexDecode(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data)/2)
+	var hi byte
+	half := false
+	for _, c := range data {
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		case c == '>':
+			if half {
+				out = append(out, hi<<4)
+			}
+			return out, nil
+		case isWhitespace(c):
+			continue
+		default:
+			return nil, fmt.Errorf("%w: ASCIIHexDecode: invalid character %q", ErrInvalidData, c)
+		}
+		if half {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		half = !half
+	}
+	if half {
+		out = append(out, hi<<4)
+	}
+	return out, nil
+}
+
+// ASCII85Decode decodes base-85 data up to "~>", ignoring whitespace and
+// an optional "<~" prefix. 'z' stands for four zero bytes.
+func ASCII85Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimLeft(data, " \t\r\n\f\x00"), []byte("<~"))
+	out := make([]byte, 0, len(data)*4/5)
+	var group [5]byte
+	n := 0
+	flush := func(count int) {
+		var v uint32
+		for i := 0; i < 5; i++ {
+			v = v*85 + uint32(group[i])
+		}
+		word := []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		out = append(out, word[:count]...)
+	}
+loop:
+	for _, c := range data {
+		switch {
+		case c == '~':
+			break loop
+		case isWhitespace(c):
+			continue
+		case c == 'z' && n == 0:
+			out = append(out, 0, 0, 0, 0)
+			continue
+		case c < '!' || c > 'u':
+			return nil, fmt.Errorf("%w: ASCII85Decode: invalid character %q", ErrInvalidData, c)
+		}
+		group[n] = c - '!'
+		n++
+		if n == 5 {
+			flush(4)
+			n = 0
+		}
+	}
+	if n == 1 {
+		return nil, fmt.Errorf("%w: ASCII85Decode: final group has one character", ErrInvalidData)
+	}
+	if n > 1 {
+		// Pad the partial group with 'u' and keep n-1 bytes
+		for i := n; i < 5; i++ {
+			group[i] = 'u' - '!'
+		}
+		flush(n - 1)
+	}
+	return out, nil
+}
+
+// RunLengthDecode decodes run-length encoded data: a length byte L below
+// 128 copies the next L+1 bytes, L above 128 repeats the next byte 257-L
+// times, and 128 ends the data.
+func RunLengthDecode(data []byte) ([]byte, error) {
+	var out []byte
+	for i := 0; i < len(data); {
+		l := int(data[i])
+		i++
+		switch {
+		case l == 128:
+			return out, nil
+		case l < 128:
+			if i+l+1 > len(data) {
+				return nil, fmt.Errorf("%w: RunLengthDecode: truncated literal run", ErrInvalidData)
+			}
+			out = append(out, data[i:i+l+1]...)
+			i += l + 1
+		default:
+			if i >= len(data) {
+				return nil, fmt.Errorf("%w: RunLengthDecode: truncated repeat run", ErrInvalidData)
+			}
+			out = append(out, bytes.Repeat(data[i:i+1], 257-l)...)
+			i++
+		}
+	}
+	return out, nil
+}
+
+// isWhitespace reports whether c is a PDF whitespace character.
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
@@ -0,0 +1,14 @@
+package filters
+
+import "errors"
+
+var (
+	// ErrUnsupportedFilter is returned for filters this package does not
+	// implement, including the image-only filters (DCTDecode,
+	// CCITTFaxDecode, JBIG2Decode, JPXDecode) which the images package
+	// handles.
+	ErrUnsupportedFilter = errors.New("unsupported filter")
+
+	// ErrInvalidData is returned when encoded data is malformed.
+	ErrInvalidData = errors.New("invalid filter data")
+)
@@ -0,0 +1,99 @@
+// Package filters decodes PDF stream data encoded with the standard
+// non-image filters (FlateDecode, LZWDecode, ASCIIHexDecode,
+// ASCII85Decode, RunLengthDecode), including PNG and TIFF predictors, so
+// raw stream bytes can be handed to the engine together with their
+// /Filter chain.
+package filters
+
+import (
+	"fmt"
+)
+
+// abbreviations maps the filter abbreviations allowed in inline images to
+// full filter names.
+var abbreviations = map[string]string{
+	"AHx": "ASCIIHexDecode",
+	"A85": "ASCII85Decode",
+	"LZW": "LZWDecode",
+	"Fl":  "FlateDecode",
+	"RL":  "RunLengthDecode",
+	"CCF": "CCITTFaxDecode",
+	"DCT": "DCTDecode",
+}
+
+// FullName returns the full name of a filter, expanding inline image
+// abbreviations such as "Fl".
+func FullName(name string) string {
+	if full, ok := abbreviations[name]; ok {
+		return full
+	}
+	return name
+}
+
+// Decode applies a filter chain in order. parms holds the DecodeParms
+// dictionary for each filter and may be shorter than filters or contain
+// nil entries.
+func Decode(data []byte, filters []string, parms []map[string]any) ([]byte, error) {
+	for i, name := range filters {
+		var p map[string]any
+		if i < len(parms) {
+			p = parms[i]
+		}
+		var err error
+		data, err = DecodeFilter(name, data, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// DecodeFilter applies a single filter. Names may be abbreviated.
+func DecodeFilter(name string, data []byte, parms map[string]any) ([]byte, error) {
+	switch FullName(name) {
+	case "FlateDecode":
+		out, err := FlateDecode(data)
+		if err != nil {
+			return nil, err
+		}
+		return ApplyPredictor(out, parms)
+	case "LZWDecode":
+		earlyChange := 1
+		if v, ok := intParam(parms, "EarlyChange"); ok {
+			earlyChange = v
+		}
+		out, err := LZWDecode(data, earlyChange)
+		if err != nil {
+			return nil, err
+		}
+		return ApplyPredictor(out, parms)
+	case "ASCIIHexDecode":
+		return ASCIIHexDecode(data)
+	case "ASCII85Decode":
+		return ASCII85Decode(data)
+	case "RunLengthDecode":
+		return RunLengthDecode(data)
+	case "Crypt":
+		// Only the Identity crypt filter can be applied without keys
+		if n, _ := parms["Name"].(string); n == "" || n == "Identity" {
+			return data, nil
+		}
+		return nil, fmt.Errorf("%w: Crypt filter %v", ErrUnsupportedFilter, parms["Name"])
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFilter, name)
+	}
+}
+
+// intParam returns an integer decode parameter. Values may be float64 (as
+// the content stream parser produces) or int.
+func intParam(parms map[string]any, key string) (int, bool) {
+	switch v := parms[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
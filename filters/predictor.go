@@ -0,0 +1,158 @@
+package filters
+
+import "fmt"
+
+// ApplyPredictor reverses the predictor named by the DecodeParms of a
+// FlateDecode or LZWDecode filter: 2 is TIFF horizontal differencing,
+// 10-15 are the PNG row filters (the filter type is read per row).
+// Predictor 1 or none returns data unchanged. Parameters describing rows
+// longer than data are rejected, so hostile ones cannot make it allocate
+// more than the size of data.
+func ApplyPredictor(data []byte, parms map[string]any) ([]byte, error) {
+	predictor, _ := intParam(parms, "Predictor")
+	if predictor <= 1 || len(data) == 0 {
+		return data, nil
+	}
+	colors := paramOr(parms, "Colors", 1)
+	bpc := paramOr(parms, "BitsPerComponent", 8)
+	columns := paramOr(parms, "Columns", 1)
+	if colors < 1 || colors > maxColors || columns < 1 || (bpc != 1 && bpc != 2 && bpc != 4 && bpc != 8 && bpc != 16) {
+		return nil, fmt.Errorf("%w: predictor parameters Colors=%d BitsPerComponent=%d Columns=%d",
+			ErrInvalidData, colors, bpc, columns)
+	}
+	// Divide rather than multiply: Columns is unbounded
+	if columns > 8*len(data)/(colors*bpc) {
+		return nil, fmt.Errorf("%w: predictor rows of %d columns longer than the %d bytes of data",
+			ErrInvalidData, columns, len(data))
+	}
+	rowLen := (columns*colors*bpc + 7) / 8
+	pixelLen := max((colors*bpc+7)/8, 1)
+
+	switch {
+	case predictor == 2:
+		return tiffPredictor(data, rowLen, colors, bpc)
+	case predictor >= 10:
+		return pngPredictor(data, rowLen, pixelLen)
+	default:
+		return nil, fmt.Errorf("%w: Predictor %d", ErrUnsupportedFilter, predictor)
+	}
+}
+
+// maxColors is the most color components a predictor row may have, the
+// limit of DeviceN color spaces.
+const maxColors = 32
+
+func paramOr(parms map[string]any, key string, def int) int {
+	if v, ok := intParam(parms, key); ok {
+		return v
+	}
+	return def
+}
+
+// pngPredictor undoes PNG row filters. Each row starts with a filter type
+// byte. A truncated final row is dropped.
+func pngPredictor(data []byte, rowLen, pixelLen int) ([]byte, error) {
+	stride := rowLen + 1
+	rows := len(data) / stride
+	out := make([]byte, rows*rowLen)
+	prev := make([]byte, rowLen)
+	for r := 0; r < rows; r++ {
+		in := data[r*stride+1 : (r+1)*stride]
+		cur := out[r*rowLen : (r+1)*rowLen]
+		switch data[r*stride] {
+		case 0: // None
+			copy(cur, in)
+		case 1: // Sub
+			for i := range cur {
+				left := byte(0)
+				if i >= pixelLen {
+					left = cur[i-pixelLen]
+				}
+				cur[i] = in[i] + left
+			}
+		case 2: // Up
+			for i := range cur {
+				cur[i] = in[i] + prev[i]
+			}
+		case 3: // Average
+			for i := range cur {
+				left := 0
+				if i >= pixelLen {
+					left = int(cur[i-pixelLen])
+				}
+				cur[i] = in[i] + byte((left+int(prev[i]))/2)
+			}
+		case 4: // Paeth
+			for i := range cur {
+				var left, upLeft byte
+				if i >= pixelLen {
+					left, upLeft = cur[i-pixelLen], prev[i-pixelLen]
+				}
+				cur[i] = in[i] + paeth(left, prev[i], upLeft)
+			}
+		default:
+			return nil, fmt.Errorf("%w: PNG filter type %d in row %d", ErrInvalidData, data[r*stride], r)
+		}
+		prev = cur
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	default:
+		return c
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// tiffPredictor undoes TIFF predictor 2: each component is stored as the
+// difference from the same component of the pixel to its left.
+func tiffPredictor(data []byte, rowLen, colors, bpc int) ([]byte, error) {
+	out := append([]byte(nil), data[:len(data)/rowLen*rowLen]...)
+	for start := 0; start < len(out); start += rowLen {
+		row := out[start : start+rowLen]
+		switch bpc {
+		case 8:
+			for i := colors; i < len(row); i++ {
+				row[i] += row[i-colors]
+			}
+		case 16:
+			for i := 2 * colors; i+1 < len(row); i += 2 {
+				v := uint16(row[i])<<8 | uint16(row[i+1])
+				p := uint16(row[i-2*colors])<<8 | uint16(row[i-2*colors+1])
+				v += p
+				row[i], row[i+1] = byte(v>>8), byte(v)
+			}
+		default:
+			// Sub-byte samples: unpack, accumulate, repack
+			mask := 1<<bpc - 1
+			samples := len(row) * 8 / bpc
+			get := func(i int) int {
+				bit := i * bpc
+				return int(row[bit/8]>>(8-bpc-bit%8)) & mask
+			}
+			set := func(i, v int) {
+				bit := i * bpc
+				shift := 8 - bpc - bit%8
+				row[bit/8] = row[bit/8]&^byte(mask<<shift) | byte((v&mask)<<shift)
+			}
+			for i := colors; i < samples; i++ {
+				set(i, get(i)+get(i-colors))
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,55 @@
+package filters
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestApplyPredictor(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		parms map[string]any
+		want  []byte
+	}{
+		{"none", []byte{1, 2, 3}, map[string]any{}, []byte{1, 2, 3}},
+		{"png up", []byte{0, 1, 2, 2, 1, 1}, map[string]any{"Predictor": 12.0, "Columns": 2.0}, []byte{1, 2, 2, 3}},
+		{"png sub", []byte{1, 1, 1, 1}, map[string]any{"Predictor": 11.0, "Columns": 3.0}, []byte{1, 2, 3}},
+		{"tiff", []byte{1, 1, 1, 5, 1, 1}, map[string]any{"Predictor": 2.0, "Columns": 3.0}, []byte{1, 2, 3, 5, 6, 7}},
+		{"empty", nil, map[string]any{"Predictor": 12.0, "Columns": 1e9}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPredictor(tt.data, tt.parms)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ApplyPredictor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPredictorRejectsHostileParameters(t *testing.T) {
+	data := []byte{0, 1, 2, 3, 4, 5, 6, 7}
+	tests := []struct {
+		name  string
+		parms map[string]any
+	}{
+		{"huge columns", map[string]any{"Predictor": 12.0, "Columns": 1e12}},
+		{"overflowing row", map[string]any{"Predictor": 12.0, "Columns": float64(1 << 60), "Colors": 32.0, "BitsPerComponent": 16.0}},
+		{"row longer than data", map[string]any{"Predictor": 2.0, "Columns": 9.0}},
+		{"too many colors", map[string]any{"Predictor": 12.0, "Colors": 1e6}},
+		{"negative columns", map[string]any{"Predictor": 12.0, "Columns": -8.0}},
+		{"bad bits", map[string]any{"Predictor": 12.0, "BitsPerComponent": 3.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ApplyPredictor(data, tt.parms); !errors.Is(err, ErrInvalidData) {
+				t.Errorf("ApplyPredictor() error = %v, want ErrInvalidData", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package font
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// CFF Top DICT operators (Adobe Technical Note #5176, Table 9)
+const (
+	cffOpCharset     = 15
+	cffOpEncoding    = 16
+	cffOpCharStrings = 17
+	cffOpROS         = 1200 + 30 // escape operator 12 30
+)
+
+// ParseCFFEncoding returns the built-in encoding and the charset of a
+// bare CFF font program, such as the contents of a FontFile3 stream with
+// /Subtype /Type1C. The charset holds the glyph names by glyph index.
+//
+// Only the first font of the program is read. The predefined Expert
+// encoding and charsets are not bundled: a font using them has a nil
+// encoding or charset. CID-keyed fonts, whose charsets name CIDs rather
+// than glyphs, are rejected.
+func ParseCFFEncoding(data []byte) (enc *GlyphEncoding, charset []string, err error) {
+	if len(data) < 4 || data[0] != 1 {
+		return nil, nil, fmt.Errorf("%w: not a CFF font", ErrInvalidFontProgram)
+	}
+	_, off, err := cffIndex(data, int(data[2])) // Name INDEX
+	if err != nil {
+		return nil, nil, err
+	}
+	topDicts, off, err := cffIndex(data, off)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(topDicts) == 0 {
+		return nil, nil, fmt.Errorf("%w: CFF font has no Top DICT", ErrInvalidFontProgram)
+	}
+	strs, _, err := cffIndex(data, off)
+	if err != nil {
+		return nil, nil, err
+	}
+	top := parseCFFDict(topDicts[0])
+	if _, ok := top[cffOpROS]; ok {
+		return nil, nil, fmt.Errorf("%w: CID-keyed CFF font has no encoding", ErrInvalidFontProgram)
+	}
+	sidName := func(sid int) string {
+		if sid < len(cffStandardStrings) {
+			return cffStandardStrings[sid]
+		}
+		if i := sid - len(cffStandardStrings); i < len(strs) {
+			return string(strs[i])
+		}
+		return ""
+	}
+
+	charStrings, _, err := cffIndex(data, top.offset(cffOpCharStrings, 0))
+	if err != nil {
+		return nil, nil, err
+	}
+	charset = cffCharset(data, top.offset(cffOpCharset, 0), len(charStrings), sidName)
+	enc = cffEncoding(data, top.offset(cffOpEncoding, 0), charset, sidName)
+	return enc, charset, nil
+}
+
+// cffCharset reads the charset at offset for a font of n glyphs, returning
+// the glyph names by glyph index.
+func cffCharset(data []byte, offset, n int, sidName func(int) string) []string {
+	switch offset {
+	case 0: // ISOAdobe: glyph i has SID i
+		charset := make([]string, min(n, 229))
+		for gid := range charset {
+			charset[gid] = sidName(gid)
+		}
+		return charset
+	case 1, 2: // Expert and ExpertSubset
+		return nil
+	}
+	if n == 0 || offset >= len(data) {
+		return nil
+	}
+	charset := []string{".notdef"}
+	format := data[offset]
+	p := offset + 1
+	for len(charset) < n {
+		switch format {
+		case 0:
+			if p+2 > len(data) {
+				return charset
+			}
+			charset = append(charset, sidName(int(binary.BigEndian.Uint16(data[p:]))))
+			p += 2
+		case 1, 2:
+			size := 3 + int(format) - 1
+			if p+size > len(data) {
+				return charset
+			}
+			first := int(binary.BigEndian.Uint16(data[p:]))
+			left := int(data[p+2])
+			if format == 2 {
+				left = int(binary.BigEndian.Uint16(data[p+2:]))
+			}
+			for sid := first; sid <= first+left && len(charset) < n; sid++ {
+				charset = append(charset, sidName(sid))
+			}
+			p += size
+		default:
+			return charset
+		}
+	}
+	return charset
+}
+
+// cffEncoding reads the encoding at offset, naming the glyphs through the
+// charset.
+func cffEncoding(data []byte, offset int, charset []string, sidName func(int) string) *GlyphEncoding {
+	switch offset {
+	case 0:
+		enc := standardEncoding
+		return &enc
+	case 1: // Expert
+		return nil
+	}
+	if offset+2 > len(data) {
+		return nil
+	}
+	var enc GlyphEncoding
+	glyphName := func(gid int) string {
+		if gid < len(charset) {
+			return charset[gid]
+		}
+		return ""
+	}
+	format := data[offset]
+	p := offset + 1
+	switch format & 0x7F {
+	case 0:
+		n := int(data[p])
+		p++
+		for gid := 1; gid <= n && p < len(data); gid++ {
+			enc[data[p]] = glyphName(gid)
+			p++
+		}
+	case 1:
+		ranges := int(data[p])
+		p++
+		gid := 1
+		for range ranges {
+			if p+2 > len(data) {
+				break
+			}
+			first, left := int(data[p]), int(data[p+1])
+			for code := first; code <= first+left && code < 256; code++ {
+				enc[code] = glyphName(gid)
+				gid++
+			}
+			p += 2
+		}
+	default:
+		return nil
+	}
+	if format&0x80 != 0 && p < len(data) {
+		sups := int(data[p])
+		p++
+		for range sups {
+			if p+3 > len(data) {
+				break
+			}
+			enc[data[p]] = sidName(int(binary.BigEndian.Uint16(data[p+1:])))
+			p += 3
+		}
+	}
+	return &enc
+}
+
+// cffIndex reads the INDEX at off, returning its items and the offset
+// after it.
+func cffIndex(data []byte, off int) (items [][]byte, end int, err error) {
+	if off < 0 || off+2 > len(data) {
+		return nil, 0, fmt.Errorf("%w: CFF INDEX out of bounds", ErrInvalidFontProgram)
+	}
+	count := int(binary.BigEndian.Uint16(data[off:]))
+	if count == 0 {
+		return nil, off + 2, nil
+	}
+	if off+3 > len(data) {
+		return nil, 0, fmt.Errorf("%w: CFF INDEX out of bounds", ErrInvalidFontProgram)
+	}
+	offSize := int(data[off+2])
+	if offSize < 1 || offSize > 4 {
+		return nil, 0, fmt.Errorf("%w: CFF INDEX offset size %d", ErrInvalidFontProgram, offSize)
+	}
+	offsets := off + 3
+	base := offsets + (count+1)*offSize - 1 // Offsets are relative to the byte before the data
+	if base >= len(data) {
+		return nil, 0, fmt.Errorf("%w: CFF INDEX out of bounds", ErrInvalidFontProgram)
+	}
+	readOffset := func(i int) int {
+		v := 0
+		for _, b := range data[offsets+i*offSize : offsets+(i+1)*offSize] {
+			v = v<<8 | int(b)
+		}
+		return base + v
+	}
+	items = make([][]byte, count)
+	start := readOffset(0)
+	for i := range count {
+		next := readOffset(i + 1)
+		if start < base || next < start || next > len(data) {
+			return nil, 0, fmt.Errorf("%w: CFF INDEX item out of bounds", ErrInvalidFontProgram)
+		}
+		items[i] = data[start:next]
+		start = next
+	}
+	return items, start, nil
+}
+
+// cffDict holds the operands of a CFF DICT by operator; escaped operators
+// (12 x) are stored as 1200+x.
+type cffDict map[int][]float64
+
+// offset returns the first operand of op as an offset, or def.
+func (d cffDict) offset(op, def int) int {
+	if v, ok := d[op]; ok && len(v) > 0 && v[0] >= 0 {
+		return int(v[0])
+	}
+	return def
+}
+
+// parseCFFDict parses DICT data, stopping at malformed data.
+func parseCFFDict(data []byte) cffDict {
+	d := make(cffDict)
+	var operands []float64
+	for p := 0; p < len(data); {
+		b0 := int(data[p])
+		switch {
+		case b0 <= 21:
+			op := b0
+			p++
+			if b0 == 12 {
+				if p >= len(data) {
+					return d
+				}
+				op = 1200 + int(data[p])
+				p++
+			}
+			d[op] = operands
+			operands = nil
+		case b0 == 28:
+			if p+3 > len(data) {
+				return d
+			}
+			operands = append(operands, float64(int16(binary.BigEndian.Uint16(data[p+1:]))))
+			p += 3
+		case b0 == 29:
+			if p+5 > len(data) {
+				return d
+			}
+			operands = append(operands, float64(int32(binary.BigEndian.Uint32(data[p+1:]))))
+			p += 5
+		case b0 == 30:
+			// Real number in packed BCD: skip to the nibble 0xF
+			p++
+			for p < len(data) && data[p]&0x0F != 0x0F && data[p]&0xF0 != 0xF0 {
+				p++
+			}
+			p++
+			operands = append(operands, 0)
+		case b0 >= 32 && b0 <= 246:
+			operands = append(operands, float64(b0-139))
+			p++
+		case b0 >= 247 && b0 <= 250:
+			if p+2 > len(data) {
+				return d
+			}
+			operands = append(operands, float64((b0-247)*256+int(data[p+1])+108))
+			p += 2
+		case b0 >= 251 && b0 <= 254:
+			if p+2 > len(data) {
+				return d
+			}
+			operands = append(operands, float64(-(b0-251)*256-int(data[p+1])-108))
+			p += 2
+		default:
+			return d
+		}
+	}
+	return d
+}
+
+// cffStandardStrings are the predefined strings of CFF fonts, by SID
+// (Adobe Technical Note #5176, Appendix A).
+var cffStandardStrings = [...]string{
+	".notdef", "space", "exclam", "quotedbl", "numbersign", "dollar",
+	"percent", "ampersand", "quoteright", "parenleft", "parenright",
+	"asterisk", "plus", "comma", "hyphen", "period", "slash", "zero", "one",
+	"two", "three", "four", "five", "six", "seven", "eight", "nine", "colon",
+	"semicolon", "less", "equal", "greater", "question", "at", "A", "B", "C",
+	"D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R",
+	"S", "T", "U", "V", "W", "X", "Y", "Z", "bracketleft", "backslash",
+	"bracketright", "asciicircum", "underscore", "quoteleft", "a", "b", "c",
+	"d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r",
+	"s", "t", "u", "v", "w", "x", "y", "z", "braceleft", "bar", "braceright",
+	"asciitilde", "exclamdown", "cent", "sterling", "fraction", "yen",
+	"florin", "section", "currency", "quotesingle", "quotedblleft",
+	"guillemotleft", "guilsinglleft", "guilsinglright", "fi", "fl", "endash",
+	"dagger", "daggerdbl", "periodcentered", "paragraph", "bullet",
+	"quotesinglbase", "quotedblbase", "quotedblright", "guillemotright",
+	"ellipsis", "perthousand", "questiondown", "grave", "acute", "circumflex",
+	"tilde", "macron", "breve", "dotaccent", "dieresis", "ring", "cedilla",
+	"hungarumlaut", "ogonek", "caron", "emdash", "AE", "ordfeminine",
+	"Lslash", "Oslash", "OE", "ordmasculine", "ae", "dotlessi", "lslash",
+	"oslash", "oe", "germandbls", "onesuperior", "logicalnot", "mu",
+	"trademark", "Eth", "onehalf", "plusminus", "Thorn", "onequarter",
+	"divide", "brokenbar", "degree", "thorn", "threequarters", "twosuperior",
+	"registered", "minus", "eth", "multiply", "threesuperior", "copyright",
+	"Aacute", "Acircumflex", "Adieresis", "Agrave", "Aring", "Atilde",
+	"Ccedilla", "Eacute", "Ecircumflex", "Edieresis", "Egrave", "Iacute",
+	"Icircumflex", "Idieresis", "Igrave", "Ntilde", "Oacute", "Ocircumflex",
+	"Odieresis", "Ograve", "Otilde", "Scaron", "Uacute", "Ucircumflex",
+	"Udieresis", "Ugrave", "Yacute", "Ydieresis", "Zcaron", "aacute",
+	"acircumflex", "adieresis", "agrave", "aring", "atilde", "ccedilla",
+	"eacute", "ecircumflex", "edieresis", "egrave", "iacute", "icircumflex",
+	"idieresis", "igrave", "ntilde", "oacute", "ocircumflex", "odieresis",
+	"ograve", "otilde", "scaron", "uacute", "ucircumflex", "udieresis",
+	"ugrave", "yacute", "ydieresis", "zcaron", "exclamsmall",
+	"Hungarumlautsmall", "dollaroldstyle", "dollarsuperior", "ampersandsmall",
+	"Acutesmall", "parenleftsuperior", "parenrightsuperior", "twodotenleader",
+	"onedotenleader", "zerooldstyle", "oneoldstyle", "twooldstyle",
+	"threeoldstyle", "fouroldstyle", "fiveoldstyle", "sixoldstyle",
+	"sevenoldstyle", "eightoldstyle", "nineoldstyle", "commasuperior",
+	"threequartersemdash", "periodsuperior", "questionsmall", "asuperior",
+	"bsuperior", "centsuperior", "dsuperior", "esuperior", "isuperior",
+	"lsuperior", "msuperior", "nsuperior", "osuperior", "rsuperior",
+	"ssuperior", "tsuperior", "ff", "ffi", "ffl", "parenleftinferior",
+	"parenrightinferior", "Circumflexsmall", "hyphensuperior", "Gravesmall",
+	"Asmall", "Bsmall", "Csmall", "Dsmall", "Esmall", "Fsmall", "Gsmall",
+	"Hsmall", "Ismall", "Jsmall", "Ksmall", "Lsmall", "Msmall", "Nsmall",
+	"Osmall", "Psmall", "Qsmall", "Rsmall", "Ssmall", "Tsmall", "Usmall",
+	"Vsmall", "Wsmall", "Xsmall", "Ysmall", "Zsmall", "colonmonetary",
+	"onefitted", "rupiah", "Tildesmall", "exclamdownsmall", "centoldstyle",
+	"Lslashsmall", "Scaronsmall", "Zcaronsmall", "Dieresissmall",
+	"Brevesmall", "Caronsmall", "Dotaccentsmall", "Macronsmall", "figuredash",
+	"hypheninferior", "Ogoneksmall", "Ringsmall", "Cedillasmall",
+	"questiondownsmall", "oneeighth", "threeeighths", "fiveeighths",
+	"seveneighths", "onethird", "twothirds", "zerosuperior", "foursuperior",
+	"fivesuperior", "sixsuperior", "sevensuperior", "eightsuperior",
+	"ninesuperior", "zeroinferior", "oneinferior", "twoinferior",
+	"threeinferior", "fourinferior", "fiveinferior", "sixinferior",
+	"seveninferior", "eightinferior", "nineinferior", "centinferior",
+	"dollarinferior", "periodinferior", "commainferior", "Agravesmall",
+	"Aacutesmall", "Acircumflexsmall", "Atildesmall", "Adieresissmall",
+	"Aringsmall", "AEsmall", "Ccedillasmall", "Egravesmall", "Eacutesmall",
+	"Ecircumflexsmall", "Edieresissmall", "Igravesmall", "Iacutesmall",
+	"Icircumflexsmall", "Idieresissmall", "Ethsmall", "Ntildesmall",
+	"Ogravesmall", "Oacutesmall", "Ocircumflexsmall", "Otildesmall",
+	"Odieresissmall", "OEsmall", "Oslashsmall", "Ugravesmall", "Uacutesmall",
+	"Ucircumflexsmall", "Udieresissmall", "Yacutesmall", "Thornsmall",
+	"Ydieresissmall", "001.000", "001.001", "001.002", "001.003", "Black",
+	"Bold", "Book", "Light", "Medium", "Regular", "Roman", "Semibold",
+}
@@ -0,0 +1,231 @@
+package font
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// CIDSystemInfo identifies the character collection of a CIDFont
+// (/CIDSystemInfo), e.g. Adobe-Japan1-6.
+type CIDSystemInfo struct {
+	Registry   string
+	Ordering   string
+	Supplement int
+}
+
+// String returns the collection as Registry-Ordering-Supplement.
+func (c CIDSystemInfo) String() string {
+	return fmt.Sprintf("%s-%s-%d", c.Registry, c.Ordering, c.Supplement)
+}
+
+// CIDFont is the descendant font of a Type0 font, which holds the glyphs
+// selected by CID.
+type CIDFont struct {
+	// Subtype is CIDFontType0 (CFF glyphs) or CIDFontType2 (TrueType
+	// glyphs).
+	Subtype string
+
+	BaseFont   string
+	SystemInfo CIDSystemInfo
+
+	// CIDToGID maps CIDs to glyph indices of a CIDFontType2 font program
+	// (/CIDToGIDMap). Nil means the identity mapping.
+	CIDToGID []uint16
+}
+
+// GID returns the glyph index for a CID.
+func (c *CIDFont) GID(cid uint32) uint32 {
+	if c.CIDToGID == nil {
+		return cid
+	}
+	if int(cid) < len(c.CIDToGID) {
+		return uint32(c.CIDToGID[cid])
+	}
+	return 0
+}
+
+// ParseCIDToGIDMap parses the contents of a /CIDToGIDMap stream: one
+// big-endian two-byte glyph index per CID.
+func ParseCIDToGIDMap(data []byte) []uint16 {
+	gids := make([]uint16, len(data)/2)
+	for i := range gids {
+		gids[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+	}
+	return gids
+}
+
+// cmapKind classifies how an encoding CMap maps codes.
+type cmapKind int
+
+const (
+	// cmapIdentity maps each two-byte code to the CID of the same value.
+	cmapIdentity cmapKind = iota
+	// cmapUnicode codes are Unicode in UTF-16BE (the Uni*-UCS2 and
+	// Uni*-UTF16 CMaps); their CIDs need the collection's tables, which
+	// are not bundled.
+	cmapUnicode
+	// cmapEmbedded is a CMap stream embedded in the document.
+	cmapEmbedded
+)
+
+// EncodingCMap is the /Encoding of a Type0 font: it splits show-strings
+// into character codes of one to four bytes and maps the codes to CIDs.
+type EncodingCMap struct {
+	// Name is the CMap name, e.g. Identity-H or UniJIS-UCS2-H.
+	Name string
+
+	// Vertical is set for vertical writing mode (WMode 1, the -V CMaps).
+	Vertical bool
+
+	kind       cmapKind
+	codespaces []CodespaceRange
+
+	// base is the predefined CMap an embedded CMap uses (usecmap)
+	base *EncodingCMap
+
+	// cids holds an embedded CMap's cidchar and cidrange mappings
+	cids *CMap
+}
+
+var (
+	twoByteCodespace = []CodespaceRange{{Low: []byte{0x00, 0x00}, High: []byte{0xFF, 0xFF}}}
+	utf16Codespace   = []CodespaceRange{
+		{Low: []byte{0x00, 0x00}, High: []byte{0xD7, 0xFF}},
+		{Low: []byte{0xD8, 0x00, 0xDC, 0x00}, High: []byte{0xDB, 0xFF, 0xDF, 0xFF}},
+		{Low: []byte{0xE0, 0x00}, High: []byte{0xFF, 0xFF}},
+	}
+)
+
+// PredefinedCMap returns the predefined CMap of the given name. Identity-H
+// and Identity-V are supported, and the Unicode CMaps of the Adobe CJK
+// collections (Uni*-UCS2-* and Uni*-UTF16-*), whose codes decode to text
+// directly. ok is false for other names, such as the legacy CJK encodings
+// (90ms-RKSJ-H, GBK-EUC-H, ...), whose tables are not bundled.
+func PredefinedCMap(name string) (cmap *EncodingCMap, ok bool) {
+	vertical := strings.HasSuffix(name, "-V")
+	switch {
+	case name == "Identity-H" || name == "Identity-V":
+		return &EncodingCMap{Name: name, Vertical: vertical, kind: cmapIdentity, codespaces: twoByteCodespace}, true
+	case strings.HasPrefix(name, "Uni") && strings.Contains(name, "-UCS2-"):
+		return &EncodingCMap{Name: name, Vertical: vertical, kind: cmapUnicode, codespaces: twoByteCodespace}, true
+	case strings.HasPrefix(name, "Uni") && strings.Contains(name, "-UTF16-"):
+		return &EncodingCMap{Name: name, Vertical: vertical, kind: cmapUnicode, codespaces: utf16Codespace}, true
+	}
+	return nil, false
+}
+
+// ParseEncodingCMap parses an embedded encoding CMap stream. Its
+// codespace ranges split show-strings into codes; a CMap that declares
+// none takes them from the predefined CMap it names with usecmap, or uses
+// two-byte codes.
+//
+// Codes are mapped to CIDs by the CMap's cidchar and cidrange sections,
+// then through the base CMap named with usecmap if there is one. A CMap
+// with no such mappings maps a code to the CID of the same value.
+func ParseEncodingCMap(r io.Reader) (*EncodingCMap, error) {
+	cm, err := ParseToUnicodeCMap(r)
+	if err != nil {
+		return nil, err
+	}
+	e := &EncodingCMap{
+		Name:       cm.name,
+		Vertical:   cm.wmode == 1,
+		kind:       cmapEmbedded,
+		codespaces: cm.codespaces,
+	}
+	if cm.HasCIDs() {
+		e.cids = cm
+	}
+	if cm.useCMap != "" {
+		e.base, _ = PredefinedCMap(cm.useCMap)
+	}
+	if len(e.codespaces) == 0 {
+		e.codespaces = twoByteCodespace
+		if e.base != nil {
+			e.codespaces = e.base.codespaces
+		}
+	}
+	return e, nil
+}
+
+// NextCode returns the character code at the start of data and its length
+// in bytes. A code is matched against the codespace ranges from the
+// shortest to the longest; bytes matching no range are consumed as a code
+// of the shortest declared length, as the spec recommends. A trailing
+// partial code is padded with zero bytes.
+func (e *EncodingCMap) NextCode(data []byte) (code uint32, n int) {
+	shortest := 4
+	for _, r := range e.codespaces {
+		shortest = min(shortest, len(r.Low))
+	}
+	n = shortest
+	for length := shortest; length <= 4 && length <= len(data); length++ {
+		if e.inCodespace(data[:length]) {
+			n = length
+			break
+		}
+	}
+	for i := range n {
+		code <<= 8
+		if i < len(data) {
+			code |= uint32(data[i])
+		}
+	}
+	return code, min(n, len(data))
+}
+
+func (e *EncodingCMap) inCodespace(code []byte) bool {
+	for _, r := range e.codespaces {
+		if r.Contains(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// CID returns the CID of a code of n bytes. ok is false if the CMap
+// cannot tell, as for the Unicode CMaps. Codes an embedded CMap's cidchar
+// and cidrange mappings leave unmapped, with no usecmap to fall back on,
+// get CID 0 (.notdef).
+func (e *EncodingCMap) CID(code uint32, n int) (cid uint32, ok bool) {
+	if e.cids != nil {
+		if cid, ok := e.cids.lookupCID(code, n); ok {
+			return cid, true
+		}
+	}
+	switch {
+	case e.kind == cmapUnicode:
+		return 0, false
+	case e.base != nil:
+		return e.base.CID(code, n)
+	case e.cids != nil:
+		return 0, true
+	default:
+		return code, true
+	}
+}
+
+// IsUnicode reports whether the CMap's codes are Unicode values, so that
+// text can be decoded without a ToUnicode CMap.
+func (e *EncodingCMap) IsUnicode() bool {
+	return e.kind == cmapUnicode || e.base != nil && e.base.IsUnicode()
+}
+
+// unicode returns the text of a code of n bytes for the Unicode CMaps.
+func (e *EncodingCMap) unicode(code uint32, n int) (string, bool) {
+	if !e.IsUnicode() {
+		return "", false
+	}
+	if n == 4 {
+		r := utf16.DecodeRune(rune(code>>16), rune(code&0xFFFF))
+		return string(r), r != unicode.ReplacementChar
+	}
+	r := rune(code)
+	if utf16.IsSurrogate(r) {
+		return "", false
+	}
+	return string(r), true
+}
@@ -3,23 +3,57 @@ package font
 import (
 	"bufio"
 	"bytes"
+	"cmp"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"iter"
+	"maps"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf16"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
 )
 
 // CMap represents a character code to Unicode mapping (ToUnicode CMap).
 type CMap struct {
-	// Mappings from character code (as hex string) to Unicode string
-	mappings map[string]string
+	// Mappings from character code to Unicode string (bfchar)
+	mappings map[cmapCode]string
+
+	// Range mappings (bfrange), sorted by first code
+	ranges []bfRange
+
+	// Declared codespace ranges (begincodespacerange)
+	codespaces []CodespaceRange
+
+	// Code to CID mappings of encoding CMaps (cidchar, cidrange), sorted
+	// by code length and then code
+	cids []cidRange
+
+	// Source entries and problems seen while parsing, kept for Validate
+	entries []cmapEntry
+	issues  []Violation
+
+	// Set by assess: see Quality and IsDegenerate
+	quality    float64
+	degenerate bool
+
+	// Header entries used by encoding CMaps: /CMapName, /WMode, and the
+	// CMap named by usecmap
+	name    string
+	wmode   int
+	useCMap string
+
+	maxEntries int // while parsing, see ParseToUnicodeCMapLimit
 }
 
 // NewCMap creates an empty CMap.
 func NewCMap() *CMap {
 	return &CMap{
-		mappings: make(map[string]string),
+		mappings: make(map[cmapCode]string),
 	}
 }
 
@@ -27,15 +61,41 @@ func NewCMap() *CMap {
 // ToUnicode CMaps use PostScript-like syntax with operators:
 //   - beginbfchar/endbfchar: single character mappings
 //   - beginbfrange/endbfrange: range mappings
+//
+// The code to CID mappings of encoding CMaps (begincidchar and
+// begincidrange) are read too, and looked up with CID.
 func ParseToUnicodeCMap(r io.Reader) (*CMap, error) {
+	return ParseToUnicodeCMapLimit(r, 0)
+}
+
+// ParseToUnicodeCMapLimit is like ParseToUnicodeCMap but fails with a
+// *parser.LimitError if the CMap has more than maxMappings bfchar,
+// bfrange, cidchar and cidrange entries, guarding against CMaps crafted to
+// exhaust memory. A range counts as one entry. Zero means no limit.
+func ParseToUnicodeCMapLimit(r io.Reader, maxMappings int) (*CMap, error) {
 	cmap := NewCMap()
+	cmap.maxEntries = maxMappings
 	scanner := bufio.NewScanner(r)
-	scanner.Split(bufio.ScanWords) // Token by token
+	scanner.Split(scanCMapTokens)
 
+	var prev string
 	for scanner.Scan() {
 		token := scanner.Text()
 
+		switch prev {
+		case "/CMapName":
+			cmap.name = strings.TrimPrefix(token, "/")
+		case "/WMode":
+			cmap.wmode, _ = strconv.Atoi(token)
+		}
+		if token == "usecmap" {
+			cmap.useCMap = strings.TrimPrefix(prev, "/")
+		}
+		prev = token
+
 		switch token {
+		case "begincodespacerange":
+			cmap.parseCodespaceRange(scanner)
 		case "beginbfchar":
 			if err := cmap.parseBfChar(scanner); err != nil {
 				return nil, fmt.Errorf("parsing bfchar: %w", err)
@@ -44,13 +104,24 @@ func ParseToUnicodeCMap(r io.Reader) (*CMap, error) {
 			if err := cmap.parseBfRange(scanner); err != nil {
 				return nil, fmt.Errorf("parsing bfrange: %w", err)
 			}
+		case "begincidchar":
+			if err := cmap.parseCIDMappings(scanner, "cidchar"); err != nil {
+				return nil, fmt.Errorf("parsing cidchar: %w", err)
+			}
+		case "begincidrange":
+			if err := cmap.parseCIDMappings(scanner, "cidrange"); err != nil {
+				return nil, fmt.Errorf("parsing cidrange: %w", err)
+			}
 		}
-		// Ignore other tokens (CMap headers, versions, etc.)
+		// Ignore other tokens (CIDSystemInfo, resource operators, etc.)
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("scanner error: %w", err)
 	}
+	cmap.sortRanges()
+	slices.SortStableFunc(cmap.cids, compareCIDRanges)
+	cmap.assess()
 
 	return cmap, nil
 }
@@ -68,6 +139,7 @@ func (cm *CMap) parseBfChar(scanner *bufio.Scanner) error {
 		// Expect hex string for source code
 		srcCode := strings.TrimSpace(token)
 		if !isHexString(srcCode) {
+			cm.addIssue("bfchar", srcCode, "source code is not a hex string")
 			continue // Skip non-hex tokens
 		}
 
@@ -77,6 +149,7 @@ func (cm *CMap) parseBfChar(scanner *bufio.Scanner) error {
 		}
 		dstUnicode := strings.TrimSpace(scanner.Text())
 		if !isHexString(dstUnicode) {
+			cm.addIssue("bfchar", srcCode+" "+dstUnicode, "destination is not a hex string")
 			continue // Skip malformed entries
 		}
 
@@ -87,10 +160,23 @@ func (cm *CMap) parseBfChar(scanner *bufio.Scanner) error {
 		// Convert destination to Unicode string
 		unicodeStr, err := hexToUnicodeString(dstHex)
 		if err != nil {
+			cm.addIssue("bfchar", srcCode+" "+dstUnicode, "invalid destination: "+err.Error())
 			continue // Skip invalid Unicode
 		}
+		src, err := hex.DecodeString(srcHex)
+		if err != nil {
+			cm.addIssue("bfchar", srcCode, "invalid source code: "+err.Error())
+			continue
+		}
+		if len(src) == 0 || len(src) > 4 {
+			cm.addIssue("bfchar", srcCode, "source code must be 1 to 4 bytes long")
+			continue
+		}
 
-		cm.mappings[srcHex] = unicodeStr
+		if err := cm.addEntry(cmapEntry{section: "bfchar", low: src, high: src, dst: dstHex}); err != nil {
+			return err
+		}
+		cm.mappings[newCMapCode(src)] = unicodeStr
 	}
 	return fmt.Errorf("endbfchar not found")
 }
@@ -108,6 +194,9 @@ func (cm *CMap) parseBfRange(scanner *bufio.Scanner) error {
 		// Expect: <start> <end> <dstStart>
 		srcStart := strings.TrimSpace(token)
 		if !isHexString(srcStart) {
+			if !strings.HasSuffix(srcStart, "]") {
+				cm.addIssue("bfrange", srcStart, "source code is not a hex string")
+			}
 			continue
 		}
 
@@ -116,6 +205,7 @@ func (cm *CMap) parseBfRange(scanner *bufio.Scanner) error {
 		}
 		srcEnd := strings.TrimSpace(scanner.Text())
 		if !isHexString(srcEnd) {
+			cm.addIssue("bfrange", srcStart+" "+srcEnd, "range end is not a hex string")
 			continue
 		}
 
@@ -133,6 +223,7 @@ func (cm *CMap) parseBfRange(scanner *bufio.Scanner) error {
 		}
 
 		if !isHexString(dstStart) {
+			cm.addIssue("bfrange", srcStart+" "+srcEnd+" "+dstStart, "destination is not a hex string")
 			continue
 		}
 
@@ -140,42 +231,224 @@ func (cm *CMap) parseBfRange(scanner *bufio.Scanner) error {
 		srcStartHex := stripHexBrackets(srcStart)
 		srcEndHex := stripHexBrackets(srcEnd)
 		dstStartHex := stripHexBrackets(dstStart)
+		entry := srcStart + " " + srcEnd + " " + dstStart
 
-		startCode, err := hexStringToInt(srcStartHex)
-		if err != nil {
+		low, err := hex.DecodeString(srcStartHex)
+		if err != nil || len(low) == 0 || len(low) > 4 {
+			cm.addIssue("bfrange", entry, "invalid range start")
 			continue
 		}
-		endCode, err := hexStringToInt(srcEndHex)
-		if err != nil {
+		high, err := hex.DecodeString(srcEndHex)
+		if err != nil || len(high) == 0 || len(high) > 4 {
+			cm.addIssue("bfrange", entry, "invalid range end")
 			continue
 		}
-		dstCode, err := hexStringToInt(dstStartHex)
-		if err != nil {
+		dst, err := hex.DecodeString(dstStartHex)
+		if err != nil || len(dst) == 0 {
+			cm.addIssue("bfrange", entry, "invalid destination")
 			continue
 		}
+		if err := cm.addEntry(cmapEntry{section: "bfrange", low: low, high: high, dst: dstStartHex}); err != nil {
+			return err
+		}
 
-		// Create mappings for the range
-		for code := startCode; code <= endCode; code++ {
-			srcHex := fmt.Sprintf("%02x", code)
-			unicodeValue := dstCode + (code - startCode)
-			unicodeStr := string(rune(unicodeValue))
-			cm.mappings[srcHex] = unicodeStr
+		// Keep the range as an interval rather than a mapping per code
+		startCode, endCode := codeValue(low), codeValue(high)
+		if startCode <= endCode {
+			cm.ranges = append(cm.ranges, bfRange{n: len(low), low: startCode, high: endCode, dst: dst, order: len(cm.ranges)})
 		}
 	}
 	return fmt.Errorf("endbfrange not found")
 }
 
+// addEntry records a parsed entry, failing if that exceeds the maximum
+// set by ParseToUnicodeCMapLimit.
+func (cm *CMap) addEntry(e cmapEntry) error {
+	if cm.maxEntries > 0 && len(cm.entries) == cm.maxEntries {
+		return &parser.LimitError{Limit: "MaxCMapMappings", Max: cm.maxEntries, Offset: -1}
+	}
+	cm.entries = append(cm.entries, e)
+	return nil
+}
+
+// bfRange is a bfrange entry: the n-byte codes low..high map to
+// consecutive destinations starting at dst.
+type bfRange struct {
+	n         int
+	low, high uint32
+	dst       []byte
+
+	// order is the declaration order; a later range overrides an earlier
+	// one where they overlap
+	order int
+
+	// reach is the largest high of this range and the ranges of the same
+	// code length sorted before it
+	reach uint32
+}
+
+// sortRanges sorts the bfrange intervals by code length and first code
+// for lookupRange.
+func (cm *CMap) sortRanges() {
+	slices.SortStableFunc(cm.ranges, func(a, b bfRange) int {
+		if a.n != b.n {
+			return cmp.Compare(a.n, b.n)
+		}
+		return cmp.Compare(a.low, b.low)
+	})
+	var reach uint32
+	for i := range cm.ranges {
+		if i == 0 || cm.ranges[i].n != cm.ranges[i-1].n {
+			reach = 0
+		}
+		reach = max(reach, cm.ranges[i].high)
+		cm.ranges[i].reach = reach
+	}
+}
+
+// lookupRange returns the text the bfrange intervals map code to. A code
+// matches only ranges whose source codes have its length.
+func (cm *CMap) lookupRange(code []byte) (string, bool) {
+	n, value := len(code), codeValue(code)
+	i := sort.Search(len(cm.ranges), func(i int) bool {
+		r := cm.ranges[i]
+		return r.n > n || r.n == n && r.low > value
+	}) - 1
+	best := -1
+	for ; i >= 0 && cm.ranges[i].n == n && cm.ranges[i].reach >= value; i-- {
+		if value <= cm.ranges[i].high && (best < 0 || cm.ranges[i].order > cm.ranges[best].order) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	r := cm.ranges[best]
+	return rangeText(r.dst, int(value-r.low)), true
+}
+
+// cidRange maps the n-byte codes low..high to consecutive CIDs starting
+// at cid. A cidchar entry is a range of one code.
+type cidRange struct {
+	n         int
+	low, high uint32
+	cid       uint32
+}
+
+func compareCIDRanges(a, b cidRange) int {
+	if a.n != b.n {
+		return cmp.Compare(a.n, b.n)
+	}
+	return cmp.Compare(a.low, b.low)
+}
+
+// parseCIDMappings parses a begincidchar/endcidchar section, whose entries
+// are <code> cid, or a begincidrange/endcidrange section, whose entries
+// are <low> <high> cid.
+// Example: <8140> <817E> 633  maps 0x8140-0x817E to CIDs 633-695
+func (cm *CMap) parseCIDMappings(scanner *bufio.Scanner, section string) error {
+	end := "end" + section
+	for scanner.Scan() {
+		token := strings.TrimSpace(scanner.Text())
+		if token == end {
+			return nil
+		}
+		if !isHexString(token) {
+			cm.addIssue(section, token, "source code is not a hex string")
+			continue
+		}
+		entry := token
+		lowToken, highToken := token, token
+		if section == "cidrange" {
+			if !scanner.Scan() {
+				return fmt.Errorf("unexpected EOF in %s", section)
+			}
+			highToken = strings.TrimSpace(scanner.Text())
+			entry += " " + highToken
+		}
+		if !scanner.Scan() {
+			return fmt.Errorf("unexpected EOF in %s", section)
+		}
+		dst := strings.TrimSpace(scanner.Text())
+		entry += " " + dst
+
+		low, err1 := hex.DecodeString(stripHexBrackets(lowToken))
+		high, err2 := hex.DecodeString(stripHexBrackets(highToken))
+		cid, err3 := strconv.ParseUint(dst, 10, 32)
+		switch {
+		case !isHexString(highToken) || err1 != nil || err2 != nil:
+			cm.addIssue(section, entry, "source code is not valid hex")
+			continue
+		case err3 != nil:
+			cm.addIssue(section, entry, "destination is not a CID")
+			continue
+		case len(low) < 1 || len(low) > 4 || len(low) != len(high):
+			cm.addIssue(section, entry, "source codes must be 1 to 4 bytes long and of equal length")
+			continue
+		}
+		if err := cm.addEntry(cmapEntry{section: section, low: low, high: high, dst: dst}); err != nil {
+			return err
+		}
+		lowCode, highCode := codeValue(low), codeValue(high)
+		if lowCode > highCode {
+			continue
+		}
+		cm.cids = append(cm.cids, cidRange{n: len(low), low: lowCode, high: highCode, cid: uint32(cid)})
+	}
+	return fmt.Errorf("%s not found", end)
+}
+
+// CID returns the CID an encoding CMap's cidchar and cidrange mappings
+// give a code. ok is false if no mapping covers it.
+func (cm *CMap) CID(code []byte) (cid uint32, ok bool) {
+	return cm.lookupCID(codeValue(code), len(code))
+}
+
+// lookupCID returns the CID mapped to the n-byte code value.
+func (cm *CMap) lookupCID(value uint32, n int) (cid uint32, ok bool) {
+	i := sort.Search(len(cm.cids), func(i int) bool {
+		r := cm.cids[i]
+		return r.n > n || r.n == n && r.high >= value
+	})
+	// Ranges may overlap; the one starting last wins
+	for ; i < len(cm.cids) && cm.cids[i].n == n && cm.cids[i].low <= value; i++ {
+		if r := cm.cids[i]; value <= r.high {
+			cid, ok = r.cid+value-r.low, true
+		}
+	}
+	return cid, ok
+}
+
+// HasCIDs reports whether the CMap has cidchar or cidrange mappings.
+func (cm *CMap) HasCIDs() bool {
+	return len(cm.cids) > 0
+}
+
 // Lookup returns the Unicode string for a given character code.
 // The code should be provided as raw bytes.
 func (cm *CMap) Lookup(code []byte) (string, bool) {
-	// Convert code to hex string (lowercase, with leading zeros)
-	// Format as zero-padded hex to match the format used when storing mappings
-	hexKey := ""
-	for _, b := range code {
-		hexKey += fmt.Sprintf("%02x", b)
+	if len(code) == 0 || len(code) > 4 {
+		return "", false
+	}
+	if unicode, ok := cm.mappings[newCMapCode(code)]; ok {
+		return unicode, true
 	}
-	unicode, ok := cm.mappings[hexKey]
-	return unicode, ok
+	return cm.lookupRange(code)
+}
+
+// cmapCode is a character code of one to four bytes: codes of different
+// lengths are distinct even if their values are equal (<0041> and <41>).
+type cmapCode struct {
+	value uint32
+	n     uint8
+}
+
+func newCMapCode(code []byte) cmapCode {
+	return cmapCode{value: codeValue(code), n: uint8(len(code))}
+}
+
+func (c cmapCode) String() string {
+	return fmt.Sprintf("%0*x", 2*int(c.n), c.value)
 }
 
 // LookupByte is a convenience method for single-byte codes.
@@ -185,6 +458,37 @@ func (cm *CMap) LookupByte(code byte) (string, bool) {
 
 // Helper functions
 
+// scanCMapTokens is a bufio.SplitFunc that splits a CMap into
+// whitespace-separated tokens, like bufio.ScanWords, and also splits hex
+// strings written with no space between them, as in <0000><FFFF>.
+func scanCMapTokens(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) && isCMapSpace(data[start]) {
+		start++
+	}
+	for i := start; i < len(data); i++ {
+		switch {
+		case isCMapSpace(data[i]):
+			return i + 1, data[start:i], nil
+		case data[i] == '<' && i > start && data[i-1] == '>':
+			return i, data[start:i], nil
+		}
+	}
+	if atEOF && start < len(data) {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+// isCMapSpace reports whether b is PDF whitespace.
+func isCMapSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\r', '\f', 0:
+		return true
+	}
+	return false
+}
+
 func isHexString(s string) bool {
 	s = strings.TrimSpace(s)
 	return strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">")
@@ -197,14 +501,9 @@ func stripHexBrackets(s string) string {
 	return s
 }
 
-// hexStringToInt converts a hex string to an integer.
-func hexStringToInt(hexStr string) (int, error) {
-	val, err := strconv.ParseInt(hexStr, 16, 64)
-	return int(val), err
-}
-
 // hexToUnicodeString converts a hex-encoded Unicode string to a Go string.
-// For multi-byte Unicode (e.g., <FEFF0041> for BOM + A), this handles UTF-16BE.
+// For multi-byte Unicode (e.g., <FEFF0041> for BOM + A), this handles UTF-16BE;
+// surrogate pairs such as <D840DC0B> decode to a single rune (U+2000B).
 func hexToUnicodeString(hexStr string) (string, error) {
 	data, err := hex.DecodeString(hexStr)
 	if err != nil {
@@ -217,16 +516,7 @@ func hexToUnicodeString(hexStr string) (string, error) {
 		if len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF {
 			data = data[2:] // Strip BOM
 		}
-
-		// Decode UTF-16BE to runes
-		var runes []rune
-		for i := 0; i < len(data); i += 2 {
-			if i+1 < len(data) {
-				r := rune(data[i])<<8 | rune(data[i+1])
-				runes = append(runes, r)
-			}
-		}
-		return string(runes), nil
+		return string(utf16.Decode(utf16BEUnits(data))), nil
 	}
 
 	// Single byte - treat as direct Unicode codepoint
@@ -237,50 +527,224 @@ func hexToUnicodeString(hexStr string) (string, error) {
 	return "", fmt.Errorf("invalid Unicode hex: %s", hexStr)
 }
 
-// DecodeString decodes a byte sequence using this CMap.
-// For multi-byte encodings, this attempts to find the longest matching prefix.
+// utf16BEUnits splits UTF-16BE data into code units, dropping an odd
+// trailing byte.
+func utf16BEUnits(data []byte) []uint16 {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+	}
+	return units
+}
+
+// rangeText returns the text of the code offset places into a bfrange
+// whose first code maps to dst. The offset is added to the last UTF-16
+// code unit, so destinations that are surrogate pairs or several
+// characters long advance like single characters do. Surrogate pairs are
+// combined into one rune; unpaired surrogates become U+FFFD.
+func rangeText(dst []byte, offset int) string {
+	if len(dst) < 2 || len(dst)%2 != 0 {
+		return string(rune(int(codeValue(dst)) + offset))
+	}
+	units := utf16BEUnits(dst)
+	if len(units) == 1 {
+		return string(rune(int(units[0]) + offset))
+	}
+	units[len(units)-1] += uint16(offset)
+	return string(utf16.Decode(units))
+}
+
+// DecodeString decodes a byte sequence using this CMap, splitting it into
+// codes as Font.DecodeShowString does for a font with this ToUnicode
+// CMap. Codes without a mapping become U+FFFD.
 func (cm *CMap) DecodeString(data []byte) string {
 	var result strings.Builder
 	result.Grow(len(data))
+	for i := 0; i < len(data); {
+		n := cm.matchCode(data[i:], cm.Lookup)
+		unicode, ok := cm.Lookup(data[i : i+n])
+		if !ok {
+			unicode = "\uFFFD"
+		}
+		result.WriteString(unicode)
+		i += n
+	}
+	return result.String()
+}
 
-	i := 0
-	for i < len(data) {
-		matched := false
-
-		// Try 2-byte code
-		if i+1 < len(data) {
-			code := data[i : i+2]
-			if unicode, ok := cm.Lookup(code); ok {
-				result.WriteString(unicode)
-				i += 2
-				matched = true
-				continue
+// matchCode returns the length of the code at the start of data, which
+// must not be empty, for decoding through the CMap with lookup. If the
+// CMap declares codespace ranges of different lengths, the range the
+// code lies in decides, as for encoding CMaps. Otherwise, and for bytes
+// outside every range, a two-byte code is taken if lookup maps it and a
+// single byte if not: ToUnicode CMaps of simple fonts often declare a
+// two-byte codespace for one-byte codes.
+func (cm *CMap) matchCode(data []byte, lookup func(code []byte) (string, bool)) int {
+	if cm.mixedCodespaces() {
+		for n := 1; n <= 4 && n <= len(data); n++ {
+			if cm.inCodespace(data[:n]) {
+				return n
 			}
 		}
-
-		// Try 1-byte code
-		if unicode, ok := cm.LookupByte(data[i]); ok {
-			result.WriteString(unicode)
-			i++
-			matched = true
+	}
+	if len(data) >= 2 {
+		if _, ok := lookup(data[:2]); ok {
+			return 2
 		}
+	}
+	return 1
+}
 
-		// No mapping found - output replacement character
-		if !matched {
-			result.WriteRune('\uFFFD')
-			i++
+// mixedCodespaces reports whether the codespace ranges declare codes of
+// more than one length.
+func (cm *CMap) mixedCodespaces() bool {
+	for _, r := range cm.codespaces {
+		if len(r.Low) != len(cm.codespaces[0].Low) {
+			return true
 		}
 	}
-
-	return result.String()
+	return false
 }
 
 // String returns a debug representation of the CMap.
 func (cm *CMap) String() string {
 	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf("CMap with %d mappings:\n", len(cm.mappings)))
+	buf.WriteString(fmt.Sprintf("CMap with %d mappings and %d ranges:\n", len(cm.mappings), len(cm.ranges)))
 	for code, unicode := range cm.mappings {
 		buf.WriteString(fmt.Sprintf("  %s -> %q\n", code, unicode))
 	}
+	for _, r := range cm.ranges {
+		buf.WriteString(fmt.Sprintf("  %0*x-%0*x -> %q...\n", 2*r.n, r.low, 2*r.n, r.high, rangeText(r.dst, 0)))
+	}
 	return buf.String()
 }
+
+// Merge adds the mappings and codespace ranges of other to cm. Where both
+// map a code, the mapping from other wins, as a later section would.
+func (cm *CMap) Merge(other *CMap) {
+	for _, r := range other.ranges {
+		maps.DeleteFunc(cm.mappings, func(code cmapCode, _ string) bool {
+			return int(code.n) == r.n && code.value >= r.low && code.value <= r.high
+		})
+	}
+	maps.Copy(cm.mappings, other.mappings)
+
+	order := 0
+	for _, r := range cm.ranges {
+		order = max(order, r.order+1)
+	}
+	for _, r := range other.ranges {
+		r.order += order
+		cm.ranges = append(cm.ranges, r)
+	}
+	cm.sortRanges()
+
+	for _, r := range other.codespaces {
+		if !slices.ContainsFunc(cm.codespaces, func(c CodespaceRange) bool {
+			return bytes.Equal(c.Low, r.Low) && bytes.Equal(c.High, r.High)
+		}) {
+			cm.codespaces = append(cm.codespaces, r)
+		}
+	}
+	cm.cids = append(cm.cids, other.cids...)
+	slices.SortStableFunc(cm.cids, compareCIDRanges)
+	cm.entries = append(cm.entries, other.entries...)
+	cm.issues = append(cm.issues, other.issues...)
+	cm.assess()
+}
+
+// ReverseLookup returns a character code that Lookup maps to unicode. If
+// several do, the shortest code with the lowest value is returned. ok is
+// false if no code maps to unicode. Each call scans all the mappings.
+func (cm *CMap) ReverseLookup(unicode string) (code []byte, ok bool) {
+	var best cmapCode
+	consider := func(c cmapCode) {
+		if ok && (c.n > best.n || c.n == best.n && c.value >= best.value) {
+			return
+		}
+		if s, found := cm.Lookup(c.bytes()); found && s == unicode {
+			best, ok = c, true
+		}
+	}
+	for c, s := range cm.mappings {
+		if s == unicode {
+			consider(c)
+		}
+	}
+	for _, r := range cm.ranges {
+		if offset, found := rangeOffset(r.dst, unicode); found && offset <= int64(r.high-r.low) {
+			consider(cmapCode{value: r.low + uint32(offset), n: uint8(r.n)})
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	return best.bytes(), true
+}
+
+// rangeOffset inverts rangeText: it returns the offset into a range
+// starting at dst whose text is s.
+func rangeOffset(dst []byte, s string) (int64, bool) {
+	var offset int64
+	if len(dst) < 4 || len(dst)%2 != 0 {
+		runes := []rune(s)
+		if len(runes) != 1 {
+			return 0, false
+		}
+		offset = int64(runes[0]) - int64(codeValue(dst))
+	} else {
+		units, target := utf16BEUnits(dst), utf16.Encode([]rune(s))
+		last := len(units) - 1
+		if len(target) != len(units) || !slices.Equal(units[:last], target[:last]) {
+			return 0, false
+		}
+		offset = int64(target[last]) - int64(units[last])
+	}
+	if offset < 0 || rangeText(dst, int(offset)) != s {
+		return 0, false
+	}
+	return offset, true
+}
+
+// bytes returns the code as n big-endian bytes.
+func (c cmapCode) bytes() []byte {
+	b := make([]byte, c.n)
+	for i := range b {
+		b[i] = byte(c.value >> (8 * (int(c.n) - 1 - i)))
+	}
+	return b
+}
+
+// Mappings returns an iterator over the codes the CMap maps and the text
+// Lookup gives each, ordered by code length and then code. Codes covered
+// by several entries are yielded once, with the mapping that takes
+// precedence. The yielded code slice is not reused between iterations.
+func (cm *CMap) Mappings() iter.Seq2[[]byte, string] {
+	return func(yield func([]byte, string) bool) {
+		spans := make([]bfRange, 0, len(cm.mappings)+len(cm.ranges))
+		for c := range cm.mappings {
+			spans = append(spans, bfRange{n: int(c.n), low: c.value, high: c.value})
+		}
+		spans = append(spans, cm.ranges...)
+		slices.SortFunc(spans, func(a, b bfRange) int {
+			if a.n != b.n {
+				return cmp.Compare(a.n, b.n)
+			}
+			return cmp.Compare(a.low, b.low)
+		})
+
+		n, next := 0, uint64(0)
+		for _, span := range spans {
+			if span.n != n {
+				n, next = span.n, 0
+			}
+			for value := max(uint64(span.low), next); value <= uint64(span.high); value++ {
+				code := cmapCode{value: uint32(value), n: uint8(n)}.bytes()
+				if s, ok := cm.Lookup(code); ok && !yield(code, s) {
+					return
+				}
+			}
+			next = max(next, uint64(span.high)+1)
+		}
+	}
+}
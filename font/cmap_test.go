@@ -0,0 +1,243 @@
+package font
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// CJK-sized CMaps: Adobe-Japan1 and Adobe-GB1 ToUnicode and encoding CMaps
+// run to tens of thousands of entries.
+const (
+	cjkBfChars     = 30000 // <1000> up, one code each
+	cjkBfRanges    = 3000  // <9000> up, 8 codes each
+	cjkCIDRanges   = 20000 // <0100> up, 2 codes each, 1 apart
+	cjkBfCharBase  = 0x1000
+	cjkBfRangeBase = 0x9000
+	cjkCIDBase     = 0x0100
+)
+
+// writeBlocks writes n entries of a CMap section in blocks of at most 100,
+// as the CMap syntax requires, with entry formatting the i-th one.
+func writeBlocks(b *strings.Builder, section string, n int, entry func(i int) string) {
+	for start := 0; start < n; start += 100 {
+		end := min(start+100, n)
+		fmt.Fprintf(b, "%d begin%s\n", end-start, section)
+		for i := start; i < end; i++ {
+			b.WriteString(entry(i))
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(b, "end%s\n", section)
+	}
+}
+
+// cjkToUnicode returns a ToUnicode CMap with cjkBfChars bfchar and
+// cjkBfRanges bfrange entries over two-byte codes.
+func cjkToUnicode(tb testing.TB) *CMap {
+	tb.Helper()
+	var b strings.Builder
+	b.WriteString("begincmap\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	writeBlocks(&b, "bfchar", cjkBfChars, func(i int) string {
+		return fmt.Sprintf("<%04X> <%04X>", cjkBfCharBase+i, 0x4E00+i%0x5000)
+	})
+	writeBlocks(&b, "bfrange", cjkBfRanges, func(i int) string {
+		low := cjkBfRangeBase + 8*i
+		return fmt.Sprintf("<%04X> <%04X> <%04X>", low, low+7, 0x3400+8*i%0x1900)
+	})
+	b.WriteString("endcmap\n")
+	cm, err := ParseToUnicodeCMap(strings.NewReader(b.String()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cm
+}
+
+// cjkEncoding returns an encoding CMap with cjkCIDRanges cidrange entries.
+func cjkEncoding(tb testing.TB) *CMap {
+	tb.Helper()
+	var b strings.Builder
+	b.WriteString("begincmap\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	writeBlocks(&b, "cidrange", cjkCIDRanges, func(i int) string {
+		low := cjkCIDBase + 3*i
+		return fmt.Sprintf("<%04X> <%04X> %d", low, low+1, 1+2*i)
+	})
+	b.WriteString("endcmap\n")
+	cm, err := ParseToUnicodeCMap(strings.NewReader(b.String()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return cm
+}
+
+// cjkCodes returns n two-byte codes spread over the mapped ones by a
+// fixed stride, alternating bfchar and bfrange codes.
+func cjkCodes(n int) [][]byte {
+	codes := make([][]byte, n)
+	for i := range codes {
+		c := cjkBfCharBase + i*7919%cjkBfChars
+		if i%2 == 1 {
+			c = cjkBfRangeBase + i*7919%(8*cjkBfRanges)
+		}
+		codes[i] = []byte{byte(c >> 8), byte(c)}
+	}
+	return codes
+}
+
+func TestCJKCMap(t *testing.T) {
+	cm := cjkToUnicode(t)
+	if got, ok := cm.Lookup([]byte{0x10, 0x01}); !ok || got != "丁" {
+		t.Errorf("bfchar <1001> = %q, %v; want U+4E01", got, ok)
+	}
+	if got, ok := cm.Lookup([]byte{0x90, 0x09}); !ok || got != "㐉" {
+		t.Errorf("bfrange <9009> = %q, %v; want U+3409", got, ok)
+	}
+	enc := cjkEncoding(t)
+	if cid, ok := enc.CID([]byte{0x01, 0x04}); !ok || cid != 4 {
+		t.Errorf("cidrange <0104> = %d, %v; want 4", cid, ok)
+	}
+	if _, ok := enc.CID([]byte{0x01, 0x02}); ok {
+		t.Error("<0102> between cidranges mapped")
+	}
+}
+
+func TestParseCMapAdjacentHexStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		cmap string
+	}{
+		{"codespace", "1 begincodespacerange\n<0000><FFFF>\nendcodespacerange\n" +
+			"2 beginbfchar\n<0003> <0048>\n<0004> <0069>\nendbfchar\n"},
+		{"every entry", "1 begincodespacerange <0000><FFFF> endcodespacerange\n" +
+			"1 beginbfchar <0003><0048> endbfchar 1 beginbfrange <0004><0004><0069> endbfrange\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, err := ParseToUnicodeCMapStrict(strings.NewReader("begincmap\n" + tt.cmap + "endcmap\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cm.DecodeString([]byte{0, 3, 0, 4}); got != "Hi" {
+				t.Errorf("DecodeString(<00030004>) = %q, want \"Hi\"", got)
+			}
+			if got := cm.Codespaces(); len(got) != 1 || !bytes.Equal(got[0].High, []byte{0xFF, 0xFF}) {
+				t.Errorf("codespaces = %v, want <0000> <FFFF>", got)
+			}
+		})
+	}
+}
+
+func TestParseCMapMalformedCodespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		codespace string
+	}{
+		{"no high bound", "1 begincodespacerange\n<0000>\nendcodespacerange\n"},
+		{"bound not hex", "1 begincodespacerange\n0000 FFFF\nendcodespacerange\n"},
+		{"no end marker", "1 begincodespacerange\n<0000> <FFFF>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmap := "begincmap\n1 beginbfchar\n<0003> <0048>\nendbfchar\n" + tt.codespace
+			cm, err := ParseToUnicodeCMap(strings.NewReader(cmap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, ok := cm.Lookup([]byte{0, 3}); !ok || got != "H" {
+				t.Errorf("<0003> = %q, %v; want \"H\"", got, ok)
+			}
+			if _, err := ParseToUnicodeCMapStrict(strings.NewReader(cmap)); !errors.Is(err, ErrInvalidCMap) {
+				t.Errorf("strict parsing error = %v, want ErrInvalidCMap", err)
+			}
+		})
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	// Shift-JIS-like: one-byte codes up to <80>, two-byte codes from <8140>.
+	mixed := "2 begincodespacerange\n<00> <80>\n<8140> <9FFC>\nendcodespacerange\n" +
+		"3 beginbfchar\n<41> <0041>\n<42> <0042>\n<8140> <3000>\nendbfchar\n" +
+		"1 beginbfrange\n<889F> <88A1> <4E9C>\nendbfrange\n"
+	// A simple font's CMap declaring a two-byte codespace for one-byte codes.
+	twoByte := "1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n" +
+		"3 beginbfchar\n<41> <0041>\n<42> <0042>\n<4142> <00C6>\nendbfchar\n"
+	tests := []struct {
+		name string
+		cmap string
+		data []byte
+		want string
+	}{
+		{"mixed one-byte codes", mixed, []byte("AB"), "AB"},
+		{"mixed two-byte codes", mixed, []byte{0x81, 0x40, 0x88, 0xA0}, "\u3000\u4E9D"},
+		{"mixed interleaved", mixed, []byte{'A', 0x88, 0x9F, 'B', 0x81, 0x40}, "A亜B\u3000"},
+		{"mixed unmapped two-byte code", mixed, []byte{0x81, 0x41, 'B'}, "\uFFFDB"},
+		{"mixed unmapped one-byte code", mixed, []byte{'C', 'A'}, "\uFFFDA"},
+		{"mixed outside every codespace", mixed, []byte{0xFF, 'A'}, "\uFFFDA"},
+		{"mixed truncated two-byte code", mixed, []byte{'A', 0x81}, "A\uFFFD"},
+		{"two-byte codespace, two-byte code", twoByte, []byte("AB"), "Æ"},
+		{"two-byte codespace, one-byte codes", twoByte, []byte("BA"), "BA"},
+		{"tab", "1 begincodespacerange <00><FF> endcodespacerange\n1 beginbfrange <21><21><0009> endbfrange\n", []byte("!"), "\t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, err := ParseToUnicodeCMap(strings.NewReader("begincmap\n" + tt.cmap + "endcmap\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cm.DecodeString(tt.data); got != tt.want {
+				t.Errorf("DecodeString() = %q, want %q", got, tt.want)
+			}
+			f := NewFont("F1")
+			f.ToUnicode = cm
+			if got, _, _ := f.DecodeShowString(tt.data); got != tt.want {
+				t.Errorf("DecodeShowString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkCMapLookup(b *testing.B) {
+	cm := cjkToUnicode(b)
+	enc := cjkEncoding(b)
+	codes := cjkCodes(1024)
+	b.Run("ToUnicode", func(b *testing.B) {
+		for b.Loop() {
+			for _, code := range codes {
+				cm.Lookup(code)
+			}
+		}
+	})
+	b.Run("CID", func(b *testing.B) {
+		for b.Loop() {
+			for _, code := range codes {
+				enc.CID(code)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeString(b *testing.B) {
+	cm := cjkToUnicode(b)
+	var data []byte
+	for _, code := range cjkCodes(2048) {
+		data = append(data, code...)
+	}
+	b.Run("CMap", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for b.Loop() {
+			cm.DecodeString(data)
+		}
+	})
+	b.Run("Font", func(b *testing.B) {
+		f := NewFont("C0")
+		f.Encoding = EncodingIdentity
+		f.IsMultiByte = true
+		f.ToUnicode = cm
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for b.Loop() {
+			f.DecodeShowString(data)
+		}
+	})
+}
@@ -0,0 +1,349 @@
+package font
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidCMap is matched (via errors.Is) by *CMapValidationError.
+var ErrInvalidCMap = errors.New("invalid CMap")
+
+// CodespaceRange is a begincodespacerange entry. Codes in the range have
+// len(Low) bytes, and each byte lies between the corresponding bytes of
+// Low and High.
+type CodespaceRange struct {
+	Low, High []byte
+}
+
+// Contains reports whether code lies in the codespace range.
+func (r CodespaceRange) Contains(code []byte) bool {
+	if len(code) != len(r.Low) {
+		return false
+	}
+	for i, b := range code {
+		if b < r.Low[i] || b > r.High[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Violation describes one consistency problem found in a CMap.
+type Violation struct {
+	// Section is the CMap section: "codespacerange", "bfchar", "bfrange",
+	// "cidchar", or "cidrange".
+	Section string
+
+	// Entry is the offending entry as written in the CMap.
+	Entry string
+
+	// Message explains the problem.
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Entry == "" {
+		return fmt.Sprintf("%s: %s", v.Section, v.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", v.Section, v.Entry, v.Message)
+}
+
+// CMapValidationError reports the violations found in strict parsing mode.
+type CMapValidationError struct {
+	Violations []Violation
+}
+
+func (e *CMapValidationError) Error() string {
+	if len(e.Violations) == 1 {
+		return fmt.Sprintf("invalid CMap: %v", e.Violations[0])
+	}
+	return fmt.Sprintf("invalid CMap: %v (and %d more)", e.Violations[0], len(e.Violations)-1)
+}
+
+// Is reports whether target is ErrInvalidCMap.
+func (e *CMapValidationError) Is(target error) bool {
+	return target == ErrInvalidCMap
+}
+
+// cmapEntry records a parsed bfchar/bfrange/cidchar/cidrange source for
+// validation.
+type cmapEntry struct {
+	section   string
+	low, high []byte
+	dst       string // destination hex digits, or the decimal CID
+}
+
+func (e cmapEntry) String() string {
+	switch e.section {
+	case "bfchar":
+		return fmt.Sprintf("<%X> <%s>", e.low, e.dst)
+	case "cidchar":
+		return fmt.Sprintf("<%X> %s", e.low, e.dst)
+	case "cidrange":
+		return fmt.Sprintf("<%X> <%X> %s", e.low, e.high, e.dst)
+	}
+	return fmt.Sprintf("<%X> <%X> <%s>", e.low, e.high, e.dst)
+}
+
+// isCID reports whether the entry maps codes to CIDs rather than Unicode.
+func (e cmapEntry) isCID() bool {
+	return e.section == "cidchar" || e.section == "cidrange"
+}
+
+// maxDestinationBytes is the largest destination string the spec allows.
+const maxDestinationBytes = 512
+
+// ParseToUnicodeCMapStrict parses a ToUnicode CMap like ParseToUnicodeCMap
+// and then validates it. If any violations are found, it returns the CMap
+// together with a *CMapValidationError, so callers may still choose to use
+// the (leniently parsed) result.
+func ParseToUnicodeCMapStrict(r io.Reader) (*CMap, error) {
+	cmap, err := ParseToUnicodeCMap(r)
+	if err != nil {
+		return nil, err
+	}
+	if violations := cmap.Validate(); len(violations) > 0 {
+		return cmap, &CMapValidationError{Violations: violations}
+	}
+	return cmap, nil
+}
+
+// Codespaces returns the declared codespace ranges.
+func (cm *CMap) Codespaces() []CodespaceRange {
+	ranges := make([]CodespaceRange, len(cm.codespaces))
+	copy(ranges, cm.codespaces)
+	return ranges
+}
+
+// Validate checks the CMap for consistency and returns the violations found:
+//   - entries skipped while parsing because they were malformed
+//   - missing or malformed codespace ranges
+//   - source codes outside every declared codespace, or of a length no
+//     codespace declares
+//   - bfrange entries whose start and end differ in length or in any byte
+//     but the last (ranges may not cross byte boundaries), or are reversed
+//   - reversed cidrange entries
+//   - destinations that are not UTF-16BE (odd length, unpaired surrogates),
+//     are empty or longer than 512 bytes, or whose last byte would
+//     overflow within a range
+//
+// A nil result means no problems were found.
+func (cm *CMap) Validate() []Violation {
+	violations := append([]Violation(nil), cm.issues...)
+
+	if len(cm.codespaces) == 0 {
+		violations = append(violations, Violation{Section: "codespacerange", Message: "no codespace ranges declared"})
+	}
+
+	for _, e := range cm.entries {
+		report := func(msg string) {
+			violations = append(violations, Violation{Section: e.section, Entry: e.String(), Message: msg})
+		}
+
+		if len(cm.codespaces) > 0 {
+			if !cm.inCodespace(e.low) || !cm.inCodespace(e.high) {
+				report("source code outside the declared codespace ranges")
+			}
+		}
+
+		if e.isCID() {
+			if bytes.Compare(e.low, e.high) > 0 {
+				report("range start is greater than range end")
+			}
+			continue
+		}
+
+		if e.section == "bfrange" {
+			switch {
+			case len(e.low) != len(e.high):
+				report("range start and end have different lengths")
+			case bytes.Compare(e.low, e.high) > 0:
+				report("range start is greater than range end")
+			case !bytes.Equal(e.low[:len(e.low)-1], e.high[:len(e.high)-1]):
+				report("range crosses a byte boundary (only the last byte may vary)")
+			}
+		}
+
+		dst, err := hex.DecodeString(e.dst)
+		switch {
+		case err != nil:
+			report("destination is not valid hex")
+			continue
+		case len(dst) == 0:
+			report("empty destination")
+			continue
+		case len(dst) > maxDestinationBytes:
+			report(fmt.Sprintf("destination longer than %d bytes", maxDestinationBytes))
+		case len(dst)%2 != 0:
+			report("destination is not UTF-16BE (odd number of bytes)")
+			continue
+		}
+		if msg := checkUTF16(dst); msg != "" {
+			report(msg)
+		}
+		if e.section == "bfrange" && len(e.low) == len(e.high) && len(e.low) > 0 {
+			span := int(e.high[len(e.high)-1]) - int(e.low[len(e.low)-1])
+			if int(dst[len(dst)-1])+span > 0xFF {
+				report("destination's last byte overflows within the range")
+			}
+		}
+	}
+	return violations
+}
+
+// inCodespace reports whether code lies in any declared codespace range.
+func (cm *CMap) inCodespace(code []byte) bool {
+	for _, r := range cm.codespaces {
+		if r.Contains(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUTF16 returns a description of the first UTF-16BE encoding error
+// in data, or "".
+func checkUTF16(data []byte) string {
+	for i := 0; i+1 < len(data); i += 2 {
+		u := uint16(data[i])<<8 | uint16(data[i+1])
+		switch {
+		case u >= 0xD800 && u < 0xDC00:
+			if i+3 >= len(data) {
+				return "destination ends with an unpaired high surrogate"
+			}
+			next := uint16(data[i+2])<<8 | uint16(data[i+3])
+			if next < 0xDC00 || next > 0xDFFF {
+				return "destination has an unpaired high surrogate"
+			}
+			i += 2
+		case u >= 0xDC00 && u <= 0xDFFF:
+			return "destination has an unpaired low surrogate"
+		}
+	}
+	return ""
+}
+
+// addIssue records a problem found while parsing.
+func (cm *CMap) addIssue(section, entry, message string) {
+	cm.issues = append(cm.issues, Violation{Section: section, Entry: entry, Message: message})
+}
+
+// parseCodespaceRange parses a begincodespacerange/endcodespacerange section.
+// Format: <low> <high>
+// Example: <0000> <FFFF>  declares 2-byte codes
+//
+// Malformed entries and a missing end marker are recorded as issues,
+// failing strict parsing only: codespace ranges are not needed to decode
+// a ToUnicode CMap.
+func (cm *CMap) parseCodespaceRange(scanner *bufio.Scanner) {
+	for scanner.Scan() {
+		token := strings.TrimSpace(scanner.Text())
+		if token == "endcodespacerange" {
+			return
+		}
+		if !isHexString(token) {
+			cm.addIssue("codespacerange", token, "bound is not a hex string")
+			continue
+		}
+		if !scanner.Scan() {
+			break
+		}
+		highToken := strings.TrimSpace(scanner.Text())
+		entry := token + " " + highToken
+		if highToken == "endcodespacerange" {
+			cm.addIssue("codespacerange", token, "range has no high bound")
+			return
+		}
+
+		low, err1 := hex.DecodeString(stripHexBrackets(token))
+		high, err2 := hex.DecodeString(stripHexBrackets(highToken))
+		switch {
+		case !isHexString(highToken) || err1 != nil || err2 != nil:
+			cm.addIssue("codespacerange", entry, "bound is not valid hex")
+			continue
+		case len(low) != len(high):
+			cm.addIssue("codespacerange", entry, "bounds have different lengths")
+			continue
+		case len(low) < 1 || len(low) > 4:
+			cm.addIssue("codespacerange", entry, "codes must be 1 to 4 bytes long")
+			continue
+		}
+		for i := range low {
+			if low[i] > high[i] {
+				cm.addIssue("codespacerange", entry, "low bound exceeds high bound")
+				break
+			}
+		}
+		cm.codespaces = append(cm.codespaces, CodespaceRange{Low: low, High: high})
+	}
+	cm.addIssue("codespacerange", "", "endcodespacerange not found")
+}
+
+// minDegenerateCodes is the number of codes a CMap must map before
+// mapping them all to the same text marks it as degenerate.
+const minDegenerateCodes = 8
+
+// Quality returns the fraction of the codes the CMap maps whose text is
+// usable, that is, not empty and not made only of NUL, U+FFFD or control
+// characters other than whitespace. An empty CMap has quality 0.
+func (cm *CMap) Quality() float64 {
+	return cm.quality
+}
+
+// IsDegenerate reports whether the CMap looks broken, as some producers
+// write ToUnicode CMaps that map every code to U+0000 or to one .notdef
+// character: none of its codes have usable text (see Quality), or at
+// least minDegenerateCodes codes all map to the same text. Decoding
+// ignores degenerate ToUnicode CMaps and falls back to the font's
+// encoding and embedded program; codes a usable CMap maps to unusable
+// text fall back one at a time.
+func (cm *CMap) IsDegenerate() bool {
+	return cm.degenerate
+}
+
+// assess computes the CMap's quality and whether it is degenerate.
+func (cm *CMap) assess() {
+	var total, usable float64
+	var first string
+	same := len(cm.mappings) > 0
+	count := func(s string, codes float64) {
+		if total == 0 {
+			first = s
+		}
+		same = same && s == first
+		total += codes
+		if usableText(s) {
+			usable += codes
+		}
+	}
+	for _, s := range cm.mappings {
+		count(s, 1)
+	}
+	for _, r := range cm.ranges {
+		count(rangeText(r.dst, 0), float64(r.high-r.low)+1)
+		same = same && r.low == r.high
+	}
+	cm.quality = 0
+	if total > 0 {
+		cm.quality = usable / total
+	}
+	cm.degenerate = total > 0 && (usable == 0 || same && total >= minDegenerateCodes)
+}
+
+// usableText reports whether s is text a code may decode to: not empty
+// and not made only of NUL, U+FFFD or other control characters. Tabs and
+// line breaks are text.
+func usableText(s string) bool {
+	for _, r := range s {
+		if r != unicode.ReplacementChar && (!unicode.IsControl(r) || unicode.IsSpace(r)) {
+			return true
+		}
+	}
+	return false
+}
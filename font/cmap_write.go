@@ -0,0 +1,132 @@
+package font
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"unicode/utf16"
+)
+
+// maxSectionEntries is the most entries a bfchar or bfrange section may
+// hold.
+const maxSectionEntries = 100
+
+// WriteTo writes the CMap as a ToUnicode CMap stream, with its
+// codespacerange, bfchar and bfrange sections, and implements io.WriterTo.
+// A CMap without declared codespace ranges gets one full range per code
+// length it maps. Ranges are written before single mappings, so that
+// bfchar entries keep precedence for readers where later entries win, and
+// are split where the spec requires (ranges may only vary in the last byte
+// of the code and of the destination).
+func (cm *CMap) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	name := cm.name
+	if name == "" {
+		name = "Adobe-Identity-UCS"
+	}
+	buf.WriteString("/CIDInit /ProcSet findresource begin\n")
+	buf.WriteString("12 dict begin\n")
+	buf.WriteString("begincmap\n")
+	buf.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	fmt.Fprintf(&buf, "/CMapName /%s def\n", name)
+	buf.WriteString("/CMapType 2 def\n")
+
+	codespaces := cm.codespaces
+	if len(codespaces) == 0 {
+		codespaces = cm.impliedCodespaces()
+	}
+	writeSections(&buf, "codespacerange", len(codespaces), func(i int) string {
+		return fmt.Sprintf("<%X> <%X>", codespaces[i].Low, codespaces[i].High)
+	})
+
+	var ranges []string
+	for _, r := range cm.rangesInOrder() {
+		ranges = append(ranges, splitRange(r)...)
+	}
+	writeSections(&buf, "bfrange", len(ranges), func(i int) string { return ranges[i] })
+
+	codes := slices.SortedFunc(maps.Keys(cm.mappings), func(a, b cmapCode) int {
+		if a.n != b.n {
+			return cmp.Compare(a.n, b.n)
+		}
+		return cmp.Compare(a.value, b.value)
+	})
+	writeSections(&buf, "bfchar", len(codes), func(i int) string {
+		code := codes[i]
+		return fmt.Sprintf("<%0*X> <%s>", 2*int(code.n), code.value, utf16BEHex(utf16.Encode([]rune(cm.mappings[code]))))
+	})
+
+	buf.WriteString("endcmap\n")
+	buf.WriteString("CMapName currentdict /CMap defineresource pop\n")
+	buf.WriteString("end\n")
+	buf.WriteString("end\n")
+	return buf.WriteTo(w)
+}
+
+// writeSections writes n entries as begin<section>/end<section> blocks of
+// at most maxSectionEntries entries each.
+func writeSections(buf *bytes.Buffer, section string, n int, entry func(i int) string) {
+	for start := 0; start < n; start += maxSectionEntries {
+		end := min(start+maxSectionEntries, n)
+		fmt.Fprintf(buf, "%d begin%s\n", end-start, section)
+		for i := start; i < end; i++ {
+			buf.WriteString(entry(i))
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(buf, "end%s\n", section)
+	}
+}
+
+// impliedCodespaces returns a full codespace range for each code length
+// the CMap maps.
+func (cm *CMap) impliedCodespaces() []CodespaceRange {
+	var lengths []int
+	for code := range cm.mappings {
+		lengths = append(lengths, int(code.n))
+	}
+	for _, r := range cm.ranges {
+		lengths = append(lengths, r.n)
+	}
+	slices.Sort(lengths)
+	var ranges []CodespaceRange
+	for _, n := range slices.Compact(lengths) {
+		ranges = append(ranges, CodespaceRange{Low: make([]byte, n), High: bytes.Repeat([]byte{0xFF}, n)})
+	}
+	return ranges
+}
+
+// rangesInOrder returns the bfrange intervals in declaration order.
+func (cm *CMap) rangesInOrder() []bfRange {
+	ranges := slices.Clone(cm.ranges)
+	slices.SortFunc(ranges, func(a, b bfRange) int { return cmp.Compare(a.order, b.order) })
+	return ranges
+}
+
+// splitRange formats r as bfrange entries, split wherever the last byte
+// of the source code or of the destination would wrap.
+func splitRange(r bfRange) []string {
+	var entries []string
+	for low := uint64(r.low); low <= uint64(r.high); {
+		dst := utf16.Encode([]rune(rangeText(r.dst, int(low-uint64(r.low)))))
+		last := uint64(0xFF - low&0xFF)
+		if len(dst) > 0 {
+			last = min(last, uint64(0xFF-dst[len(dst)-1]&0xFF))
+		}
+		high := min(low+last, uint64(r.high))
+		entries = append(entries, fmt.Sprintf("<%0*X> <%0*X> <%s>", 2*r.n, low, 2*r.n, high, utf16BEHex(dst)))
+		low = high + 1
+	}
+	return entries
+}
+
+// utf16BEHex returns UTF-16 code units as UTF-16BE hex digits.
+func utf16BEHex(units []uint16) string {
+	var b bytes.Buffer
+	for _, u := range units {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	return b.String()
+}
@@ -0,0 +1,271 @@
+package font
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GlyphInfo describes one character code of a decoded show-string.
+type GlyphInfo struct {
+	// Code is the character code (big-endian for multi-byte codes).
+	Code uint32
+
+	// CID is the CID the code selects in a Type0 font, and equals Code
+	// in other fonts. HasCID is false if the encoding CMap cannot tell.
+	CID    uint32
+	HasCID bool
+
+	// Offset and Length locate the code's bytes in the show-string.
+	Offset, Length int
+
+	// Text is the Unicode text the code decodes to (U+FFFD if unmapped).
+	Text string
+
+	// Width is the glyph width in thousandths of an em; HasWidth is false
+	// if the font carries no width for the code.
+	Width    float64
+	HasWidth bool
+
+	// Source is the decoding source that produced Text.
+	Source DecodeSource
+}
+
+// DiagnosticKind classifies decoding problems.
+type DiagnosticKind int
+
+const (
+	// DiagnosticUnmapped means a code has no Unicode mapping and was
+	// replaced with U+FFFD.
+	DiagnosticUnmapped DiagnosticKind = iota
+	// DiagnosticRawFallback means the font has neither a ToUnicode CMap
+	// nor a usable encoding, so bytes were passed through unchanged and
+	// the text is probably wrong.
+	DiagnosticRawFallback
+)
+
+// String returns the kind name.
+func (k DiagnosticKind) String() string {
+	switch k {
+	case DiagnosticUnmapped:
+		return "unmapped"
+	case DiagnosticRawFallback:
+		return "raw-fallback"
+	default:
+		return fmt.Sprintf("DiagnosticKind(%d)", int(k))
+	}
+}
+
+// Diagnostic is a problem found while decoding a show-string.
+type Diagnostic struct {
+	Kind DiagnosticKind
+
+	// Offset is the byte offset in the show-string, and Code the character
+	// code there.
+	Offset int
+	Code   uint32
+
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("offset %d: %s: %s", d.Offset, d.Kind, d.Message)
+}
+
+// DecodeShowString decodes the bytes of a show-string (the operand of Tj,
+// TJ, ' or ") with this font. It returns the text, one GlyphInfo per
+// character code, and diagnostics for codes that could not be mapped.
+//
+// Each code is tried against the sources of the font's FallbackChain in
+// order (see DefaultFallbackChain), and GlyphInfo.Source records the one
+// that decoded it; codes no source decodes become U+FFFD. A degenerate
+// ToUnicode CMap (see CMap.IsDegenerate) is skipped, and codes it maps to
+// NUL, U+FFFD or control characters other than whitespace go on to the
+// next source.
+//
+// Type0 fonts with an encoding CMap are split into codes by its codespace
+// ranges. Other fonts with a ToUnicode CMap match two-byte codes before
+// one-byte codes, and the rest use the font's code length.
+func (f *Font) DecodeShowString(raw []byte) (text string, glyphs []GlyphInfo, diags []Diagnostic) {
+	var b strings.Builder
+	b.Grow(len(raw))
+
+	add := func(offset, length int, s string, source DecodeSource) {
+		code := codeValue(raw[offset : offset+length])
+		g := GlyphInfo{Code: code, Offset: offset, Length: length, Text: s, Source: source}
+		g.CID, g.HasCID = f.CID(code, length)
+		g.Width, g.HasWidth = f.CodeWidth(code, length)
+		glyphs = append(glyphs, g)
+		b.WriteString(s)
+		if s == "\uFFFD" {
+			diags = append(diags, Diagnostic{
+				Kind:    DiagnosticUnmapped,
+				Offset:  offset,
+				Code:    code,
+				Message: fmt.Sprintf("no Unicode mapping for code 0x%0*X in %s", 2*length, code, f.Name),
+			})
+		}
+	}
+
+	chain := f.FallbackChain
+	if chain == nil {
+		chain = DefaultFallbackChain
+	}
+	lossy := false
+	for i := 0; i < len(raw); {
+		code, n := f.nextCode(raw[i:], chain)
+		s, source := f.decodeCode(raw[i:i+n], code, n, chain)
+		if source == SourceRaw {
+			lossy = lossy || f.rawFallbackIsLossy(raw[i:i+n])
+		}
+		add(i, n, s, source)
+		i += n
+	}
+	if lossy {
+		diags = append(diags, Diagnostic{
+			Kind:    DiagnosticRawFallback,
+			Message: fmt.Sprintf("%s has no ToUnicode CMap or known encoding; bytes passed through", f.Name),
+		})
+	}
+	return b.String(), glyphs, diags
+}
+
+// differencesText decodes a single-byte code through the glyph name the
+// /Differences array assigns it. ok is false for codes it does not list
+// and for names without a known Unicode value (e.g. "g12").
+func (f *Font) differencesText(code byte) (string, bool) {
+	if f.Differences == nil || f.CodeLength() != 1 {
+		return "", false
+	}
+	return f.Differences.Text(code)
+}
+
+// glyphID returns the glyph index a code of n bytes selects in a Type0
+// font: its CID, mapped through /CIDToGIDMap.
+func (f *Font) glyphID(code uint32, n int) (uint32, bool) {
+	cid, ok := f.CID(code, n)
+	if !ok {
+		return 0, false
+	}
+	if f.CIDFont != nil {
+		return f.CIDFont.GID(cid), true
+	}
+	return cid, true
+}
+
+// cmapText decodes a code through the cmap table of an embedded TrueType
+// or OpenType program: the glyph is selected by CID (and /CIDToGIDMap)
+// in Type0 fonts, and through the symbol or Macintosh subtable in simple
+// fonts, then mapped back to Unicode.
+func (f *Font) cmapText(code uint32, n int) (string, bool) {
+	if f.EmbeddedCmap == nil {
+		return "", false
+	}
+	var gid uint32
+	if f.IsMultiByte || f.EncodingCMap != nil {
+		var ok bool
+		if gid, ok = f.glyphID(code, n); !ok {
+			return "", false
+		}
+	} else {
+		g, ok := f.EmbeddedCmap.CodeGlyph(byte(code))
+		if !ok || n != 1 {
+			return "", false
+		}
+		gid = uint32(g)
+	}
+	if gid == 0 || gid > 0xFFFF {
+		return "", false
+	}
+	r, ok := f.EmbeddedCmap.GlyphUnicode(uint16(gid))
+	if !ok {
+		return "", false
+	}
+	return string(r), true
+}
+
+// glyphNameText decodes a code through the glyph names of an embedded
+// Type 1 or CFF program: the built-in encoding names the glyph of a
+// simple font's code, and the charset that of a Type0 font's glyph index.
+func (f *Font) glyphNameText(code uint32, n int) (string, bool) {
+	if f.IsMultiByte || f.EncodingCMap != nil {
+		gid, ok := f.glyphID(code, n)
+		if !ok || gid == 0 || int(gid) >= len(f.Charset) {
+			return "", false
+		}
+		return GlyphNameToUnicode(f.Charset[gid])
+	}
+	if f.BuiltinEncoding == nil || n != 1 {
+		return "", false
+	}
+	return f.BuiltinEncoding.Text(byte(code))
+}
+
+// GlyphName returns the name of the glyph a code of n bytes selects, as
+// given by the /Differences array, the built-in encoding of an embedded
+// Type 1 or CFF program, or the charset of a CFF program in a Type0
+// font. ok is false if none of them names the glyph.
+func (f *Font) GlyphName(code uint32, n int) (name string, ok bool) {
+	if f.IsMultiByte || f.EncodingCMap != nil {
+		gid, ok := f.glyphID(code, n)
+		if !ok || gid == 0 || int(gid) >= len(f.Charset) {
+			return "", false
+		}
+		return f.Charset[gid], true
+	}
+	if n != 1 {
+		return "", false
+	}
+	for _, enc := range []*GlyphEncoding{f.Differences, f.BuiltinEncoding} {
+		if enc != nil && enc[code] != "" && enc[code] != ".notdef" {
+			return enc[code], true
+		}
+	}
+	return "", false
+}
+
+// rawFallbackIsLossy reports whether passing raw through unchanged is
+// likely wrong: always for multi-byte fonts, and for single-byte fonts
+// once bytes leave the ASCII range.
+func (f *Font) rawFallbackIsLossy(raw []byte) bool {
+	if f.IsMultiByte || f.Encoding == EncodingIdentity {
+		return len(raw) > 0
+	}
+	for _, c := range raw {
+		if c >= 0x80 {
+			return true
+		}
+	}
+	return false
+}
+
+// codeValue returns the big-endian value of a character code's bytes.
+func codeValue(b []byte) uint32 {
+	var code uint32
+	for _, c := range b {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+// IsPrivateUse reports whether r is in the Private Use Area of the BMP or
+// in the supplementary private use planes 15 and 16, where fonts put
+// glyphs that have no standard Unicode value.
+func IsPrivateUse(r rune) bool {
+	return (r >= 0xE000 && r <= 0xF8FF) || (r >= 0xF0000 && r <= 0x10FFFD)
+}
+
+// hasUsableToUnicode reports whether the font has a ToUnicode CMap that
+// is not degenerate.
+func (f *Font) hasUsableToUnicode() bool {
+	return f.ToUnicode != nil && !f.ToUnicode.IsDegenerate()
+}
+
+// toUnicodeText returns the text the ToUnicode CMap maps code to. ok is
+// false if the CMap is degenerate or gives no usable text for the code.
+func (f *Font) toUnicodeText(code []byte) (string, bool) {
+	if !f.hasUsableToUnicode() {
+		return "", false
+	}
+	s, ok := f.ToUnicode.Lookup(code)
+	return s, ok && usableText(s)
+}
@@ -0,0 +1,165 @@
+package font
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIsPrivateUse(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want bool
+	}{
+		{0xDFFF, false},
+		{0xE000, true},
+		{0xF8FF, true},
+		{0xF900, false},
+		{0xEFFFF, false},
+		{0xF0000, true},
+		{0x10FFFD, true},
+		{0x10FFFE, false},
+		{'A', false},
+	}
+	for _, tt := range tests {
+		if got := IsPrivateUse(tt.r); got != tt.want {
+			t.Errorf("IsPrivateUse(%U) = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+}
+
+// toUnicodeCMap parses a ToUnicode CMap with the given codespace range and
+// bfchar lines.
+func toUnicodeCMap(t *testing.T, codespace string, bfchars ...string) *CMap {
+	t.Helper()
+	var b strings.Builder
+	fmt.Fprintf(&b, "begincmap\n1 begincodespacerange\n%s\nendcodespacerange\n", codespace)
+	fmt.Fprintf(&b, "%d beginbfchar\n%s\nendbfchar\nendcmap\n", len(bfchars), strings.Join(bfchars, "\n"))
+	cmap, err := ParseToUnicodeCMap(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmap
+}
+
+func TestDecodeShowString(t *testing.T) {
+	type diag struct {
+		kind   DiagnosticKind
+		offset int
+	}
+	tests := []struct {
+		name   string
+		font   func(t *testing.T) *Font
+		raw    string
+		text   string
+		glyphs []GlyphInfo
+		diags  []diag
+	}{
+		{
+			name: "ToUnicode",
+			font: func(t *testing.T) *Font {
+				f := NewFont("F1")
+				f.Encoding = EncodingWinAnsi
+				f.ToUnicode = toUnicodeCMap(t, "<00> <FF>", "<41> <0058>", "<42> <0059>")
+				f.SetWidths(0x41, []float64{600, 700})
+				return f
+			},
+			raw:  "AB",
+			text: "XY",
+			glyphs: []GlyphInfo{
+				{Code: 0x41, CID: 0x41, HasCID: true, Offset: 0, Length: 1, Text: "X", Width: 600, HasWidth: true, Source: SourceToUnicode},
+				{Code: 0x42, CID: 0x42, HasCID: true, Offset: 1, Length: 1, Text: "Y", Width: 700, HasWidth: true, Source: SourceToUnicode},
+			},
+		},
+		{
+			name: "ToUnicode of a Type0 font with an unmapped code",
+			font: func(t *testing.T) *Font {
+				f := NewFont("F2")
+				f.IsMultiByte = true
+				f.EncodingCMap, _ = PredefinedCMap("Identity-H")
+				f.ToUnicode = toUnicodeCMap(t, "<0000> <FFFF>", "<0003> <0041>")
+				return f
+			},
+			raw:  "\x00\x03\x00\x05",
+			text: "A�",
+			glyphs: []GlyphInfo{
+				{Code: 3, CID: 3, HasCID: true, Offset: 0, Length: 2, Text: "A", Width: DefaultCIDWidth, HasWidth: true, Source: SourceToUnicode},
+				{Code: 5, CID: 5, HasCID: true, Offset: 2, Length: 2, Text: "�", Width: DefaultCIDWidth, HasWidth: true, Source: SourceNone},
+			},
+			diags: []diag{{DiagnosticUnmapped, 2}},
+		},
+		{
+			name: "encoding",
+			font: func(t *testing.T) *Font {
+				f := NewFont("F3")
+				f.Encoding = EncodingWinAnsi
+				return f
+			},
+			raw:  "\x80a",
+			text: "€a",
+			glyphs: []GlyphInfo{
+				{Code: 0x80, CID: 0x80, HasCID: true, Offset: 0, Length: 1, Text: "€", Source: SourceEncoding},
+				{Code: 0x61, CID: 0x61, HasCID: true, Offset: 1, Length: 1, Text: "a", Source: SourceEncoding},
+			},
+		},
+		{
+			name: "degenerate ToUnicode",
+			font: func(t *testing.T) *Font {
+				f := NewFont("F4")
+				f.Encoding = EncodingWinAnsi
+				var bfchars []string
+				for c := 0x41; c < 0x41+minDegenerateCodes; c++ {
+					bfchars = append(bfchars, fmt.Sprintf("<%02X> <0058>", c)) // all X
+				}
+				f.ToUnicode = toUnicodeCMap(t, "<00> <FF>", bfchars...)
+				return f
+			},
+			raw:  "AB",
+			text: "AB",
+			glyphs: []GlyphInfo{
+				{Code: 0x41, CID: 0x41, HasCID: true, Offset: 0, Length: 1, Text: "A", Source: SourceEncoding},
+				{Code: 0x42, CID: 0x42, HasCID: true, Offset: 1, Length: 1, Text: "B", Source: SourceEncoding},
+			},
+		},
+		{
+			name: "raw ASCII",
+			font: func(t *testing.T) *Font { return NewFont("F5") },
+			raw:  "ab",
+			text: "ab",
+			glyphs: []GlyphInfo{
+				{Code: 0x61, CID: 0x61, HasCID: true, Offset: 0, Length: 1, Text: "a", Source: SourceRaw},
+				{Code: 0x62, CID: 0x62, HasCID: true, Offset: 1, Length: 1, Text: "b", Source: SourceRaw},
+			},
+		},
+		{
+			name: "raw high bytes",
+			font: func(t *testing.T) *Font { return NewFont("F6") },
+			raw:  "a\xE9",
+			text: "a\xE9",
+			glyphs: []GlyphInfo{
+				{Code: 0x61, CID: 0x61, HasCID: true, Offset: 0, Length: 1, Text: "a", Source: SourceRaw},
+				{Code: 0xE9, CID: 0xE9, HasCID: true, Offset: 1, Length: 1, Text: "\xE9", Source: SourceRaw},
+			},
+			diags: []diag{{DiagnosticRawFallback, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, glyphs, diags := tt.font(t).DecodeShowString([]byte(tt.raw))
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			if !slices.Equal(glyphs, tt.glyphs) {
+				t.Errorf("glyphs = %+v\nwant %+v", glyphs, tt.glyphs)
+			}
+			var got []diag
+			for _, d := range diags {
+				got = append(got, diag{d.Kind, d.Offset})
+			}
+			if !slices.Equal(got, tt.diags) {
+				t.Errorf("diagnostics = %v, want kinds and offsets %v", diags, tt.diags)
+			}
+		})
+	}
+}
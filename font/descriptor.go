@@ -0,0 +1,123 @@
+package font
+
+import (
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// FontFlags are the /Flags of a font descriptor (ISO 32000-1, Table 123).
+type FontFlags uint32
+
+// Font descriptor flags.
+const (
+	FlagFixedPitch  FontFlags = 1 << 0
+	FlagSerif       FontFlags = 1 << 1
+	FlagSymbolic    FontFlags = 1 << 2
+	FlagScript      FontFlags = 1 << 3
+	FlagNonsymbolic FontFlags = 1 << 5
+	FlagItalic      FontFlags = 1 << 6
+	FlagAllCap      FontFlags = 1 << 16
+	FlagSmallCap    FontFlags = 1 << 17
+	FlagForceBold   FontFlags = 1 << 18
+)
+
+// FontDescriptor holds the style metrics of a font's /FontDescriptor.
+type FontDescriptor struct {
+	Flags FontFlags
+
+	// HasFlags is false if the descriptor has no /Flags entry.
+	HasFlags bool
+
+	// ItalicAngle is the angle of the font's dominant vertical strokes,
+	// in degrees counterclockwise from vertical: negative for fonts that
+	// slope to the right.
+	ItalicAngle float64
+
+	// StemV is the thickness of the dominant vertical stems, and
+	// FontWeight the weight (100 to 900, 400 normal, 700 bold) if the
+	// descriptor gives it, else zero.
+	StemV      float64
+	FontWeight float64
+
+	// FontBBox is the union of the glyph bounding boxes, as llx, lly,
+	// urx, ury in glyph space.
+	FontBBox [4]float64
+}
+
+// ParseFontDescriptor reads a /FontDescriptor dictionary given in the
+// operand types of the parser.
+func ParseFontDescriptor(d parser.Dict) *FontDescriptor {
+	fd := &FontDescriptor{}
+	var flags float64
+	flags, fd.HasFlags = d.Number("Flags")
+	fd.Flags = FontFlags(uint32(flags))
+	fd.ItalicAngle, _ = d.Number("ItalicAngle")
+	fd.StemV, _ = d.Number("StemV")
+	fd.FontWeight, _ = d.Number("FontWeight")
+	if bbox, ok := d.Array("FontBBox"); ok && len(bbox) == 4 {
+		for i, v := range bbox {
+			fd.FontBBox[i], _ = toFloat(v)
+		}
+	}
+	return fd
+}
+
+// Has reports whether all of flags are set.
+func (fd *FontDescriptor) Has(flags FontFlags) bool {
+	return fd.Flags&flags == flags
+}
+
+// Thresholds for IsBold: the usual FontWeight of bold faces, and a StemV
+// above the regular weights of the common text faces (Helvetica 88,
+// Times-Roman 84) and below their bold ones (118 and 139).
+const (
+	boldFontWeight = 600
+	boldStemV      = 110
+)
+
+// IsBold reports whether the font is bold: by the descriptor's ForceBold
+// flag, FontWeight or StemV, or else by the weight in its name.
+func (f *Font) IsBold() bool {
+	if fd := f.Descriptor; fd != nil {
+		switch {
+		case fd.Has(FlagForceBold), fd.FontWeight >= boldFontWeight:
+			return true
+		case fd.FontWeight == 0 && fd.StemV >= boldStemV:
+			return true
+		}
+	}
+	return f.FontName().IsBold()
+}
+
+// IsItalic reports whether the font is italic or oblique: by the
+// descriptor's Italic flag or ItalicAngle, or else by the style in its
+// name.
+func (f *Font) IsItalic() bool {
+	if fd := f.Descriptor; fd != nil && (fd.Has(FlagItalic) || fd.ItalicAngle != 0) {
+		return true
+	}
+	return f.FontName().Italic
+}
+
+// IsSymbolic reports whether the font's glyphs are outside the Adobe
+// standard Latin character set, so that its codes must be read through
+// its built-in encoding rather than a standard one. Fonts without
+// descriptor flags are symbolic unless they are Latin standard 14 fonts.
+func (f *Font) IsSymbolic() bool {
+	if fd := f.Descriptor; fd != nil && fd.HasFlags {
+		return fd.Has(FlagSymbolic) && !fd.Has(FlagNonsymbolic)
+	}
+	std, ok := StandardFontMetrics(f.BaseFont)
+	return !ok || std.IsSymbolic()
+}
+
+// IsMonospace reports whether all the font's glyphs have the same width:
+// by the descriptor's FixedPitch flag, or for the Courier standard fonts.
+func (f *Font) IsMonospace() bool {
+	if fd := f.Descriptor; fd != nil && fd.Has(FlagFixedPitch) {
+		return true
+	}
+	std, ok := StandardFontMetrics(f.BaseFont)
+	return ok && strings.HasPrefix(std.Name, "Courier")
+}
@@ -59,9 +59,17 @@ func DecodeWinAnsi(data []byte) string {
 	return b.String()
 }
 
-// pdfDocToUnicode maps PDFDocEncoding bytes (0x80-0xFF) to Unicode runes.
-// PDFDocEncoding is similar to ISO Latin-1 but with some differences in 0x80-0x9F range.
+// pdfDocToUnicode maps PDFDocEncoding bytes that differ from ISO Latin-1
+// (0x18-0x1F, 0x80-0x9F, 0xA0) to Unicode runes.
 var pdfDocToUnicode = map[byte]rune{
+	0x18: '\u02D8', // Breve
+	0x19: '\u02C7', // Caron
+	0x1A: '\u02C6', // Modifier Letter Circumflex Accent
+	0x1B: '\u02D9', // Dot Above
+	0x1C: '\u02DD', // Double Acute Accent
+	0x1D: '\u02DB', // Ogonek
+	0x1E: '\u02DA', // Ring Above
+	0x1F: '\u02DC', // Small Tilde
 	0x80: '\u2022', // Bullet
 	0x81: '\u2020', // Dagger
 	0x82: '\u2021', // Double Dagger
@@ -94,21 +102,24 @@ var pdfDocToUnicode = map[byte]rune{
 	0x9D: '\u0161', // Latin Small Letter S with Caron
 	0x9E: '\u017E', // Latin Small Letter Z with Caron
 	0x9F: '\uFFFD', // Replacement Character
-	// 0xA0-0xFF are same as ISO Latin-1
+	0xA0: '\u20AC', // Euro
+	// 0xA1-0xFF are same as ISO Latin-1
 }
 
 // DecodePDFDoc converts bytes from PDFDocEncoding to UTF-8 string.
-// Characters 0x00-0x7F are standard ASCII.
-// Characters 0x80-0x9F use special PDFDocEncoding mappings.
-// Characters 0xA0-0xFF are ISO Latin-1.
+// Characters 0x00-0x7F are standard ASCII, except the accents at 0x18-0x1F.
+// Characters 0x80-0xA0 use special PDFDocEncoding mappings.
+// Characters 0xA1-0xFF are ISO Latin-1.
 func DecodePDFDoc(data []byte) string {
 	var b strings.Builder
 	b.Grow(len(data))
 	for _, byteVal := range data {
-		if byteVal < 0x80 {
+		if byteVal >= 0x18 && byteVal <= 0x1F {
+			b.WriteRune(pdfDocToUnicode[byteVal])
+		} else if byteVal < 0x80 {
 			// Standard ASCII
 			b.WriteByte(byteVal)
-		} else if byteVal < 0xA0 {
+		} else if byteVal <= 0xA0 {
 			// PDFDocEncoding special range
 			if r, ok := pdfDocToUnicode[byteVal]; ok {
 				b.WriteRune(r)
@@ -117,7 +128,7 @@ func DecodePDFDoc(data []byte) string {
 				b.WriteRune('\uFFFD')
 			}
 		} else {
-			// 0xA0-0xFF: same as ISO Latin-1
+			// 0xA1-0xFF: same as ISO Latin-1
 			b.WriteRune(rune(byteVal))
 		}
 	}
@@ -137,3 +148,727 @@ func DecodeMacRoman(data []byte) string {
 	}
 	return b.String()
 }
+
+// winAnsiEncoding names the glyphs of WinAnsiEncoding (ISO 32000-1,
+// Annex D).
+var winAnsiEncoding = GlyphEncoding{
+	0x20: "space",
+	0x21: "exclam",
+	0x22: "quotedbl",
+	0x23: "numbersign",
+	0x24: "dollar",
+	0x25: "percent",
+	0x26: "ampersand",
+	0x27: "quotesingle",
+	0x28: "parenleft",
+	0x29: "parenright",
+	0x2A: "asterisk",
+	0x2B: "plus",
+	0x2C: "comma",
+	0x2D: "hyphen",
+	0x2E: "period",
+	0x2F: "slash",
+	0x30: "zero",
+	0x31: "one",
+	0x32: "two",
+	0x33: "three",
+	0x34: "four",
+	0x35: "five",
+	0x36: "six",
+	0x37: "seven",
+	0x38: "eight",
+	0x39: "nine",
+	0x3A: "colon",
+	0x3B: "semicolon",
+	0x3C: "less",
+	0x3D: "equal",
+	0x3E: "greater",
+	0x3F: "question",
+	0x40: "at",
+	0x41: "A",
+	0x42: "B",
+	0x43: "C",
+	0x44: "D",
+	0x45: "E",
+	0x46: "F",
+	0x47: "G",
+	0x48: "H",
+	0x49: "I",
+	0x4A: "J",
+	0x4B: "K",
+	0x4C: "L",
+	0x4D: "M",
+	0x4E: "N",
+	0x4F: "O",
+	0x50: "P",
+	0x51: "Q",
+	0x52: "R",
+	0x53: "S",
+	0x54: "T",
+	0x55: "U",
+	0x56: "V",
+	0x57: "W",
+	0x58: "X",
+	0x59: "Y",
+	0x5A: "Z",
+	0x5B: "bracketleft",
+	0x5C: "backslash",
+	0x5D: "bracketright",
+	0x5E: "asciicircum",
+	0x5F: "underscore",
+	0x60: "grave",
+	0x61: "a",
+	0x62: "b",
+	0x63: "c",
+	0x64: "d",
+	0x65: "e",
+	0x66: "f",
+	0x67: "g",
+	0x68: "h",
+	0x69: "i",
+	0x6A: "j",
+	0x6B: "k",
+	0x6C: "l",
+	0x6D: "m",
+	0x6E: "n",
+	0x6F: "o",
+	0x70: "p",
+	0x71: "q",
+	0x72: "r",
+	0x73: "s",
+	0x74: "t",
+	0x75: "u",
+	0x76: "v",
+	0x77: "w",
+	0x78: "x",
+	0x79: "y",
+	0x7A: "z",
+	0x7B: "braceleft",
+	0x7C: "bar",
+	0x7D: "braceright",
+	0x7E: "asciitilde",
+	0x80: "Euro",
+	0x82: "quotesinglbase",
+	0x83: "florin",
+	0x84: "quotedblbase",
+	0x85: "ellipsis",
+	0x86: "dagger",
+	0x87: "daggerdbl",
+	0x88: "circumflex",
+	0x89: "perthousand",
+	0x8A: "Scaron",
+	0x8B: "guilsinglleft",
+	0x8C: "OE",
+	0x8E: "Zcaron",
+	0x91: "quoteleft",
+	0x92: "quoteright",
+	0x93: "quotedblleft",
+	0x94: "quotedblright",
+	0x95: "bullet",
+	0x96: "endash",
+	0x97: "emdash",
+	0x98: "tilde",
+	0x99: "trademark",
+	0x9A: "scaron",
+	0x9B: "guilsinglright",
+	0x9C: "oe",
+	0x9E: "zcaron",
+	0x9F: "Ydieresis",
+	0xA0: "space",
+	0xA1: "exclamdown",
+	0xA2: "cent",
+	0xA3: "sterling",
+	0xA4: "currency",
+	0xA5: "yen",
+	0xA6: "brokenbar",
+	0xA7: "section",
+	0xA8: "dieresis",
+	0xA9: "copyright",
+	0xAA: "ordfeminine",
+	0xAB: "guillemotleft",
+	0xAC: "logicalnot",
+	0xAD: "hyphen",
+	0xAE: "registered",
+	0xAF: "macron",
+	0xB0: "degree",
+	0xB1: "plusminus",
+	0xB2: "twosuperior",
+	0xB3: "threesuperior",
+	0xB4: "acute",
+	0xB5: "mu",
+	0xB6: "paragraph",
+	0xB7: "periodcentered",
+	0xB8: "cedilla",
+	0xB9: "onesuperior",
+	0xBA: "ordmasculine",
+	0xBB: "guillemotright",
+	0xBC: "onequarter",
+	0xBD: "onehalf",
+	0xBE: "threequarters",
+	0xBF: "questiondown",
+	0xC0: "Agrave",
+	0xC1: "Aacute",
+	0xC2: "Acircumflex",
+	0xC3: "Atilde",
+	0xC4: "Adieresis",
+	0xC5: "Aring",
+	0xC6: "AE",
+	0xC7: "Ccedilla",
+	0xC8: "Egrave",
+	0xC9: "Eacute",
+	0xCA: "Ecircumflex",
+	0xCB: "Edieresis",
+	0xCC: "Igrave",
+	0xCD: "Iacute",
+	0xCE: "Icircumflex",
+	0xCF: "Idieresis",
+	0xD0: "Eth",
+	0xD1: "Ntilde",
+	0xD2: "Ograve",
+	0xD3: "Oacute",
+	0xD4: "Ocircumflex",
+	0xD5: "Otilde",
+	0xD6: "Odieresis",
+	0xD7: "multiply",
+	0xD8: "Oslash",
+	0xD9: "Ugrave",
+	0xDA: "Uacute",
+	0xDB: "Ucircumflex",
+	0xDC: "Udieresis",
+	0xDD: "Yacute",
+	0xDE: "Thorn",
+	0xDF: "germandbls",
+	0xE0: "agrave",
+	0xE1: "aacute",
+	0xE2: "acircumflex",
+	0xE3: "atilde",
+	0xE4: "adieresis",
+	0xE5: "aring",
+	0xE6: "ae",
+	0xE7: "ccedilla",
+	0xE8: "egrave",
+	0xE9: "eacute",
+	0xEA: "ecircumflex",
+	0xEB: "edieresis",
+	0xEC: "igrave",
+	0xED: "iacute",
+	0xEE: "icircumflex",
+	0xEF: "idieresis",
+	0xF0: "eth",
+	0xF1: "ntilde",
+	0xF2: "ograve",
+	0xF3: "oacute",
+	0xF4: "ocircumflex",
+	0xF5: "otilde",
+	0xF6: "odieresis",
+	0xF7: "divide",
+	0xF8: "oslash",
+	0xF9: "ugrave",
+	0xFA: "uacute",
+	0xFB: "ucircumflex",
+	0xFC: "udieresis",
+	0xFD: "yacute",
+	0xFE: "thorn",
+	0xFF: "ydieresis",
+}
+
+// macRomanEncoding names the glyphs of MacRomanEncoding (ISO 32000-1,
+// Annex D), which omits the Mac OS Roman mathematical symbols.
+var macRomanEncoding = GlyphEncoding{
+	0x20: "space",
+	0x21: "exclam",
+	0x22: "quotedbl",
+	0x23: "numbersign",
+	0x24: "dollar",
+	0x25: "percent",
+	0x26: "ampersand",
+	0x27: "quotesingle",
+	0x28: "parenleft",
+	0x29: "parenright",
+	0x2A: "asterisk",
+	0x2B: "plus",
+	0x2C: "comma",
+	0x2D: "hyphen",
+	0x2E: "period",
+	0x2F: "slash",
+	0x30: "zero",
+	0x31: "one",
+	0x32: "two",
+	0x33: "three",
+	0x34: "four",
+	0x35: "five",
+	0x36: "six",
+	0x37: "seven",
+	0x38: "eight",
+	0x39: "nine",
+	0x3A: "colon",
+	0x3B: "semicolon",
+	0x3C: "less",
+	0x3D: "equal",
+	0x3E: "greater",
+	0x3F: "question",
+	0x40: "at",
+	0x41: "A",
+	0x42: "B",
+	0x43: "C",
+	0x44: "D",
+	0x45: "E",
+	0x46: "F",
+	0x47: "G",
+	0x48: "H",
+	0x49: "I",
+	0x4A: "J",
+	0x4B: "K",
+	0x4C: "L",
+	0x4D: "M",
+	0x4E: "N",
+	0x4F: "O",
+	0x50: "P",
+	0x51: "Q",
+	0x52: "R",
+	0x53: "S",
+	0x54: "T",
+	0x55: "U",
+	0x56: "V",
+	0x57: "W",
+	0x58: "X",
+	0x59: "Y",
+	0x5A: "Z",
+	0x5B: "bracketleft",
+	0x5C: "backslash",
+	0x5D: "bracketright",
+	0x5E: "asciicircum",
+	0x5F: "underscore",
+	0x60: "grave",
+	0x61: "a",
+	0x62: "b",
+	0x63: "c",
+	0x64: "d",
+	0x65: "e",
+	0x66: "f",
+	0x67: "g",
+	0x68: "h",
+	0x69: "i",
+	0x6A: "j",
+	0x6B: "k",
+	0x6C: "l",
+	0x6D: "m",
+	0x6E: "n",
+	0x6F: "o",
+	0x70: "p",
+	0x71: "q",
+	0x72: "r",
+	0x73: "s",
+	0x74: "t",
+	0x75: "u",
+	0x76: "v",
+	0x77: "w",
+	0x78: "x",
+	0x79: "y",
+	0x7A: "z",
+	0x7B: "braceleft",
+	0x7C: "bar",
+	0x7D: "braceright",
+	0x7E: "asciitilde",
+	0x80: "Adieresis",
+	0x81: "Aring",
+	0x82: "Ccedilla",
+	0x83: "Eacute",
+	0x84: "Ntilde",
+	0x85: "Odieresis",
+	0x86: "Udieresis",
+	0x87: "aacute",
+	0x88: "agrave",
+	0x89: "acircumflex",
+	0x8A: "adieresis",
+	0x8B: "atilde",
+	0x8C: "aring",
+	0x8D: "ccedilla",
+	0x8E: "eacute",
+	0x8F: "egrave",
+	0x90: "ecircumflex",
+	0x91: "edieresis",
+	0x92: "iacute",
+	0x93: "igrave",
+	0x94: "icircumflex",
+	0x95: "idieresis",
+	0x96: "ntilde",
+	0x97: "oacute",
+	0x98: "ograve",
+	0x99: "ocircumflex",
+	0x9A: "odieresis",
+	0x9B: "otilde",
+	0x9C: "uacute",
+	0x9D: "ugrave",
+	0x9E: "ucircumflex",
+	0x9F: "udieresis",
+	0xA0: "dagger",
+	0xA1: "degree",
+	0xA2: "cent",
+	0xA3: "sterling",
+	0xA4: "section",
+	0xA5: "bullet",
+	0xA6: "paragraph",
+	0xA7: "germandbls",
+	0xA8: "registered",
+	0xA9: "copyright",
+	0xAA: "trademark",
+	0xAB: "acute",
+	0xAC: "dieresis",
+	0xAE: "AE",
+	0xAF: "Oslash",
+	0xB1: "plusminus",
+	0xB4: "yen",
+	0xB5: "mu",
+	0xBB: "ordfeminine",
+	0xBC: "ordmasculine",
+	0xBE: "ae",
+	0xBF: "oslash",
+	0xC0: "questiondown",
+	0xC1: "exclamdown",
+	0xC2: "logicalnot",
+	0xC4: "florin",
+	0xC7: "guillemotleft",
+	0xC8: "guillemotright",
+	0xC9: "ellipsis",
+	0xCA: "space",
+	0xCB: "Agrave",
+	0xCC: "Atilde",
+	0xCD: "Otilde",
+	0xCE: "OE",
+	0xCF: "oe",
+	0xD0: "endash",
+	0xD1: "emdash",
+	0xD2: "quotedblleft",
+	0xD3: "quotedblright",
+	0xD4: "quoteleft",
+	0xD5: "quoteright",
+	0xD6: "divide",
+	0xD8: "ydieresis",
+	0xD9: "Ydieresis",
+	0xDA: "fraction",
+	0xDB: "currency",
+	0xDC: "guilsinglleft",
+	0xDD: "guilsinglright",
+	0xDE: "fi",
+	0xDF: "fl",
+	0xE0: "daggerdbl",
+	0xE1: "periodcentered",
+	0xE2: "quotesinglbase",
+	0xE3: "quotedblbase",
+	0xE4: "perthousand",
+	0xE5: "Acircumflex",
+	0xE6: "Ecircumflex",
+	0xE7: "Aacute",
+	0xE8: "Edieresis",
+	0xE9: "Egrave",
+	0xEA: "Iacute",
+	0xEB: "Icircumflex",
+	0xEC: "Idieresis",
+	0xED: "Igrave",
+	0xEE: "Oacute",
+	0xEF: "Ocircumflex",
+	0xF1: "Ograve",
+	0xF2: "Uacute",
+	0xF3: "Ucircumflex",
+	0xF4: "Ugrave",
+	0xF5: "dotlessi",
+	0xF6: "circumflex",
+	0xF7: "tilde",
+	0xF8: "macron",
+	0xF9: "breve",
+	0xFA: "dotaccent",
+	0xFB: "ring",
+	0xFC: "cedilla",
+	0xFD: "hungarumlaut",
+	0xFE: "ogonek",
+	0xFF: "caron",
+}
+
+// EncodingFromName returns the encoding type an /Encoding or /BaseEncoding
+// name stands for. Other names, such as MacExpertEncoding or the
+// predefined CJK CMaps, are EncodingUnknown.
+func EncodingFromName(name string) EncodingType {
+	switch name {
+	case "WinAnsiEncoding":
+		return EncodingWinAnsi
+	case "MacRomanEncoding":
+		return EncodingMacRoman
+	case "PDFDocEncoding":
+		return EncodingPDFDoc
+	case "StandardEncoding":
+		return EncodingStandard
+	case "Identity-H", "Identity-V":
+		return EncodingIdentity
+	default:
+		return EncodingUnknown
+	}
+}
+
+// standardToUnicode maps StandardEncoding codes to Unicode; unmapped codes
+// are zero.
+var standardToUnicode = encodingRunes(&standardEncoding, glyphNames)
+
+// DecodeStandard converts bytes in Adobe StandardEncoding to a UTF-8
+// string. It differs from ASCII in the quotes at 0x27 and 0x60; unused
+// codes become U+FFFD.
+func DecodeStandard(data []byte) string {
+	return decodeTable(data, &standardToUnicode)
+}
+
+// symbolToUnicode and zapfDingbatsToUnicode map the codes of the Symbol
+// and ZapfDingbats fonts' built-in encodings to Unicode; unmapped codes
+// are zero.
+var (
+	symbolToUnicode = func() [256]rune {
+		t := encodingRunes(&symbolEncoding, glyphNames)
+		// Symbol's Delta, Omega and mu are Greek letters, not the
+		// increment, ohm and micro signs those names stand for elsewhere
+		t['D'], t['W'], t['m'] = '\u0394', '\u03A9', '\u03BC'
+		return t
+	}()
+	zapfDingbatsToUnicode = encodingRunes(&zapfDingbatsEncoding, zapfDingbatsGlyphNames)
+)
+
+// encodingRunes maps the codes of enc to Unicode through the glyph names.
+func encodingRunes(enc *GlyphEncoding, names map[string]rune) (t [256]rune) {
+	for code, name := range enc {
+		t[code] = names[name]
+	}
+	return t
+}
+
+// DecodeSymbol converts bytes in the built-in encoding of the Symbol font
+// (Greek letters, mathematical symbols) to a UTF-8 string. Unused codes
+// become U+FFFD.
+func DecodeSymbol(data []byte) string {
+	return decodeTable(data, &symbolToUnicode)
+}
+
+// DecodeZapfDingbats converts bytes in the built-in encoding of the
+// ZapfDingbats font to a UTF-8 string of Unicode dingbats. Unused codes
+// become U+FFFD.
+func DecodeZapfDingbats(data []byte) string {
+	return decodeTable(data, &zapfDingbatsToUnicode)
+}
+
+func decodeTable(data []byte, table *[256]rune) string {
+	var b strings.Builder
+	b.Grow(len(data) * 3)
+	for _, byteVal := range data {
+		if r := table[byteVal]; r != 0 {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('\uFFFD')
+		}
+	}
+	return b.String()
+}
+
+// zapfDingbatsGlyphNames maps the glyph names of ZapfDingbats to Unicode,
+// following the ITC Zapf Dingbats Glyph List. The names (a1, a2, ...)
+// apply to this font only.
+var zapfDingbatsGlyphNames = map[string]rune{
+	"a1":    0x2701,
+	"a2":    0x2702,
+	"a3":    0x2704,
+	"a4":    0x260E,
+	"a5":    0x2706,
+	"a6":    0x271D,
+	"a7":    0x271E,
+	"a8":    0x271F,
+	"a9":    0x2720,
+	"a10":   0x2721,
+	"a11":   0x261B,
+	"a12":   0x261E,
+	"a13":   0x270C,
+	"a14":   0x270D,
+	"a15":   0x270E,
+	"a16":   0x270F,
+	"a17":   0x2711,
+	"a18":   0x2712,
+	"a19":   0x2713,
+	"a20":   0x2714,
+	"a21":   0x2715,
+	"a22":   0x2716,
+	"a23":   0x2717,
+	"a24":   0x2718,
+	"a25":   0x2719,
+	"a26":   0x271A,
+	"a27":   0x271B,
+	"a28":   0x271C,
+	"a29":   0x2722,
+	"a30":   0x2723,
+	"a31":   0x2724,
+	"a32":   0x2725,
+	"a33":   0x2726,
+	"a34":   0x2727,
+	"a35":   0x2605,
+	"a36":   0x2729,
+	"a37":   0x272A,
+	"a38":   0x272B,
+	"a39":   0x272C,
+	"a40":   0x272D,
+	"a41":   0x272E,
+	"a42":   0x272F,
+	"a43":   0x2730,
+	"a44":   0x2731,
+	"a45":   0x2732,
+	"a46":   0x2733,
+	"a47":   0x2734,
+	"a48":   0x2735,
+	"a49":   0x2736,
+	"a50":   0x2737,
+	"a51":   0x2738,
+	"a52":   0x2739,
+	"a53":   0x273A,
+	"a54":   0x273B,
+	"a55":   0x273C,
+	"a56":   0x273D,
+	"a57":   0x273E,
+	"a58":   0x273F,
+	"a59":   0x2740,
+	"a60":   0x2741,
+	"a61":   0x2742,
+	"a62":   0x2743,
+	"a63":   0x2744,
+	"a64":   0x2745,
+	"a65":   0x2746,
+	"a66":   0x2747,
+	"a67":   0x2748,
+	"a68":   0x2749,
+	"a69":   0x274A,
+	"a70":   0x274B,
+	"a71":   0x25CF,
+	"a72":   0x274D,
+	"a73":   0x25A0,
+	"a74":   0x274F,
+	"a75":   0x2751,
+	"a76":   0x25B2,
+	"a77":   0x25BC,
+	"a78":   0x25C6,
+	"a79":   0x2756,
+	"a81":   0x25D7,
+	"a82":   0x2758,
+	"a83":   0x2759,
+	"a84":   0x275A,
+	"a85":   0x276F,
+	"a86":   0x2771,
+	"a87":   0x2772,
+	"a88":   0x2773,
+	"a89":   0x2768,
+	"a90":   0x2769,
+	"a91":   0x276C,
+	"a92":   0x276D,
+	"a93":   0x276A,
+	"a94":   0x276B,
+	"a95":   0x2774,
+	"a96":   0x2775,
+	"a97":   0x275B,
+	"a98":   0x275C,
+	"a99":   0x275D,
+	"a100":  0x275E,
+	"a101":  0x2761,
+	"a102":  0x2762,
+	"a103":  0x2763,
+	"a104":  0x2764,
+	"a105":  0x2710,
+	"a106":  0x2765,
+	"a107":  0x2766,
+	"a108":  0x2767,
+	"a109":  0x2660,
+	"a110":  0x2665,
+	"a111":  0x2666,
+	"a112":  0x2663,
+	"a117":  0x2709,
+	"a118":  0x2708,
+	"a119":  0x2707,
+	"a120":  0x2460,
+	"a121":  0x2461,
+	"a122":  0x2462,
+	"a123":  0x2463,
+	"a124":  0x2464,
+	"a125":  0x2465,
+	"a126":  0x2466,
+	"a127":  0x2467,
+	"a128":  0x2468,
+	"a129":  0x2469,
+	"a130":  0x2776,
+	"a131":  0x2777,
+	"a132":  0x2778,
+	"a133":  0x2779,
+	"a134":  0x277A,
+	"a135":  0x277B,
+	"a136":  0x277C,
+	"a137":  0x277D,
+	"a138":  0x277E,
+	"a139":  0x277F,
+	"a140":  0x2780,
+	"a141":  0x2781,
+	"a142":  0x2782,
+	"a143":  0x2783,
+	"a144":  0x2784,
+	"a145":  0x2785,
+	"a146":  0x2786,
+	"a147":  0x2787,
+	"a148":  0x2788,
+	"a149":  0x2789,
+	"a150":  0x278A,
+	"a151":  0x278B,
+	"a152":  0x278C,
+	"a153":  0x278D,
+	"a154":  0x278E,
+	"a155":  0x278F,
+	"a156":  0x2790,
+	"a157":  0x2791,
+	"a158":  0x2792,
+	"a159":  0x2793,
+	"a160":  0x2794,
+	"a161":  0x2192,
+	"a162":  0x27A3,
+	"a163":  0x2194,
+	"a164":  0x2195,
+	"a165":  0x2799,
+	"a166":  0x279B,
+	"a167":  0x279C,
+	"a168":  0x279D,
+	"a169":  0x279E,
+	"a170":  0x279F,
+	"a171":  0x27A0,
+	"a172":  0x27A1,
+	"a173":  0x27A2,
+	"a174":  0x27A4,
+	"a175":  0x27A5,
+	"a176":  0x27A6,
+	"a177":  0x27A7,
+	"a178":  0x27A8,
+	"a179":  0x27A9,
+	"a180":  0x27AB,
+	"a181":  0x27AD,
+	"a182":  0x27AF,
+	"a183":  0x27B2,
+	"a184":  0x27B3,
+	"a185":  0x27B5,
+	"a186":  0x27B8,
+	"a187":  0x27BA,
+	"a188":  0x27BB,
+	"a189":  0x27BC,
+	"a190":  0x27BD,
+	"a191":  0x27BE,
+	"a192":  0x279A,
+	"a193":  0x27AA,
+	"a194":  0x27B6,
+	"a195":  0x27B9,
+	"a196":  0x2798,
+	"a197":  0x27B4,
+	"a198":  0x27B7,
+	"a199":  0x27AC,
+	"a200":  0x27AE,
+	"a201":  0x27B1,
+	"a202":  0x2703,
+	"a203":  0x2750,
+	"a204":  0x2752,
+	"a205":  0x276E,
+	"a206":  0x2770,
+	"space": 0x0020,
+}
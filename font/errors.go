@@ -0,0 +1,12 @@
+package font
+
+import "errors"
+
+// ErrUnsupportedFont reports a font whose character codes cannot be mapped
+// to Unicode with the information available (e.g., an Identity-encoded
+// CID font without a ToUnicode CMap).
+var ErrUnsupportedFont = errors.New("unsupported font")
+
+// ErrInvalidFontProgram reports an embedded font program that could not be
+// parsed.
+var ErrInvalidFontProgram = errors.New("invalid font program")
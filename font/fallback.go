@@ -0,0 +1,131 @@
+package font
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DecodeSource is a way of mapping a character code to Unicode.
+type DecodeSource int
+
+const (
+	// SourceNone means no source decoded the code, which became U+FFFD.
+	SourceNone DecodeSource = iota
+	// SourceToUnicode is the font's ToUnicode CMap.
+	SourceToUnicode
+	// SourceEmbeddedCmap is the cmap table of the embedded TrueType or
+	// OpenType font program.
+	SourceEmbeddedCmap
+	// SourceEncoding is the glyph names of the /Differences array, a
+	// Unicode encoding CMap (e.g. UniGB-UCS2-H), and the base encoding
+	// of a simple font (WinAnsi, MacRoman, PDFDoc, Standard, Symbol,
+	// ZapfDingbats).
+	SourceEncoding
+	// SourceGlyphNames is the glyph names of an embedded Type 1 or CFF
+	// font program: its built-in encoding, or its charset.
+	SourceGlyphNames
+	// SourceRaw passes the code's bytes through unchanged. It is used
+	// only for fonts with neither a ToUnicode CMap nor an encoding CMap,
+	// whose codes are otherwise known not to be text.
+	SourceRaw
+)
+
+// DefaultFallbackChain is the order in which sources are tried for fonts
+// that do not set FallbackChain.
+var DefaultFallbackChain = []DecodeSource{
+	SourceToUnicode,
+	SourceEmbeddedCmap,
+	SourceEncoding,
+	SourceGlyphNames,
+	SourceRaw,
+}
+
+// String returns the source name.
+func (s DecodeSource) String() string {
+	switch s {
+	case SourceNone:
+		return "none"
+	case SourceToUnicode:
+		return "ToUnicode"
+	case SourceEmbeddedCmap:
+		return "embedded-cmap"
+	case SourceEncoding:
+		return "encoding"
+	case SourceGlyphNames:
+		return "glyph-names"
+	case SourceRaw:
+		return "raw"
+	default:
+		return fmt.Sprintf("DecodeSource(%d)", int(s))
+	}
+}
+
+// nextCode returns the character code at the start of data and its
+// length in bytes.
+func (f *Font) nextCode(data []byte, chain []DecodeSource) (code uint32, n int) {
+	switch {
+	case f.EncodingCMap != nil:
+		return f.EncodingCMap.NextCode(data)
+	case f.hasUsableToUnicode() && slices.Contains(chain, SourceToUnicode):
+		n = f.ToUnicode.matchCode(data, f.toUnicodeText)
+		return codeValue(data[:n]), n
+	}
+	n = min(f.CodeLength(), len(data))
+	return codeValue(data[:n]), n
+}
+
+// decodeCode maps a code of n bytes through the sources of chain and
+// returns its text and the source that decoded it.
+func (f *Font) decodeCode(raw []byte, code uint32, n int, chain []DecodeSource) (string, DecodeSource) {
+	for _, source := range chain {
+		var s string
+		ok := false
+		switch source {
+		case SourceToUnicode:
+			s, ok = f.toUnicodeText(raw)
+		case SourceEmbeddedCmap:
+			s, ok = f.cmapText(code, n)
+		case SourceEncoding:
+			s, ok = f.encodingText(raw, code, n)
+		case SourceGlyphNames:
+			s, ok = f.glyphNameText(code, n)
+		case SourceRaw:
+			s, ok = string(raw), f.ToUnicode == nil && f.EncodingCMap == nil
+		}
+		if ok {
+			return s, source
+		}
+	}
+	return "\uFFFD", SourceNone
+}
+
+// encodingText decodes a code through the /Differences glyph names, a
+// Unicode encoding CMap, or the base encoding of a simple font.
+func (f *Font) encodingText(raw []byte, code uint32, n int) (string, bool) {
+	if n == 1 {
+		if s, ok := f.differencesText(raw[0]); ok {
+			return s, true
+		}
+	}
+	if f.EncodingCMap != nil {
+		return f.EncodingCMap.unicode(code, n)
+	}
+	if f.IsMultiByte || n != 1 {
+		return "", false
+	}
+	switch f.Encoding {
+	case EncodingWinAnsi:
+		return DecodeWinAnsi(raw), true
+	case EncodingPDFDoc:
+		return DecodePDFDoc(raw), true
+	case EncodingMacRoman:
+		return DecodeMacRoman(raw), true
+	case EncodingSymbol:
+		return DecodeSymbol(raw), true
+	case EncodingZapfDingbats:
+		return DecodeZapfDingbats(raw), true
+	case EncodingStandard:
+		return DecodeStandard(raw), true
+	}
+	return "", false
+}
@@ -20,8 +20,41 @@ const (
 	EncodingIdentity
 	// EncodingCustom indicates a custom encoding with ToUnicode CMap
 	EncodingCustom
+	// EncodingSymbol is the built-in encoding of the Symbol font
+	EncodingSymbol
+	// EncodingZapfDingbats is the built-in encoding of the ZapfDingbats font
+	EncodingZapfDingbats
+	// EncodingStandard is Adobe StandardEncoding, the built-in encoding
+	// of the Latin Type 1 fonts
+	EncodingStandard
 )
 
+// String returns the encoding name.
+func (e EncodingType) String() string {
+	switch e {
+	case EncodingUnknown:
+		return "Unknown"
+	case EncodingWinAnsi:
+		return "WinAnsi"
+	case EncodingMacRoman:
+		return "MacRoman"
+	case EncodingPDFDoc:
+		return "PDFDoc"
+	case EncodingIdentity:
+		return "Identity"
+	case EncodingCustom:
+		return "Custom"
+	case EncodingSymbol:
+		return "Symbol"
+	case EncodingZapfDingbats:
+		return "ZapfDingbats"
+	case EncodingStandard:
+		return "Standard"
+	default:
+		return fmt.Sprintf("EncodingType(%d)", int(e))
+	}
+}
+
 // Font represents a PDF font with its encoding information.
 type Font struct {
 	// Name is the font resource name (e.g., "/F1", "/TT2")
@@ -33,11 +66,63 @@ type Font struct {
 	// Encoding type (WinAnsi, MacRoman, etc.)
 	Encoding EncodingType
 
+	// Differences holds the glyph names the /Differences array of the
+	// /Encoding dictionary assigns, which take precedence over the base
+	// encoding; codes it does not list have the empty name.
+	Differences *GlyphEncoding
+
 	// ToUnicode CMap (if available)
 	ToUnicode *CMap
 
 	// Whether this font uses multi-byte character codes
 	IsMultiByte bool
+
+	// EncodingCMap and CIDFont are set for Type0 fonts: the /Encoding
+	// CMap, which maps codes to CIDs, and the descendant font. Type0 fonts
+	// without a known encoding CMap are decoded with two-byte codes.
+	EncodingCMap *EncodingCMap
+	CIDFont      *CIDFont
+
+	// EmbeddedCmap is the cmap table of the embedded TrueType or OpenType
+	// font program, used to decode codes the ToUnicode CMap and encoding
+	// do not cover.
+	EmbeddedCmap *TrueTypeCmap
+
+	// BuiltinEncoding and Charset come from an embedded Type 1 or CFF
+	// font program: the encoding built into the program, and for CFF
+	// programs the glyph names by glyph index. Like EmbeddedCmap, they
+	// decode codes the ToUnicode CMap and encoding do not cover, through
+	// the glyph names.
+	BuiltinEncoding *GlyphEncoding
+	Charset         []string
+
+	// FallbackChain is the order in which DecodeShowString tries the
+	// decoding sources; sources left out are not used. Nil means
+	// DefaultFallbackChain.
+	FallbackChain []DecodeSource
+
+	// Vertical is set for fonts in vertical writing mode (WMode 1, e.g.
+	// Identity-V): glyphs advance downward and TJ adjustments move the
+	// vertical coordinate.
+	Vertical bool
+
+	// Simple-font glyph widths (/FirstChar, /Widths, /MissingWidth),
+	// in thousandths of an em
+	FirstChar    int
+	Widths       []float64
+	MissingWidth float64
+
+	// Descriptor is the font's /FontDescriptor (a Type0 font's descendant
+	// one), or nil if it has none.
+	Descriptor *FontDescriptor
+
+	// Standard holds the metrics of a standard 14 font, which give the
+	// widths of fonts without a /Widths array.
+	Standard *StandardFont
+
+	// CID-font default width (/DW); per-CID widths (/W) are set with SetCIDWidths
+	DefaultWidth float64
+	cidWidths    []cidWidthRange
 }
 
 // NewFont creates a new Font with the given name.
@@ -51,27 +136,89 @@ func NewFont(name string) *Font {
 }
 
 // DecodeText decodes text bytes using this font's encoding.
-// It prioritizes ToUnicode CMap if available, then falls back to standard encodings.
+// It prioritizes ToUnicode CMap if available, then falls back to standard
+// encodings. It is DecodeShowString without the glyph details.
 func (f *Font) DecodeText(data []byte) string {
-	// If we have a ToUnicode CMap, use it
-	if f.ToUnicode != nil {
-		return f.ToUnicode.DecodeString(data)
+	text, _, _ := f.DecodeShowString(data)
+	return text
+}
+
+// CheckDecodable reports whether the font's codes can be mapped to Unicode.
+// It returns an error wrapping ErrUnsupportedFont when decoding can only
+// fall back to raw bytes.
+func (f *Font) CheckDecodable() error {
+	if f.hasUsableToUnicode() || f.hasProgramMapping() || f.EncodingCMap != nil && f.EncodingCMap.IsUnicode() {
+		return nil
+	}
+	if f.Encoding == EncodingIdentity || f.IsMultiByte {
+		return fmt.Errorf("%w: %s has multi-byte codes and no ToUnicode CMap", ErrUnsupportedFont, f.Name)
 	}
+	return nil
+}
 
-	// Fall back to standard encodings
-	switch f.Encoding {
-	case EncodingWinAnsi:
-		return DecodeWinAnsi(data)
-	case EncodingPDFDoc:
-		return DecodePDFDoc(data)
-	case EncodingIdentity:
-		// Identity encoding - typically means we need ToUnicode
-		// Without it, we can't decode properly
-		return string(data) // Raw bytes as fallback
+// Capability describes how reliably a font's character codes map to
+// Unicode.
+type Capability int
+
+const (
+	// CapabilityToUnicode means the font has a ToUnicode CMap that is not
+	// degenerate.
+	CapabilityToUnicode Capability = iota
+	// CapabilityEncoding means codes are decoded through a known base
+	// encoding (WinAnsi, MacRoman, PDFDoc, Standard, Symbol,
+	// ZapfDingbats) or a Unicode encoding CMap.
+	CapabilityEncoding
+	// CapabilityFallback means neither is available and codes are decoded
+	// as raw bytes or Latin-1, which is usually wrong for subset and CID
+	// fonts.
+	CapabilityFallback
+	// CapabilityEmbedded means codes are decoded through the embedded
+	// font program: its cmap table, or its built-in encoding and glyph
+	// names, which cover only glyphs with Unicode mappings or known
+	// names.
+	CapabilityEmbedded
+)
+
+// String returns the capability name.
+func (c Capability) String() string {
+	switch c {
+	case CapabilityToUnicode:
+		return "ToUnicode"
+	case CapabilityEncoding:
+		return "Encoding"
+	case CapabilityFallback:
+		return "Fallback"
+	case CapabilityEmbedded:
+		return "Embedded"
 	default:
-		// Unknown encoding - try as ASCII/Latin1
-		return string(data)
+		return fmt.Sprintf("Capability(%d)", int(c))
+	}
+}
+
+// Capability reports how the font's codes are mapped to Unicode.
+func (f *Font) Capability() Capability {
+	if f.hasUsableToUnicode() {
+		return CapabilityToUnicode
+	}
+	if f.EncodingCMap != nil && f.EncodingCMap.IsUnicode() {
+		return CapabilityEncoding
 	}
+	if !f.IsMultiByte {
+		switch f.Encoding {
+		case EncodingWinAnsi, EncodingMacRoman, EncodingPDFDoc, EncodingSymbol, EncodingZapfDingbats, EncodingStandard:
+			return CapabilityEncoding
+		}
+	}
+	if f.hasProgramMapping() {
+		return CapabilityEmbedded
+	}
+	return CapabilityFallback
+}
+
+// hasProgramMapping reports whether the embedded font program provides a
+// way to map codes to Unicode.
+func (f *Font) hasProgramMapping() bool {
+	return f.EmbeddedCmap != nil || f.BuiltinEncoding != nil || f.Charset != nil
 }
 
 // String returns a debug representation of the font.
@@ -0,0 +1,160 @@
+package font
+
+import "github.com/apex-woot/pdf-stream-engine/parser"
+
+// FromPDFDict builds a Font from a font dictionary whose values use the
+// operand types of the parser (see parser.Dict), with indirect objects
+// resolved and streams given as their dictionaries.
+//
+// It sets the base font, the font descriptor, the glyph widths, the
+// standard 14 metrics, and
+// the encoding: the /Encoding name, or the /BaseEncoding and /Differences
+// of an /Encoding dictionary. Fonts without a base encoding get the one
+// ISO 32000-1, 9.6.6 implies: the built-in encoding of Symbol and
+// ZapfDingbats, and StandardEncoding for nonsymbolic Type 1 fonts that
+// are not embedded (whose built-in encoding the font program would give).
+// Type0 fonts get their predefined encoding CMap and descendant CIDFont.
+//
+// ToUnicode CMaps, embedded encoding CMaps, /CIDToGIDMap streams and font
+// programs need the stream contents, and are left to the caller.
+func FromPDFDict(name string, d parser.Dict) (*Font, error) {
+	f := NewFont(name)
+	f.BaseFont, _ = d.String("BaseFont")
+	subtype, _ := d.String("Subtype")
+	if subtype == "Type0" {
+		return f, fromType0Dict(f, d)
+	}
+
+	descriptor, ok := d.Dict("FontDescriptor")
+	if ok {
+		f.Descriptor = ParseFontDescriptor(descriptor)
+	}
+	if firstChar, ok := d.Number("FirstChar"); ok {
+		widths, _ := d.Array("Widths")
+		w := make([]float64, 0, len(widths))
+		for _, v := range widths {
+			width, _ := toFloat(v)
+			w = append(w, width)
+		}
+		f.SetWidths(int(firstChar), w)
+		f.MissingWidth, _ = descriptor.Number("MissingWidth")
+	}
+
+	switch enc := d["Encoding"].(type) {
+	case string:
+		f.Encoding = EncodingFromName(enc)
+	case parser.Dict:
+		if base, ok := enc.String("BaseEncoding"); ok {
+			f.Encoding = EncodingFromName(base)
+		}
+		if differences, ok := enc.Array("Differences"); ok {
+			f.Differences = ParseDifferences(differences)
+		}
+	}
+	if f.Encoding == EncodingIdentity {
+		// Identity on a simple font: read two-byte codes as if it were
+		// a Type0 font
+		f.IsMultiByte = true
+		f.Vertical = d["Encoding"] == "Identity-V"
+	}
+
+	if subtype == "Type3" {
+		return f, nil
+	}
+	std, isStandard := StandardFontMetrics(f.BaseFont)
+	if isStandard && f.Widths == nil {
+		f.Standard = std
+	}
+	if f.Encoding == EncodingUnknown {
+		switch {
+		case isStandard && std.IsSymbolic():
+			f.Encoding = std.EncodingType()
+		case (subtype == "Type1" || subtype == "MMType1") && !f.IsSymbolic() && !hasFontFile(descriptor):
+			f.Encoding = EncodingStandard
+		}
+	}
+	return f, nil
+}
+
+// fromType0Dict sets the predefined encoding CMap, descendant CIDFont and
+// CID widths of a Type0 font.
+func fromType0Dict(f *Font, d parser.Dict) error {
+	f.IsMultiByte = true
+	if enc, ok := d.String("Encoding"); ok {
+		if EncodingFromName(enc) == EncodingIdentity {
+			f.Encoding = EncodingIdentity
+		}
+		if cmap, ok := PredefinedCMap(enc); ok {
+			f.EncodingCMap = cmap
+			f.Vertical = cmap.Vertical
+		}
+	}
+
+	descendants, _ := d.Array("DescendantFonts")
+	if len(descendants) == 0 {
+		return nil
+	}
+	cidFont, ok := descendants[0].(parser.Dict)
+	if !ok {
+		return nil
+	}
+	c := &CIDFont{}
+	c.Subtype, _ = cidFont.String("Subtype")
+	c.BaseFont, _ = cidFont.String("BaseFont")
+	if descriptor, ok := cidFont.Dict("FontDescriptor"); ok {
+		f.Descriptor = ParseFontDescriptor(descriptor)
+	}
+	if info, ok := cidFont.Dict("CIDSystemInfo"); ok {
+		c.SystemInfo.Registry = textValue(info["Registry"])
+		c.SystemInfo.Ordering = textValue(info["Ordering"])
+		supplement, _ := info.Number("Supplement")
+		c.SystemInfo.Supplement = int(supplement)
+	}
+	f.CIDFont = c
+
+	dw, _ := cidFont.Number("DW")
+	w, _ := cidFont.Array("W")
+	return f.SetCIDWidths(dw, w)
+}
+
+// ParseDifferences parses a /Differences array: each number gives the
+// code of the glyph name after it, and each further name the next code.
+// Elements of other types are skipped.
+func ParseDifferences(differences []any) *GlyphEncoding {
+	var enc GlyphEncoding
+	code := -1
+	for _, v := range differences {
+		switch v := v.(type) {
+		case float64:
+			code = int(v)
+		case string:
+			if code >= 0 && code < len(enc) {
+				enc[code] = v
+			}
+			code++
+		}
+	}
+	return &enc
+}
+
+// hasFontFile reports whether the descriptor embeds a font program.
+func hasFontFile(descriptor parser.Dict) bool {
+	for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		if _, ok := descriptor[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// textValue returns a string operand (a name or literal string) or a hex
+// string as text.
+func textValue(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	}
+	return ""
+}
@@ -0,0 +1,640 @@
+package font
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// GlyphNameToUnicode returns the text a glyph name stands for, following
+// the Adobe Glyph List Specification: a suffix after the first period is
+// ignored ("a.sc" is "a"), ligature components are joined with
+// underscores ("f_f_i"), and names of the forms uniXXXX (one or more
+// UTF-16 code units) and uXXXX to uXXXXXX give code points directly.
+// Other names are looked up in a table of the common Latin, Greek and
+// symbol glyphs. ok is false if a component is not recognized.
+func GlyphNameToUnicode(name string) (string, bool) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return "", false
+	}
+	var b strings.Builder
+	for component := range strings.SplitSeq(name, "_") {
+		s, ok := glyphComponentText(component)
+		if !ok {
+			return "", false
+		}
+		b.WriteString(s)
+	}
+	return b.String(), true
+}
+
+// glyphComponentText maps one component of a glyph name.
+func glyphComponentText(name string) (string, bool) {
+	if r, ok := glyphNames[name]; ok {
+		return string(r), true
+	}
+	if hex, ok := strings.CutPrefix(name, "uni"); ok && len(hex) >= 4 && len(hex)%4 == 0 {
+		var b strings.Builder
+		for i := 0; i < len(hex); i += 4 {
+			v, err := strconv.ParseUint(hex[i:i+4], 16, 16)
+			if err != nil || !isUpperHex(hex[i:i+4]) || v >= 0xD800 && v <= 0xDFFF {
+				return "", false
+			}
+			b.WriteRune(rune(v))
+		}
+		return b.String(), true
+	}
+	if hex, ok := strings.CutPrefix(name, "u"); ok && len(hex) >= 4 && len(hex) <= 6 && isUpperHex(hex) {
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(v)) {
+			return "", false
+		}
+		return string(rune(v)), true
+	}
+	return "", false
+}
+
+// isUpperHex reports whether s consists of the digits 0-9 and A-F, the
+// only hex digits the glyph list specification allows in uni and u names.
+func isUpperHex(s string) bool {
+	for i := range len(s) {
+		if c := s[i]; !(c >= '0' && c <= '9' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// glyphNames maps the glyph names of the Adobe standard Latin character
+// set, the Symbol font, and common Greek, mathematical and ligature glyphs,
+// to Unicode, following the Adobe Glyph List.
+var glyphNames = map[string]rune{
+	"A":              0x0041,
+	"AE":             0x00C6,
+	"AEacute":        0x01FC,
+	"Aacute":         0x00C1,
+	"Abreve":         0x0102,
+	"Acircumflex":    0x00C2,
+	"Adieresis":      0x00C4,
+	"Agrave":         0x00C0,
+	"Alpha":          0x0391,
+	"Amacron":        0x0100,
+	"Aogonek":        0x0104,
+	"Aring":          0x00C5,
+	"Aringacute":     0x01FA,
+	"Atilde":         0x00C3,
+	"B":              0x0042,
+	"Beta":           0x0392,
+	"C":              0x0043,
+	"Cacute":         0x0106,
+	"Ccaron":         0x010C,
+	"Ccedilla":       0x00C7,
+	"Ccircumflex":    0x0108,
+	"Cdotaccent":     0x010A,
+	"Chi":            0x03A7,
+	"D":              0x0044,
+	"Dcaron":         0x010E,
+	"Dcroat":         0x0110,
+	"Delta":          0x2206,
+	"E":              0x0045,
+	"Eacute":         0x00C9,
+	"Ebreve":         0x0114,
+	"Ecaron":         0x011A,
+	"Ecircumflex":    0x00CA,
+	"Edieresis":      0x00CB,
+	"Edotaccent":     0x0116,
+	"Egrave":         0x00C8,
+	"Emacron":        0x0112,
+	"Eng":            0x014A,
+	"Eogonek":        0x0118,
+	"Epsilon":        0x0395,
+	"Eta":            0x0397,
+	"Eth":            0x00D0,
+	"Euro":           0x20AC,
+	"F":              0x0046,
+	"G":              0x0047,
+	"Gamma":          0x0393,
+	"Gbreve":         0x011E,
+	"Gcircumflex":    0x011C,
+	"Gcommaaccent":   0x0122,
+	"Gdotaccent":     0x0120,
+	"H":              0x0048,
+	"Hbar":           0x0126,
+	"Hcircumflex":    0x0124,
+	"I":              0x0049,
+	"IJ":             0x0132,
+	"Iacute":         0x00CD,
+	"Ibreve":         0x012C,
+	"Icircumflex":    0x00CE,
+	"Idieresis":      0x00CF,
+	"Idotaccent":     0x0130,
+	"Ifraktur":       0x2111,
+	"Igrave":         0x00CC,
+	"Imacron":        0x012A,
+	"Iogonek":        0x012E,
+	"Iota":           0x0399,
+	"Itilde":         0x0128,
+	"J":              0x004A,
+	"Jcircumflex":    0x0134,
+	"K":              0x004B,
+	"Kappa":          0x039A,
+	"Kcommaaccent":   0x0136,
+	"L":              0x004C,
+	"Lacute":         0x0139,
+	"Lambda":         0x039B,
+	"Lcaron":         0x013D,
+	"Lcommaaccent":   0x013B,
+	"Ldot":           0x013F,
+	"Lslash":         0x0141,
+	"M":              0x004D,
+	"Mu":             0x039C,
+	"N":              0x004E,
+	"Nacute":         0x0143,
+	"Ncaron":         0x0147,
+	"Ncommaaccent":   0x0145,
+	"Ntilde":         0x00D1,
+	"Nu":             0x039D,
+	"O":              0x004F,
+	"OE":             0x0152,
+	"Oacute":         0x00D3,
+	"Obreve":         0x014E,
+	"Ocircumflex":    0x00D4,
+	"Odieresis":      0x00D6,
+	"Ograve":         0x00D2,
+	"Ohm":            0x2126,
+	"Ohorn":          0x01A0,
+	"Ohungarumlaut":  0x0150,
+	"Omacron":        0x014C,
+	"Omega":          0x2126,
+	"Omicron":        0x039F,
+	"Oslash":         0x00D8,
+	"Oslashacute":    0x01FE,
+	"Otilde":         0x00D5,
+	"P":              0x0050,
+	"Phi":            0x03A6,
+	"Pi":             0x03A0,
+	"Psi":            0x03A8,
+	"Q":              0x0051,
+	"R":              0x0052,
+	"Racute":         0x0154,
+	"Rcaron":         0x0158,
+	"Rcommaaccent":   0x0156,
+	"Rfraktur":       0x211C,
+	"Rho":            0x03A1,
+	"S":              0x0053,
+	"Sacute":         0x015A,
+	"Scaron":         0x0160,
+	"Scedilla":       0x015E,
+	"Schwa":          0x018F,
+	"Scircumflex":    0x015C,
+	"Scommaaccent":   0x0218,
+	"Sigma":          0x03A3,
+	"T":              0x0054,
+	"Tau":            0x03A4,
+	"Tbar":           0x0166,
+	"Tcaron":         0x0164,
+	"Tcedilla":       0x0162,
+	"Tcommaaccent":   0x0162,
+	"Theta":          0x0398,
+	"Thorn":          0x00DE,
+	"U":              0x0055,
+	"Uacute":         0x00DA,
+	"Ubreve":         0x016C,
+	"Ucircumflex":    0x00DB,
+	"Udieresis":      0x00DC,
+	"Ugrave":         0x00D9,
+	"Uhorn":          0x01AF,
+	"Uhungarumlaut":  0x0170,
+	"Umacron":        0x016A,
+	"Uogonek":        0x0172,
+	"Upsilon":        0x03A5,
+	"Upsilon1":       0x03D2,
+	"Uring":          0x016E,
+	"Utilde":         0x0168,
+	"V":              0x0056,
+	"W":              0x0057,
+	"Wacute":         0x1E82,
+	"Wcircumflex":    0x0174,
+	"Wdieresis":      0x1E84,
+	"Wgrave":         0x1E80,
+	"X":              0x0058,
+	"Xi":             0x039E,
+	"Y":              0x0059,
+	"Yacute":         0x00DD,
+	"Ycircumflex":    0x0176,
+	"Ydieresis":      0x0178,
+	"Ygrave":         0x1EF2,
+	"Z":              0x005A,
+	"Zacute":         0x0179,
+	"Zcaron":         0x017D,
+	"Zdotaccent":     0x017B,
+	"Zeta":           0x0396,
+	"a":              0x0061,
+	"aacute":         0x00E1,
+	"abreve":         0x0103,
+	"acircumflex":    0x00E2,
+	"acute":          0x00B4,
+	"adieresis":      0x00E4,
+	"ae":             0x00E6,
+	"aeacute":        0x01FD,
+	"afii61352":      0x2116,
+	"agrave":         0x00E0,
+	"aleph":          0x2135,
+	"alpha":          0x03B1,
+	"amacron":        0x0101,
+	"ampersand":      0x0026,
+	"angle":          0x2220,
+	"angleleft":      0x2329,
+	"angleright":     0x232A,
+	"aogonek":        0x0105,
+	"apostrophe":     0x0027,
+	"apple":          0xF8FF,
+	"approxequal":    0x2248,
+	"aring":          0x00E5,
+	"aringacute":     0x01FB,
+	"arrowboth":      0x2194,
+	"arrowdblboth":   0x21D4,
+	"arrowdbldown":   0x21D3,
+	"arrowdblleft":   0x21D0,
+	"arrowdblright":  0x21D2,
+	"arrowdblup":     0x21D1,
+	"arrowdown":      0x2193,
+	"arrowhorizex":   0x23AF,
+	"arrowleft":      0x2190,
+	"arrowright":     0x2192,
+	"arrowup":        0x2191,
+	"arrowvertex":    0x23D0,
+	"asciicircum":    0x005E,
+	"asciitilde":     0x007E,
+	"asterisk":       0x002A,
+	"asteriskmath":   0x2217,
+	"at":             0x0040,
+	"atilde":         0x00E3,
+	"b":              0x0062,
+	"backslash":      0x005C,
+	"bar":            0x007C,
+	"beta":           0x03B2,
+	"braceex":        0x23AA,
+	"braceleft":      0x007B,
+	"braceleftbt":    0x23A9,
+	"braceleftmid":   0x23A8,
+	"bracelefttp":    0x23A7,
+	"braceright":     0x007D,
+	"bracerightbt":   0x23AD,
+	"bracerightmid":  0x23AC,
+	"bracerighttp":   0x23AB,
+	"bracketleft":    0x005B,
+	"bracketleftbt":  0x23A3,
+	"bracketleftex":  0x23A2,
+	"bracketlefttp":  0x23A1,
+	"bracketright":   0x005D,
+	"bracketrightbt": 0x23A6,
+	"bracketrightex": 0x23A5,
+	"bracketrighttp": 0x23A4,
+	"breve":          0x02D8,
+	"brokenbar":      0x00A6,
+	"bullet":         0x2022,
+	"c":              0x0063,
+	"cacute":         0x0107,
+	"caron":          0x02C7,
+	"carriagereturn": 0x21B5,
+	"ccaron":         0x010D,
+	"ccedilla":       0x00E7,
+	"ccircumflex":    0x0109,
+	"cdotaccent":     0x010B,
+	"cedilla":        0x00B8,
+	"cent":           0x00A2,
+	"chi":            0x03C7,
+	"circlemultiply": 0x2297,
+	"circleplus":     0x2295,
+	"circumflex":     0x02C6,
+	"club":           0x2663,
+	"colon":          0x003A,
+	"colonmonetary":  0x20A1,
+	"comma":          0x002C,
+	"commaaccent":    0xF6C3,
+	"congruent":      0x2245,
+	"copyright":      0x00A9,
+	"copyrightsans":  0x00A9,
+	"copyrightserif": 0x00A9,
+	"currency":       0x00A4,
+	"d":              0x0064,
+	"dagger":         0x2020,
+	"daggerdbl":      0x2021,
+	"dcaron":         0x010F,
+	"dcroat":         0x0111,
+	"degree":         0x00B0,
+	"delta":          0x03B4,
+	"diamond":        0x2666,
+	"dieresis":       0x00A8,
+	"dieresistonos":  0x0385,
+	"divide":         0x00F7,
+	"dollar":         0x0024,
+	"dong":           0x20AB,
+	"dotaccent":      0x02D9,
+	"dotlessi":       0x0131,
+	"dotlessj":       0x0237,
+	"dotmath":        0x22C5,
+	"e":              0x0065,
+	"eacute":         0x00E9,
+	"ebreve":         0x0115,
+	"ecaron":         0x011B,
+	"ecircumflex":    0x00EA,
+	"edieresis":      0x00EB,
+	"edotaccent":     0x0117,
+	"egrave":         0x00E8,
+	"eight":          0x0038,
+	"eightinferior":  0x2088,
+	"eightsuperior":  0x2078,
+	"element":        0x2208,
+	"ellipsis":       0x2026,
+	"emacron":        0x0113,
+	"emdash":         0x2014,
+	"emptyset":       0x2205,
+	"endash":         0x2013,
+	"eng":            0x014B,
+	"eogonek":        0x0119,
+	"epsilon":        0x03B5,
+	"equal":          0x003D,
+	"equivalence":    0x2261,
+	"estimated":      0x212E,
+	"eta":            0x03B7,
+	"eth":            0x00F0,
+	"exclam":         0x0021,
+	"exclamdbl":      0x203C,
+	"exclamdown":     0x00A1,
+	"existential":    0x2203,
+	"f":              0x0066,
+	"ff":             0xFB00,
+	"ffi":            0xFB03,
+	"ffl":            0xFB04,
+	"fi":             0xFB01,
+	"figuredash":     0x2012,
+	"five":           0x0035,
+	"fiveeighths":    0x215D,
+	"fiveinferior":   0x2085,
+	"fivesuperior":   0x2075,
+	"fl":             0xFB02,
+	"florin":         0x0192,
+	"four":           0x0034,
+	"fourinferior":   0x2084,
+	"foursuperior":   0x2074,
+	"fraction":       0x2044,
+	"franc":          0x20A3,
+	"g":              0x0067,
+	"gamma":          0x03B3,
+	"gbreve":         0x011F,
+	"gcircumflex":    0x011D,
+	"gcommaaccent":   0x0123,
+	"gdotaccent":     0x0121,
+	"germandbls":     0x00DF,
+	"gradient":       0x2207,
+	"grave":          0x0060,
+	"greater":        0x003E,
+	"greaterequal":   0x2265,
+	"guillemotleft":  0x00AB,
+	"guillemotright": 0x00BB,
+	"guilsinglleft":  0x2039,
+	"guilsinglright": 0x203A,
+	"h":              0x0068,
+	"hbar":           0x0127,
+	"hcircumflex":    0x0125,
+	"heart":          0x2665,
+	"house":          0x2302,
+	"hungarumlaut":   0x02DD,
+	"hyphen":         0x002D,
+	"i":              0x0069,
+	"iacute":         0x00ED,
+	"ibreve":         0x012D,
+	"icircumflex":    0x00EE,
+	"idieresis":      0x00EF,
+	"igrave":         0x00EC,
+	"ij":             0x0133,
+	"imacron":        0x012B,
+	"infinity":       0x221E,
+	"integral":       0x222B,
+	"integralbt":     0x2321,
+	"integralex":     0x23AE,
+	"integraltp":     0x2320,
+	"intersection":   0x2229,
+	"iogonek":        0x012F,
+	"iota":           0x03B9,
+	"itilde":         0x0129,
+	"j":              0x006A,
+	"jcircumflex":    0x0135,
+	"k":              0x006B,
+	"kappa":          0x03BA,
+	"kcommaaccent":   0x0137,
+	"kgreenlandic":   0x0138,
+	"l":              0x006C,
+	"lacute":         0x013A,
+	"lambda":         0x03BB,
+	"lcaron":         0x013E,
+	"lcommaaccent":   0x013C,
+	"ldot":           0x0140,
+	"less":           0x003C,
+	"lessequal":      0x2264,
+	"lira":           0x20A4,
+	"logicaland":     0x2227,
+	"logicalnot":     0x00AC,
+	"logicalor":      0x2228,
+	"longs":          0x017F,
+	"lozenge":        0x25CA,
+	"lslash":         0x0142,
+	"m":              0x006D,
+	"macron":         0x00AF,
+	"middot":         0x00B7,
+	"minus":          0x2212,
+	"minute":         0x2032,
+	"mu":             0x00B5,
+	"multiply":       0x00D7,
+	"n":              0x006E,
+	"nacute":         0x0144,
+	"napostrophe":    0x0149,
+	"nbspace":        0x00A0,
+	"ncaron":         0x0148,
+	"ncommaaccent":   0x0146,
+	"nine":           0x0039,
+	"nineinferior":   0x2089,
+	"ninesuperior":   0x2079,
+	"notelement":     0x2209,
+	"notequal":       0x2260,
+	"notsubset":      0x2284,
+	"ntilde":         0x00F1,
+	"nu":             0x03BD,
+	"numbersign":     0x0023,
+	"o":              0x006F,
+	"oacute":         0x00F3,
+	"obreve":         0x014F,
+	"ocircumflex":    0x00F4,
+	"odieresis":      0x00F6,
+	"oe":             0x0153,
+	"ogonek":         0x02DB,
+	"ograve":         0x00F2,
+	"ohorn":          0x01A1,
+	"ohungarumlaut":  0x0151,
+	"omacron":        0x014D,
+	"omega":          0x03C9,
+	"omega1":         0x03D6,
+	"omicron":        0x03BF,
+	"one":            0x0031,
+	"onedotenleader": 0x2024,
+	"oneeighth":      0x215B,
+	"onehalf":        0x00BD,
+	"oneinferior":    0x2081,
+	"onequarter":     0x00BC,
+	"onesuperior":    0x00B9,
+	"onethird":       0x2153,
+	"ordfeminine":    0x00AA,
+	"ordmasculine":   0x00BA,
+	"oslash":         0x00F8,
+	"oslashacute":    0x01FF,
+	"otilde":         0x00F5,
+	"p":              0x0070,
+	"paragraph":      0x00B6,
+	"parenleft":      0x0028,
+	"parenleftbt":    0x239D,
+	"parenleftex":    0x239C,
+	"parenlefttp":    0x239B,
+	"parenright":     0x0029,
+	"parenrightbt":   0x23A0,
+	"parenrightex":   0x239F,
+	"parenrighttp":   0x239E,
+	"partialdiff":    0x2202,
+	"percent":        0x0025,
+	"period":         0x002E,
+	"periodcentered": 0x00B7,
+	"perpendicular":  0x22A5,
+	"perthousand":    0x2030,
+	"peseta":         0x20A7,
+	"phi":            0x03C6,
+	"phi1":           0x03D5,
+	"pi":             0x03C0,
+	"plus":           0x002B,
+	"plusminus":      0x00B1,
+	"product":        0x220F,
+	"propersubset":   0x2282,
+	"propersuperset": 0x2283,
+	"proportional":   0x221D,
+	"psi":            0x03C8,
+	"q":              0x0071,
+	"question":       0x003F,
+	"questiondown":   0x00BF,
+	"quotedbl":       0x0022,
+	"quotedblbase":   0x201E,
+	"quotedblleft":   0x201C,
+	"quotedblright":  0x201D,
+	"quoteleft":      0x2018,
+	"quotereversed":  0x201B,
+	"quoteright":     0x2019,
+	"quotesinglbase": 0x201A,
+	"quotesingle":    0x0027,
+	"r":              0x0072,
+	"racute":         0x0155,
+	"radical":        0x221A,
+	"radicalex":      0x203E,
+	"rcaron":         0x0159,
+	"rcommaaccent":   0x0157,
+	"reflexsubset":   0x2286,
+	"reflexsuperset": 0x2287,
+	"registered":     0x00AE,
+	"registersans":   0x00AE,
+	"registerserif":  0x00AE,
+	"rho":            0x03C1,
+	"ring":           0x02DA,
+	"s":              0x0073,
+	"sacute":         0x015B,
+	"scaron":         0x0161,
+	"scedilla":       0x015F,
+	"schwa":          0x0259,
+	"scircumflex":    0x015D,
+	"scommaaccent":   0x0219,
+	"second":         0x2033,
+	"section":        0x00A7,
+	"semicolon":      0x003B,
+	"seven":          0x0037,
+	"seveneighths":   0x215E,
+	"seveninferior":  0x2087,
+	"sevensuperior":  0x2077,
+	"sfthyphen":      0x00AD,
+	"sigma":          0x03C3,
+	"sigma1":         0x03C2,
+	"similar":        0x223C,
+	"six":            0x0036,
+	"sixinferior":    0x2086,
+	"sixsuperior":    0x2076,
+	"slash":          0x002F,
+	"space":          0x0020,
+	"spade":          0x2660,
+	"sterling":       0x00A3,
+	"suchthat":       0x220B,
+	"summation":      0x2211,
+	"t":              0x0074,
+	"tau":            0x03C4,
+	"tbar":           0x0167,
+	"tcaron":         0x0165,
+	"tcedilla":       0x0163,
+	"tcommaaccent":   0x0163,
+	"therefore":      0x2234,
+	"theta":          0x03B8,
+	"theta1":         0x03D1,
+	"thorn":          0x00FE,
+	"three":          0x0033,
+	"threeeighths":   0x215C,
+	"threeinferior":  0x2083,
+	"threequarters":  0x00BE,
+	"threesuperior":  0x00B3,
+	"tilde":          0x02DC,
+	"tonos":          0x0384,
+	"trademark":      0x2122,
+	"trademarksans":  0x2122,
+	"trademarkserif": 0x2122,
+	"two":            0x0032,
+	"twodotenleader": 0x2025,
+	"twoinferior":    0x2082,
+	"twosuperior":    0x00B2,
+	"twothirds":      0x2154,
+	"u":              0x0075,
+	"uacute":         0x00FA,
+	"ubreve":         0x016D,
+	"ucircumflex":    0x00FB,
+	"udieresis":      0x00FC,
+	"ugrave":         0x00F9,
+	"uhorn":          0x01B0,
+	"uhungarumlaut":  0x0171,
+	"umacron":        0x016B,
+	"underscore":     0x005F,
+	"uni00A0":        0x00A0,
+	"union":          0x222A,
+	"universal":      0x2200,
+	"uogonek":        0x0173,
+	"upsilon":        0x03C5,
+	"uring":          0x016F,
+	"utilde":         0x0169,
+	"v":              0x0076,
+	"w":              0x0077,
+	"wacute":         0x1E83,
+	"wcircumflex":    0x0175,
+	"wdieresis":      0x1E85,
+	"weierstrass":    0x2118,
+	"wgrave":         0x1E81,
+	"x":              0x0078,
+	"xi":             0x03BE,
+	"y":              0x0079,
+	"yacute":         0x00FD,
+	"ycircumflex":    0x0177,
+	"ydieresis":      0x00FF,
+	"yen":            0x00A5,
+	"ygrave":         0x1EF3,
+	"z":              0x007A,
+	"zacute":         0x017A,
+	"zcaron":         0x017E,
+	"zdotaccent":     0x017C,
+	"zero":           0x0030,
+	"zeroinferior":   0x2080,
+	"zerosuperior":   0x2070,
+	"zeta":           0x03B6,
+}
@@ -0,0 +1,168 @@
+package font
+
+import "strings"
+
+// FontName is the breakdown of a /BaseFont name into family, weight and
+// style, e.g. ABCDEF+Arial-BoldItalicMT into the Arial family, weight 700,
+// italic. Runs whose fonts share a family, weight and style come from the
+// same logical font even if they use different subsets.
+type FontName struct {
+	// PostScriptName is the name without the subset prefix, e.g.
+	// Arial-BoldItalicMT.
+	PostScriptName string
+
+	// Subset is set if the name had a subset prefix (ABCDEF+).
+	Subset bool
+
+	// Family is the family name, without the style and the MT and PS
+	// suffixes of Monotype and Windows names, e.g. Arial or TimesNewRoman.
+	Family string
+
+	// Weight is the weight from 100 to 900: 400 for regular faces and
+	// names without a weight, 700 for bold.
+	Weight int
+
+	// Italic is set for italic and oblique faces.
+	Italic bool
+}
+
+// IsBold reports whether the weight is semibold or heavier.
+func (n FontName) IsBold() bool {
+	return n.Weight >= boldFontWeight
+}
+
+// StripSubsetPrefix returns baseFont without the tag that marks a subset
+// font: six uppercase letters and a plus sign (ISO 32000-1, 9.6.4).
+func StripSubsetPrefix(baseFont string) string {
+	if len(baseFont) > 7 && baseFont[6] == '+' {
+		for i := range 6 {
+			if baseFont[i] < 'A' || baseFont[i] > 'Z' {
+				return baseFont
+			}
+		}
+		return baseFont[7:]
+	}
+	return baseFont
+}
+
+// styleWord describes a weight or style word of font names.
+type styleWord struct {
+	weight int // 0 if the word is not a weight
+	italic bool
+}
+
+// styleWords are the weight and style words of font names, lowercase.
+// Compound words also match when written as two capitalized words
+// (SemiBold).
+var styleWords = map[string]styleWord{
+	"thin": {weight: 100}, "extralight": {weight: 200}, "ultralight": {weight: 200},
+	"light": {weight: 300}, "regular": {weight: 400}, "normal": {weight: 400},
+	"book": {weight: 400}, "roman": {weight: 400}, "medium": {weight: 500},
+	"semibold": {weight: 600}, "demibold": {weight: 600}, "demi": {weight: 600},
+	"bold": {weight: 700}, "extrabold": {weight: 800}, "ultrabold": {weight: 800},
+	"heavy": {weight: 800}, "black": {weight: 900},
+	"italic": {italic: true}, "oblique": {italic: true}, "ital": {italic: true},
+	"it": {italic: true},
+}
+
+// familySuffixes are dropped from family names: ArialMT is Arial.
+var familySuffixes = []string{"PSMT", "MT", "PS"}
+
+// ParseFontName breaks a /BaseFont name into family, weight and style.
+// The style follows the family after a comma (Arial,Bold) or hyphen
+// (Arial-BoldMT); names without either are searched for trailing style
+// words (ArialBold).
+func ParseFontName(baseFont string) FontName {
+	n := FontName{PostScriptName: StripSubsetPrefix(baseFont), Weight: 400}
+	n.Subset = n.PostScriptName != baseFont
+
+	family, style := n.PostScriptName, ""
+	if i := strings.IndexAny(family, ",-"); i > 0 {
+		family, style = family[:i], family[i+1:]
+	} else {
+		family, style = splitTrailingStyle(family)
+	}
+	for _, suffix := range familySuffixes {
+		if s, ok := strings.CutSuffix(family, suffix); ok && s != "" {
+			family = s
+			break
+		}
+	}
+	n.Family = family
+
+	words := splitWords(style)
+	for i := 0; i < len(words); i++ {
+		w, ok := styleWord{}, false
+		if i+1 < len(words) {
+			if w, ok = styleWords[words[i]+words[i+1]]; ok {
+				i++
+			}
+		}
+		if !ok {
+			w, ok = styleWords[words[i]]
+		}
+		if !ok {
+			continue
+		}
+		if w.weight != 0 {
+			n.Weight = w.weight
+		}
+		n.Italic = n.Italic || w.italic
+	}
+	return n
+}
+
+// splitWords splits a style such as BoldItalicMT into lowercase words at
+// uppercase letters following lowercase ones and at non-letters.
+func splitWords(s string) []string {
+	var words []string
+	start := -1
+	for i := 0; i <= len(s); i++ {
+		boundary := i == len(s) || !isLetter(s[i]) ||
+			i > 0 && isUpper(s[i]) && !isUpper(s[i-1])
+		if boundary && start >= 0 {
+			words = append(words, strings.ToLower(s[start:i]))
+			start = -1
+		}
+		if i < len(s) && isLetter(s[i]) && start < 0 {
+			start = i
+		}
+	}
+	return words
+}
+
+func isUpper(c byte) bool  { return c >= 'A' && c <= 'Z' }
+func isLetter(c byte) bool { return isUpper(c) || c >= 'a' && c <= 'z' }
+
+// trailingStyleWords are the capitalized words splitTrailingStyle
+// removes from the end of a name without a style separator.
+var trailingStyleWords = []string{"Bold", "Italic", "Oblique", "Light", "Medium", "Regular"}
+
+// splitTrailingStyle splits the style words off the end of a name such as
+// ArialBoldItalic.
+func splitTrailingStyle(name string) (family, style string) {
+	family = name
+	for {
+		found := false
+		for _, w := range trailingStyleWords {
+			if s, ok := strings.CutSuffix(family, w); ok && s != "" {
+				family, style = s, w+style
+				found = true
+				break
+			}
+		}
+		if !found {
+			return family, style
+		}
+	}
+}
+
+// PostScriptName returns the font's /BaseFont without its subset prefix.
+func (f *Font) PostScriptName() string {
+	return StripSubsetPrefix(f.BaseFont)
+}
+
+// FontName returns the breakdown of the font's /BaseFont.
+func (f *Font) FontName() FontName {
+	return ParseFontName(f.BaseFont)
+}
@@ -0,0 +1,112 @@
+package font
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sync"
+)
+
+// ParseCache shares parsed streams between the fonts of one document:
+// ToUnicode CMaps, embedded encoding CMaps, /CIDToGIDMap streams and font
+// programs are parsed once per distinct content, however many fonts or
+// pages refer to them. Parsed values are shared, not copied. It is safe
+// for concurrent use; the zero value is not, use NewParseCache.
+type ParseCache struct {
+	mu      sync.Mutex
+	entries map[parseKey]any
+}
+
+// parseKey identifies a stream by what it is parsed as and the SHA-256 of
+// its contents.
+type parseKey struct {
+	kind string
+	sum  [sha256.Size]byte
+}
+
+// parseResult is a cached parse, kept with its error so that streams that
+// do not parse are not parsed again either.
+type parseResult[T any] struct {
+	value T
+	err   error
+}
+
+// cffProgram is the result of ParseCFFEncoding.
+type cffProgram struct {
+	enc     *GlyphEncoding
+	charset []string
+}
+
+// NewParseCache creates an empty parse cache.
+func NewParseCache() *ParseCache {
+	return &ParseCache{entries: make(map[parseKey]any)}
+}
+
+// Len returns the number of cached streams.
+func (c *ParseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// RegistryFromResources is like the package-level RegistryFromResources
+// but parses each distinct stream only once.
+func (c *ParseCache) RegistryFromResources(dict map[string]any, fetch StreamFetcher) (*FontRegistry, error) {
+	return registryFromResources(dict, fetch, c)
+}
+
+// cachedParse returns parse(data), reusing an earlier result for the same
+// kind and contents. A nil cache parses every time.
+func cachedParse[T any](c *ParseCache, kind string, data []byte, parse func([]byte) (T, error)) (T, error) {
+	if c == nil {
+		return parse(data)
+	}
+	key := parseKey{kind: kind, sum: sha256.Sum256(data)}
+	c.mu.Lock()
+	cached, ok := c.entries[key].(parseResult[T])
+	c.mu.Unlock()
+	if ok {
+		return cached.value, cached.err
+	}
+	// Parsed outside the lock: two goroutines may parse the same stream,
+	// and the last result is kept
+	value, err := parse(data)
+	c.mu.Lock()
+	c.entries[key] = parseResult[T]{value: value, err: err}
+	c.mu.Unlock()
+	return value, err
+}
+
+func (c *ParseCache) toUnicodeCMap(data []byte) (*CMap, error) {
+	return cachedParse(c, "ToUnicode", data, func(data []byte) (*CMap, error) {
+		return ParseToUnicodeCMap(bytes.NewReader(data))
+	})
+}
+
+func (c *ParseCache) encodingCMap(data []byte) (*EncodingCMap, error) {
+	return cachedParse(c, "Encoding", data, func(data []byte) (*EncodingCMap, error) {
+		return ParseEncodingCMap(bytes.NewReader(data))
+	})
+}
+
+func (c *ParseCache) cidToGIDMap(data []byte) []uint16 {
+	gids, _ := cachedParse(c, "CIDToGIDMap", data, func(data []byte) ([]uint16, error) {
+		return ParseCIDToGIDMap(data), nil
+	})
+	return gids
+}
+
+func (c *ParseCache) trueTypeCmap(data []byte) (*TrueTypeCmap, error) {
+	return cachedParse(c, "TrueType", data, ParseTrueTypeCmap)
+}
+
+func (c *ParseCache) type1Encoding(data []byte) (*GlyphEncoding, error) {
+	return cachedParse(c, "Type1", data, ParseType1Encoding)
+}
+
+func (c *ParseCache) cffEncoding(data []byte) (*GlyphEncoding, []string, error) {
+	program, err := cachedParse(c, "CFF", data, func(data []byte) (cffProgram, error) {
+		enc, charset, err := ParseCFFEncoding(data)
+		return cffProgram{enc: enc, charset: charset}, err
+	})
+	return program.enc, program.charset, err
+}
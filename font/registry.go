@@ -2,17 +2,26 @@ package font
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
 // FontRegistry manages a collection of fonts and provides lookup by name.
 // It's safe for concurrent use.
+//
+// Font resource names are scoped: /F1 may name different fonts on
+// different pages and in form XObjects. Push layers the fonts of a nested
+// resource dictionary over a registry, and Pop returns to the enclosing
+// scope.
 type FontRegistry struct {
 	mu    sync.RWMutex
 	fonts map[string]*Font
 
 	// Default font used when a font is not found
 	defaultFont *Font
+
+	// parent is the enclosing scope, for registries created by Push
+	parent *FontRegistry
 }
 
 // NewFontRegistry creates a new font registry.
@@ -52,16 +61,48 @@ func (fr *FontRegistry) RegisterWithToUnicode(name string, cmap *CMap) *Font {
 	return font
 }
 
-// Lookup retrieves a font by name.
+// Lookup retrieves a font by name, searching the enclosing scopes of a
+// registry created by Push after its own fonts.
 // If the font is not found, returns the default font and false.
 func (fr *FontRegistry) Lookup(name string) (*Font, bool) {
+	for scope := fr; scope != nil; scope = scope.parent {
+		scope.mu.RLock()
+		font, ok := scope.fonts[name]
+		scope.mu.RUnlock()
+		if ok {
+			return font, true
+		}
+	}
 	fr.mu.RLock()
 	defer fr.mu.RUnlock()
+	return fr.defaultFont, false
+}
 
-	if font, ok := fr.fonts[name]; ok {
-		return font, true
+// Push returns a registry for a nested resource scope, such as the
+// /Resources of a form XObject: it holds the fonts of scope, and looks up
+// names it does not define in fr. Neither fr nor scope is modified, so a
+// registry shared between pages can be pushed onto concurrently.
+//
+// Count, List, Each and Clear of the returned registry see only the
+// fonts of scope.
+func (fr *FontRegistry) Push(scope *FontRegistry) *FontRegistry {
+	child := &FontRegistry{fonts: make(map[string]*Font), parent: fr}
+	fr.mu.RLock()
+	child.defaultFont = fr.defaultFont
+	fr.mu.RUnlock()
+	if scope != nil {
+		scope.Each(func(name string, font *Font) bool {
+			child.fonts[name] = font
+			return true
+		})
 	}
-	return fr.defaultFont, false
+	return child
+}
+
+// Pop returns the enclosing scope of a registry created by Push, or nil
+// for a registry that is not nested.
+func (fr *FontRegistry) Pop() *FontRegistry {
+	return fr.parent
 }
 
 // MustLookup retrieves a font by name, returning the default font if not found.
@@ -84,18 +125,42 @@ func (fr *FontRegistry) Count() int {
 	return len(fr.fonts)
 }
 
-// List returns a slice of all registered font names.
+// List returns the names of all registered fonts, sorted.
 func (fr *FontRegistry) List() []string {
 	fr.mu.RLock()
 	defer fr.mu.RUnlock()
+	return fr.sortedNames()
+}
 
+// sortedNames returns the registered names in order. The caller must hold mu.
+func (fr *FontRegistry) sortedNames() []string {
 	names := make([]string, 0, len(fr.fonts))
 	for name := range fr.fonts {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
+// Each calls visit for every registered font in name order, stopping early
+// if visit returns false. It works on a snapshot, so visit may call other
+// registry methods; fonts registered meanwhile are not visited.
+func (fr *FontRegistry) Each(visit func(name string, font *Font) bool) {
+	fr.mu.RLock()
+	names := fr.sortedNames()
+	fonts := make([]*Font, len(names))
+	for i, name := range names {
+		fonts[i] = fr.fonts[name]
+	}
+	fr.mu.RUnlock()
+
+	for i, name := range names {
+		if !visit(name, fonts[i]) {
+			return
+		}
+	}
+}
+
 // Clear removes all registered fonts.
 func (fr *FontRegistry) Clear() {
 	fr.mu.Lock()
@@ -108,5 +173,5 @@ func (fr *FontRegistry) String() string {
 	fr.mu.RLock()
 	defer fr.mu.RUnlock()
 
-	return fmt.Sprintf("FontRegistry with %d fonts: %v", len(fr.fonts), fr.List())
+	return fmt.Sprintf("FontRegistry with %d fonts: %v", len(fr.fonts), fr.sortedNames())
 }
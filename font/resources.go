@@ -0,0 +1,197 @@
+package font
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// StreamFetcher returns the decoded contents of a stream found in the
+// resources passed to RegistryFromResources. stream is the value of the
+// entry holding it, such as /ToUnicode or /FontFile2: a reference or
+// stream object in the caller's own representation, or the stream's
+// dictionary (as a parser.Dict).
+type StreamFetcher func(stream any) ([]byte, error)
+
+// maxResourceDepth bounds the nesting of the resource dictionaries
+// RegistryFromResources converts.
+const maxResourceDepth = 32
+
+// RegistryFromResources builds a font registry from a decoded /Resources
+// dictionary in plain Go types: the operand types of the parser (see
+// parser.Dict), with map[string]any accepted for dictionaries and
+// indirect objects resolved. Each entry of its /Font subdictionary is
+// registered under its resource name; entries that are not dictionaries
+// are skipped.
+//
+// Fonts are built with FromPDFDict, and fetch supplies the streams it
+// leaves out: ToUnicode CMaps, embedded encoding CMaps, /CIDToGIDMap
+// streams and embedded font programs. A nil fetch skips them. Errors from
+// fetch are returned; a stream that does not parse is ignored, and the
+// font falls back to its encoding. To parse streams shared between
+// fonts or pages only once, use a ParseCache.
+func RegistryFromResources(dict map[string]any, fetch StreamFetcher) (*FontRegistry, error) {
+	return registryFromResources(dict, fetch, nil)
+}
+
+// registryFromResources implements RegistryFromResources, parsing streams
+// through cache if it is not nil.
+func registryFromResources(dict map[string]any, fetch StreamFetcher, cache *ParseCache) (*FontRegistry, error) {
+	registry := NewFontRegistry()
+	fonts, ok := plainValue(dict["Font"], 0).(parser.Dict)
+	if !ok {
+		return registry, nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(fonts)) {
+		d, ok := fonts[name].(parser.Dict)
+		if !ok {
+			continue
+		}
+		f, err := fontFromResources(name, d, fetch, cache)
+		if err != nil {
+			return nil, fmt.Errorf("font %s: %w", name, err)
+		}
+		registry.Register(f)
+	}
+	return registry, nil
+}
+
+// plainValue converts the map[string]any dictionaries in v, at any depth,
+// to parser.Dict.
+func plainValue(v any, depth int) any {
+	if depth > maxResourceDepth {
+		return nil
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		return plainDict(v, depth)
+	case parser.Dict:
+		return plainDict(v, depth)
+	case []any:
+		arr := make([]any, len(v))
+		for i, elem := range v {
+			arr[i] = plainValue(elem, depth+1)
+		}
+		return arr
+	}
+	return v
+}
+
+func plainDict(m map[string]any, depth int) parser.Dict {
+	d := make(parser.Dict, len(m))
+	for key, v := range m {
+		d[key] = plainValue(v, depth+1)
+	}
+	return d
+}
+
+// fontFromResources builds a font with FromPDFDict and reads its streams
+// through fetch, parsing them through cache if it is not nil.
+func fontFromResources(name string, d parser.Dict, fetch StreamFetcher, cache *ParseCache) (*Font, error) {
+	f, err := FromPDFDict(name, d)
+	if err != nil || fetch == nil {
+		return f, err
+	}
+
+	subtype, _ := d.String("Subtype")
+	programDict := d
+	if subtype == "Type0" {
+		if enc, ok := d["Encoding"]; ok {
+			if _, isName := enc.(string); !isName {
+				data, err := fetch(enc)
+				if err != nil {
+					return nil, fmt.Errorf("encoding CMap: %w", err)
+				}
+				if cmap, err := cache.encodingCMap(data); err == nil {
+					f.EncodingCMap = cmap
+					f.Vertical = cmap.Vertical
+				}
+			}
+		}
+		descendants, _ := d.Array("DescendantFonts")
+		cidFont, _ := firstElement(descendants).(parser.Dict)
+		if gidMap, ok := cidFont["CIDToGIDMap"]; ok && f.CIDFont != nil {
+			if _, isName := gidMap.(string); !isName {
+				data, err := fetch(gidMap)
+				if err != nil {
+					return nil, fmt.Errorf("CIDToGIDMap: %w", err)
+				}
+				f.CIDFont.CIDToGID = cache.cidToGIDMap(data)
+			}
+		}
+		programDict = cidFont
+	}
+
+	if descriptor, ok := programDict.Dict("FontDescriptor"); ok {
+		if err := loadFontProgram(f, descriptor, fetch, cache); err != nil {
+			return nil, err
+		}
+	}
+
+	if toUnicode, ok := d["ToUnicode"]; ok {
+		if _, isName := toUnicode.(string); !isName {
+			data, err := fetch(toUnicode)
+			if err != nil {
+				return nil, fmt.Errorf("ToUnicode: %w", err)
+			}
+			if cmap, err := cache.toUnicodeCMap(data); err == nil {
+				f.ToUnicode = cmap
+			}
+		}
+	}
+	return f, nil
+}
+
+// loadFontProgram reads the embedded font program of a font descriptor:
+// the cmap table of a TrueType or OpenType program, or the built-in
+// encoding and charset of a Type 1 or CFF program. A /FontFile3 stream
+// given other than as its dictionary is told apart by its contents.
+func loadFontProgram(f *Font, descriptor parser.Dict, fetch StreamFetcher, cache *ParseCache) error {
+	for _, key := range []string{"FontFile2", "FontFile3", "FontFile"} {
+		stream, ok := descriptor[key]
+		if !ok {
+			continue
+		}
+		data, err := fetch(stream)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		program := key
+		if key == "FontFile3" {
+			if sd, ok := stream.(parser.Dict); ok {
+				program, _ = sd.String("Subtype")
+			} else if bytes.HasPrefix(data, []byte("OTTO")) {
+				program = "OpenType"
+			} else {
+				program = "Type1C"
+			}
+		}
+		switch program {
+		case "FontFile2", "OpenType":
+			if cmap, err := cache.trueTypeCmap(data); err == nil {
+				f.EmbeddedCmap = cmap
+			}
+		case "FontFile":
+			if enc, err := cache.type1Encoding(data); err == nil {
+				f.BuiltinEncoding = enc
+			}
+		case "Type1C", "CIDFontType0C":
+			if enc, charset, err := cache.cffEncoding(data); err == nil {
+				f.BuiltinEncoding, f.Charset = enc, charset
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// firstElement returns the first element of arr, or nil if it is empty.
+func firstElement(arr []any) any {
+	if len(arr) == 0 {
+		return nil
+	}
+	return arr[0]
+}
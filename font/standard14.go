@@ -0,0 +1,151 @@
+package font
+
+import "sort"
+
+// StandardFont holds the metrics of one of the standard 14 fonts, which
+// PDF readers provide, so documents may use them without embedding them
+// or giving their widths.
+type StandardFont struct {
+	// Name is the PostScript name, e.g. Helvetica-Bold.
+	Name string
+
+	// glyphs are the glyph names, sorted, and widths their widths.
+	glyphs []string
+	widths []uint16
+
+	// encoding is the built-in encoding of Symbol and ZapfDingbats; the
+	// Latin fonts use StandardEncoding.
+	encoding *GlyphEncoding
+}
+
+// standardFonts are the standard 14 fonts by name.
+var standardFonts = map[string]*StandardFont{
+	"Courier":               &courier,
+	"Courier-Bold":          &courierBold,
+	"Courier-BoldOblique":   &courierBoldOblique,
+	"Courier-Oblique":       &courierOblique,
+	"Helvetica":             &helvetica,
+	"Helvetica-Bold":        &helveticaBold,
+	"Helvetica-BoldOblique": &helveticaBoldOblique,
+	"Helvetica-Oblique":     &helveticaOblique,
+	"Times-Roman":           &timesRoman,
+	"Times-Bold":            &timesBold,
+	"Times-BoldItalic":      &timesBoldItalic,
+	"Times-Italic":          &timesItalic,
+	"Symbol":                &symbol,
+	"ZapfDingbats":          &zapfDingbats,
+}
+
+// standardFontAliases maps other names of the standard fonts, the
+// "Family,Style" names of ISO 32000-1, 9.6.2.2 and the names of their
+// metric-compatible Windows counterparts, to the standard names.
+var standardFontAliases = map[string]string{
+	"Arial":                        "Helvetica",
+	"Arial,Bold":                   "Helvetica-Bold",
+	"Arial,BoldItalic":             "Helvetica-BoldOblique",
+	"Arial,Italic":                 "Helvetica-Oblique",
+	"Arial-Bold":                   "Helvetica-Bold",
+	"Arial-BoldItalic":             "Helvetica-BoldOblique",
+	"Arial-BoldItalicMT":           "Helvetica-BoldOblique",
+	"Arial-BoldMT":                 "Helvetica-Bold",
+	"Arial-Italic":                 "Helvetica-Oblique",
+	"Arial-ItalicMT":               "Helvetica-Oblique",
+	"ArialMT":                      "Helvetica",
+	"Courier,Bold":                 "Courier-Bold",
+	"Courier,BoldItalic":           "Courier-BoldOblique",
+	"Courier,Italic":               "Courier-Oblique",
+	"Courier-BoldItalic":           "Courier-BoldOblique",
+	"Courier-Italic":               "Courier-Oblique",
+	"CourierNew":                   "Courier",
+	"CourierNew,Bold":              "Courier-Bold",
+	"CourierNew,BoldItalic":        "Courier-BoldOblique",
+	"CourierNew,Italic":            "Courier-Oblique",
+	"CourierNewPS-BoldItalicMT":    "Courier-BoldOblique",
+	"CourierNewPS-BoldMT":          "Courier-Bold",
+	"CourierNewPS-ItalicMT":        "Courier-Oblique",
+	"CourierNewPSMT":               "Courier",
+	"Helvetica,Bold":               "Helvetica-Bold",
+	"Helvetica,BoldItalic":         "Helvetica-BoldOblique",
+	"Helvetica,Italic":             "Helvetica-Oblique",
+	"Helvetica-BoldItalic":         "Helvetica-BoldOblique",
+	"Helvetica-Italic":             "Helvetica-Oblique",
+	"Symbol,Bold":                  "Symbol",
+	"Symbol,BoldItalic":            "Symbol",
+	"Symbol,Italic":                "Symbol",
+	"Times":                        "Times-Roman",
+	"Times,Bold":                   "Times-Bold",
+	"Times,BoldItalic":             "Times-BoldItalic",
+	"Times,Italic":                 "Times-Italic",
+	"TimesNewRoman":                "Times-Roman",
+	"TimesNewRoman,Bold":           "Times-Bold",
+	"TimesNewRoman,BoldItalic":     "Times-BoldItalic",
+	"TimesNewRoman,Italic":         "Times-Italic",
+	"TimesNewRomanPS-BoldItalicMT": "Times-BoldItalic",
+	"TimesNewRomanPS-BoldMT":       "Times-Bold",
+	"TimesNewRomanPS-ItalicMT":     "Times-Italic",
+	"TimesNewRomanPSMT":            "Times-Roman",
+}
+
+// StandardFontMetrics returns the metrics of the standard font a
+// /BaseFont names: one of the standard 14 fonts, or an alias such as
+// Arial or TimesNewRoman,Bold. A subset prefix (ABCDEF+) is ignored.
+func StandardFontMetrics(baseFont string) (*StandardFont, bool) {
+	baseFont = StripSubsetPrefix(baseFont)
+	if alias, ok := standardFontAliases[baseFont]; ok {
+		baseFont = alias
+	}
+	f, ok := standardFonts[baseFont]
+	return f, ok
+}
+
+// IsSymbolic reports whether the font is Symbol or ZapfDingbats, whose
+// codes are always read through their built-in encodings.
+func (s *StandardFont) IsSymbolic() bool {
+	return s.encoding != nil
+}
+
+// EncodingType returns the encoding type of a symbolic font's built-in
+// encoding, EncodingSymbol or EncodingZapfDingbats, and EncodingUnknown
+// for the Latin fonts.
+func (s *StandardFont) EncodingType() EncodingType {
+	switch s.encoding {
+	case &symbolEncoding:
+		return EncodingSymbol
+	case &zapfDingbatsEncoding:
+		return EncodingZapfDingbats
+	}
+	return EncodingUnknown
+}
+
+// Encoding returns the font's built-in encoding: StandardEncoding for
+// the Latin fonts.
+func (s *StandardFont) Encoding() *GlyphEncoding {
+	if s.encoding != nil {
+		return s.encoding
+	}
+	return &standardEncoding
+}
+
+// GlyphWidth returns the width of the named glyph in thousandths of an em.
+func (s *StandardFont) GlyphWidth(name string) (float64, bool) {
+	i := sort.SearchStrings(s.glyphs, name)
+	if i == len(s.glyphs) || s.glyphs[i] != name {
+		return 0, false
+	}
+	return float64(s.widths[i]), true
+}
+
+// glyphName returns the name of the glyph a code selects in the font:
+// through the named base encoding of a Latin font, and the built-in
+// encoding otherwise.
+func (s *StandardFont) glyphName(code byte, enc EncodingType) string {
+	if !s.IsSymbolic() {
+		switch enc {
+		case EncodingWinAnsi:
+			return winAnsiEncoding[code]
+		case EncodingMacRoman:
+			return macRomanEncoding[code]
+		}
+	}
+	return s.Encoding()[code]
+}
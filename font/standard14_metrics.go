@@ -0,0 +1,899 @@
+package font
+
+// The widths below are from the Adobe Core14 AFM files, in thousandths of
+// an em, in the order of the glyph-name lists.
+
+// latinGlyphs are the glyph names of the Latin standard fonts, sorted.
+var latinGlyphs = []string{
+	"A", "AE", "Aacute", "Abreve", "Acircumflex", "Adieresis", "Agrave",
+	"Amacron", "Aogonek", "Aring", "Atilde", "B", "C", "Cacute", "Ccaron",
+	"Ccedilla", "D", "Dcaron", "Dcroat", "Delta", "E", "Eacute", "Ecaron",
+	"Ecircumflex", "Edieresis", "Edotaccent", "Egrave", "Emacron", "Eogonek",
+	"Eth", "Euro", "F", "G", "Gbreve", "Gcommaaccent", "H", "I", "Iacute",
+	"Icircumflex", "Idieresis", "Idotaccent", "Igrave", "Imacron", "Iogonek",
+	"J", "K", "Kcommaaccent", "L", "Lacute", "Lcaron", "Lcommaaccent",
+	"Lslash", "M", "N", "Nacute", "Ncaron", "Ncommaaccent", "Ntilde", "O",
+	"OE", "Oacute", "Ocircumflex", "Odieresis", "Ograve", "Ohungarumlaut",
+	"Omacron", "Oslash", "Otilde", "P", "Q", "R", "Racute", "Rcaron",
+	"Rcommaaccent", "S", "Sacute", "Scaron", "Scedilla", "Scommaaccent", "T",
+	"Tcaron", "Tcommaaccent", "Thorn", "U", "Uacute", "Ucircumflex",
+	"Udieresis", "Ugrave", "Uhungarumlaut", "Umacron", "Uogonek", "Uring",
+	"V", "W", "X", "Y", "Yacute", "Ydieresis", "Z", "Zacute", "Zcaron",
+	"Zdotaccent", "a", "aacute", "abreve", "acircumflex", "acute",
+	"adieresis", "ae", "agrave", "amacron", "ampersand", "aogonek", "aring",
+	"asciicircum", "asciitilde", "asterisk", "at", "atilde", "b", "backslash",
+	"bar", "braceleft", "braceright", "bracketleft", "bracketright", "breve",
+	"brokenbar", "bullet", "c", "cacute", "caron", "ccaron", "ccedilla",
+	"cedilla", "cent", "circumflex", "colon", "comma", "commaaccent",
+	"copyright", "currency", "d", "dagger", "daggerdbl", "dcaron", "dcroat",
+	"degree", "dieresis", "divide", "dollar", "dotaccent", "dotlessi", "e",
+	"eacute", "ecaron", "ecircumflex", "edieresis", "edotaccent", "egrave",
+	"eight", "ellipsis", "emacron", "emdash", "endash", "eogonek", "equal",
+	"eth", "exclam", "exclamdown", "f", "fi", "five", "fl", "florin", "four",
+	"fraction", "g", "gbreve", "gcommaaccent", "germandbls", "grave",
+	"greater", "greaterequal", "guillemotleft", "guillemotright",
+	"guilsinglleft", "guilsinglright", "h", "hungarumlaut", "hyphen", "i",
+	"iacute", "icircumflex", "idieresis", "igrave", "imacron", "iogonek", "j",
+	"k", "kcommaaccent", "l", "lacute", "lcaron", "lcommaaccent", "less",
+	"lessequal", "logicalnot", "lozenge", "lslash", "m", "macron", "minus",
+	"mu", "multiply", "n", "nacute", "ncaron", "ncommaaccent", "nine",
+	"notequal", "ntilde", "numbersign", "o", "oacute", "ocircumflex",
+	"odieresis", "oe", "ogonek", "ograve", "ohungarumlaut", "omacron", "one",
+	"onehalf", "onequarter", "onesuperior", "ordfeminine", "ordmasculine",
+	"oslash", "otilde", "p", "paragraph", "parenleft", "parenright",
+	"partialdiff", "percent", "period", "periodcentered", "perthousand",
+	"plus", "plusminus", "q", "question", "questiondown", "quotedbl",
+	"quotedblbase", "quotedblleft", "quotedblright", "quoteleft",
+	"quoteright", "quotesinglbase", "quotesingle", "r", "racute", "radical",
+	"rcaron", "rcommaaccent", "registered", "ring", "s", "sacute", "scaron",
+	"scedilla", "scommaaccent", "section", "semicolon", "seven", "six",
+	"slash", "space", "sterling", "summation", "t", "tcaron", "tcommaaccent",
+	"thorn", "three", "threequarters", "threesuperior", "tilde", "trademark",
+	"two", "twosuperior", "u", "uacute", "ucircumflex", "udieresis", "ugrave",
+	"uhungarumlaut", "umacron", "underscore", "uogonek", "uring", "v", "w",
+	"x", "y", "yacute", "ydieresis", "yen", "z", "zacute", "zcaron",
+	"zdotaccent", "zero",
+}
+
+var courier = StandardFont{
+	Name:   "Courier",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+	},
+}
+
+var courierBold = StandardFont{
+	Name:   "Courier-Bold",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+	},
+}
+
+var courierBoldOblique = StandardFont{
+	Name:   "Courier-BoldOblique",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+	},
+}
+
+var courierOblique = StandardFont{
+	Name:   "Courier-Oblique",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+		600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600, 600,
+	},
+}
+
+var helvetica = StandardFont{
+	Name:   "Helvetica",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		667, 1000, 667, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722, 722, 722,
+		722, 722, 722, 722, 612, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722,
+		556, 611, 778, 778, 778, 722, 278, 278, 278, 278, 278, 278, 278, 278, 500,
+		667, 667, 556, 556, 556, 556, 556, 833, 722, 722, 722, 722, 722, 778, 1000,
+		778, 778, 778, 778, 778, 778, 778, 778, 667, 778, 722, 722, 722, 722, 667,
+		667, 667, 667, 667, 611, 611, 611, 667, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 667, 944, 667, 667, 667, 667, 611, 611, 611, 611, 556, 556, 556,
+		556, 333, 556, 889, 556, 556, 667, 556, 556, 469, 584, 389, 1015, 556, 556,
+		278, 260, 334, 334, 278, 278, 333, 260, 350, 500, 500, 333, 500, 500, 333,
+		556, 333, 278, 278, 250, 737, 556, 556, 556, 556, 643, 556, 400, 333, 584,
+		556, 333, 278, 556, 556, 556, 556, 556, 556, 556, 556, 1000, 556, 1000,
+		556, 556, 584, 556, 278, 333, 278, 500, 556, 500, 556, 556, 167, 556, 556,
+		556, 611, 333, 584, 549, 556, 556, 333, 333, 556, 333, 333, 222, 278, 278,
+		278, 278, 278, 222, 222, 500, 500, 222, 222, 299, 222, 584, 549, 584, 471,
+		222, 833, 333, 584, 556, 584, 556, 556, 556, 556, 556, 549, 556, 556, 556,
+		556, 556, 556, 944, 333, 556, 556, 556, 556, 834, 834, 333, 370, 365, 611,
+		556, 556, 537, 333, 333, 476, 889, 278, 278, 1000, 584, 584, 556, 556, 611,
+		355, 333, 333, 333, 222, 222, 222, 191, 333, 333, 453, 333, 333, 737, 333,
+		500, 500, 500, 500, 500, 556, 278, 556, 556, 278, 278, 556, 600, 278, 317,
+		278, 556, 556, 834, 333, 333, 1000, 556, 333, 556, 556, 556, 556, 556, 556,
+		556, 556, 556, 556, 500, 722, 500, 500, 500, 500, 556, 500, 500, 500, 500,
+		556,
+	},
+}
+
+var helveticaBold = StandardFont{
+	Name:   "Helvetica-Bold",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		722, 1000, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 722, 722, 612, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722,
+		556, 611, 778, 778, 778, 722, 278, 278, 278, 278, 278, 278, 278, 278, 556,
+		722, 722, 611, 611, 611, 611, 611, 833, 722, 722, 722, 722, 722, 778, 1000,
+		778, 778, 778, 778, 778, 778, 778, 778, 667, 778, 722, 722, 722, 722, 667,
+		667, 667, 667, 667, 611, 611, 611, 667, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 667, 944, 667, 667, 667, 667, 611, 611, 611, 611, 556, 556, 556,
+		556, 333, 556, 889, 556, 556, 722, 556, 556, 584, 584, 389, 975, 556, 611,
+		278, 280, 389, 389, 333, 333, 333, 280, 350, 556, 556, 333, 556, 556, 333,
+		556, 333, 333, 278, 250, 737, 556, 611, 556, 556, 743, 611, 400, 333, 584,
+		556, 333, 278, 556, 556, 556, 556, 556, 556, 556, 556, 1000, 556, 1000,
+		556, 556, 584, 611, 333, 333, 333, 611, 556, 611, 556, 556, 167, 611, 611,
+		611, 611, 333, 584, 549, 556, 556, 333, 333, 611, 333, 333, 278, 278, 278,
+		278, 278, 278, 278, 278, 556, 556, 278, 278, 400, 278, 584, 549, 584, 494,
+		278, 889, 333, 584, 611, 584, 611, 611, 611, 611, 556, 549, 611, 556, 611,
+		611, 611, 611, 944, 333, 611, 611, 611, 556, 834, 834, 333, 370, 365, 611,
+		611, 611, 556, 333, 333, 494, 889, 278, 278, 1000, 584, 584, 611, 611, 611,
+		474, 500, 500, 500, 278, 278, 278, 238, 389, 389, 549, 389, 389, 737, 333,
+		556, 556, 556, 556, 556, 556, 333, 556, 556, 278, 278, 556, 600, 333, 389,
+		333, 611, 556, 834, 333, 333, 1000, 556, 333, 611, 611, 611, 611, 611, 611,
+		611, 556, 611, 611, 556, 778, 556, 556, 556, 556, 556, 500, 500, 500, 500,
+		556,
+	},
+}
+
+var helveticaBoldOblique = StandardFont{
+	Name:   "Helvetica-BoldOblique",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		722, 1000, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 722, 722, 612, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722,
+		556, 611, 778, 778, 778, 722, 278, 278, 278, 278, 278, 278, 278, 278, 556,
+		722, 722, 611, 611, 611, 611, 611, 833, 722, 722, 722, 722, 722, 778, 1000,
+		778, 778, 778, 778, 778, 778, 778, 778, 667, 778, 722, 722, 722, 722, 667,
+		667, 667, 667, 667, 611, 611, 611, 667, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 667, 944, 667, 667, 667, 667, 611, 611, 611, 611, 556, 556, 556,
+		556, 333, 556, 889, 556, 556, 722, 556, 556, 584, 584, 389, 975, 556, 611,
+		278, 280, 389, 389, 333, 333, 333, 280, 350, 556, 556, 333, 556, 556, 333,
+		556, 333, 333, 278, 250, 737, 556, 611, 556, 556, 743, 611, 400, 333, 584,
+		556, 333, 278, 556, 556, 556, 556, 556, 556, 556, 556, 1000, 556, 1000,
+		556, 556, 584, 611, 333, 333, 333, 611, 556, 611, 556, 556, 167, 611, 611,
+		611, 611, 333, 584, 549, 556, 556, 333, 333, 611, 333, 333, 278, 278, 278,
+		278, 278, 278, 278, 278, 556, 556, 278, 278, 400, 278, 584, 549, 584, 494,
+		278, 889, 333, 584, 611, 584, 611, 611, 611, 611, 556, 549, 611, 556, 611,
+		611, 611, 611, 944, 333, 611, 611, 611, 556, 834, 834, 333, 370, 365, 611,
+		611, 611, 556, 333, 333, 494, 889, 278, 278, 1000, 584, 584, 611, 611, 611,
+		474, 500, 500, 500, 278, 278, 278, 238, 389, 389, 549, 389, 389, 737, 333,
+		556, 556, 556, 556, 556, 556, 333, 556, 556, 278, 278, 556, 600, 333, 389,
+		333, 611, 556, 834, 333, 333, 1000, 556, 333, 611, 611, 611, 611, 611, 611,
+		611, 556, 611, 611, 556, 778, 556, 556, 556, 556, 556, 500, 500, 500, 500,
+		556,
+	},
+}
+
+var helveticaOblique = StandardFont{
+	Name:   "Helvetica-Oblique",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		667, 1000, 667, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722, 722, 722,
+		722, 722, 722, 722, 612, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722,
+		556, 611, 778, 778, 778, 722, 278, 278, 278, 278, 278, 278, 278, 278, 500,
+		667, 667, 556, 556, 556, 556, 556, 833, 722, 722, 722, 722, 722, 778, 1000,
+		778, 778, 778, 778, 778, 778, 778, 778, 667, 778, 722, 722, 722, 722, 667,
+		667, 667, 667, 667, 611, 611, 611, 667, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 667, 944, 667, 667, 667, 667, 611, 611, 611, 611, 556, 556, 556,
+		556, 333, 556, 889, 556, 556, 667, 556, 556, 469, 584, 389, 1015, 556, 556,
+		278, 260, 334, 334, 278, 278, 333, 260, 350, 500, 500, 333, 500, 500, 333,
+		556, 333, 278, 278, 250, 737, 556, 556, 556, 556, 643, 556, 400, 333, 584,
+		556, 333, 278, 556, 556, 556, 556, 556, 556, 556, 556, 1000, 556, 1000,
+		556, 556, 584, 556, 278, 333, 278, 500, 556, 500, 556, 556, 167, 556, 556,
+		556, 611, 333, 584, 549, 556, 556, 333, 333, 556, 333, 333, 222, 278, 278,
+		278, 278, 278, 222, 222, 500, 500, 222, 222, 299, 222, 584, 549, 584, 471,
+		222, 833, 333, 584, 556, 584, 556, 556, 556, 556, 556, 549, 556, 556, 556,
+		556, 556, 556, 944, 333, 556, 556, 556, 556, 834, 834, 333, 370, 365, 611,
+		556, 556, 537, 333, 333, 476, 889, 278, 278, 1000, 584, 584, 556, 556, 611,
+		355, 333, 333, 333, 222, 222, 222, 191, 333, 333, 453, 333, 333, 737, 333,
+		500, 500, 500, 500, 500, 556, 278, 556, 556, 278, 278, 556, 600, 278, 317,
+		278, 556, 556, 834, 333, 333, 1000, 556, 333, 556, 556, 556, 556, 556, 556,
+		556, 556, 556, 556, 500, 722, 500, 500, 500, 500, 556, 500, 500, 500, 500,
+		556,
+	},
+}
+
+var timesRoman = StandardFont{
+	Name:   "Times-Roman",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		722, 889, 722, 722, 722, 722, 722, 722, 722, 722, 722, 667, 667, 667, 667,
+		667, 722, 722, 722, 612, 611, 611, 611, 611, 611, 611, 611, 611, 611, 722,
+		500, 556, 722, 722, 722, 722, 333, 333, 333, 333, 333, 333, 333, 333, 389,
+		722, 722, 611, 611, 611, 611, 611, 889, 722, 722, 722, 722, 722, 722, 889,
+		722, 722, 722, 722, 722, 722, 722, 722, 556, 722, 667, 667, 667, 667, 556,
+		556, 556, 556, 556, 611, 611, 611, 556, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 722, 944, 722, 722, 722, 722, 611, 611, 611, 611, 444, 444, 444,
+		444, 333, 444, 667, 444, 444, 778, 444, 444, 469, 541, 500, 921, 444, 500,
+		278, 200, 480, 480, 333, 333, 333, 200, 350, 444, 444, 333, 444, 444, 333,
+		500, 333, 278, 250, 250, 760, 500, 500, 500, 500, 588, 500, 400, 333, 564,
+		500, 333, 278, 444, 444, 444, 444, 444, 444, 444, 500, 1000, 444, 1000,
+		500, 444, 564, 500, 333, 333, 333, 556, 500, 556, 500, 500, 167, 500, 500,
+		500, 500, 333, 564, 549, 500, 500, 333, 333, 500, 333, 333, 278, 278, 278,
+		278, 278, 278, 278, 278, 500, 500, 278, 278, 344, 278, 564, 549, 564, 471,
+		278, 778, 333, 564, 500, 564, 500, 500, 500, 500, 500, 549, 500, 500, 500,
+		500, 500, 500, 722, 333, 500, 500, 500, 500, 750, 750, 300, 276, 310, 500,
+		500, 500, 453, 333, 333, 476, 833, 250, 250, 1000, 564, 564, 500, 444, 444,
+		408, 444, 444, 444, 333, 333, 333, 180, 333, 333, 453, 333, 333, 760, 333,
+		389, 389, 389, 389, 389, 500, 278, 500, 500, 278, 250, 500, 600, 278, 326,
+		278, 500, 500, 750, 300, 333, 980, 500, 300, 500, 500, 500, 500, 500, 500,
+		500, 500, 500, 500, 500, 722, 500, 500, 500, 500, 500, 444, 444, 444, 444,
+		500,
+	},
+}
+
+var timesBold = StandardFont{
+	Name:   "Times-Bold",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		722, 1000, 722, 722, 722, 722, 722, 722, 722, 722, 722, 667, 722, 722, 722,
+		722, 722, 722, 722, 612, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722,
+		500, 611, 778, 778, 778, 778, 389, 389, 389, 389, 389, 389, 389, 389, 500,
+		778, 778, 667, 667, 667, 667, 667, 944, 722, 722, 722, 722, 722, 778, 1000,
+		778, 778, 778, 778, 778, 778, 778, 778, 611, 778, 722, 722, 722, 722, 556,
+		556, 556, 556, 556, 667, 667, 667, 611, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 722, 1000, 722, 722, 722, 722, 667, 667, 667, 667, 500, 500, 500,
+		500, 333, 500, 722, 500, 500, 833, 500, 500, 581, 520, 500, 930, 500, 556,
+		278, 220, 394, 394, 333, 333, 333, 220, 350, 444, 444, 333, 444, 444, 333,
+		500, 333, 333, 250, 250, 747, 500, 556, 500, 500, 672, 556, 400, 333, 570,
+		500, 333, 278, 444, 444, 444, 444, 444, 444, 444, 500, 1000, 444, 1000,
+		500, 444, 570, 500, 333, 333, 333, 556, 500, 556, 500, 500, 167, 500, 500,
+		500, 556, 333, 570, 549, 500, 500, 333, 333, 556, 333, 333, 278, 278, 278,
+		278, 278, 278, 278, 333, 556, 556, 278, 278, 394, 278, 570, 549, 570, 494,
+		278, 833, 333, 570, 556, 570, 556, 556, 556, 556, 500, 549, 556, 500, 500,
+		500, 500, 500, 722, 333, 500, 500, 500, 500, 750, 750, 300, 300, 330, 500,
+		500, 556, 540, 333, 333, 494, 1000, 250, 250, 1000, 570, 570, 556, 500,
+		500, 555, 500, 500, 500, 333, 333, 333, 278, 444, 444, 549, 444, 444, 747,
+		333, 389, 389, 389, 389, 389, 500, 333, 500, 500, 278, 250, 500, 600, 333,
+		416, 333, 556, 500, 750, 300, 333, 1000, 500, 300, 556, 556, 556, 556, 556,
+		556, 556, 500, 556, 556, 500, 722, 500, 500, 500, 500, 500, 444, 444, 444,
+		444, 500,
+	},
+}
+
+var timesBoldItalic = StandardFont{
+	Name:   "Times-BoldItalic",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		667, 944, 667, 667, 667, 667, 667, 667, 667, 667, 667, 667, 667, 667, 667,
+		667, 722, 722, 722, 612, 667, 667, 667, 667, 667, 667, 667, 667, 667, 722,
+		500, 667, 722, 722, 722, 778, 389, 389, 389, 389, 389, 389, 389, 389, 500,
+		667, 667, 611, 611, 611, 611, 611, 889, 722, 722, 722, 722, 722, 722, 944,
+		722, 722, 722, 722, 722, 722, 722, 722, 611, 722, 667, 667, 667, 667, 556,
+		556, 556, 556, 556, 611, 611, 611, 611, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 667, 889, 667, 611, 611, 611, 611, 611, 611, 611, 500, 500, 500,
+		500, 333, 500, 722, 500, 500, 778, 500, 500, 570, 570, 500, 832, 500, 500,
+		278, 220, 348, 348, 333, 333, 333, 220, 350, 444, 444, 333, 444, 444, 333,
+		500, 333, 333, 250, 250, 747, 500, 500, 500, 500, 608, 500, 400, 333, 570,
+		500, 333, 278, 444, 444, 444, 444, 444, 444, 444, 500, 1000, 444, 1000,
+		500, 444, 570, 500, 389, 389, 333, 556, 500, 556, 500, 500, 167, 500, 500,
+		500, 500, 333, 570, 549, 500, 500, 333, 333, 556, 333, 333, 278, 278, 278,
+		278, 278, 278, 278, 278, 500, 500, 278, 278, 382, 278, 570, 549, 606, 494,
+		278, 778, 333, 606, 576, 570, 556, 556, 556, 556, 500, 549, 556, 500, 500,
+		500, 500, 500, 722, 333, 500, 500, 500, 500, 750, 750, 300, 266, 300, 500,
+		500, 500, 500, 333, 333, 494, 833, 250, 250, 1000, 570, 570, 500, 500, 500,
+		555, 500, 500, 500, 333, 333, 333, 278, 389, 389, 549, 389, 389, 747, 333,
+		389, 389, 389, 389, 389, 500, 333, 500, 500, 278, 250, 500, 600, 278, 366,
+		278, 500, 500, 750, 300, 333, 1000, 500, 300, 556, 556, 556, 556, 556, 556,
+		556, 500, 556, 556, 444, 667, 500, 444, 444, 444, 500, 389, 389, 389, 389,
+		500,
+	},
+}
+
+var timesItalic = StandardFont{
+	Name:   "Times-Italic",
+	glyphs: latinGlyphs,
+	widths: []uint16{
+		611, 889, 611, 611, 611, 611, 611, 611, 611, 611, 611, 611, 667, 667, 667,
+		667, 722, 722, 722, 612, 611, 611, 611, 611, 611, 611, 611, 611, 611, 722,
+		500, 611, 722, 722, 722, 722, 333, 333, 333, 333, 333, 333, 333, 333, 444,
+		667, 667, 556, 556, 611, 556, 556, 833, 667, 667, 667, 667, 667, 722, 944,
+		722, 722, 722, 722, 722, 722, 722, 722, 611, 722, 611, 611, 611, 611, 500,
+		500, 500, 500, 500, 556, 556, 556, 611, 722, 722, 722, 722, 722, 722, 722,
+		722, 722, 611, 833, 611, 556, 556, 556, 556, 556, 556, 556, 500, 500, 500,
+		500, 333, 500, 667, 500, 500, 778, 500, 500, 422, 541, 500, 920, 500, 500,
+		278, 275, 400, 400, 389, 389, 333, 275, 350, 444, 444, 333, 444, 444, 333,
+		500, 333, 333, 250, 250, 760, 500, 500, 500, 500, 544, 500, 400, 333, 675,
+		500, 333, 278, 444, 444, 444, 444, 444, 444, 444, 500, 889, 444, 889, 500,
+		444, 675, 500, 333, 389, 278, 500, 500, 500, 500, 500, 167, 500, 500, 500,
+		500, 333, 675, 549, 500, 500, 333, 333, 500, 333, 333, 278, 278, 278, 278,
+		278, 278, 278, 278, 444, 444, 278, 278, 300, 278, 675, 549, 675, 471, 278,
+		722, 333, 675, 500, 675, 500, 500, 500, 500, 500, 549, 500, 500, 500, 500,
+		500, 500, 667, 333, 500, 500, 500, 500, 750, 750, 300, 276, 310, 500, 500,
+		500, 523, 333, 333, 476, 833, 250, 250, 1000, 675, 675, 500, 500, 500, 420,
+		556, 556, 556, 333, 333, 333, 214, 389, 389, 453, 389, 389, 760, 333, 389,
+		389, 389, 389, 389, 500, 333, 500, 500, 278, 250, 500, 600, 278, 300, 278,
+		500, 500, 750, 300, 333, 980, 500, 300, 500, 500, 500, 500, 500, 500, 500,
+		500, 500, 500, 444, 667, 444, 444, 444, 444, 500, 389, 389, 389, 389, 500,
+	},
+}
+
+var symbolGlyphs = []string{
+	"Alpha", "Beta", "Chi", "Delta", "Epsilon", "Eta", "Euro", "Gamma",
+	"Ifraktur", "Iota", "Kappa", "Lambda", "Mu", "Nu", "Omega", "Omicron",
+	"Phi", "Pi", "Psi", "Rfraktur", "Rho", "Sigma", "Tau", "Theta", "Upsilon",
+	"Upsilon1", "Xi", "Zeta", "aleph", "alpha", "ampersand", "angle",
+	"angleleft", "angleright", "apple", "approxequal", "arrowboth",
+	"arrowdblboth", "arrowdbldown", "arrowdblleft", "arrowdblright",
+	"arrowdblup", "arrowdown", "arrowhorizex", "arrowleft", "arrowright",
+	"arrowup", "arrowvertex", "asteriskmath", "bar", "beta", "braceex",
+	"braceleft", "braceleftbt", "braceleftmid", "bracelefttp", "braceright",
+	"bracerightbt", "bracerightmid", "bracerighttp", "bracketleft",
+	"bracketleftbt", "bracketleftex", "bracketlefttp", "bracketright",
+	"bracketrightbt", "bracketrightex", "bracketrighttp", "bullet",
+	"carriagereturn", "chi", "circlemultiply", "circleplus", "club", "colon",
+	"comma", "congruent", "copyrightsans", "copyrightserif", "degree",
+	"delta", "diamond", "divide", "dotmath", "eight", "element", "ellipsis",
+	"emptyset", "epsilon", "equal", "equivalence", "eta", "exclam",
+	"existential", "five", "florin", "four", "fraction", "gamma", "gradient",
+	"greater", "greaterequal", "heart", "infinity", "integral", "integralbt",
+	"integralex", "integraltp", "intersection", "iota", "kappa", "lambda",
+	"less", "lessequal", "logicaland", "logicalnot", "logicalor", "lozenge",
+	"minus", "minute", "mu", "multiply", "nine", "notelement", "notequal",
+	"notsubset", "nu", "numbersign", "omega", "omega1", "omicron", "one",
+	"parenleft", "parenleftbt", "parenleftex", "parenlefttp", "parenright",
+	"parenrightbt", "parenrightex", "parenrighttp", "partialdiff", "percent",
+	"period", "perpendicular", "phi", "phi1", "pi", "plus", "plusminus",
+	"product", "propersubset", "propersuperset", "proportional", "psi",
+	"question", "radical", "radicalex", "reflexsubset", "reflexsuperset",
+	"registersans", "registerserif", "rho", "second", "semicolon", "seven",
+	"sigma", "sigma1", "similar", "six", "slash", "space", "spade",
+	"suchthat", "summation", "tau", "therefore", "theta", "theta1", "three",
+	"trademarksans", "trademarkserif", "two", "underscore", "union",
+	"universal", "upsilon", "weierstrass", "xi", "zero", "zeta",
+}
+
+var symbolEncoding = GlyphEncoding{
+	0x20: "space",
+	0x21: "exclam",
+	0x22: "universal",
+	0x23: "numbersign",
+	0x24: "existential",
+	0x25: "percent",
+	0x26: "ampersand",
+	0x27: "suchthat",
+	0x28: "parenleft",
+	0x29: "parenright",
+	0x2A: "asteriskmath",
+	0x2B: "plus",
+	0x2C: "comma",
+	0x2D: "minus",
+	0x2E: "period",
+	0x2F: "slash",
+	0x30: "zero",
+	0x31: "one",
+	0x32: "two",
+	0x33: "three",
+	0x34: "four",
+	0x35: "five",
+	0x36: "six",
+	0x37: "seven",
+	0x38: "eight",
+	0x39: "nine",
+	0x3A: "colon",
+	0x3B: "semicolon",
+	0x3C: "less",
+	0x3D: "equal",
+	0x3E: "greater",
+	0x3F: "question",
+	0x40: "congruent",
+	0x41: "Alpha",
+	0x42: "Beta",
+	0x43: "Chi",
+	0x44: "Delta",
+	0x45: "Epsilon",
+	0x46: "Phi",
+	0x47: "Gamma",
+	0x48: "Eta",
+	0x49: "Iota",
+	0x4A: "theta1",
+	0x4B: "Kappa",
+	0x4C: "Lambda",
+	0x4D: "Mu",
+	0x4E: "Nu",
+	0x4F: "Omicron",
+	0x50: "Pi",
+	0x51: "Theta",
+	0x52: "Rho",
+	0x53: "Sigma",
+	0x54: "Tau",
+	0x55: "Upsilon",
+	0x56: "sigma1",
+	0x57: "Omega",
+	0x58: "Xi",
+	0x59: "Psi",
+	0x5A: "Zeta",
+	0x5B: "bracketleft",
+	0x5C: "therefore",
+	0x5D: "bracketright",
+	0x5E: "perpendicular",
+	0x5F: "underscore",
+	0x60: "radicalex",
+	0x61: "alpha",
+	0x62: "beta",
+	0x63: "chi",
+	0x64: "delta",
+	0x65: "epsilon",
+	0x66: "phi",
+	0x67: "gamma",
+	0x68: "eta",
+	0x69: "iota",
+	0x6A: "phi1",
+	0x6B: "kappa",
+	0x6C: "lambda",
+	0x6D: "mu",
+	0x6E: "nu",
+	0x6F: "omicron",
+	0x70: "pi",
+	0x71: "theta",
+	0x72: "rho",
+	0x73: "sigma",
+	0x74: "tau",
+	0x75: "upsilon",
+	0x76: "omega1",
+	0x77: "omega",
+	0x78: "xi",
+	0x79: "psi",
+	0x7A: "zeta",
+	0x7B: "braceleft",
+	0x7C: "bar",
+	0x7D: "braceright",
+	0x7E: "similar",
+	0xA0: "Euro",
+	0xA1: "Upsilon1",
+	0xA2: "minute",
+	0xA3: "lessequal",
+	0xA4: "fraction",
+	0xA5: "infinity",
+	0xA6: "florin",
+	0xA7: "club",
+	0xA8: "diamond",
+	0xA9: "heart",
+	0xAA: "spade",
+	0xAB: "arrowboth",
+	0xAC: "arrowleft",
+	0xAD: "arrowup",
+	0xAE: "arrowright",
+	0xAF: "arrowdown",
+	0xB0: "degree",
+	0xB1: "plusminus",
+	0xB2: "second",
+	0xB3: "greaterequal",
+	0xB4: "multiply",
+	0xB5: "proportional",
+	0xB6: "partialdiff",
+	0xB7: "bullet",
+	0xB8: "divide",
+	0xB9: "notequal",
+	0xBA: "equivalence",
+	0xBB: "approxequal",
+	0xBC: "ellipsis",
+	0xBD: "arrowvertex",
+	0xBE: "arrowhorizex",
+	0xBF: "carriagereturn",
+	0xC0: "aleph",
+	0xC1: "Ifraktur",
+	0xC2: "Rfraktur",
+	0xC3: "weierstrass",
+	0xC4: "circlemultiply",
+	0xC5: "circleplus",
+	0xC6: "emptyset",
+	0xC7: "intersection",
+	0xC8: "union",
+	0xC9: "propersuperset",
+	0xCA: "reflexsuperset",
+	0xCB: "notsubset",
+	0xCC: "propersubset",
+	0xCD: "reflexsubset",
+	0xCE: "element",
+	0xCF: "notelement",
+	0xD0: "angle",
+	0xD1: "gradient",
+	0xD2: "registerserif",
+	0xD3: "copyrightserif",
+	0xD4: "trademarkserif",
+	0xD5: "product",
+	0xD6: "radical",
+	0xD7: "dotmath",
+	0xD8: "logicalnot",
+	0xD9: "logicaland",
+	0xDA: "logicalor",
+	0xDB: "arrowdblboth",
+	0xDC: "arrowdblleft",
+	0xDD: "arrowdblup",
+	0xDE: "arrowdblright",
+	0xDF: "arrowdbldown",
+	0xE0: "lozenge",
+	0xE1: "angleleft",
+	0xE2: "registersans",
+	0xE3: "copyrightsans",
+	0xE4: "trademarksans",
+	0xE5: "summation",
+	0xE6: "parenlefttp",
+	0xE7: "parenleftex",
+	0xE8: "parenleftbt",
+	0xE9: "bracketlefttp",
+	0xEA: "bracketleftex",
+	0xEB: "bracketleftbt",
+	0xEC: "bracelefttp",
+	0xED: "braceleftmid",
+	0xEE: "braceleftbt",
+	0xEF: "braceex",
+	0xF1: "angleright",
+	0xF2: "integral",
+	0xF3: "integraltp",
+	0xF4: "integralex",
+	0xF5: "integralbt",
+	0xF6: "parenrighttp",
+	0xF7: "parenrightex",
+	0xF8: "parenrightbt",
+	0xF9: "bracketrighttp",
+	0xFA: "bracketrightex",
+	0xFB: "bracketrightbt",
+	0xFC: "bracerighttp",
+	0xFD: "bracerightmid",
+	0xFE: "bracerightbt",
+}
+
+var symbol = StandardFont{
+	Name:     "Symbol",
+	glyphs:   symbolGlyphs,
+	encoding: &symbolEncoding,
+	widths: []uint16{
+		722, 667, 722, 612, 611, 722, 750, 603, 686, 333, 722, 686, 889, 722, 768,
+		722, 763, 768, 795, 795, 556, 592, 611, 741, 690, 620, 645, 611, 823, 631,
+		778, 768, 329, 329, 790, 549, 1042, 1042, 603, 987, 987, 603, 603, 1000,
+		987, 987, 603, 603, 500, 200, 549, 494, 480, 494, 494, 494, 480, 494, 494,
+		494, 333, 384, 384, 384, 333, 384, 384, 384, 460, 658, 549, 768, 768, 753,
+		278, 250, 549, 790, 790, 400, 494, 753, 549, 250, 500, 713, 1000, 823, 439,
+		549, 549, 603, 333, 549, 500, 500, 500, 167, 411, 713, 549, 549, 753, 713,
+		274, 686, 686, 686, 768, 329, 549, 549, 549, 549, 603, 713, 603, 494, 549,
+		247, 576, 549, 500, 713, 549, 713, 521, 500, 686, 713, 549, 500, 333, 384,
+		384, 384, 333, 384, 384, 384, 494, 833, 250, 658, 521, 603, 549, 549, 549,
+		823, 713, 713, 713, 686, 444, 549, 500, 713, 713, 790, 790, 549, 411, 278,
+		500, 603, 439, 549, 500, 278, 250, 753, 439, 713, 439, 863, 521, 631, 500,
+		786, 890, 500, 500, 768, 713, 576, 987, 493, 500, 494,
+	},
+}
+
+var zapfDingbatsGlyphs = []string{
+	"a1", "a10", "a100", "a101", "a102", "a103", "a104", "a105", "a106",
+	"a107", "a108", "a109", "a11", "a110", "a111", "a112", "a117", "a118",
+	"a119", "a12", "a120", "a121", "a122", "a123", "a124", "a125", "a126",
+	"a127", "a128", "a129", "a13", "a130", "a131", "a132", "a133", "a134",
+	"a135", "a136", "a137", "a138", "a139", "a14", "a140", "a141", "a142",
+	"a143", "a144", "a145", "a146", "a147", "a148", "a149", "a15", "a150",
+	"a151", "a152", "a153", "a154", "a155", "a156", "a157", "a158", "a159",
+	"a16", "a160", "a161", "a162", "a163", "a164", "a165", "a166", "a167",
+	"a168", "a169", "a17", "a170", "a171", "a172", "a173", "a174", "a175",
+	"a176", "a177", "a178", "a179", "a18", "a180", "a181", "a182", "a183",
+	"a184", "a185", "a186", "a187", "a188", "a189", "a19", "a190", "a191",
+	"a192", "a193", "a194", "a195", "a196", "a197", "a198", "a199", "a2",
+	"a20", "a200", "a201", "a202", "a203", "a204", "a205", "a206", "a21",
+	"a22", "a23", "a24", "a25", "a26", "a27", "a28", "a29", "a3", "a30",
+	"a31", "a32", "a33", "a34", "a35", "a36", "a37", "a38", "a39", "a4",
+	"a40", "a41", "a42", "a43", "a44", "a45", "a46", "a47", "a48", "a49",
+	"a5", "a50", "a51", "a52", "a53", "a54", "a55", "a56", "a57", "a58",
+	"a59", "a6", "a60", "a61", "a62", "a63", "a64", "a65", "a66", "a67",
+	"a68", "a69", "a7", "a70", "a71", "a72", "a73", "a74", "a75", "a76",
+	"a77", "a78", "a79", "a8", "a81", "a82", "a83", "a84", "a85", "a86",
+	"a87", "a88", "a89", "a9", "a90", "a91", "a92", "a93", "a94", "a95",
+	"a96", "a97", "a98", "a99", "space",
+}
+
+var zapfDingbatsEncoding = GlyphEncoding{
+	0x20: "space",
+	0x21: "a1",
+	0x22: "a2",
+	0x23: "a202",
+	0x24: "a3",
+	0x25: "a4",
+	0x26: "a5",
+	0x27: "a119",
+	0x28: "a118",
+	0x29: "a117",
+	0x2A: "a11",
+	0x2B: "a12",
+	0x2C: "a13",
+	0x2D: "a14",
+	0x2E: "a15",
+	0x2F: "a16",
+	0x30: "a105",
+	0x31: "a17",
+	0x32: "a18",
+	0x33: "a19",
+	0x34: "a20",
+	0x35: "a21",
+	0x36: "a22",
+	0x37: "a23",
+	0x38: "a24",
+	0x39: "a25",
+	0x3A: "a26",
+	0x3B: "a27",
+	0x3C: "a28",
+	0x3D: "a6",
+	0x3E: "a7",
+	0x3F: "a8",
+	0x40: "a9",
+	0x41: "a10",
+	0x42: "a29",
+	0x43: "a30",
+	0x44: "a31",
+	0x45: "a32",
+	0x46: "a33",
+	0x47: "a34",
+	0x48: "a35",
+	0x49: "a36",
+	0x4A: "a37",
+	0x4B: "a38",
+	0x4C: "a39",
+	0x4D: "a40",
+	0x4E: "a41",
+	0x4F: "a42",
+	0x50: "a43",
+	0x51: "a44",
+	0x52: "a45",
+	0x53: "a46",
+	0x54: "a47",
+	0x55: "a48",
+	0x56: "a49",
+	0x57: "a50",
+	0x58: "a51",
+	0x59: "a52",
+	0x5A: "a53",
+	0x5B: "a54",
+	0x5C: "a55",
+	0x5D: "a56",
+	0x5E: "a57",
+	0x5F: "a58",
+	0x60: "a59",
+	0x61: "a60",
+	0x62: "a61",
+	0x63: "a62",
+	0x64: "a63",
+	0x65: "a64",
+	0x66: "a65",
+	0x67: "a66",
+	0x68: "a67",
+	0x69: "a68",
+	0x6A: "a69",
+	0x6B: "a70",
+	0x6C: "a71",
+	0x6D: "a72",
+	0x6E: "a73",
+	0x6F: "a74",
+	0x70: "a203",
+	0x71: "a75",
+	0x72: "a204",
+	0x73: "a76",
+	0x74: "a77",
+	0x75: "a78",
+	0x76: "a79",
+	0x77: "a81",
+	0x78: "a82",
+	0x79: "a83",
+	0x7A: "a84",
+	0x7B: "a97",
+	0x7C: "a98",
+	0x7D: "a99",
+	0x7E: "a100",
+	0x80: "a89",
+	0x81: "a90",
+	0x82: "a93",
+	0x83: "a94",
+	0x84: "a91",
+	0x85: "a92",
+	0x86: "a205",
+	0x87: "a85",
+	0x88: "a206",
+	0x89: "a86",
+	0x8A: "a87",
+	0x8B: "a88",
+	0x8C: "a95",
+	0x8D: "a96",
+	0xA1: "a101",
+	0xA2: "a102",
+	0xA3: "a103",
+	0xA4: "a104",
+	0xA5: "a106",
+	0xA6: "a107",
+	0xA7: "a108",
+	0xA8: "a112",
+	0xA9: "a111",
+	0xAA: "a110",
+	0xAB: "a109",
+	0xAC: "a120",
+	0xAD: "a121",
+	0xAE: "a122",
+	0xAF: "a123",
+	0xB0: "a124",
+	0xB1: "a125",
+	0xB2: "a126",
+	0xB3: "a127",
+	0xB4: "a128",
+	0xB5: "a129",
+	0xB6: "a130",
+	0xB7: "a131",
+	0xB8: "a132",
+	0xB9: "a133",
+	0xBA: "a134",
+	0xBB: "a135",
+	0xBC: "a136",
+	0xBD: "a137",
+	0xBE: "a138",
+	0xBF: "a139",
+	0xC0: "a140",
+	0xC1: "a141",
+	0xC2: "a142",
+	0xC3: "a143",
+	0xC4: "a144",
+	0xC5: "a145",
+	0xC6: "a146",
+	0xC7: "a147",
+	0xC8: "a148",
+	0xC9: "a149",
+	0xCA: "a150",
+	0xCB: "a151",
+	0xCC: "a152",
+	0xCD: "a153",
+	0xCE: "a154",
+	0xCF: "a155",
+	0xD0: "a156",
+	0xD1: "a157",
+	0xD2: "a158",
+	0xD3: "a159",
+	0xD4: "a160",
+	0xD5: "a161",
+	0xD6: "a163",
+	0xD7: "a164",
+	0xD8: "a196",
+	0xD9: "a165",
+	0xDA: "a192",
+	0xDB: "a166",
+	0xDC: "a167",
+	0xDD: "a168",
+	0xDE: "a169",
+	0xDF: "a170",
+	0xE0: "a171",
+	0xE1: "a172",
+	0xE2: "a173",
+	0xE3: "a162",
+	0xE4: "a174",
+	0xE5: "a175",
+	0xE6: "a176",
+	0xE7: "a177",
+	0xE8: "a178",
+	0xE9: "a179",
+	0xEA: "a193",
+	0xEB: "a180",
+	0xEC: "a199",
+	0xED: "a181",
+	0xEE: "a200",
+	0xEF: "a182",
+	0xF1: "a201",
+	0xF2: "a183",
+	0xF3: "a184",
+	0xF4: "a197",
+	0xF5: "a185",
+	0xF6: "a194",
+	0xF7: "a198",
+	0xF8: "a186",
+	0xF9: "a195",
+	0xFA: "a187",
+	0xFB: "a188",
+	0xFC: "a189",
+	0xFD: "a190",
+	0xFE: "a191",
+}
+
+var zapfDingbats = StandardFont{
+	Name:     "ZapfDingbats",
+	glyphs:   zapfDingbatsGlyphs,
+	encoding: &zapfDingbatsEncoding,
+	widths: []uint16{
+		974, 692, 668, 732, 544, 544, 910, 911, 667, 760, 760, 626, 960, 694, 595,
+		776, 690, 791, 790, 939, 788, 788, 788, 788, 788, 788, 788, 788, 788, 788,
+		549, 788, 788, 788, 788, 788, 788, 788, 788, 788, 788, 855, 788, 788, 788,
+		788, 788, 788, 788, 788, 788, 788, 911, 788, 788, 788, 788, 788, 788, 788,
+		788, 788, 788, 933, 894, 838, 924, 1016, 458, 924, 918, 927, 928, 928, 945,
+		834, 873, 828, 924, 917, 930, 931, 463, 883, 836, 974, 867, 696, 874, 760,
+		946, 865, 967, 831, 873, 927, 755, 970, 918, 748, 836, 771, 888, 748, 771,
+		888, 867, 961, 846, 696, 874, 974, 762, 759, 509, 410, 762, 761, 571, 677,
+		763, 760, 759, 754, 786, 980, 788, 788, 790, 793, 794, 816, 823, 789, 841,
+		823, 719, 833, 816, 831, 923, 744, 723, 749, 790, 792, 695, 789, 776, 768,
+		792, 759, 707, 708, 682, 701, 826, 815, 494, 789, 789, 707, 687, 696, 689,
+		786, 787, 713, 791, 552, 785, 791, 873, 761, 762, 759, 892, 892, 788, 784,
+		537, 438, 138, 277, 415, 509, 410, 234, 234, 390, 577, 390, 276, 276, 317,
+		317, 334, 334, 392, 392, 668, 278,
+	},
+}
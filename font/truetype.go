@@ -0,0 +1,229 @@
+package font
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// maxCmapGlyphs bounds the mappings read from one cmap subtable, so that a
+// corrupt table, such as format 4 segments that all span the whole code
+// range or a huge format 12 group, cannot expand into billions of entries.
+const maxCmapGlyphs = 0x110000
+
+// TrueTypeCmap holds the character mappings of an embedded TrueType or
+// OpenType font program (the sfnt cmap table). Fonts without a ToUnicode
+// CMap fall back to it: the glyph a code selects is looked up in the
+// reverse of the font's Unicode mapping.
+type TrueTypeCmap struct {
+	// glyphUnicode is the reverse of the Unicode subtables: glyph index to
+	// the lowest code point mapped to it.
+	glyphUnicode map[uint16]rune
+
+	// codeGlyphs maps character codes to glyph indices through the
+	// symbol (3,0) or Macintosh Roman (1,0) subtable, which simple
+	// TrueType fonts use to select glyphs.
+	codeGlyphs map[uint32]uint16
+}
+
+// ParseTrueTypeCmap parses the cmap table of a TrueType or OpenType font
+// program, such as the contents of a FontFile2 stream. Subtables of
+// formats 0, 4, 6 and 12 are read; others are skipped.
+func ParseTrueTypeCmap(data []byte) (*TrueTypeCmap, error) {
+	table, err := sfntTable(data, "cmap")
+	if err != nil {
+		return nil, err
+	}
+	if len(table) < 4 {
+		return nil, fmt.Errorf("%w: cmap table too short", ErrInvalidFontProgram)
+	}
+
+	type subtable struct {
+		platform, encoding uint16
+		data               []byte
+	}
+	var unicodeTables, symbolTables []subtable
+	n := int(binary.BigEndian.Uint16(table[2:]))
+	for i := range n {
+		rec := 4 + 8*i
+		if rec+8 > len(table) {
+			break
+		}
+		st := subtable{
+			platform: binary.BigEndian.Uint16(table[rec:]),
+			encoding: binary.BigEndian.Uint16(table[rec+2:]),
+		}
+		offset := int(binary.BigEndian.Uint32(table[rec+4:]))
+		if offset >= len(table) {
+			continue
+		}
+		st.data = table[offset:]
+		switch {
+		case st.platform == 0, st.platform == 3 && (st.encoding == 1 || st.encoding == 10):
+			unicodeTables = append(unicodeTables, st)
+		case st.platform == 3 && st.encoding == 0, st.platform == 1 && st.encoding == 0:
+			symbolTables = append(symbolTables, st)
+		}
+	}
+
+	c := &TrueTypeCmap{glyphUnicode: make(map[uint16]rune), codeGlyphs: make(map[uint32]uint16)}
+	for _, st := range unicodeTables {
+		readCmapSubtable(st.data, func(code uint32, gid uint16) {
+			if prev, ok := c.glyphUnicode[gid]; !ok || rune(code) < prev {
+				c.glyphUnicode[gid] = rune(code)
+			}
+		})
+	}
+	for _, st := range symbolTables {
+		readCmapSubtable(st.data, func(code uint32, gid uint16) {
+			if _, ok := c.codeGlyphs[code]; !ok {
+				c.codeGlyphs[code] = gid
+			}
+		})
+	}
+	return c, nil
+}
+
+// GlyphUnicode returns the character a glyph index represents, according
+// to the font's Unicode subtables.
+func (c *TrueTypeCmap) GlyphUnicode(gid uint16) (rune, bool) {
+	r, ok := c.glyphUnicode[gid]
+	return r, ok
+}
+
+// CodeGlyph returns the glyph index a simple TrueType font selects for a
+// single-byte code through its symbol or Macintosh subtable. Symbol
+// subtables usually map the codes at U+F000, U+F100 or U+F200 plus the
+// code (ISO 32000-1, 9.6.6.4), so those are tried first.
+func (c *TrueTypeCmap) CodeGlyph(code byte) (uint16, bool) {
+	for _, base := range []uint32{0xF000, 0xF100, 0xF200, 0} {
+		if gid, ok := c.codeGlyphs[base|uint32(code)]; ok {
+			return gid, true
+		}
+	}
+	return 0, false
+}
+
+// sfntTable returns the named table of an sfnt font program.
+func sfntTable(data []byte, tag string) ([]byte, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("%w: font program too short", ErrInvalidFontProgram)
+	}
+	switch v := binary.BigEndian.Uint32(data); v {
+	case 0x00010000, 0x74727565, 0x4F54544F: // 1.0, 'true', 'OTTO'
+	default:
+		return nil, fmt.Errorf("%w: not a TrueType or OpenType font (version %#08x)", ErrInvalidFontProgram, v)
+	}
+	n := int(binary.BigEndian.Uint16(data[4:]))
+	for i := range n {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			break
+		}
+		if string(data[rec:rec+4]) != tag {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if offset < 0 || length < 0 || offset > len(data) || length > len(data)-offset {
+			return nil, fmt.Errorf("%w: %s table out of bounds", ErrInvalidFontProgram, tag)
+		}
+		return data[offset : offset+length], nil
+	}
+	return nil, fmt.Errorf("%w: no %s table", ErrInvalidFontProgram, tag)
+}
+
+// readCmapSubtable calls add for each code-to-glyph mapping of a cmap
+// subtable, skipping glyph 0 (.notdef). Truncated subtables are read as
+// far as they go.
+func readCmapSubtable(st []byte, add func(code uint32, gid uint16)) {
+	if len(st) < 2 {
+		return
+	}
+	u16 := func(off int) (uint16, bool) {
+		if off < 0 || off+2 > len(st) {
+			return 0, false
+		}
+		return binary.BigEndian.Uint16(st[off:]), true
+	}
+	u32 := func(off int) (uint32, bool) {
+		if off < 0 || off+4 > len(st) {
+			return 0, false
+		}
+		return binary.BigEndian.Uint32(st[off:]), true
+	}
+	emit := func(code uint32, gid uint16) {
+		if gid != 0 {
+			add(code, gid)
+		}
+	}
+
+	switch format, _ := u16(0); format {
+	case 0: // Byte encoding table
+		for code := 0; code < 256 && 6+code < len(st); code++ {
+			emit(uint32(code), uint16(st[6+code]))
+		}
+
+	case 4: // Segment mapping to delta values
+		segX2, _ := u16(6)
+		segCount := int(segX2 / 2)
+		endCodes := 14
+		startCodes := endCodes + 2*segCount + 2
+		deltas := startCodes + 2*segCount
+		rangeOffsets := deltas + 2*segCount
+		total := 0
+		for seg := range segCount {
+			end, ok1 := u16(endCodes + 2*seg)
+			start, ok2 := u16(startCodes + 2*seg)
+			delta, ok3 := u16(deltas + 2*seg)
+			ro, ok4 := u16(rangeOffsets + 2*seg)
+			if !ok1 || !ok2 || !ok3 || !ok4 {
+				return
+			}
+			for code := uint32(start); code <= uint32(end) && code != 0xFFFF; code++ {
+				if total++; total > maxCmapGlyphs {
+					return
+				}
+				if ro == 0 {
+					emit(code, uint16(code)+delta)
+					continue
+				}
+				gid, ok := u16(rangeOffsets + 2*seg + int(ro) + 2*int(code-uint32(start)))
+				if !ok {
+					break
+				}
+				if gid != 0 {
+					emit(code, gid+delta)
+				}
+			}
+		}
+
+	case 6: // Trimmed table mapping
+		first, _ := u16(6)
+		count, _ := u16(8)
+		for i := range int(count) {
+			gid, ok := u16(10 + 2*i)
+			if !ok {
+				return
+			}
+			emit(uint32(first)+uint32(i), gid)
+		}
+
+	case 12: // Segmented coverage
+		groups, _ := u32(12)
+		total := 0
+		for i := range int(min(groups, uint32(len(st)/12))) {
+			start, ok1 := u32(16 + 12*i)
+			end, ok2 := u32(20 + 12*i)
+			startGlyph, ok3 := u32(24 + 12*i)
+			if !ok1 || !ok2 || !ok3 || end < start {
+				return
+			}
+			for code := start; code <= end; code++ {
+				if total++; total > maxCmapGlyphs {
+					return
+				}
+				emit(code, uint16(startGlyph+code-start))
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package font
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// format4 builds a cmap format 4 subtable from segments of start, end and
+// delta, with no glyph index array.
+func format4(segments [][3]uint16) []byte {
+	n := len(segments)
+	st := make([]byte, 16+8*n)
+	binary.BigEndian.PutUint16(st[0:], 4)
+	binary.BigEndian.PutUint16(st[6:], uint16(2*n))
+	for i, seg := range segments {
+		binary.BigEndian.PutUint16(st[14+2*i:], seg[1])
+		binary.BigEndian.PutUint16(st[16+2*n+2*i:], seg[0])
+		binary.BigEndian.PutUint16(st[16+4*n+2*i:], seg[2])
+	}
+	return st
+}
+
+func TestReadCmapSubtableFormat4(t *testing.T) {
+	st := format4([][3]uint16{{0x41, 0x43, 3}, {0xFFFF, 0xFFFF, 1}})
+	got := map[uint32]uint16{}
+	readCmapSubtable(st, func(code uint32, gid uint16) { got[code] = gid })
+	want := map[uint32]uint16{0x41: 0x44, 0x42: 0x45, 0x43: 0x46}
+	if len(got) != len(want) {
+		t.Fatalf("mappings = %v, want %v", got, want)
+	}
+	for code, gid := range want {
+		if got[code] != gid {
+			t.Errorf("code %#x: gid %d, want %d", code, got[code], gid)
+		}
+	}
+}
+
+func TestReadCmapSubtableFormat4Budget(t *testing.T) {
+	segments := make([][3]uint16, 1000)
+	for i := range segments {
+		segments[i] = [3]uint16{0, 0xFFFE, 1}
+	}
+	calls := 0
+	readCmapSubtable(format4(segments), func(uint32, uint16) { calls++ })
+	if calls > maxCmapGlyphs {
+		t.Errorf("%d mappings read, want at most %d", calls, maxCmapGlyphs)
+	}
+}
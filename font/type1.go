@@ -0,0 +1,260 @@
+package font
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// GlyphEncoding maps the single-byte codes of a simple font to glyph
+// names; codes without a glyph have the empty name.
+type GlyphEncoding [256]string
+
+// Text returns the text of the glyph a code selects, derived from its
+// glyph name with GlyphNameToUnicode.
+func (e *GlyphEncoding) Text(code byte) (string, bool) {
+	name := e[code]
+	if name == "" || name == ".notdef" {
+		return "", false
+	}
+	return GlyphNameToUnicode(name)
+}
+
+// ParseType1Encoding returns the built-in encoding of a Type 1 font
+// program, such as the contents of a FontFile stream. The encoding is read
+// from the program's cleartext portion, which comes before eexec: either
+// "/Encoding StandardEncoding def" or an array filled with
+// "dup <code> /<name> put". PFB segment headers are skipped.
+func ParseType1Encoding(data []byte) (*GlyphEncoding, error) {
+	if len(data) >= 6 && data[0] == 0x80 && data[1] == 0x01 {
+		data = data[6:]
+	}
+	if i := bytes.Index(data, []byte("eexec")); i >= 0 {
+		data = data[:i]
+	}
+	if !bytes.HasPrefix(data, []byte("%!")) {
+		return nil, fmt.Errorf("%w: not a Type 1 font", ErrInvalidFontProgram)
+	}
+	i := bytes.Index(data, []byte("/Encoding"))
+	if i < 0 {
+		return nil, fmt.Errorf("%w: no /Encoding in Type 1 font", ErrInvalidFontProgram)
+	}
+
+	s := type1Scanner{data: data[i+len("/Encoding"):]}
+	if s.next() == "StandardEncoding" {
+		enc := standardEncoding
+		return &enc, nil
+	}
+	var enc GlyphEncoding
+	// Tokens of the form dup <code> /<name> put, up to the def or
+	// readonly that ends the array
+	var window [4]string
+	for {
+		token := s.next()
+		if token == "" || token == "def" || token == "readonly" {
+			break
+		}
+		copy(window[:], window[1:])
+		window[3] = token
+		if window[0] != "dup" || window[3] != "put" || len(window[2]) < 2 || window[2][0] != '/' {
+			continue
+		}
+		if code, err := strconv.Atoi(window[1]); err == nil && code >= 0 && code < 256 {
+			enc[code] = window[2][1:]
+		}
+	}
+	return &enc, nil
+}
+
+// type1Scanner splits the PostScript cleartext of a Type 1 font into
+// tokens. Names keep their leading slash; comments are skipped.
+type type1Scanner struct {
+	data []byte
+}
+
+// next returns the next token, or "" at the end of the data.
+func (s *type1Scanner) next() string {
+	for len(s.data) > 0 {
+		switch c := s.data[0]; {
+		case isType1Space(c):
+			s.data = s.data[1:]
+		case c == '%':
+			if i := bytes.IndexAny(s.data, "\r\n"); i >= 0 {
+				s.data = s.data[i:]
+			} else {
+				s.data = nil
+			}
+		default:
+			n := 1
+			for n < len(s.data) && !isType1Delimiter(s.data[n]) {
+				n++
+			}
+			token := string(s.data[:n])
+			s.data = s.data[n:]
+			return token
+		}
+	}
+	return ""
+}
+
+func isType1Space(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isType1Delimiter(c byte) bool {
+	return isType1Space(c) || bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// standardEncoding is the Adobe StandardEncoding (ISO 32000-1, Annex D),
+// the built-in encoding of most Latin Type 1 fonts.
+var standardEncoding = GlyphEncoding{
+	0x20: "space",
+	0x21: "exclam",
+	0x22: "quotedbl",
+	0x23: "numbersign",
+	0x24: "dollar",
+	0x25: "percent",
+	0x26: "ampersand",
+	0x27: "quoteright",
+	0x28: "parenleft",
+	0x29: "parenright",
+	0x2A: "asterisk",
+	0x2B: "plus",
+	0x2C: "comma",
+	0x2D: "hyphen",
+	0x2E: "period",
+	0x2F: "slash",
+	0x30: "zero",
+	0x31: "one",
+	0x32: "two",
+	0x33: "three",
+	0x34: "four",
+	0x35: "five",
+	0x36: "six",
+	0x37: "seven",
+	0x38: "eight",
+	0x39: "nine",
+	0x3A: "colon",
+	0x3B: "semicolon",
+	0x3C: "less",
+	0x3D: "equal",
+	0x3E: "greater",
+	0x3F: "question",
+	0x40: "at",
+	0x41: "A",
+	0x42: "B",
+	0x43: "C",
+	0x44: "D",
+	0x45: "E",
+	0x46: "F",
+	0x47: "G",
+	0x48: "H",
+	0x49: "I",
+	0x4A: "J",
+	0x4B: "K",
+	0x4C: "L",
+	0x4D: "M",
+	0x4E: "N",
+	0x4F: "O",
+	0x50: "P",
+	0x51: "Q",
+	0x52: "R",
+	0x53: "S",
+	0x54: "T",
+	0x55: "U",
+	0x56: "V",
+	0x57: "W",
+	0x58: "X",
+	0x59: "Y",
+	0x5A: "Z",
+	0x5B: "bracketleft",
+	0x5C: "backslash",
+	0x5D: "bracketright",
+	0x5E: "asciicircum",
+	0x5F: "underscore",
+	0x60: "quoteleft",
+	0x61: "a",
+	0x62: "b",
+	0x63: "c",
+	0x64: "d",
+	0x65: "e",
+	0x66: "f",
+	0x67: "g",
+	0x68: "h",
+	0x69: "i",
+	0x6A: "j",
+	0x6B: "k",
+	0x6C: "l",
+	0x6D: "m",
+	0x6E: "n",
+	0x6F: "o",
+	0x70: "p",
+	0x71: "q",
+	0x72: "r",
+	0x73: "s",
+	0x74: "t",
+	0x75: "u",
+	0x76: "v",
+	0x77: "w",
+	0x78: "x",
+	0x79: "y",
+	0x7A: "z",
+	0x7B: "braceleft",
+	0x7C: "bar",
+	0x7D: "braceright",
+	0x7E: "asciitilde",
+	0xA1: "exclamdown",
+	0xA2: "cent",
+	0xA3: "sterling",
+	0xA4: "fraction",
+	0xA5: "yen",
+	0xA6: "florin",
+	0xA7: "section",
+	0xA8: "currency",
+	0xA9: "quotesingle",
+	0xAA: "quotedblleft",
+	0xAB: "guillemotleft",
+	0xAC: "guilsinglleft",
+	0xAD: "guilsinglright",
+	0xAE: "fi",
+	0xAF: "fl",
+	0xB1: "endash",
+	0xB2: "dagger",
+	0xB3: "daggerdbl",
+	0xB4: "periodcentered",
+	0xB6: "paragraph",
+	0xB7: "bullet",
+	0xB8: "quotesinglbase",
+	0xB9: "quotedblbase",
+	0xBA: "quotedblright",
+	0xBB: "guillemotright",
+	0xBC: "ellipsis",
+	0xBD: "perthousand",
+	0xBF: "questiondown",
+	0xC1: "grave",
+	0xC2: "acute",
+	0xC3: "circumflex",
+	0xC4: "tilde",
+	0xC5: "macron",
+	0xC6: "breve",
+	0xC7: "dotaccent",
+	0xC8: "dieresis",
+	0xCA: "ring",
+	0xCB: "cedilla",
+	0xCD: "hungarumlaut",
+	0xCE: "ogonek",
+	0xCF: "caron",
+	0xD0: "emdash",
+	0xE1: "AE",
+	0xE3: "ordfeminine",
+	0xE8: "Lslash",
+	0xE9: "Oslash",
+	0xEA: "OE",
+	0xEB: "ordmasculine",
+	0xF1: "ae",
+	0xF5: "dotlessi",
+	0xF8: "lslash",
+	0xF9: "oslash",
+	0xFA: "oe",
+	0xFB: "germandbls",
+}
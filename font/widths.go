@@ -0,0 +1,220 @@
+package font
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultCIDWidth is the glyph width used by CID fonts without a /DW entry,
+// in thousandths of an em.
+const DefaultCIDWidth = 1000.0
+
+// cidWidthRange maps a contiguous range of CIDs to widths.
+// Either widths holds one width per CID starting at first (the
+// "c [w1 w2 ...]" form) or width applies to the whole range
+// (the "cfirst clast w" form).
+type cidWidthRange struct {
+	first, last uint32
+	widths      []float64
+	width       float64
+}
+
+// SetWidths sets the simple-font /FirstChar and /Widths entries.
+// Widths are in thousandths of an em.
+func (f *Font) SetWidths(firstChar int, widths []float64) {
+	f.FirstChar = firstChar
+	f.Widths = widths
+}
+
+// SetCIDWidths sets the CID-font /DW and /W entries.
+// w holds the /W array using the operand types produced by the parser:
+// numbers as float64 (or int) and nested arrays as []any, e.g.
+//
+//	[120 [400 325 500] 7080 8032 1000]
+//
+// A dw of zero selects DefaultCIDWidth.
+func (f *Font) SetCIDWidths(dw float64, w []any) error {
+	if dw == 0 {
+		dw = DefaultCIDWidth
+	}
+	ranges, err := parseCIDWidths(w)
+	if err != nil {
+		return err
+	}
+	f.DefaultWidth = dw
+	f.cidWidths = ranges
+	return nil
+}
+
+// parseCIDWidths parses a /W array into sorted ranges.
+func parseCIDWidths(w []any) ([]cidWidthRange, error) {
+	var ranges []cidWidthRange
+	for i := 0; i < len(w); {
+		first, ok := toFloat(w[i])
+		if !ok {
+			return nil, fmt.Errorf("W entry %d: expected CID, got %T", i, w[i])
+		}
+		if i+1 >= len(w) {
+			return nil, fmt.Errorf("W entry %d: missing widths", i)
+		}
+
+		if arr, ok := w[i+1].([]any); ok {
+			// c [w1 w2 ...]
+			widths := make([]float64, 0, len(arr))
+			for _, v := range arr {
+				width, ok := toFloat(v)
+				if !ok {
+					return nil, fmt.Errorf("W entry %d: width not a number", i)
+				}
+				widths = append(widths, width)
+			}
+			if len(widths) > 0 {
+				ranges = append(ranges, cidWidthRange{
+					first:  uint32(first),
+					last:   uint32(first) + uint32(len(widths)) - 1,
+					widths: widths,
+				})
+			}
+			i += 2
+			continue
+		}
+
+		// cfirst clast w
+		if i+2 >= len(w) {
+			return nil, fmt.Errorf("W entry %d: incomplete range", i)
+		}
+		last, ok1 := toFloat(w[i+1])
+		width, ok2 := toFloat(w[i+2])
+		if !ok1 || !ok2 || last < first {
+			return nil, fmt.Errorf("W entry %d: invalid range", i)
+		}
+		ranges = append(ranges, cidWidthRange{first: uint32(first), last: uint32(last), width: width})
+		i += 3
+	}
+
+	sort.Slice(ranges, func(a, b int) bool { return ranges[a].first < ranges[b].first })
+	return ranges, nil
+}
+
+// GlyphWidth returns the width of the glyph for a character code in
+// thousandths of an em. ok is false when the font carries no width
+// information for the code, in which case callers should estimate.
+//
+// For CID fonts the code is the CID; codes not covered by /W use /DW.
+// For simple fonts, codes outside /FirstChar../LastChar use /MissingWidth;
+// standard 14 fonts without /Widths use the font's AFM metrics.
+func (f *Font) GlyphWidth(code uint32) (width float64, ok bool) {
+	if f.IsMultiByte {
+		i := sort.Search(len(f.cidWidths), func(i int) bool { return f.cidWidths[i].last >= code })
+		if i < len(f.cidWidths) && f.cidWidths[i].first <= code {
+			r := f.cidWidths[i]
+			if r.widths != nil {
+				return r.widths[code-r.first], true
+			}
+			return r.width, true
+		}
+		return f.defaultCIDWidth(), true
+	}
+
+	if idx := int(code) - f.FirstChar; f.Widths != nil && idx >= 0 && idx < len(f.Widths) {
+		return f.Widths[idx], true
+	}
+	if f.Widths == nil && f.Standard != nil && code <= 0xFF {
+		name := f.Standard.glyphName(byte(code), f.Encoding)
+		if f.Differences != nil && f.Differences[code] != "" {
+			name = f.Differences[code]
+		}
+		if w, ok := f.Standard.GlyphWidth(name); ok {
+			return w, true
+		}
+	}
+	if f.MissingWidth > 0 {
+		return f.MissingWidth, true
+	}
+	return 0, false
+}
+
+// CodeWidth returns the width of the glyph for a character code of n bytes,
+// as returned by NextCode, in thousandths of an em. Unlike GlyphWidth it
+// takes character codes for CID fonts too, mapping them to CIDs through
+// the encoding CMap; codes whose CID is unknown use /DW.
+func (f *Font) CodeWidth(code uint32, n int) (width float64, ok bool) {
+	cid, ok := f.CID(code, n)
+	if !ok {
+		return f.defaultCIDWidth(), true
+	}
+	return f.GlyphWidth(cid)
+}
+
+func (f *Font) defaultCIDWidth() float64 {
+	if f.DefaultWidth > 0 {
+		return f.DefaultWidth
+	}
+	return DefaultCIDWidth
+}
+
+// HasWidths reports whether the font carries any width information.
+func (f *Font) HasWidths() bool {
+	return f.IsMultiByte || f.Widths != nil || f.MissingWidth > 0 || f.Standard != nil
+}
+
+// CodeLength returns the number of bytes per character code. Type0 fonts
+// whose encoding CMap mixes code lengths are split with NextCode instead.
+func (f *Font) CodeLength() int {
+	if f.IsMultiByte || f.EncodingCMap != nil {
+		return 2
+	}
+	return 1
+}
+
+// NextCode returns the character code at the start of data and its length
+// in bytes, splitting by the encoding CMap's codespace ranges for Type0
+// fonts. A trailing partial multi-byte code is padded with zero bytes.
+func (f *Font) NextCode(data []byte) (code uint32, n int) {
+	if f.EncodingCMap != nil {
+		return f.EncodingCMap.NextCode(data)
+	}
+	n = f.CodeLength()
+	for j := 0; j < n; j++ {
+		code <<= 8
+		if j < len(data) {
+			code |= uint32(data[j])
+		}
+	}
+	return code, min(n, len(data))
+}
+
+// CID returns the CID selected by a character code of n bytes: through
+// the encoding CMap for Type0 fonts, and the code itself otherwise. ok is
+// false if the encoding CMap cannot tell.
+func (f *Font) CID(code uint32, n int) (cid uint32, ok bool) {
+	if f.EncodingCMap != nil {
+		return f.EncodingCMap.CID(code, n)
+	}
+	return code, true
+}
+
+// Codes splits a show-string into character codes.
+// A trailing partial multi-byte code is padded with zero bytes.
+func (f *Font) Codes(data []byte) []uint32 {
+	codes := make([]uint32, 0, len(data)/f.CodeLength()+1)
+	for i := 0; i < len(data); {
+		code, n := f.NextCode(data[i:])
+		codes = append(codes, code)
+		i += n
+	}
+	return codes
+}
+
+// toFloat converts a numeric operand to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,187 @@
+// Package formats writes extraction results in structured formats, for
+// consumers that need the layout of the text rather than the flat string:
+// pages of blocks (paragraphs) of lines of runs, with their coordinates,
+// fonts and styles.
+//
+// All formats share the model of Document, which NewDocument builds from
+// the runs of a streamengine.DocumentText with the layout package.
+// Coordinates are in PDF page space: points, with y growing upward from
+// the bottom of the page.
+package formats
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/layout"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+// SchemaVersion is the version of the document model that the formats
+// write. It changes when fields are removed or change meaning; fields
+// may be added without a change.
+const SchemaVersion = 1
+
+// Document is an extraction result as pages of blocks of lines of runs.
+type Document struct {
+	Version int    `json:"version"`
+	Pages   []Page `json:"pages"`
+}
+
+// Page is the text of one page.
+type Page struct {
+	// Number is the 1-based page number.
+	Number int `json:"number"`
+
+	// Text is the page's extracted text, as streamengine returns it.
+	Text string `json:"text"`
+
+	// Blocks are the page's paragraphs, top to bottom.
+	Blocks []Block `json:"blocks"`
+
+	// Partial reports that extraction stopped early (see
+	// streamengine.PageResult), and Error why the page could not be
+	// extracted, if it could not.
+	Partial bool   `json:"partial,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Block is a paragraph.
+type Block struct {
+	Text  string `json:"text"`
+	BBox  BBox   `json:"bbox"`
+	Lines []Line `json:"lines"`
+}
+
+// Line is a line of text: runs sharing a baseline, left to right.
+type Line struct {
+	Text     string  `json:"text"`
+	BBox     BBox    `json:"bbox"`
+	Baseline float64 `json:"baseline"`
+	FontSize float64 `json:"font_size"`
+	Runs     []Run   `json:"runs"`
+}
+
+// Run is a piece of text shown with one font and style (see
+// interpreter.TextRun).
+type Run struct {
+	Text string `json:"text"`
+	BBox BBox   `json:"bbox"`
+
+	// X, Y is the baseline origin of the first glyph, and Width the
+	// advance of the run along its writing direction.
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Width float64 `json:"width"`
+
+	// Font is the font's resource name and BaseFont its PostScript name.
+	Font     string  `json:"font"`
+	BaseFont string  `json:"base_font,omitempty"`
+	FontSize float64 `json:"font_size"`
+
+	Bold     bool `json:"bold,omitempty"`
+	Italic   bool `json:"italic,omitempty"`
+	Vertical bool `json:"vertical,omitempty"`
+
+	// Color is the color painting the glyphs as #rrggbb, or "" if it is
+	// not known (patterns, spot colors).
+	Color string `json:"color,omitempty"`
+
+	// RenderMode is the text rendering mode (Tr), and Invisible is set
+	// for the modes that paint nothing.
+	RenderMode int  `json:"render_mode"`
+	Invisible  bool `json:"invisible,omitempty"`
+
+	// Script is "superscript" or "subscript", or "" for normal text.
+	Script string `json:"script,omitempty"`
+}
+
+// BBox is a bounding box: x0, y0, x1, y1 with x0 <= x1 and y0 <= y1.
+type BBox [4]float64
+
+// NewDocument builds the document model from extraction results,
+// assembling the runs of each page into lines and paragraphs with the
+// layout package's default options. Coordinates are rounded to
+// hundredths of a point.
+func NewDocument(doc streamengine.DocumentText) Document {
+	out := Document{Version: SchemaVersion, Pages: make([]Page, len(doc.Pages))}
+	for i, result := range doc.Pages {
+		out.Pages[i] = newPage(result)
+	}
+	return out
+}
+
+func newPage(result streamengine.PageResult) Page {
+	page := Page{Number: result.Number, Text: result.Text, Partial: result.Partial, Blocks: []Block{}}
+	if result.Err != nil {
+		page.Error = result.Err.Error()
+	}
+	lines := layout.AssembleLines(result.Runs, layout.LineOptions{})
+	for _, p := range layout.DetectParagraphs(lines, layout.ParagraphOptions{}) {
+		block := Block{Text: p.Text, BBox: newBBox(p.Bounds), Lines: make([]Line, len(p.Lines))}
+		for j, l := range p.Lines {
+			line := Line{
+				Text:     l.Text,
+				BBox:     newBBox(l.Bounds),
+				Baseline: round(l.Baseline),
+				FontSize: round(l.FontSize),
+				Runs:     make([]Run, len(l.Runs)),
+			}
+			for k, r := range l.Runs {
+				line.Runs[k] = newRun(r)
+			}
+			block.Lines[j] = line
+		}
+		page.Blocks = append(page.Blocks, block)
+	}
+	return page
+}
+
+func newRun(r interpreter.TextRun) Run {
+	run := Run{
+		Text:       r.Text,
+		BBox:       newBBox(r.Bounds()),
+		X:          round(r.X),
+		Y:          round(r.Y),
+		Width:      round(r.Width),
+		Font:       r.FontName,
+		BaseFont:   r.BaseFont,
+		FontSize:   round(r.FontSize),
+		Bold:       r.Bold,
+		Italic:     r.Italic,
+		Vertical:   r.Vertical,
+		RenderMode: int(r.RenderMode),
+		Invisible:  !r.RenderMode.IsVisible(),
+	}
+	if r.Script != interpreter.ScriptNormal {
+		run.Script = strings.ToLower(r.Script.String())
+	}
+	color := r.FillColor
+	if r.RenderMode == interpreter.RenderStroke || r.RenderMode == interpreter.RenderStrokeClip {
+		color = r.StrokeColor
+	}
+	if red, green, blue, ok := color.RGB(); ok {
+		run.Color = fmt.Sprintf("#%02x%02x%02x", toByte(red), toByte(green), toByte(blue))
+	}
+	return run
+}
+
+func newBBox(r geom.Rect) BBox {
+	return BBox{round(r.X0), round(r.Y0), round(r.X1), round(r.Y1)}
+}
+
+// round rounds v to hundredths.
+func round(v float64) float64 {
+	r := math.Round(v*100) / 100
+	if r == 0 {
+		return 0 // not -0
+	}
+	return r
+}
+
+func toByte(v float64) int {
+	return int(math.Round(v * 255))
+}
@@ -0,0 +1,157 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+// HTMLOptions tunes WriteHTML. Zero fields select the defaults.
+type HTMLOptions struct {
+	// PageWidth and PageHeight are the page size in points, which
+	// extraction results do not record. Default US Letter, 612 by 792.
+	PageWidth, PageHeight float64
+
+	// Title is the document title. Default "Extracted text".
+	Title string
+}
+
+func (o HTMLOptions) withDefaults() HTMLOptions {
+	if o.PageWidth <= 0 {
+		o.PageWidth = 612
+	}
+	if o.PageHeight <= 0 {
+		o.PageHeight = 792
+	}
+	if o.Title == "" {
+		o.Title = "Extracted text"
+	}
+	return o
+}
+
+// WriteHTML writes doc as an XHTML page for reviewing extraction layout in
+// a browser: each page is a box of the page size, one CSS pixel per
+// point, holding one absolutely positioned <span> per run with the run's
+// font size, font family, weight, style and color. Invisible runs (OCR
+// layers) are outlined instead of hidden; hovering a run shows its font
+// and coordinates.
+func WriteHTML(w io.Writer, doc streamengine.DocumentText, opts HTMLOptions) error {
+	return EncodeHTML(w, NewDocument(doc), opts)
+}
+
+// EncodeHTML is like WriteHTML for a document model built already.
+func EncodeHTML(w io.Writer, doc Document, opts HTMLOptions) error {
+	opts = opts.withDefaults()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<meta charset="UTF-8"/>
+<title>%s</title>
+<style>
+body { background: #ddd; margin: 16px; }
+.page { position: relative; width: %gpx; height: %gpx; margin: 0 auto 16px; background: #fff; box-shadow: 0 0 4px #888; overflow: hidden; }
+.run { position: absolute; white-space: pre; line-height: 1; }
+.run.vertical { writing-mode: vertical-rl; }
+.run.invisible { color: transparent; outline: 1px dashed rgba(0, 0, 255, 0.6); }
+.run:hover { background: rgba(255, 200, 0, 0.4); }
+</style>
+</head>
+<body>
+`, html.EscapeString(opts.Title), opts.PageWidth, opts.PageHeight)
+
+	for _, page := range doc.Pages {
+		fmt.Fprintf(bw, "<div class=\"page\" id=\"page-%d\" title=\"page %d\">\n", page.Number, page.Number)
+		for _, block := range page.Blocks {
+			for _, line := range block.Lines {
+				for _, run := range line.Runs {
+					writeRunSpan(bw, run, opts.PageHeight)
+				}
+			}
+		}
+		bw.WriteString("</div>\n")
+	}
+	bw.WriteString("</body>\n</html>\n")
+	return bw.Flush()
+}
+
+// writeRunSpan writes the span of one run, positioned by the top left
+// corner of its box. PDF y grows upward, CSS top downward.
+func writeRunSpan(w *bufio.Writer, run Run, pageHeight float64) {
+	class := "run"
+	if run.Vertical {
+		class += " vertical"
+	}
+	if run.Invisible {
+		class += " invisible"
+	}
+	style := fmt.Sprintf("left:%gpx;top:%gpx;font-size:%gpx;font-family:%s",
+		run.BBox[0], round(pageHeight-run.BBox[3]), run.FontSize, cssFontFamily(run.BaseFont))
+	if run.Bold {
+		style += ";font-weight:bold"
+	}
+	if run.Italic {
+		style += ";font-style:italic"
+	}
+	if run.Color != "" && !run.Invisible {
+		style += ";color:" + run.Color
+	}
+	title := run.Font
+	if run.BaseFont != "" {
+		title += " " + run.BaseFont
+	}
+	title += fmt.Sprintf(" %gpt at (%g, %g)", run.FontSize, run.X, run.Y)
+	fmt.Fprintf(w, "<span class=\"%s\" style=\"%s\" title=\"%s\">%s</span>\n",
+		class, html.EscapeString(style), xmlEscape(title), xmlEscape(run.Text))
+}
+
+// xmlEscape escapes s for XHTML text and attribute values, dropping the
+// control characters XML does not allow, which decoded text can hold.
+func xmlEscape(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' || r == 0xFFFE || r == 0xFFFF {
+			return -1
+		}
+		return r
+	}, s)
+	return html.EscapeString(s)
+}
+
+// cssFontFamily returns a CSS font-family list for the font named
+// baseFont: its family, followed by the generic family it likely belongs
+// to.
+func cssFontFamily(baseFont string) string {
+	generic := "sans-serif"
+	if baseFont == "" {
+		return generic
+	}
+	family := font.ParseFontName(baseFont).Family
+	lower := strings.ToLower(family)
+	has := func(words ...string) bool {
+		return slices.ContainsFunc(words, func(w string) bool { return strings.Contains(lower, w) })
+	}
+	switch {
+	case has("courier", "mono", "consol"):
+		generic = "monospace"
+	case has("times", "georgia", "garamond", "roman") || has("serif") && !has("sans"):
+		generic = "serif"
+	}
+	if family == "" {
+		return generic
+	}
+	// PostScript names hold no quotes or backslashes but in broken files;
+	// drop them rather than end the CSS string
+	return fmt.Sprintf("'%s', %s", strings.Map(func(r rune) rune {
+		if r == '\'' || r == '\\' {
+			return -1
+		}
+		return r
+	}, family), generic)
+}
@@ -0,0 +1,46 @@
+package formats
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+// WriteJSON writes doc as one JSON object in the schema of Document:
+//
+//	{
+//	  "version": 1,
+//	  "pages": [{
+//	    "number": 1,
+//	    "text": "...",
+//	    "blocks": [{
+//	      "text": "...", "bbox": [x0, y0, x1, y1],
+//	      "lines": [{
+//	        "text": "...", "bbox": [...], "baseline": 700, "font_size": 12,
+//	        "runs": [{
+//	          "text": "...", "bbox": [...], "x": 72, "y": 700, "width": 55.2,
+//	          "font": "F1", "base_font": "Helvetica-Bold", "font_size": 12,
+//	          "bold": true, "italic": false, "vertical": false,
+//	          "color": "#000000", "render_mode": 0, "invisible": false,
+//	          "script": ""
+//	        }]
+//	      }]
+//	    }],
+//	    "partial": false, "error": ""
+//	  }]
+//	}
+//
+// Fields holding their zero value are left out where the schema allows
+// it (the booleans, "base_font", "color", "script", "partial" and
+// "error"). See Document and its parts for what each field means.
+func WriteJSON(w io.Writer, doc streamengine.DocumentText) error {
+	return EncodeJSON(w, NewDocument(doc))
+}
+
+// EncodeJSON is like WriteJSON for a document model built already.
+func EncodeJSON(w io.Writer, doc Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
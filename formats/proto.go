@@ -0,0 +1,144 @@
+package formats
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"math"
+
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+// ProtoSchema is the protobuf schema (extraction.proto) of the messages
+// MarshalProto encodes, for generating decoders in other languages.
+//
+//go:embed extraction.proto
+var ProtoSchema string
+
+// MarshalProto encodes doc as a pdfstream.extraction.v1.Document protobuf
+// message (see ProtoSchema), the binary counterpart of WriteJSON.
+func MarshalProto(doc streamengine.DocumentText) []byte {
+	return NewDocument(doc).MarshalProto()
+}
+
+// MarshalProto encodes the document model as a
+// pdfstream.extraction.v1.Document protobuf message. Like protobuf
+// encoders for proto3, it leaves out fields holding their zero value.
+func (d Document) MarshalProto() []byte {
+	var b protoBuffer
+	b.varint(1, uint64(d.Version))
+	for _, page := range d.Pages {
+		b.message(2, func(b *protoBuffer) { page.marshalProto(b) })
+	}
+	return b
+}
+
+func (p Page) marshalProto(b *protoBuffer) {
+	b.varint(1, uint64(p.Number))
+	b.text(2, p.Text)
+	for _, block := range p.Blocks {
+		b.message(3, func(b *protoBuffer) {
+			b.text(1, block.Text)
+			b.bbox(2, block.BBox)
+			for _, line := range block.Lines {
+				b.message(3, func(b *protoBuffer) { line.marshalProto(b) })
+			}
+		})
+	}
+	b.flag(4, p.Partial)
+	b.text(5, p.Error)
+}
+
+func (l Line) marshalProto(b *protoBuffer) {
+	b.text(1, l.Text)
+	b.bbox(2, l.BBox)
+	b.double(3, l.Baseline)
+	b.double(4, l.FontSize)
+	for _, run := range l.Runs {
+		b.message(5, func(b *protoBuffer) { run.marshalProto(b) })
+	}
+}
+
+// protoScripts maps Run.Script to the values of the Script enum.
+var protoScripts = map[string]uint64{"superscript": 1, "subscript": 2}
+
+func (r Run) marshalProto(b *protoBuffer) {
+	b.text(1, r.Text)
+	b.bbox(2, r.BBox)
+	b.double(3, r.X)
+	b.double(4, r.Y)
+	b.double(5, r.Width)
+	b.text(6, r.Font)
+	b.text(7, r.BaseFont)
+	b.double(8, r.FontSize)
+	b.flag(9, r.Bold)
+	b.flag(10, r.Italic)
+	b.flag(11, r.Vertical)
+	b.text(12, r.Color)
+	b.varint(13, uint64(r.RenderMode))
+	b.flag(14, r.Invisible)
+	b.varint(15, protoScripts[r.Script])
+}
+
+// protoBuffer appends fields in the protobuf wire format.
+type protoBuffer []byte
+
+// Wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+func (b *protoBuffer) tag(field, wireType int) {
+	*b = binary.AppendUvarint(*b, uint64(field)<<3|uint64(wireType))
+}
+
+func (b *protoBuffer) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	b.tag(field, wireVarint)
+	*b = binary.AppendUvarint(*b, v)
+}
+
+func (b *protoBuffer) flag(field int, v bool) {
+	if v {
+		b.varint(field, 1)
+	}
+}
+
+func (b *protoBuffer) double(field int, v float64) {
+	if v == 0 {
+		return
+	}
+	b.tag(field, wireFixed64)
+	*b = binary.LittleEndian.AppendUint64(*b, math.Float64bits(v))
+}
+
+func (b *protoBuffer) text(field int, s string) {
+	if s == "" {
+		return
+	}
+	b.tag(field, wireBytes)
+	*b = binary.AppendUvarint(*b, uint64(len(s)))
+	*b = append(*b, s...)
+}
+
+// message appends an embedded message, which encode writes. Embedded
+// messages are written even if empty, so that repeated ones keep their
+// count.
+func (b *protoBuffer) message(field int, encode func(b *protoBuffer)) {
+	var m protoBuffer
+	encode(&m)
+	b.tag(field, wireBytes)
+	*b = binary.AppendUvarint(*b, uint64(len(m)))
+	*b = append(*b, m...)
+}
+
+func (b *protoBuffer) bbox(field int, box BBox) {
+	b.message(field, func(b *protoBuffer) {
+		for i, v := range box {
+			b.double(i+1, v)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// Matrix is a PDF transformation matrix [a b c d e f], representing
+//
+//	| a b 0 |
+//	| c d 0 |
+//	| e f 1 |
+type Matrix [6]float64
+
+// Identity returns the identity matrix.
+func Identity() Matrix {
+	return Matrix{1, 0, 0, 1, 0, 0}
+}
+
+// Translate returns a matrix translating by (tx, ty).
+func Translate(tx, ty float64) Matrix {
+	return Matrix{1, 0, 0, 1, tx, ty}
+}
+
+// Multiply returns m × n, i.e., the transform that applies m first and then n.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// Apply transforms the point (x, y).
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+// ScaleY returns the length of the transformed unit vertical vector,
+// which is the factor applied to font sizes by this matrix.
+func (m Matrix) ScaleY() float64 {
+	return math.Hypot(m[2], m[3])
+}
+
+// ScaleX returns the length of the transformed unit horizontal vector.
+func (m Matrix) ScaleX() float64 {
+	return math.Hypot(m[0], m[1])
+}
+
+// String returns the matrix in content-stream operand order.
+func (m Matrix) String() string {
+	return fmt.Sprintf("[%g %g %g %g %g %g]", m[0], m[1], m[2], m[3], m[4], m[5])
+}
+
+// Point is a position in user space.
+type Point struct {
+	X, Y float64
+}
+
+// Rect is an axis-aligned rectangle with (X0, Y0) the lower-left and
+// (X1, Y1) the upper-right corner, in PDF coordinates (y grows upward).
+type Rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// NewRect returns the rectangle spanned by two corners in any order.
+func NewRect(x0, y0, x1, y1 float64) Rect {
+	return Rect{
+		X0: math.Min(x0, x1),
+		Y0: math.Min(y0, y1),
+		X1: math.Max(x0, x1),
+		Y1: math.Max(y0, y1),
+	}
+}
+
+// Width returns the horizontal extent of the rectangle.
+func (r Rect) Width() float64 {
+	return r.X1 - r.X0
+}
+
+// Height returns the vertical extent of the rectangle.
+func (r Rect) Height() float64 {
+	return r.Y1 - r.Y0
+}
+
+// IsEmpty reports whether the rectangle has no area.
+func (r Rect) IsEmpty() bool {
+	return r.X0 >= r.X1 || r.Y0 >= r.Y1
+}
+
+// Contains reports whether the point lies inside the rectangle (edges included).
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.X0 && p.X <= r.X1 && p.Y >= r.Y0 && p.Y <= r.Y1
+}
+
+// Intersects reports whether the two rectangles overlap.
+func (r Rect) Intersects(o Rect) bool {
+	return r.X0 < o.X1 && o.X0 < r.X1 && r.Y0 < o.Y1 && o.Y0 < r.Y1
+}
+
+// Intersect returns the overlapping area of the two rectangles.
+// The result is empty if they do not overlap.
+func (r Rect) Intersect(o Rect) Rect {
+	return Rect{
+		X0: math.Max(r.X0, o.X0),
+		Y0: math.Max(r.Y0, o.Y0),
+		X1: math.Min(r.X1, o.X1),
+		Y1: math.Min(r.Y1, o.Y1),
+	}
+}
+
+// Union returns the smallest rectangle containing both rectangles.
+func (r Rect) Union(o Rect) Rect {
+	return Rect{
+		X0: math.Min(r.X0, o.X0),
+		Y0: math.Min(r.Y0, o.Y0),
+		X1: math.Max(r.X1, o.X1),
+		Y1: math.Max(r.Y1, o.Y1),
+	}
+}
+
+// Transform returns the bounding box of the rectangle after applying m.
+func (r Rect) Transform(m Matrix) Rect {
+	x0, y0 := m.Apply(r.X0, r.Y0)
+	x1, y1 := m.Apply(r.X1, r.Y0)
+	x2, y2 := m.Apply(r.X1, r.Y1)
+	x3, y3 := m.Apply(r.X0, r.Y1)
+	return Rect{
+		X0: math.Min(math.Min(x0, x1), math.Min(x2, x3)),
+		Y0: math.Min(math.Min(y0, y1), math.Min(y2, y3)),
+		X1: math.Max(math.Max(x0, x1), math.Max(x2, x3)),
+		Y1: math.Max(math.Max(y0, y1), math.Max(y2, y3)),
+	}
+}
+
+// String returns a debug representation of the rectangle.
+func (r Rect) String() string {
+	return fmt.Sprintf("[%g %g %g %g]", r.X0, r.Y0, r.X1, r.Y1)
+}
@@ -1,3 +1,18 @@
 module github.com/apex-woot/pdf-stream-engine
 
 go 1.25.4
+
+require golang.org/x/image v0.45.0
+
+require (
+	github.com/clipperhouse/uax29/v2 v2.7.0 // indirect
+	github.com/mattn/go-runewidth v0.0.27 // indirect
+	golang.org/x/crypto v0.54.0 // indirect
+)
+
+require (
+	github.com/hhrutter/tiff v1.0.6 // indirect
+	github.com/pdfcpu/pdfcpu v0.15.0
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/text v0.41.0
+)
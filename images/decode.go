@@ -0,0 +1,308 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	"github.com/apex-woot/pdf-stream-engine/filters"
+	"golang.org/x/image/ccitt"
+)
+
+// Decode decodes an image from its dictionary (XObject keys or inline
+// image abbreviations) and raw stream data. DCTDecode, CCITTFaxDecode,
+// and the general-purpose filters of the filters package are supported;
+// other filters return ErrUnsupportedFilter.
+//
+// Gray, Separation, and 1-bit mask images decode to *image.Gray, RGB to
+// *image.NRGBA, CMYK to *image.CMYK, and Indexed to *image.Paletted.
+// JPEG images keep the type image/jpeg returns.
+func Decode(dict map[string]any, data []byte) (image.Image, error) {
+	d, err := parseImageDict(dict)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, filter := range d.filters {
+		last := i == len(d.filters)-1
+		switch filter {
+		case "DCTDecode":
+			if !last {
+				return nil, fmt.Errorf("%w: DCTDecode must be the last filter", ErrUnsupportedFilter)
+			}
+			img, err := jpeg.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("%w: DCTDecode: %w", ErrInvalidImage, err)
+			}
+			return img, nil
+		case "CCITTFaxDecode":
+			if !last {
+				return nil, fmt.Errorf("%w: CCITTFaxDecode must be the last filter", ErrUnsupportedFilter)
+			}
+			return ccittDecode(d, data, d.filterParms(i))
+		default:
+			data, err = filters.DecodeFilter(filter, data, d.filterParms(i))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return samplesToImage(d, data)
+}
+
+// ccittDecode decodes CCITT Group 3 or Group 4 fax data into a gray image.
+func ccittDecode(d imageDict, data []byte, parms map[string]any) (image.Image, error) {
+	k, _ := intValue(parms["K"])
+	subFormat := ccitt.Group3
+	if k < 0 {
+		subFormat = ccitt.Group4
+	}
+	opts := &ccitt.Options{
+		Align:  boolValue(parms["EncodedByteAlign"]),
+		Invert: boolValue(parms["BlackIs1"]),
+	}
+	img := image.NewGray(image.Rect(0, 0, d.width, d.height))
+	if err := ccitt.DecodeIntoGray(img, bytes.NewReader(data), ccitt.MSB, subFormat, opts); err != nil {
+		return nil, fmt.Errorf("%w: CCITTFaxDecode: %w", ErrInvalidImage, err)
+	}
+	if len(d.decode) >= 2 && d.decode[0] > d.decode[1] {
+		for i, v := range img.Pix {
+			img.Pix[i] = 255 - v
+		}
+	}
+	return img, nil
+}
+
+// colorSpace is a resolved image color space.
+type colorSpace struct {
+	kind string // "gray", "rgb", "cmyk", "separation", "indexed"
+	n    int    // components per sample
+
+	// Indexed color spaces
+	base   *colorSpace
+	hival  int
+	lookup []byte
+}
+
+// resolveColorSpace resolves the ColorSpace entry. Color spaces that only
+// the page resources define (e.g., /CS0) and ICC profiles report false;
+// the caller then infers the component count from the data size.
+func resolveColorSpace(cs any) (colorSpace, bool) {
+	switch v := cs.(type) {
+	case string:
+		switch v {
+		case "DeviceGray", "CalGray":
+			return colorSpace{kind: "gray", n: 1}, true
+		case "DeviceRGB", "CalRGB", "Lab":
+			return colorSpace{kind: "rgb", n: 3}, true
+		case "DeviceCMYK":
+			return colorSpace{kind: "cmyk", n: 4}, true
+		}
+	case []any:
+		if len(v) == 0 {
+			return colorSpace{}, false
+		}
+		name, _ := v[0].(string)
+		switch name {
+		case "CalGray", "CalRGB", "Lab":
+			return resolveColorSpace(name)
+		case "ICCBased":
+			if len(v) > 1 {
+				if dict, ok := v[1].(map[string]any); ok {
+					if n, ok := intValue(dict["N"]); ok {
+						return inferColorSpace(n), true
+					}
+				}
+			}
+		case "Separation":
+			return colorSpace{kind: "separation", n: 1}, true
+		case "DeviceN":
+			if len(v) > 1 {
+				if names, ok := v[1].([]any); ok {
+					return inferColorSpace(len(names)), true
+				}
+			}
+		case "Indexed":
+			if len(v) < 4 {
+				return colorSpace{}, false
+			}
+			base, ok := resolveColorSpace(v[1])
+			if !ok || base.kind == "indexed" {
+				return colorSpace{}, false
+			}
+			hival, ok := intValue(v[2])
+			if !ok {
+				return colorSpace{}, false
+			}
+			var lookup []byte
+			switch l := v[3].(type) {
+			case string:
+				lookup = []byte(l)
+			case []byte:
+				lookup = l
+			default:
+				return colorSpace{}, false
+			}
+			return colorSpace{kind: "indexed", n: 1, base: &base, hival: hival, lookup: lookup}, true
+		}
+	}
+	return colorSpace{}, false
+}
+
+// inferColorSpace picks a device color space from a component count.
+func inferColorSpace(n int) colorSpace {
+	switch n {
+	case 3:
+		return colorSpace{kind: "rgb", n: 3}
+	case 4:
+		return colorSpace{kind: "cmyk", n: 4}
+	default:
+		return colorSpace{kind: "gray", n: 1}
+	}
+}
+
+// samplesToImage converts unfiltered sample data to an image.
+func samplesToImage(d imageDict, data []byte) (image.Image, error) {
+	cs, ok := resolveColorSpace(d.colorSpace)
+	if d.mask {
+		cs, ok = colorSpace{kind: "gray", n: 1}, true
+	}
+	if !ok {
+		// Infer the component count from the data size
+		cs = inferColorSpace(1)
+		for _, n := range []int{1, 3, 4} {
+			if (d.width*n*d.bpc+7)/8*d.height == len(data) {
+				cs = inferColorSpace(n)
+				break
+			}
+		}
+	}
+
+	if err := checkSize(d.width, d.height, cs.n); err != nil {
+		return nil, err
+	}
+	rowBytes := (d.width*cs.n*d.bpc + 7) / 8
+	if len(data) < rowBytes*d.height {
+		return nil, fmt.Errorf("%w: %d bytes of sample data, want %d", ErrInvalidImage, len(data), rowBytes*d.height)
+	}
+
+	maxVal := float64(int(1)<<d.bpc - 1)
+	decode := d.decode
+	if len(decode) < 2*cs.n {
+		decode = make([]float64, 2*cs.n)
+		for i := 0; i < cs.n; i++ {
+			decode[2*i+1] = 1
+			if cs.kind == "indexed" {
+				decode[2*i+1] = maxVal
+			}
+		}
+	}
+	// value returns component c of pixel x in row, mapped through Decode
+	value := func(row []byte, x, c int) float64 {
+		s := float64(sample(row, x*cs.n+c, d.bpc))
+		return decode[2*c] + s*(decode[2*c+1]-decode[2*c])/maxVal
+	}
+	bounds := image.Rect(0, 0, d.width, d.height)
+
+	switch cs.kind {
+	case "gray", "separation":
+		img := image.NewGray(bounds)
+		for y := 0; y < d.height; y++ {
+			row := data[y*rowBytes:]
+			for x := 0; x < d.width; x++ {
+				v := value(row, x, 0)
+				if cs.kind == "separation" {
+					v = 1 - v // Tint 1 is full ink
+				}
+				img.Pix[y*img.Stride+x] = toByte(v)
+			}
+		}
+		return img, nil
+	case "rgb":
+		img := image.NewNRGBA(bounds)
+		for y := 0; y < d.height; y++ {
+			row := data[y*rowBytes:]
+			for x := 0; x < d.width; x++ {
+				i := y*img.Stride + 4*x
+				img.Pix[i] = toByte(value(row, x, 0))
+				img.Pix[i+1] = toByte(value(row, x, 1))
+				img.Pix[i+2] = toByte(value(row, x, 2))
+				img.Pix[i+3] = 255
+			}
+		}
+		return img, nil
+	case "cmyk":
+		img := image.NewCMYK(bounds)
+		for y := 0; y < d.height; y++ {
+			row := data[y*rowBytes:]
+			for x := 0; x < d.width; x++ {
+				i := y*img.Stride + 4*x
+				for c := 0; c < 4; c++ {
+					img.Pix[i+c] = toByte(value(row, x, c))
+				}
+			}
+		}
+		return img, nil
+	case "indexed":
+		palette := indexedPalette(cs)
+		img := image.NewPaletted(bounds, palette)
+		for y := 0; y < d.height; y++ {
+			row := data[y*rowBytes:]
+			for x := 0; x < d.width; x++ {
+				index := int(value(row, x, 0) + 0.5)
+				img.Pix[y*img.Stride+x] = uint8(min(max(index, 0), len(palette)-1))
+			}
+		}
+		return img, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported color space", ErrInvalidImage)
+}
+
+// indexedPalette builds the palette of an Indexed color space from its
+// lookup table. Missing lookup bytes read as zero.
+func indexedPalette(cs colorSpace) color.Palette {
+	n := cs.base.n
+	count := min(cs.hival+1, 256)
+	palette := make(color.Palette, count)
+	entry := func(i, c int) uint8 {
+		if j := i*n + c; j < len(cs.lookup) {
+			return cs.lookup[j]
+		}
+		return 0
+	}
+	for i := range palette {
+		switch cs.base.kind {
+		case "rgb":
+			palette[i] = color.NRGBA{entry(i, 0), entry(i, 1), entry(i, 2), 255}
+		case "cmyk":
+			palette[i] = color.CMYK{entry(i, 0), entry(i, 1), entry(i, 2), entry(i, 3)}
+		case "separation":
+			palette[i] = color.Gray{255 - entry(i, 0)}
+		default:
+			palette[i] = color.Gray{entry(i, 0)}
+		}
+	}
+	return palette
+}
+
+// sample returns the i-th sample of a row packed at bpc bits per sample.
+func sample(row []byte, i, bpc int) uint32 {
+	switch bpc {
+	case 8:
+		return uint32(row[i])
+	case 16:
+		return uint32(row[2*i])<<8 | uint32(row[2*i+1])
+	default:
+		bit := i * bpc
+		b := row[bit/8]
+		shift := 8 - bpc - bit%8
+		return uint32(b>>shift) & (1<<bpc - 1)
+	}
+}
+
+// toByte converts a component in [0, 1] to a byte, clamping.
+func toByte(v float64) uint8 {
+	return uint8(min(max(v, 0), 1)*255 + 0.5)
+}
@@ -0,0 +1,39 @@
+package images
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeRejectsHugeDimensions(t *testing.T) {
+	tests := []struct {
+		name string
+		dict map[string]any
+	}{
+		{"gray", map[string]any{"Width": 1e6, "Height": 1e6, "BitsPerComponent": 8.0, "ColorSpace": "DeviceGray"}},
+		{"rgb overflowing row size", map[string]any{"Width": float64(1 << 40), "Height": float64(1 << 30), "BitsPerComponent": 8.0, "ColorSpace": "DeviceRGB"}},
+		{"rgb over the sample cap", map[string]any{"Width": float64(1 << 14), "Height": float64(1 << 13), "BitsPerComponent": 8.0, "ColorSpace": "DeviceRGB"}},
+		{"ccitt", map[string]any{"Width": 1e6, "Height": 1e6, "Filter": "CCITTFaxDecode"}},
+		{"negative", map[string]any{"Width": -1.0, "Height": 10.0}},
+		{"huge float", map[string]any{"Width": 1e300, "Height": 1e300}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.dict, []byte{0, 0, 0, 0})
+			if !errors.Is(err, ErrInvalidImage) {
+				t.Errorf("Decode() error = %v, want ErrInvalidImage", err)
+			}
+		})
+	}
+}
+
+func TestDecodeGray(t *testing.T) {
+	dict := map[string]any{"W": 2.0, "H": 2.0, "BPC": 8.0, "CS": "G"}
+	img, err := Decode(dict, []byte{0, 64, 128, 255})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got.X != 2 || got.Y != 2 {
+		t.Errorf("size = %v, want 2x2", got)
+	}
+}
@@ -0,0 +1,187 @@
+package images
+
+import (
+	"fmt"
+
+	"github.com/apex-woot/pdf-stream-engine/filters"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// keyAbbreviations maps the abbreviated keys of inline image dictionaries
+// to the full keys of image XObjects.
+var keyAbbreviations = map[string]string{
+	"W":   "Width",
+	"H":   "Height",
+	"BPC": "BitsPerComponent",
+	"CS":  "ColorSpace",
+	"F":   "Filter",
+	"DP":  "DecodeParms",
+	"D":   "Decode",
+	"IM":  "ImageMask",
+	"I":   "Interpolate",
+	"L":   "Length",
+}
+
+// colorSpaceAbbreviations maps inline image color space abbreviations to
+// full names.
+var colorSpaceAbbreviations = map[string]string{
+	"G":    "DeviceGray",
+	"RGB":  "DeviceRGB",
+	"CMYK": "DeviceCMYK",
+	"I":    "Indexed",
+}
+
+// imageDict is the normalized form of an image dictionary.
+type imageDict struct {
+	width, height int
+	bpc           int
+	colorSpace    any
+	filters       []string
+	parms         []map[string]any // one per filter, nil entries allowed
+	decode        []float64
+	mask          bool
+}
+
+// parseImageDict normalizes an image XObject or inline image dictionary.
+func parseImageDict(raw map[string]any) (imageDict, error) {
+	dict := make(map[string]any, len(raw))
+	for key, value := range raw {
+		if full, ok := keyAbbreviations[key]; ok {
+			key = full
+		}
+		dict[key] = value
+	}
+
+	var d imageDict
+	var ok bool
+	if d.width, ok = intValue(dict["Width"]); !ok || d.width <= 0 {
+		return d, fmt.Errorf("%w: missing or bad Width", ErrInvalidImage)
+	}
+	if d.height, ok = intValue(dict["Height"]); !ok || d.height <= 0 {
+		return d, fmt.Errorf("%w: missing or bad Height", ErrInvalidImage)
+	}
+	if err := checkSize(d.width, d.height, 1); err != nil {
+		return d, err
+	}
+	d.mask = boolValue(dict["ImageMask"])
+	d.bpc, ok = intValue(dict["BitsPerComponent"])
+	if d.mask || !ok {
+		d.bpc = 1 // Masks are always 1 bit; JPEG and CCITT images may omit it
+	}
+	switch d.bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		return d, fmt.Errorf("%w: BitsPerComponent %d", ErrInvalidImage, d.bpc)
+	}
+
+	d.colorSpace = dict["ColorSpace"]
+	if name, ok := d.colorSpace.(string); ok {
+		if full, ok := colorSpaceAbbreviations[name]; ok {
+			d.colorSpace = full
+		}
+	}
+	if cs, ok := d.colorSpace.([]any); ok && len(cs) > 0 {
+		if name, ok := cs[0].(string); ok && colorSpaceAbbreviations[name] == "Indexed" {
+			cs = append([]any{"Indexed"}, cs[1:]...)
+			if base, ok := cs[1].(string); ok && len(cs) > 1 {
+				if full, ok := colorSpaceAbbreviations[base]; ok {
+					cs[1] = full
+				}
+			}
+			d.colorSpace = cs
+		}
+	}
+
+	switch f := dict["Filter"].(type) {
+	case string:
+		d.filters = []string{f}
+	case []any:
+		for _, v := range f {
+			if name, ok := v.(string); ok {
+				d.filters = append(d.filters, name)
+			}
+		}
+	}
+	for i, f := range d.filters {
+		d.filters[i] = filters.FullName(f)
+	}
+
+	switch p := dict["DecodeParms"].(type) {
+	case []any:
+		for _, v := range p {
+			d.parms = append(d.parms, dictValue(v))
+		}
+	case nil:
+	default:
+		d.parms = []map[string]any{dictValue(p)}
+	}
+
+	if arr, ok := dict["Decode"].([]any); ok {
+		for _, v := range arr {
+			f, ok := v.(float64)
+			if !ok {
+				return d, fmt.Errorf("%w: non-numeric Decode entry", ErrInvalidImage)
+			}
+			d.decode = append(d.decode, f)
+		}
+	}
+	return d, nil
+}
+
+// filterParms returns the decode parameters of the i-th filter.
+func (d imageDict) filterParms(i int) map[string]any {
+	if i < len(d.parms) && d.parms[i] != nil {
+		return d.parms[i]
+	}
+	return map[string]any{}
+}
+
+// dictValue converts a dictionary operand to a map. Inline images carry
+// dictionaries as the parser.Dict values the parser returns for them.
+func dictValue(v any) map[string]any {
+	switch d := v.(type) {
+	case map[string]any:
+		return d
+	case parser.Dict:
+		return d
+	}
+	return nil
+}
+
+// maxImageSamples caps the samples (pixels times color components) of a
+// decoded image, so that a corrupt or hostile dictionary cannot make
+// Decode allocate gigabytes. 1<<28 samples cover an A3 page scanned in
+// color at 600 dpi.
+const maxImageSamples = 1 << 28
+
+// checkSize reports ErrInvalidImage if an image of width by height pixels
+// with n components per pixel exceeds maxImageSamples. width and height
+// must be positive.
+func checkSize(width, height, n int) error {
+	if width > maxImageSamples/n/height {
+		return fmt.Errorf("%w: %dx%d image with %d components is too large", ErrInvalidImage, width, height, n)
+	}
+	return nil
+}
+
+func intValue(v any) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+func boolValue(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
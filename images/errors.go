@@ -0,0 +1,20 @@
+package images
+
+import (
+	"errors"
+
+	"github.com/apex-woot/pdf-stream-engine/filters"
+)
+
+var (
+	// ErrUnsupportedFilter is returned for images encoded with a filter
+	// this package cannot decode (e.g., JPXDecode, JBIG2Decode).
+	ErrUnsupportedFilter = filters.ErrUnsupportedFilter
+
+	// ErrInvalidImage is returned for image dictionaries or data that are
+	// inconsistent (missing dimensions, truncated samples).
+	ErrInvalidImage = errors.New("invalid image")
+
+	// ErrNotImage is returned when a Do operand names a form XObject.
+	ErrNotImage = errors.New("XObject is not an image")
+)
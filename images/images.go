@@ -0,0 +1,95 @@
+// Package images extracts the images painted on a page, both image
+// XObjects drawn with Do and inline images (BI/ID/EI), decodes them to
+// image.Image, and reports where on the page they are placed.
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// XObject is an XObject stream from the page resources.
+type XObject struct {
+	// Subtype is "Image" or "Form".
+	Subtype string
+
+	// Dict holds the stream dictionary entries (Width, Height,
+	// BitsPerComponent, ColorSpace, Filter, DecodeParms, ImageMask,
+	// Decode, ...) with names as strings without the slash, numbers as
+	// float64, arrays as []any, dictionaries as map[string]any, and
+	// booleans as bool.
+	Dict map[string]any
+
+	// Data is the raw stream data, still encoded by its filters.
+	Data []byte
+}
+
+// ResourceProvider resolves XObject resource names of a page.
+type ResourceProvider interface {
+	XObject(name string) (*XObject, error)
+}
+
+// Image is a decoded image and its placement.
+type Image struct {
+	// Name is the XObject resource name, or "" for inline images.
+	Name string
+
+	// Image is the decoded image, or nil if Err is set.
+	Image image.Image
+
+	// Bounds is the placement rectangle on the page.
+	Bounds geom.Rect
+
+	// Matrix is the CTM the image was painted with.
+	Matrix geom.Matrix
+
+	// Err reports why the image could not be decoded.
+	Err error
+}
+
+// Extract resolves and decodes the images among placements (see
+// interpreter.GetImagePlacements). Placements naming form XObjects are
+// skipped; images inside forms are not found. Images that fail to decode
+// are returned with Err set so callers still see their placement.
+// resources may be nil if the page has only inline images.
+func Extract(placements []interpreter.ImagePlacement, resources ResourceProvider) []Image {
+	var result []Image
+	for _, p := range placements {
+		img := Image{Name: p.Name, Bounds: p.Bounds, Matrix: p.Matrix}
+		switch {
+		case p.Inline != nil:
+			img.Image, img.Err = Decode(p.Inline.Dict, p.Inline.Data)
+		case resources == nil:
+			img.Err = fmt.Errorf("XObject %q: no resources", p.Name)
+		default:
+			xobj, err := resources.XObject(p.Name)
+			if err != nil {
+				img.Err = fmt.Errorf("XObject %q: %w", p.Name, err)
+				break
+			}
+			if xobj.Subtype != "Image" {
+				continue
+			}
+			img.Image, img.Err = Decode(xobj.Dict, xobj.Data)
+			if img.Err != nil {
+				img.Err = fmt.Errorf("XObject %q: %w", p.Name, img.Err)
+			}
+		}
+		result = append(result, img)
+	}
+	return result
+}
+
+// ExtractFromStream interprets a decoded content stream and returns its
+// images. Text and paths are ignored.
+func ExtractFromStream(data []byte, resources ResourceProvider) ([]Image, error) {
+	interp := interpreter.NewInterpreter(nil)
+	if err := interp.ProcessStream(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return Extract(interp.GetImagePlacements(), resources), nil
+}
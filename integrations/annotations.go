@@ -0,0 +1,154 @@
+// Package integrations extracts text from PDF structures that live outside
+// page content streams, such as annotation appearance streams and
+// embedded file attachments.
+//
+// The types here mirror the relevant PDF dictionaries; callers fill them
+// from their PDF library (decoding streams and building font registries)
+// and this package decides what is visible and interprets it.
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// AppearanceStream is a decoded form XObject drawn for an annotation.
+type AppearanceStream struct {
+	// Content is the decoded stream data.
+	Content []byte
+
+	// Fonts resolves the stream's font resource names. If nil, a default
+	// registry with WinAnsi encoding is used.
+	Fonts *font.FontRegistry
+}
+
+// Appearance is one entry of an appearance dictionary (/N, /R, or /D).
+// It is either a single stream or a subdictionary of streams keyed by
+// appearance state (e.g. /On and /Off for a checkbox).
+type Appearance struct {
+	// Stream is set when the entry is a single stream.
+	Stream *AppearanceStream
+
+	// States is set when the entry is a subdictionary keyed by state name.
+	// A nil stream for a state means the state draws nothing.
+	States map[string]*AppearanceStream
+}
+
+// Select returns the stream to draw for the given appearance state.
+// A single stream ignores the state. With a state dictionary, the named
+// state is used; if state is empty and the dictionary has exactly one
+// entry, that entry is used.
+func (a Appearance) Select(state string) (*AppearanceStream, bool) {
+	if a.Stream != nil {
+		return a.Stream, true
+	}
+	if state == "" && len(a.States) == 1 {
+		for _, s := range a.States {
+			return s, s != nil
+		}
+	}
+	s, ok := a.States[state]
+	return s, ok && s != nil
+}
+
+// IsEmpty reports whether the entry has no streams at all.
+func (a Appearance) IsEmpty() bool {
+	return a.Stream == nil && len(a.States) == 0
+}
+
+// AppearanceMode selects which appearance dictionary entry is drawn.
+type AppearanceMode int
+
+const (
+	// AppearanceNormal is /N, drawn when the annotation is not interacting
+	// with the user. This is what a printed or flattened page shows.
+	AppearanceNormal AppearanceMode = iota
+
+	// AppearanceRollover is /R, drawn while the cursor hovers over the
+	// annotation.
+	AppearanceRollover
+
+	// AppearanceDown is /D, drawn while the annotation is pressed.
+	AppearanceDown
+)
+
+func (m AppearanceMode) String() string {
+	switch m {
+	case AppearanceNormal:
+		return "N"
+	case AppearanceRollover:
+		return "R"
+	case AppearanceDown:
+		return "D"
+	default:
+		return fmt.Sprintf("AppearanceMode(%d)", int(m))
+	}
+}
+
+// Annotation holds the parts of an annotation dictionary that affect its
+// rendered text.
+type Annotation struct {
+	// Subtype is the annotation type without the slash (Widget, FreeText, ...).
+	Subtype string
+
+	// Rect is the annotation rectangle in default user space.
+	Rect geom.Rect
+
+	// State is the current appearance state (/AS), e.g. "On" or "Off".
+	State string
+
+	// Normal, Rollover, and Down are the /AP entries. Rollover and Down
+	// fall back to Normal when absent, as viewers do.
+	Normal, Rollover, Down Appearance
+}
+
+// VisibleAppearance returns the appearance stream drawn in mode, applying
+// the /AS state. It reports false when nothing is drawn, for example when
+// a checkbox is Off and its Off state has no stream.
+func (a Annotation) VisibleAppearance(mode AppearanceMode) (*AppearanceStream, bool) {
+	entry := a.Normal
+	switch mode {
+	case AppearanceRollover:
+		if !a.Rollover.IsEmpty() {
+			entry = a.Rollover
+		}
+	case AppearanceDown:
+		if !a.Down.IsEmpty() {
+			entry = a.Down
+		}
+	}
+	return entry.Select(a.State)
+}
+
+// States lists the appearance states defined by the normal appearance,
+// sorted by name.
+func (a Annotation) States() []string {
+	states := make([]string, 0, len(a.Normal.States))
+	for name := range a.Normal.States {
+		states = append(states, name)
+	}
+	sort.Strings(states)
+	return states
+}
+
+// AnnotationText extracts the text of the appearance visible in mode.
+// It returns "" with a nil error when the current state draws nothing.
+// Enable opts.RecognizeCheckboxes to get "[x]"/"[ ]" markers for checkbox
+// glyphs drawn by the On state.
+func AnnotationText(a Annotation, mode AppearanceMode, opts interpreter.Options) (string, error) {
+	stream, ok := a.VisibleAppearance(mode)
+	if !ok {
+		return "", nil
+	}
+	interp := interpreter.NewInterpreterWithOptions(stream.Fonts, opts)
+	if err := interp.ProcessStream(bytes.NewReader(stream.Content)); err != nil {
+		return strings.TrimSpace(interp.GetText()), fmt.Errorf("%s appearance (state %q): %w", mode, a.State, err)
+	}
+	return strings.TrimSpace(interp.GetText()), nil
+}
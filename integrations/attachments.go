@@ -0,0 +1,245 @@
+package integrations
+
+import (
+	"bytes"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/textstring"
+)
+
+// EmbeddedFile is one entry of the document's EmbeddedFiles name tree,
+// with its file specification and decoded embedded file stream.
+type EmbeddedFile struct {
+	// Name is the name tree key.
+	Name string
+
+	// FileName is the file specification's /UF, or /F if absent,
+	// already decoded (see textstring.Decode).
+	FileName string
+
+	// Description is the file specification's /Desc.
+	Description string
+
+	// MIMEType is the embedded file stream's /Subtype, e.g. "text/xml".
+	// The "#2F" escape producers write for the slash is accepted.
+	MIMEType string
+
+	// Relationship is the file specification's /AFRelationship
+	// (Data, Source, Alternative, Supplement, Unspecified).
+	Relationship string
+
+	// Data is the decoded embedded file stream.
+	Data []byte
+}
+
+// AttachmentKind classifies a text-like attachment.
+type AttachmentKind int
+
+const (
+	// AttachmentText is plain text.
+	AttachmentText AttachmentKind = iota
+
+	// AttachmentCSV is comma- or tab-separated values.
+	AttachmentCSV
+
+	// AttachmentXML is XML, including e-invoices.
+	AttachmentXML
+)
+
+func (k AttachmentKind) String() string {
+	switch k {
+	case AttachmentText:
+		return "text"
+	case AttachmentCSV:
+		return "csv"
+	case AttachmentXML:
+		return "xml"
+	default:
+		return "unknown"
+	}
+}
+
+// Attachment is an embedded file whose content was extracted as text.
+type Attachment struct {
+	File EmbeddedFile
+	Kind AttachmentKind
+
+	// Text is the attachment content decoded to UTF-8.
+	Text string
+
+	// Invoice reports a structured e-invoice (ZUGFeRD/Factur-X,
+	// XRechnung, UBL).
+	Invoice bool
+}
+
+// AttachmentOptions controls attachment extraction. Zero fields select
+// the defaults.
+type AttachmentOptions struct {
+	// MaxSize is the largest attachment, in bytes, that is extracted.
+	// Default 10 MiB.
+	MaxSize int
+}
+
+func (o AttachmentOptions) withDefaults() AttachmentOptions {
+	if o.MaxSize <= 0 {
+		o.MaxSize = 10 << 20
+	}
+	return o
+}
+
+// textExtensions maps file extensions to attachment kinds.
+var textExtensions = map[string]AttachmentKind{
+	".txt":  AttachmentText,
+	".text": AttachmentText,
+	".md":   AttachmentText,
+	".csv":  AttachmentCSV,
+	".tsv":  AttachmentCSV,
+	".xml":  AttachmentXML,
+}
+
+// invoiceFileNames are the attachment names mandated by e-invoice formats.
+var invoiceFileNames = map[string]bool{
+	"factur-x.xml":        true,
+	"zugferd-invoice.xml": true,
+	"xrechnung.xml":       true,
+	"order-x.xml":         true,
+}
+
+// invoiceMarkers are root element names and namespaces of e-invoice XML.
+var invoiceMarkers = []string{
+	"CrossIndustryInvoice",
+	"CrossIndustryDocument",
+	"urn:oasis:names:specification:ubl:schema:xsd:Invoice",
+	"urn:oasis:names:specification:ubl:schema:xsd:CreditNote",
+}
+
+// ExtractAttachments returns the text-like attachments among files, in
+// input order. Binary payloads (images, spreadsheets, nested PDFs) and
+// files larger than opts.MaxSize are skipped.
+func ExtractAttachments(files []EmbeddedFile, opts AttachmentOptions) []Attachment {
+	opts = opts.withDefaults()
+
+	var attachments []Attachment
+	for _, f := range files {
+		if len(f.Data) > opts.MaxSize {
+			continue
+		}
+		kind, ok := DetectAttachmentKind(f)
+		if !ok {
+			continue
+		}
+		text := decodeAttachmentText(f.Data)
+		attachments = append(attachments, Attachment{
+			File:    f,
+			Kind:    kind,
+			Text:    text,
+			Invoice: kind == AttachmentXML && isInvoice(f, text),
+		})
+	}
+	return attachments
+}
+
+// DetectAttachmentKind classifies f from its MIME type, then its file
+// extension, then its content. It reports false for binary payloads.
+func DetectAttachmentKind(f EmbeddedFile) (AttachmentKind, bool) {
+	if looksBinary(f.Data) {
+		return 0, false
+	}
+
+	mime := strings.ToLower(strings.ReplaceAll(f.MIMEType, "#2F", "/"))
+	mime, _, _ = strings.Cut(mime, ";")
+	switch {
+	case mime == "text/csv" || mime == "text/tab-separated-values":
+		return AttachmentCSV, true
+	case mime == "text/xml" || mime == "application/xml" || strings.HasSuffix(mime, "+xml"):
+		return AttachmentXML, true
+	case strings.HasPrefix(mime, "text/"):
+		return AttachmentText, true
+	}
+
+	name := f.FileName
+	if name == "" {
+		name = f.Name
+	}
+	if kind, ok := textExtensions[strings.ToLower(path.Ext(name))]; ok {
+		return kind, true
+	}
+
+	// Unknown type: sniff the content
+	head := bytes.TrimLeft(stripBOM(f.Data), " \t\r\n")
+	if bytes.HasPrefix(head, []byte("<?xml")) {
+		return AttachmentXML, true
+	}
+	return 0, false
+}
+
+// JoinWithAttachments appends the attachments to the page text, each
+// under a header line naming the file, so callers get one text covering
+// the whole document.
+func JoinWithAttachments(pageText string, attachments []Attachment) string {
+	var b strings.Builder
+	b.WriteString(pageText)
+	for _, a := range attachments {
+		name := a.File.FileName
+		if name == "" {
+			name = a.File.Name
+		}
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteByte('\n')
+		}
+		b.WriteString("\n--- Attachment: " + name + " ---\n")
+		b.WriteString(a.Text)
+	}
+	return b.String()
+}
+
+// looksBinary reports whether data contains NUL bytes outside a UTF-16
+// byte order mark prefix, checking the first 8 KiB.
+func looksBinary(data []byte) bool {
+	if bytes.HasPrefix(data, []byte{0xFE, 0xFF}) || bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+		return false
+	}
+	if len(data) > 8<<10 {
+		data = data[:8<<10]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// stripBOM removes a UTF-8 byte order mark.
+func stripBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+}
+
+// decodeAttachmentText decodes UTF-8 (with or without BOM) and UTF-16
+// with BOM. Other data that is not valid UTF-8 is assumed to be
+// Windows-1252, the usual encoding of legacy text exports.
+func decodeAttachmentText(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}), bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return textstring.Decode(data)
+	case utf8.Valid(stripBOM(data)):
+		return string(stripBOM(data))
+	default:
+		return font.DecodeWinAnsi(data)
+	}
+}
+
+// isInvoice reports whether an XML attachment is an e-invoice.
+func isInvoice(f EmbeddedFile, text string) bool {
+	if invoiceFileNames[strings.ToLower(f.FileName)] || invoiceFileNames[strings.ToLower(f.Name)] {
+		return true
+	}
+	head := text
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	for _, marker := range invoiceMarkers {
+		if strings.Contains(head, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,359 @@
+// Package streamgen produces synthetic PDF content streams for benchmarks
+// and fuzz seeding. Output is fully determined by the Config (including its
+// Seed), so performance work can be measured reproducibly without shipping
+// large binary fixtures.
+package streamgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// FontSpec describes a font the generated streams select with Tf.
+type FontSpec struct {
+	// Name is the resource name (without the leading slash).
+	Name string
+
+	// Encoding is the encoding registered for the font.
+	Encoding font.EncodingType
+
+	// MultiByte makes the font use 2-byte codes and an identity-style
+	// ToUnicode CMap, like a CID font.
+	MultiByte bool
+}
+
+// Mix weights the operator categories emitted. Zero weights disable a
+// category; the zero Mix selects DefaultMix.
+type Mix struct {
+	Text          int // Tj, TJ, ', "
+	Positioning   int // Td, TD, Tm, T*
+	TextState     int // Tf, Tc, Tw, Tr
+	Graphics      int // q/Q, cm, rg, re, path painting
+	MarkedContent int // BMC/BDC ... EMC
+}
+
+// DefaultMix approximates the operator distribution of typical text-heavy pages.
+var DefaultMix = Mix{Text: 40, Positioning: 30, TextState: 10, Graphics: 15, MarkedContent: 5}
+
+// Pathological enables constructs that stress parser and interpreter edge cases.
+type Pathological struct {
+	HugeStrings   bool // literal and hex strings of tens of kilobytes
+	DeepArrays    bool // deeply nested operand arrays
+	UnbalancedOps bool // stray Q, EMC, ET and show ops outside BT
+	GarbageTokens bool // unparsable operands and unknown operators
+	EscapeHeavy   bool // strings dense with octal and backslash escapes
+	InlineDicts   bool // BDC with nested inline dictionaries
+}
+
+// Config controls generation.
+type Config struct {
+	// Size is the approximate output size in bytes.
+	Size int
+
+	// Seed makes output reproducible; equal configs yield equal streams.
+	Seed uint64
+
+	// Mix weights operator categories.
+	Mix Mix
+
+	// Fonts are selected at random by Tf. Nil selects DefaultFonts.
+	Fonts []FontSpec
+
+	// Pathological enables adversarial constructs.
+	Pathological Pathological
+}
+
+// DefaultFonts covers a single-byte WinAnsi font, a MacRoman font,
+// and a 2-byte CID-style font.
+var DefaultFonts = []FontSpec{
+	{Name: "F1", Encoding: font.EncodingWinAnsi},
+	{Name: "F2", Encoding: font.EncodingMacRoman},
+	{Name: "C0", Encoding: font.EncodingIdentity, MultiByte: true},
+}
+
+// Generate returns a synthetic content stream for cfg.
+func Generate(cfg Config) []byte {
+	var buf bytes.Buffer
+	buf.Grow(cfg.Size + 256)
+	_, _ = WriteTo(&buf, cfg)
+	return buf.Bytes()
+}
+
+// WriteTo writes a synthetic content stream for cfg to w and returns the
+// number of bytes written.
+func WriteTo(w io.Writer, cfg Config) (int64, error) {
+	g := newGenerator(cfg)
+	cw := &countingWriter{w: w}
+	for cw.n < int64(cfg.Size) && cw.err == nil {
+		g.block(cw)
+	}
+	return cw.n, cw.err
+}
+
+// Registry returns a font registry matching the fonts used by cfg, with
+// identity ToUnicode CMaps for multi-byte fonts.
+func Registry(cfg Config) *font.FontRegistry {
+	reg := font.NewFontRegistry()
+	for _, spec := range fonts(cfg) {
+		if spec.MultiByte {
+			f := reg.RegisterWithToUnicode(spec.Name, identityCMap())
+			f.IsMultiByte = true
+			continue
+		}
+		reg.RegisterSimple(spec.Name, spec.Encoding)
+	}
+	return reg
+}
+
+// identityCMap maps the 2-byte codes used by the generator to Basic Latin.
+func identityCMap() *font.CMap {
+	var src bytes.Buffer
+	src.WriteString("1 begincodespacerange <0000> <FFFF> endcodespacerange\n")
+	src.WriteString("1 beginbfrange <0020> <007E> <0020> endbfrange\n")
+	cmap, err := font.ParseToUnicodeCMap(&src)
+	if err != nil {
+		panic(fmt.Sprintf("streamgen: identity CMap: %v", err))
+	}
+	return cmap
+}
+
+func fonts(cfg Config) []FontSpec {
+	if len(cfg.Fonts) == 0 {
+		return DefaultFonts
+	}
+	return cfg.Fonts
+}
+
+// generator holds the per-stream random source and state.
+type generator struct {
+	cfg   Config
+	rng   *rand.Rand
+	fonts []FontSpec
+	mix   Mix
+	font  FontSpec
+}
+
+func newGenerator(cfg Config) *generator {
+	mix := cfg.Mix
+	if mix == (Mix{}) {
+		mix = DefaultMix
+	}
+	fs := fonts(cfg)
+	return &generator{
+		cfg:   cfg,
+		rng:   rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15)),
+		fonts: fs,
+		mix:   mix,
+		font:  fs[0],
+	}
+}
+
+// words is the vocabulary for generated text.
+var words = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "invoice", "total", "page",
+	"stream", "engine", "font", "glyph", "(paren)", "back\\slash", "1,234.56",
+	"caf\351", "\223quoted\224", "na\357ve",
+}
+
+// block writes one BT ... ET text object, possibly surrounded by graphics
+// operators, marked content, and pathological constructs.
+func (g *generator) block(w io.Writer) {
+	if g.mix.Graphics > 0 && g.rng.IntN(2) == 0 {
+		g.graphics(w)
+	}
+	marked := g.mix.MarkedContent > 0 && g.rng.IntN(100) < g.mix.MarkedContent*4
+	if marked {
+		g.beginMarked(w)
+	}
+	fmt.Fprint(w, "BT\n")
+	g.selectFont(w)
+	fmt.Fprintf(w, "%d %d Td\n", 36+g.rng.IntN(400), 36+g.rng.IntN(720))
+
+	ops := 4 + g.rng.IntN(12)
+	for range ops {
+		g.op(w)
+	}
+	fmt.Fprint(w, "ET\n")
+	if marked {
+		fmt.Fprint(w, "EMC\n")
+	}
+	g.pathological(w)
+}
+
+// op writes one operator chosen by the configured mix.
+func (g *generator) op(w io.Writer) {
+	total := g.mix.Text + g.mix.Positioning + g.mix.TextState
+	if total == 0 {
+		g.showText(w)
+		return
+	}
+	n := g.rng.IntN(total)
+	switch {
+	case n < g.mix.Text:
+		g.showText(w)
+	case n < g.mix.Text+g.mix.Positioning:
+		g.position(w)
+	default:
+		g.textState(w)
+	}
+}
+
+func (g *generator) showText(w io.Writer) {
+	switch g.rng.IntN(4) {
+	case 0:
+		fmt.Fprintf(w, "%s Tj\n", g.str(g.word()))
+	case 1:
+		fmt.Fprint(w, "[")
+		for i := range 1 + g.rng.IntN(6) {
+			if i > 0 {
+				fmt.Fprintf(w, " %d ", g.rng.IntN(400)-300)
+			}
+			fmt.Fprint(w, g.str(g.word()))
+		}
+		fmt.Fprint(w, "] TJ\n")
+	case 2:
+		fmt.Fprintf(w, "%s '\n", g.str(g.word()))
+	default:
+		fmt.Fprintf(w, "%d %d %s \"\n", g.rng.IntN(3), g.rng.IntN(2), g.str(g.word()))
+	}
+}
+
+func (g *generator) position(w io.Writer) {
+	switch g.rng.IntN(4) {
+	case 0:
+		fmt.Fprintf(w, "%d 0 Td\n", 1+g.rng.IntN(60))
+	case 1:
+		fmt.Fprintf(w, "0 %d TD\n", -10-g.rng.IntN(10))
+	case 2:
+		fmt.Fprintf(w, "1 0 0 1 %d %d Tm\n", 36+g.rng.IntN(500), 36+g.rng.IntN(720))
+	default:
+		fmt.Fprint(w, "T*\n")
+	}
+}
+
+func (g *generator) textState(w io.Writer) {
+	switch g.rng.IntN(4) {
+	case 0:
+		g.selectFont(w)
+	case 1:
+		fmt.Fprintf(w, "%.2f Tc\n", g.rng.Float64())
+	case 2:
+		fmt.Fprintf(w, "%.2f Tw\n", g.rng.Float64()*3)
+	default:
+		fmt.Fprintf(w, "%d Tr\n", g.rng.IntN(4))
+	}
+}
+
+func (g *generator) selectFont(w io.Writer) {
+	g.font = g.fonts[g.rng.IntN(len(g.fonts))]
+	fmt.Fprintf(w, "/%s %d Tf\n", g.font.Name, 6+g.rng.IntN(18))
+}
+
+func (g *generator) graphics(w io.Writer) {
+	fmt.Fprint(w, "q\n")
+	fmt.Fprintf(w, "1 0 0 1 %d %d cm\n", g.rng.IntN(20), g.rng.IntN(20))
+	fmt.Fprintf(w, "%.3f %.3f %.3f rg\n", g.rng.Float64(), g.rng.Float64(), g.rng.Float64())
+	fmt.Fprintf(w, "%d %d %d %d re f\n", g.rng.IntN(500), g.rng.IntN(700), 10+g.rng.IntN(200), 1+g.rng.IntN(20))
+	fmt.Fprint(w, "Q\n")
+}
+
+func (g *generator) beginMarked(w io.Writer) {
+	switch {
+	case g.cfg.Pathological.InlineDicts && g.rng.IntN(2) == 0:
+		fmt.Fprintf(w, "/Span <</ActualText (x) /Nested <</MCID %d /Lang (en-US)>> >> BDC\n", g.rng.IntN(100))
+	case g.rng.IntN(3) == 0:
+		fmt.Fprint(w, "/Artifact <</Type /Pagination>> BDC\n")
+	default:
+		fmt.Fprintf(w, "/P <</MCID %d>> BDC\n", g.rng.IntN(100))
+	}
+}
+
+// pathological occasionally writes an adversarial construct.
+func (g *generator) pathological(w io.Writer) {
+	p := g.cfg.Pathological
+	if g.rng.IntN(8) != 0 {
+		return
+	}
+	switch g.rng.IntN(5) {
+	case 0:
+		if p.HugeStrings {
+			fmt.Fprintf(w, "BT /%s 10 Tf (%s) Tj <%s> Tj ET\n", g.font.Name,
+				bytes.Repeat([]byte("x"), 16<<10+g.rng.IntN(16<<10)),
+				bytes.Repeat([]byte("4142"), 8<<10))
+		}
+	case 1:
+		if p.DeepArrays {
+			depth := 32 + g.rng.IntN(64)
+			fmt.Fprintf(w, "%s1%s pop\n", bytes.Repeat([]byte("["), depth), bytes.Repeat([]byte("]"), depth))
+		}
+	case 2:
+		if p.UnbalancedOps {
+			fmt.Fprint(w, "Q EMC ET (orphan) Tj\n")
+		}
+	case 3:
+		if p.GarbageTokens {
+			fmt.Fprint(w, "BT 12 #junk @@ 1.2.3 Xyzzy (ok) Tj ET\n")
+		}
+	case 4:
+		if p.EscapeHeavy {
+			fmt.Fprint(w, "BT (\\101\\102\\103\\(\\)\\\\\\n\\r\\t\\b\\f\\7\\77\\777\\q) Tj ET\n")
+		}
+	}
+}
+
+func (g *generator) word() string {
+	return words[g.rng.IntN(len(words))]
+}
+
+// str encodes s as a string operand suited to the current font.
+func (g *generator) str(s string) string {
+	if g.font.MultiByte {
+		var b bytes.Buffer
+		b.WriteByte('<')
+		for i := 0; i < len(s); i++ {
+			fmt.Fprintf(&b, "00%02X", s[i])
+		}
+		b.WriteByte('>')
+		return b.String()
+	}
+	if g.rng.IntN(5) == 0 {
+		return fmt.Sprintf("<%X>", s)
+	}
+	var b bytes.Buffer
+	b.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c >= 0x80:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// countingWriter tracks bytes written and the first error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,288 @@
+package interpreter
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/geom"
+)
+
+// Checkbox is a checkbox or radio-button indicator recognized in the text.
+type Checkbox struct {
+	// Checked reports whether the indicator is in its "on" state.
+	Checked bool
+
+	// Radio is true for round (radio-button) indicators.
+	Radio bool
+
+	// Label is the text following the indicator on the same line.
+	Label string
+
+	// offset is the position of the marker in the text builder.
+	offset int
+}
+
+// Marker returns the plain-text marker emitted for the indicator:
+// "[x]"/"[ ]" for checkboxes and "(x)"/"( )" for radio buttons.
+func (cb Checkbox) Marker() string {
+	switch {
+	case cb.Radio && cb.Checked:
+		return "(x)"
+	case cb.Radio:
+		return "( )"
+	case cb.Checked:
+		return "[x]"
+	default:
+		return "[ ]"
+	}
+}
+
+// checkboxGlyph describes the state a glyph represents.
+type checkboxGlyph struct {
+	checked bool
+	radio   bool
+}
+
+// zapfDingbatsCheckboxes maps ZapfDingbats codes to checkbox states.
+// These cover the AcroForm check styles (check, cross, circle, square,
+// diamond, star) plus the hollow box and circle glyphs used for "off".
+var zapfDingbatsCheckboxes = map[byte]checkboxGlyph{
+	0x33: {checked: true},               // a19 check mark
+	0x34: {checked: true},               // a20 heavy check mark
+	0x35: {checked: true},               // a21 multiplication x
+	0x36: {checked: true},               // a22 heavy multiplication x
+	0x37: {checked: true},               // a23 ballot x
+	0x38: {checked: true},               // a24 heavy ballot x
+	0x48: {checked: true},               // a35 black star
+	0x55: {checked: true},               // a48 eight pointed pinwheel star
+	0x56: {checked: true},               // a49 six pointed black star
+	0x6C: {checked: true, radio: true},  // a71 black circle
+	0x6D: {checked: false, radio: true}, // a72 shadowed white circle
+	0x6E: {checked: true},               // a73 black square
+	0x6F: {checked: false},              // a74 lower right drop-shadowed white square
+	0x70: {checked: false},              // a203 upper right drop-shadowed white square
+	0x71: {checked: false},              // a75 lower right shadowed white square
+	0x72: {checked: false},              // a204 upper right shadowed white square
+	0x75: {checked: true},               // a78 black diamond
+}
+
+// wingdingsCheckboxes maps Wingdings codes to checkbox states.
+var wingdingsCheckboxes = map[byte]checkboxGlyph{
+	0x6C: {checked: true, radio: true},  // black circle
+	0x6E: {checked: true},               // black square
+	0x6F: {checked: false},              // white square
+	0x71: {checked: false},              // shadowed white square
+	0x72: {checked: false},              // shadowed white square
+	0xA1: {checked: false, radio: true}, // white circle
+	0xA4: {checked: true, radio: true},  // fisheye
+	0xA8: {checked: false},              // white medium square
+	0xFB: {checked: true},               // ballot x
+	0xFC: {checked: true},               // check mark
+	0xFD: {checked: true},               // ballot box with x
+	0xFE: {checked: true},               // ballot box with check
+}
+
+// unicodeCheckboxes maps decoded Unicode characters to checkbox states.
+// Filled squares and circles are deliberately absent: outside dingbat
+// fonts they are far more often list bullets than form controls.
+var unicodeCheckboxes = map[rune]checkboxGlyph{
+	'☐': {checked: false},              // ballot box
+	'☑': {checked: true},               // ballot box with check
+	'☒': {checked: true},               // ballot box with x
+	'✓': {checked: true},               // check mark
+	'✔': {checked: true},               // heavy check mark
+	'✗': {checked: true},               // ballot x
+	'✘': {checked: true},               // heavy ballot x
+	'□': {checked: false},              // white square
+	'❏': {checked: false},              // lower right drop-shadowed white square
+	'❐': {checked: false},              // upper right drop-shadowed white square
+	'❑': {checked: false},              // lower right shadowed white square
+	'❒': {checked: false},              // upper right shadowed white square
+	'○': {checked: false, radio: true}, // white circle
+	'◯': {checked: false, radio: true}, // large circle
+	'◉': {checked: true, radio: true},  // fisheye
+	'◎': {checked: true, radio: true},  // bullseye
+}
+
+// dingbatTable returns the glyph table for fonts whose codes are not
+// meaningful as text (ZapfDingbats, Wingdings), or nil.
+func dingbatTable(f *font.Font) map[byte]checkboxGlyph {
+	name := strings.ToLower(f.BaseFont)
+	switch {
+	case strings.Contains(name, "zapfdingbats"), strings.Contains(name, "dingbats"):
+		return zapfDingbatsCheckboxes
+	case strings.Contains(name, "wingdings"):
+		return wingdingsCheckboxes
+	}
+	return nil
+}
+
+// showCheckboxText emits a run with checkbox recognition enabled.
+// Recognized glyphs are replaced with markers and recorded; everything
+// else is emitted as decoded. A square drawn just before the run is a
+// checkbox too (see drawnCheckbox).
+func (interp *Interpreter) showCheckboxText(data []byte, decoded string, run TextRun) {
+	if glyph, ok := interp.drawnCheckbox(run); ok {
+		// The marker takes the place of the run in separating it from
+		// the previous one
+		if interp.hasLastRun && interp.wordBreak(run) {
+			interp.pendingSpace = true
+		}
+		interp.hasLastRun = false
+		interp.emitCheckbox(glyph)
+	}
+
+	if table := dingbatTable(interp.textState.Font); table != nil {
+		for i, code := range data {
+			if glyph, ok := table[code]; ok {
+				interp.emitCheckbox(glyph)
+				continue
+			}
+			interp.emitText(interp.decodeText(data[i:i+1]), run)
+		}
+		return
+	}
+
+	start := 0
+	for i, r := range decoded {
+		glyph, ok := unicodeCheckboxes[r]
+		if !ok {
+			continue
+		}
+		interp.emitText(decoded[start:i], run)
+		interp.emitCheckbox(glyph)
+		start = i + len(string(r))
+	}
+	interp.emitText(decoded[start:], run)
+}
+
+// emitCheckbox writes a checkbox marker and records the indicator.
+func (interp *Interpreter) emitCheckbox(glyph checkboxGlyph) {
+	if interp.suppressed() {
+		return
+	}
+	if interp.pendingSpace {
+		interp.pendingSpace = false
+		interp.emit(" ")
+	}
+	if interp.afterCheckbox {
+		// Adjacent markers are separated like a marker and its label
+		interp.afterCheckbox = false
+		interp.write(" ")
+	}
+	cb := Checkbox{Checked: glyph.checked, Radio: glyph.radio, offset: interp.textBuilder.Len()}
+	interp.emit(cb.Marker())
+	interp.checkboxes = append(interp.checkboxes, cb)
+	interp.afterCheckbox = true
+}
+
+// GetCheckboxes returns the checkbox and radio indicators recognized so far,
+// each labeled with the text that follows it on the same line.
+// It is empty unless Options.RecognizeCheckboxes is set. Indicators whose
+// marker was cut off by Options.MaxOutputBytes are left out.
+func (interp *Interpreter) GetCheckboxes() []Checkbox {
+	text := interp.textBuilder.String()
+	result := make([]Checkbox, 0, len(interp.checkboxes))
+	for i, cb := range interp.checkboxes {
+		if cb.offset+len(cb.Marker()) > len(text) {
+			break
+		}
+		rest := text[cb.offset+len(cb.Marker()):]
+		if end := strings.IndexAny(rest, "\r\n"); end >= 0 {
+			rest = rest[:end]
+		}
+		// Stop at the next indicator on the same line
+		for _, next := range interp.checkboxes[i+1:] {
+			if n := next.offset - (cb.offset + len(cb.Marker())); n >= 0 && n < len(rest) {
+				rest = rest[:n]
+				break
+			}
+		}
+		cb.Label = strings.TrimSpace(rest)
+		result = append(result, cb)
+	}
+	return result
+}
+
+// drawnCheckbox looks for a checkbox drawn with path operators just
+// before run: a stroked square about the size of the text, left of the
+// run's first glyph and on its line, that no earlier run claimed. The box
+// is checked if a smaller path, such as a check mark, a cross or a filled
+// square, was drawn inside it. Boxes drawn after their label are not
+// found.
+func (interp *Interpreter) drawnCheckbox(run TextRun) (checkboxGlyph, bool) {
+	if run.Vertical || run.FontSize <= 0 {
+		return checkboxGlyph{}, false
+	}
+	size := run.FontSize
+	for i, p := range interp.paths {
+		if !p.Stroke || slices.Contains(interp.checkboxPaths, i) {
+			continue
+		}
+		box, ok := rectangle(p)
+		if !ok || box.Width() < size/2 || box.Width() > 2*size || math.Abs(box.Width()-box.Height()) > box.Width()/5 {
+			continue
+		}
+		gap := run.X - box.X1
+		center := (box.Y0 + box.Y1) / 2
+		if gap < -size/4 || gap > 2*size || center < run.Y-size/2 || center > run.Y+size {
+			continue
+		}
+		interp.checkboxPaths = append(interp.checkboxPaths, i)
+		return checkboxGlyph{checked: interp.markedInside(i, box)}, true
+	}
+	return checkboxGlyph{}, false
+}
+
+// markedInside reports whether a path other than the box at index skip
+// lies inside box and is clearly smaller than it, as a check mark is;
+// same-sized paths are the box's own background or border.
+func (interp *Interpreter) markedInside(skip int, box geom.Rect) bool {
+	tolerance := box.Width() / 10
+	outer := geom.NewRect(box.X0-tolerance, box.Y0-tolerance, box.X1+tolerance, box.Y1+tolerance)
+	for i, p := range interp.paths {
+		if i == skip || len(p.Segments) == 0 {
+			continue
+		}
+		b := p.Bounds()
+		inside := outer.Contains(geom.Point{X: b.X0, Y: b.Y0}) && outer.Contains(geom.Point{X: b.X1, Y: b.Y1})
+		if inside && b.Width()*b.Height() < 0.8*box.Width()*box.Height() {
+			return true
+		}
+	}
+	return false
+}
+
+// rectangle returns the bounds of p if it is a single axis-aligned
+// rectangle, as re draws it or as four lines.
+func rectangle(p Path) (geom.Rect, bool) {
+	subpaths := p.Subpaths()
+	if len(subpaths) != 1 {
+		return geom.Rect{}, false
+	}
+	var corners []geom.Point
+	for i, seg := range subpaths[0] {
+		switch {
+		case seg.Kind == MoveTo && i == 0, seg.Kind == LineTo:
+			corners = append(corners, seg.Points[0])
+		case seg.Kind == Close:
+		default:
+			return geom.Rect{}, false
+		}
+	}
+	if n := len(corners); n == 5 && corners[4] == corners[0] {
+		corners = corners[:4]
+	}
+	if len(corners) != 4 {
+		return geom.Rect{}, false
+	}
+	r := p.Bounds()
+	for _, c := range corners {
+		if (c.X != r.X0 && c.X != r.X1) || (c.Y != r.Y0 && c.Y != r.Y1) {
+			return geom.Rect{}, false
+		}
+	}
+	return r, !r.IsEmpty()
+}
@@ -0,0 +1,113 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// checkboxInterpreter returns an interpreter recognizing checkboxes, with
+// /F1 a WinAnsi font and /ZaDb ZapfDingbats.
+func checkboxInterpreter(opts Options) *Interpreter {
+	registry := font.NewFontRegistry()
+	registry.RegisterSimple("F1", font.EncodingWinAnsi)
+	zapf := registry.RegisterSimple("ZaDb", font.EncodingZapfDingbats)
+	zapf.BaseFont = "ZapfDingbats"
+	opts.RecognizeCheckboxes = true
+	return NewInterpreterWithOptions(registry, opts)
+}
+
+func TestCheckboxes(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		text   string
+		want   []Checkbox
+	}{
+		{
+			name:   "glyph and label",
+			stream: "BT /ZaDb 10 Tf 72 700 Td (4) Tj /F1 10 Tf ( Yes) Tj ET",
+			text:   "[x] Yes",
+			want:   []Checkbox{{Checked: true, Label: "Yes"}},
+		},
+		{
+			name:   "adjacent glyphs",
+			stream: "BT /ZaDb 10 Tf 72 700 Td (4o) Tj /F1 10 Tf (Label) Tj ET",
+			text:   "[x] [ ] Label",
+			want:   []Checkbox{{Checked: true, Label: ""}, {Checked: false, Label: "Label"}},
+		},
+		{
+			name:   "pinwheel star",
+			stream: "BT /ZaDb 10 Tf 72 700 Td (U) Tj /F1 10 Tf ( Yes) Tj ET",
+			text:   "[x] Yes",
+			want:   []Checkbox{{Checked: true, Label: "Yes"}},
+		},
+		{
+			name:   "six pointed star",
+			stream: "BT /ZaDb 10 Tf 72 700 Td (V) Tj /F1 10 Tf ( Yes) Tj ET",
+			text:   "[x] Yes",
+			want:   []Checkbox{{Checked: true, Label: "Yes"}},
+		},
+		{
+			name:   "radio",
+			stream: "BT /ZaDb 10 Tf 72 700 Td (l) Tj /F1 10 Tf (On) Tj ET",
+			text:   "(x) On",
+			want:   []Checkbox{{Checked: true, Radio: true, Label: "On"}},
+		},
+		{
+			name:   "drawn empty square",
+			stream: "72 698 8 8 re S BT /F1 10 Tf 84 700 Td (Option A) Tj ET",
+			text:   "[ ] Option A",
+			want:   []Checkbox{{Checked: false, Label: "Option A"}},
+		},
+		{
+			name:   "drawn square with check mark",
+			stream: "72 698 8 8 re S 73 702 m 75 699 l 79 705 l S BT /F1 10 Tf 84 700 Td (Option B) Tj ET",
+			text:   "[x] Option B",
+			want:   []Checkbox{{Checked: true, Label: "Option B"}},
+		},
+		{
+			name:   "drawn square with filled background only",
+			stream: "72 698 8 8 re f 72 698 8 8 re S BT /F1 10 Tf 84 700 Td (Option C) Tj ET",
+			text:   "[ ] Option C",
+			want:   []Checkbox{{Checked: false, Label: "Option C"}},
+		},
+		{
+			name:   "table cell is not a checkbox",
+			stream: "60 690 200 30 re S BT /F1 10 Tf 84 700 Td (Cell) Tj ET",
+			text:   "Cell",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := checkboxInterpreter(Options{})
+			if err := interp.ProcessStream(strings.NewReader(tt.stream)); err != nil {
+				t.Fatal(err)
+			}
+			if got := interp.GetText(); got != tt.text {
+				t.Errorf("text = %q, want %q", got, tt.text)
+			}
+			got := interp.GetCheckboxes()
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkboxes = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				got[i].offset = 0
+				if got[i] != tt.want[i] {
+					t.Errorf("checkbox %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckboxesTruncated(t *testing.T) {
+	interp := checkboxInterpreter(Options{MaxOutputBytes: 6})
+	stream := "BT /ZaDb 10 Tf 72 700 Td (4) Tj /F1 10 Tf ( Yes) Tj /ZaDb 10 Tf 0 -12 Td (4) Tj ET"
+	_ = interp.ProcessStream(strings.NewReader(stream))
+	got := interp.GetCheckboxes()
+	if len(got) != 1 || got[0].Label != "Y" {
+		t.Errorf("checkboxes = %+v, want one labeled \"Y\"", got)
+	}
+}
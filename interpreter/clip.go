@@ -0,0 +1,41 @@
+package interpreter
+
+import "github.com/apex-woot/pdf-stream-engine/geom"
+
+// setClip marks the current path as a clipping path (W, W*), which takes
+// effect when the path is painted or ended with n.
+func (interp *Interpreter) setClip() {
+	interp.pendingClip = true
+}
+
+// applyClip intersects the clipping region with the bounding box of the
+// current path if W or W* marked it. The region is tracked as a device
+// space rectangle, so it only ever grows relative to the real clip: text
+// outside it is certainly clipped away, text inside may still be.
+func (interp *Interpreter) applyClip() {
+	if !interp.pendingClip {
+		return
+	}
+	interp.pendingClip = false
+	if len(interp.currentPath) == 0 {
+		return
+	}
+	ts := &interp.textState
+	box := Path{Segments: interp.currentPath}.Bounds()
+	if ts.Clipped {
+		box = box.Intersect(ts.Clip)
+	}
+	ts.Clip, ts.Clipped = box, true
+}
+
+// clippedAway reports whether a run with bounding box box lies entirely
+// outside the clipping region.
+func (interp *Interpreter) clippedAway(box geom.Rect) bool {
+	ts := &interp.textState
+	if !ts.Clipped {
+		return false
+	}
+	clip := ts.Clip
+	return clip.X0 > clip.X1 || clip.Y0 > clip.Y1 ||
+		box.X1 < clip.X0 || box.X0 > clip.X1 || box.Y1 < clip.Y0 || box.Y0 > clip.Y1
+}
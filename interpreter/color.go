@@ -0,0 +1,220 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+)
+
+// Color is a color set by the color operators (g, rg, k, cs, sc, scn and
+// their stroking forms).
+type Color struct {
+	// Space is the color space family: "DeviceGray", "DeviceRGB" or
+	// "DeviceCMYK" for the colors set by g, rg and k, or the operand of
+	// cs. For the name of a /ColorSpace resource, that is the family of
+	// the resource (e.g. "ICCBased", "Separation") if the
+	// ResourceProvider resolves it, and the name itself otherwise.
+	Space string
+
+	// N is the number of color components, and Components holds them
+	// (up to four; those of DeviceN colors with more are dropped). N is 0
+	// while the color is a pattern or the initial color of a space named
+	// by resource, whose components are not known. Colors are comparable
+	// with ==, like the TextState and TextRun that hold them.
+	N          int
+	Components [4]float64
+
+	// Pattern is the /Pattern resource name set by scn, or "".
+	Pattern string
+}
+
+// black is the initial color of the graphics state.
+var black = Color{Space: "DeviceGray", N: 1}
+
+// RGB returns the color as red, green and blue components in [0, 1].
+// CalGray, CalRGB and ICCBased colors are read by their number of
+// components as gray, RGB or CMYK, which is close for the profiles
+// producers use. ok is false for patterns and the other spaces, whose
+// components do not map to RGB without their parameters, such as the
+// tints of Separation and DeviceN colors.
+func (c Color) RGB() (r, g, b float64, ok bool) {
+	switch c.Space {
+	case "DeviceGray", "DeviceRGB", "DeviceCMYK", "CalGray", "CalRGB", "ICCBased":
+	default:
+		return 0, 0, 0, false
+	}
+	if c.Pattern != "" {
+		return 0, 0, 0, false
+	}
+	var v [4]float64
+	for i, x := range c.Values() {
+		v[i] = math.Max(0, math.Min(1, x))
+	}
+	switch c.N {
+	case 1:
+		return v[0], v[0], v[0], true
+	case 3:
+		return v[0], v[1], v[2], true
+	case 4:
+		k := 1 - v[3]
+		return (1 - v[0]) * k, (1 - v[1]) * k, (1 - v[2]) * k, true
+	}
+	return 0, 0, 0, false
+}
+
+// Values returns the color components.
+func (c Color) Values() []float64 {
+	return c.Components[:c.N]
+}
+
+// String formats the color as its space and components, e.g.
+// "DeviceRGB(1 0 0)".
+func (c Color) String() string {
+	s := c.Space + "("
+	for i, v := range c.Values() {
+		if i > 0 {
+			s += " "
+		}
+		s += fmt.Sprint(v)
+	}
+	if c.Pattern != "" {
+		if c.N > 0 {
+			s += " "
+		}
+		s += "/" + c.Pattern
+	}
+	return s + ")"
+}
+
+// initialColor returns the color that cs selects along with space, a
+// family name or the name of a /ColorSpace resource: black for the
+// device families, and components of 0 otherwise, except for spaces
+// whose component count is unknown.
+func (interp *Interpreter) initialColor(space string) Color {
+	switch space {
+	case "DeviceGray", "CalGray":
+		return Color{Space: space, N: 1}
+	case "DeviceRGB", "CalRGB", "Lab":
+		return Color{Space: space, N: 3}
+	case "DeviceCMYK":
+		return Color{Space: space, N: 4, Components: [4]float64{0, 0, 0, 1}}
+	case "Pattern":
+		return Color{Space: space}
+	}
+	if interp.resources != nil {
+		if family, n, ok := interp.resources.ColorSpace(space); ok {
+			return Color{Space: family, N: min(n, 4)}
+		}
+	}
+	return Color{Space: space}
+}
+
+// setColor applies a color operator to the fill color, or for the
+// uppercase operators the stroke color.
+func (interp *Interpreter) setColor(name string, operands []any) error {
+	ts := &interp.textState
+	target := &ts.FillColor
+	switch name {
+	case "G", "RG", "K", "CS", "SC", "SCN":
+		target = &ts.StrokeColor
+	}
+
+	if name == "cs" || name == "CS" {
+		if len(operands) != 1 {
+			return errOperandCount(name, 1, len(operands))
+		}
+		space, ok := operands[0].(string)
+		if !ok {
+			return fmt.Errorf("%w: %s color space not a name", ErrInvalidOperand, name)
+		}
+		*target = interp.initialColor(space)
+		return nil
+	}
+
+	// scn and SCN end with a pattern name for pattern color spaces
+	pattern := ""
+	if len(operands) > 0 && (name == "scn" || name == "SCN") {
+		if p, ok := operands[len(operands)-1].(string); ok {
+			pattern, operands = p, operands[:len(operands)-1]
+		}
+	}
+	if want := colorOperandCount(name); want > 0 && len(operands) != want {
+		return errOperandCount(name, want, len(operands))
+	}
+	color := Color{Space: target.Space, N: min(len(operands), 4), Pattern: pattern}
+	for i, operand := range operands {
+		v, err := operandToFloat(operand)
+		if err != nil {
+			return fmt.Errorf("%w: %s operand %d not a number", ErrInvalidOperand, name, i)
+		}
+		if i < color.N {
+			color.Components[i] = v
+		}
+	}
+
+	switch name {
+	case "g", "G":
+		color.Space = "DeviceGray"
+	case "rg", "RG":
+		color.Space = "DeviceRGB"
+	case "k", "K":
+		color.Space = "DeviceCMYK"
+	}
+	*target = color
+	return nil
+}
+
+// colorOperandCount returns the number of components a device color
+// operator takes, or 0 for sc and scn, which take as many as the color
+// space has.
+func colorOperandCount(name string) int {
+	switch name {
+	case "g", "G":
+		return 1
+	case "rg", "RG":
+		return 3
+	case "k", "K":
+		return 4
+	}
+	return 0
+}
+
+// maxBackgroundColorDistance is the largest difference in any RGB
+// component between text and its background for the text to count as
+// drawn in the background color, allowing for near-white on white.
+const maxBackgroundColorDistance = 0.05
+
+// matchesBackground reports whether run is painted in the color of its
+// background: the fill color of the last filled path whose bounding box
+// contains the run, or white, the page, if there is none. Text in colors
+// the interpreter cannot convert to RGB does not match.
+func (interp *Interpreter) matchesBackground(run TextRun) bool {
+	var paint Color
+	switch run.RenderMode {
+	case RenderFill, RenderFillStroke, RenderFillClip, RenderFillStrokeClip:
+		paint = run.FillColor
+	case RenderStroke, RenderStrokeClip:
+		paint = run.StrokeColor
+	default:
+		return false
+	}
+	r, g, b, ok := paint.RGB()
+	if !ok {
+		return false
+	}
+
+	box := run.Bounds()
+	bgR, bgG, bgB := 1.0, 1.0, 1.0
+	for i := len(interp.paths) - 1; i >= 0; i-- {
+		path := interp.paths[i]
+		if !path.Fill || path.Bounds().Intersect(box) != box {
+			continue
+		}
+		if bgR, bgG, bgB, ok = path.FillColor.RGB(); !ok {
+			return false
+		}
+		break
+	}
+	return math.Abs(r-bgR) <= maxBackgroundColorDistance &&
+		math.Abs(g-bgG) <= maxBackgroundColorDistance &&
+		math.Abs(b-bgB) <= maxBackgroundColorDistance
+}
@@ -0,0 +1,96 @@
+package interpreter
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+)
+
+// minDuplicateOverlap is the fraction of the smaller of two run boxes
+// that must overlap the other for runs with the same text to count as
+// one. Producers faking bold offset the copies by a fraction of a point,
+// far less than this allows.
+const minDuplicateOverlap = 0.7
+
+// maxDuplicateSizeRatio bounds the ratio of the font sizes of two runs
+// that count as one, as OCR layers size their text only approximately.
+const maxDuplicateSizeRatio = 1.25
+
+// shownRun is a run kept for duplicate detection.
+type shownRun struct {
+	box  geom.Rect
+	size float64
+}
+
+// shownText holds the runs shown with one text, sorted by the bottom of
+// their boxes, so that only those a new run can overlap vertically are
+// compared with it.
+type shownText struct {
+	runs      []shownRun
+	maxHeight float64
+}
+
+// duplicate reports whether run draws the same text over a run already
+// shown, as producers do to fake bold and OCR layers do over the text
+// they recognized. Text counts as the same if it differs only in
+// whitespace, and runs overlap if most of the smaller box lies within the
+// other. A run that is no duplicate is remembered for later ones.
+func (interp *Interpreter) duplicate(run TextRun) bool {
+	key := strings.Join(strings.Fields(run.Text), " ")
+	if key == "" {
+		return false
+	}
+	box := run.Bounds()
+	shown := interp.shownRuns[key]
+	if shown == nil {
+		if interp.shownRuns == nil {
+			interp.shownRuns = make(map[string]*shownText)
+		}
+		shown = &shownText{}
+		interp.shownRuns[key] = shown
+	}
+	if shown.find(box, run.FontSize) {
+		return true
+	}
+	i, _ := slices.BinarySearchFunc(shown.runs, box.Y0, func(r shownRun, y float64) int {
+		return cmp.Compare(r.box.Y0, y)
+	})
+	shown.runs = slices.Insert(shown.runs, i, shownRun{box: box, size: run.FontSize})
+	shown.maxHeight = math.Max(shown.maxHeight, box.Height())
+	return false
+}
+
+// find reports whether a run of the given box and font size duplicates
+// one of t's runs.
+func (t *shownText) find(box geom.Rect, size float64) bool {
+	// A run overlapping box starts below its top and less than the
+	// tallest run's height below its bottom.
+	from, _ := slices.BinarySearchFunc(t.runs, box.Y0-t.maxHeight, func(r shownRun, y float64) int {
+		return cmp.Compare(r.box.Y0, y)
+	})
+	for _, shown := range t.runs[from:] {
+		if shown.box.Y0 > box.Y1 {
+			break
+		}
+		ratio := size / shown.size
+		if ratio > maxDuplicateSizeRatio || ratio < 1/maxDuplicateSizeRatio {
+			continue
+		}
+		overlap := box.Intersect(shown.box)
+		if overlap.IsEmpty() {
+			continue
+		}
+		smaller := math.Min(area(box), area(shown.box))
+		if area(overlap) >= minDuplicateOverlap*smaller {
+			return true
+		}
+	}
+	return false
+}
+
+func area(r geom.Rect) float64 {
+	return r.Width() * r.Height()
+}
@@ -0,0 +1,68 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// ErrUnbalancedTextObject reports a BT inside an open text object, an ET
+// without a matching BT, or a text object left open at the end of a stream.
+var ErrUnbalancedTextObject = errors.New("unbalanced text object")
+
+// ErrUnbalancedQ reports a Q without a matching q.
+var ErrUnbalancedQ = errors.New("unbalanced Q operator")
+
+// ErrUnbalancedEMC reports an EMC without a matching BMC or BDC.
+var ErrUnbalancedEMC = errors.New("unbalanced EMC operator")
+
+// ErrTextOutsideBT reports a text-showing operator outside a BT/ET block.
+var ErrTextOutsideBT = errors.New("text shown outside BT/ET block")
+
+// ErrOperandCount reports an operator with the wrong number of operands.
+var ErrOperandCount = errors.New("wrong number of operands")
+
+// ErrInvalidOperand reports an operand of the wrong type or value.
+var ErrInvalidOperand = errors.New("invalid operand")
+
+// ErrMissingResource reports a resource name, such as the operand of gs,
+// that the resource provider does not define.
+var ErrMissingResource = errors.New("missing resource")
+
+// ErrInlineImage reports a malformed inline image (BI ... ID ... EI).
+var ErrInlineImage = errors.New("malformed inline image")
+
+// ErrWarningsSuppressed is the error of the last warning GetWarnings
+// returns when Options.MaxWarnings suppressed some.
+var ErrWarningsSuppressed = errors.New("warnings suppressed")
+
+// Parser errors, re-exported so callers of the interpreter need not import
+// the parser package to test for them.
+var (
+	ErrMalformedToken       = parser.ErrMalformedToken
+	ErrUnclosedArray        = parser.ErrUnclosedArray
+	ErrUnexpectedArrayEnd   = parser.ErrUnexpectedArrayEnd
+	ErrInvalidHexString     = parser.ErrInvalidHexString
+	ErrInvalidLiteralString = parser.ErrInvalidLiteralString
+	ErrInvalidDict          = parser.ErrInvalidDict
+	ErrUnknownOperand       = parser.ErrUnknownOperand
+)
+
+// errOperandCount reports an operator called with the wrong number of
+// operands.
+func errOperandCount(name string, want, got int) error {
+	noun := "operands"
+	if want == 1 {
+		noun = "operand"
+	}
+	return fmt.Errorf("%w: %s expects %d %s, got %d", ErrOperandCount, name, want, noun, got)
+}
+
+// ErrLimitExceeded reports input exceeding a processing limit.
+// It is the same value as parser.ErrLimitExceeded, so either can be used
+// with errors.Is.
+var ErrLimitExceeded = parser.ErrLimitExceeded
+
+// LimitError reports which limit was exceeded; see parser.LimitError.
+type LimitError = parser.LimitError
@@ -0,0 +1,65 @@
+package interpreter
+
+import "github.com/apex-woot/pdf-stream-engine/geom"
+
+// Glyph is one character code shown by a text-showing operator, as
+// Options.GlyphFunc receives it.
+type Glyph struct {
+	// Op and Element locate the string holding the code, as in
+	// Provenance: the index of the operation in the stream, and of the
+	// string in a TJ array operand. Offset and Length are the code's
+	// bytes in the string.
+	Op, Element    int
+	Offset, Length int
+
+	// Text is the decoded text of the code, before the text filters
+	// (PUA remapping, ligature expansion and the like).
+	Text string
+
+	// Bounds is the approximate box of the glyph in device space,
+	// estimated like TextRun.Bounds.
+	Bounds geom.Rect
+
+	// Adjustment is the TJ adjustment, in thousandths of a text space
+	// unit, that moves the text position as far as showing the glyph
+	// does: putting it in place of the glyph removes the glyph without
+	// moving the text after it.
+	Adjustment float64
+}
+
+// reportGlyphs passes the glyphs of data, shown at the current text
+// position, to Options.GlyphFunc.
+func (interp *Interpreter) reportGlyphs(data []byte) {
+	f := interp.textState.Font
+	ts := &interp.textState
+	trm := ts.RenderingMatrix()
+	fs := ts.FontSize
+	_, glyphs, _ := f.DecodeShowString(data)
+	pos := 0.0
+	for _, g := range glyphs {
+		advance := interp.codeAdvance(g.Code, g.Length)
+		var box geom.Rect
+		var adjustment float64
+		if f.Vertical {
+			box = geom.NewRect(-0.5*fs, -(pos + advance), 0.5*fs, -pos)
+			if fs != 0 {
+				adjustment = 1000 * advance / fs
+			}
+		} else {
+			box = geom.NewRect(pos, -0.2*fs, pos+advance, 0.8*fs)
+			if scale := fs * ts.horizontalScale(); scale != 0 {
+				adjustment = -1000 * advance / scale
+			}
+		}
+		interp.options.GlyphFunc(Glyph{
+			Op:         interp.opIndex,
+			Element:    interp.opElement,
+			Offset:     g.Offset,
+			Length:     g.Length,
+			Text:       g.Text,
+			Bounds:     box.Transform(trm),
+			Adjustment: adjustment,
+		})
+		pos += advance
+	}
+}
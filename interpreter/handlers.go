@@ -0,0 +1,42 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// OperatorHandler is a custom handler for a content stream operator. It
+// receives the operation and the text state, which includes the CTM. It
+// may modify the state, e.g. to emulate a vendor-specific operator that
+// moves the text position.
+//
+// A non-nil error is recorded as a warning for the operation, like the
+// errors of the built-in handlers; processing continues.
+type OperatorHandler func(op parser.Operation, state *TextState) error
+
+// RegisterHandler adds a handler for the operator name (e.g. "re", or a
+// vendor extension inside BX/EX). Handlers run after the built-in
+// handling of the operator, so they see its effect on the state; several
+// handlers for one operator run in registration order. Operators the
+// interpreter does not know are otherwise ignored.
+//
+// Register handlers before calling ProcessStream.
+func (interp *Interpreter) RegisterHandler(name string, handler OperatorHandler) {
+	if interp.handlers == nil {
+		interp.handlers = make(map[string][]OperatorHandler)
+	}
+	interp.handlers[name] = append(interp.handlers[name], handler)
+}
+
+// runHandlers calls the registered handlers for op, joining their errors.
+func (interp *Interpreter) runHandlers(op parser.Operation) error {
+	var errs []error
+	for _, handler := range interp.handlers[op.Name] {
+		if err := handler(op, &interp.textState); err != nil {
+			errs = append(errs, fmt.Errorf("handler: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
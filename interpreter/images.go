@@ -0,0 +1,112 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+)
+
+// InlineImage is an image embedded in the content stream with BI/ID/EI.
+type InlineImage struct {
+	// Dict holds the image dictionary entries as written, usually with
+	// abbreviated keys (W, H, BPC, CS, F, DP, ...). Values use the
+	// parser's operand types.
+	Dict map[string]any
+
+	// Data is the raw image data, still encoded by the image's filters.
+	Data []byte
+}
+
+// ImagePlacement records an XObject painted with Do or an inline image.
+// The interpreter cannot tell image XObjects from form XObjects, so Do
+// placements of forms are recorded too; resolve Name through the page
+// resources to find out.
+type ImagePlacement struct {
+	// Name is the XObject resource name, or "" for inline images.
+	Name string
+
+	// Inline is the inline image, or nil for XObjects.
+	Inline *InlineImage
+
+	// Matrix is the CTM at the time of painting. Images are drawn into
+	// the unit square of the space it defines.
+	Matrix geom.Matrix
+
+	// Bounds is the unit square transformed by Matrix: the image's
+	// placement rectangle on the page.
+	Bounds geom.Rect
+}
+
+// GetImagePlacements returns the XObjects and inline images painted so far.
+func (interp *Interpreter) GetImagePlacements() []ImagePlacement {
+	placements := make([]ImagePlacement, len(interp.images))
+	copy(placements, interp.images)
+	return placements
+}
+
+// placeImage records an image painted with the current CTM.
+func (interp *Interpreter) placeImage(name string, inline *InlineImage) {
+	ctm := interp.textState.CTM
+	interp.images = append(interp.images, ImagePlacement{
+		Name:   name,
+		Inline: inline,
+		Matrix: ctm,
+		Bounds: geom.NewRect(0, 0, 1, 1).Transform(ctm),
+	})
+}
+
+// collectInlineImageEntry gathers the dictionary of an inline image
+// between BI and ID. The entries are normally all operands of ID, but a
+// stray bare word splits them into several operations; the operator is
+// dropped and the operands are kept.
+func (interp *Interpreter) collectInlineImageEntry(operands []any) {
+	interp.inlineImageOperands = append(interp.inlineImageOperands, operands...)
+}
+
+// processImageOperation handles Do and the inline image operators. BI
+// starts an image, ID carries its dictionary entries as operands, and EI
+// carries the raw data (see parser).
+func (interp *Interpreter) processImageOperation(name string, operands []any) error {
+	switch name {
+	case "Do":
+		if len(operands) != 1 {
+			return errOperandCount("Do", 1, len(operands))
+		}
+		xobject, ok := operands[0].(string)
+		if !ok {
+			return fmt.Errorf("%w: Do operand not a name: %v", ErrInvalidOperand, operands[0])
+		}
+		interp.placeImage(xobject, nil)
+	case "BI":
+		interp.inlineImageDict = nil
+		interp.inlineImageOperands = nil
+		interp.inInlineImage = true
+	case "ID":
+		operands = append(interp.inlineImageOperands, operands...)
+		interp.inlineImageOperands = nil
+		interp.inInlineImage = false
+		if len(operands)%2 != 0 {
+			return fmt.Errorf("%w: ID expects key/value pairs, got %d operands", ErrOperandCount, len(operands))
+		}
+		dict := make(map[string]any, len(operands)/2)
+		for i := 0; i < len(operands); i += 2 {
+			key, ok := operands[i].(string)
+			if !ok {
+				return fmt.Errorf("%w: ID dictionary key not a name: %v", ErrInvalidOperand, operands[i])
+			}
+			dict[key] = operands[i+1]
+		}
+		interp.inlineImageDict = dict
+	case "EI":
+		if interp.inlineImageDict == nil {
+			return fmt.Errorf("%w: EI without BI/ID", ErrInlineImage)
+		}
+		var data []byte
+		if len(operands) == 1 {
+			data, _ = operands[0].([]byte)
+		}
+		interp.placeImage("", &InlineImage{Dict: interp.inlineImageDict, Data: data})
+		interp.inlineImageDict = nil
+	}
+	return nil
+}
@@ -1,69 +1,288 @@
 package interpreter
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"maps"
 	"math"
 	"strconv"
 	"strings"
 
 	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/geom"
 	"github.com/apex-woot/pdf-stream-engine/parser"
 )
 
 // Interpreter processes a stream of PDF operations.
 type Interpreter struct {
-	parser       *parser.Parser
-	textBuilder  strings.Builder
-	inTextObject bool
-	textState    TextState
-	stateStack   []TextState // For q/Q operators
+	parser        *parser.Parser
+	textBuilder   textBuffer
+	provenance    []Provenance // with Options.RecordProvenance
+	inTextObject  bool
+	textObjectPos parser.Position // position of the BT opening the current text object
+	opPos         parser.Position // position of the operation being processed
+	opIndex       int             // index of the operation being processed
+	opElement     int             // index of the TJ element being shown
+	textState     TextState
+	stateStack    []TextState // For q/Q operators
 
 	// Font management
 	fontRegistry *font.FontRegistry
-	currentFont  *font.Font
+	formDepth    int        // font scopes pushed, for Options.MaxFormDepth
+	styleFont    *font.Font // the font bold and italic describe
+	bold, italic bool
+
+	resources ResourceProvider // resolves gs names; nil ignores gs
+
+	// Marked content (BMC/BDC ... EMC)
+	markedContent []MarkedContent
+	artifactDepth int
+	hiddenDepth   int // sequences on hidden layers (Options.Layers)
+
+	// Positioned output
+	runs         []TextRun
+	lastRun      TextRun
+	lastRunEnd   geom.Point
+	runDirection geom.Point
+	hasLastRun   bool
+	pendingSpace bool // a word separator is due before the next text
+
+	// Last run on the baseline, which superscripts and subscripts are
+	// shifted from
+	scriptBase    TextRun
+	hasScriptBase bool
+
+	// Checkbox recognition
+	checkboxes    []Checkbox
+	checkboxPaths []int // indices of the paths recognized as checkboxes
+	afterCheckbox bool  // separate the next label from its marker
+
+	// Vector paths
+	paths        []Path
+	currentPath  []PathSegment
+	currentPoint geom.Point
+	subpathStart geom.Point
+	pendingClip  bool // W or W* applies to the current path
+
+	// Run filters (SkipClipped, SkipTextMatchingPageBackground,
+	// MinFontSize, RunFilter, SkipDuplicates)
+	droppedRun  bool                  // the run being shown is dropped
+	droppedLine bool                  // the current line holds only dropped runs
+	shownRuns   map[string]*shownText // runs shown so far by normalized text
+
+	// Images
+	images          []ImagePlacement
+	inlineImageDict map[string]any // set by ID, consumed by EI
+
+	inInlineImage       bool  // between BI and ID
+	inlineImageOperands []any // dictionary entries seen since BI
+
+	warnings           []Warning
+	suppressedWarnings int // past Options.MaxWarnings
+
+	handlers map[string][]OperatorHandler // custom operator handlers
+
+	operatorCounts map[string]int // with Options.CountOperators
+
+	showedText bool // a string of Tj, TJ, ' or " had glyphs
+
+	options Options
 }
 
 // NewInterpreter creates a new interpreter.
 // If fontRegistry is nil, a default registry with WinAnsi encoding will be created.
 func NewInterpreter(fontRegistry *font.FontRegistry) *Interpreter {
+	return NewInterpreterWithOptions(fontRegistry, Options{})
+}
+
+// NewInterpreterWithOptions creates a new interpreter with the given options.
+// If fontRegistry is nil, a default registry with WinAnsi encoding will be created.
+func NewInterpreterWithOptions(fontRegistry *font.FontRegistry, opts Options) *Interpreter {
 	if fontRegistry == nil {
 		fontRegistry = font.NewFontRegistry()
 	}
 
-	// Get default font from registry
-	defaultFont := fontRegistry.MustLookup("DefaultFont")
-
-	return &Interpreter{
-		textBuilder:  strings.Builder{},
+	interp := &Interpreter{
+		textBuilder:  textBuffer{limit: opts.MaxOutputBytes},
 		inTextObject: false,
 		textState:    NewTextState(),
 		stateStack:   make([]TextState, 0),
 		fontRegistry: fontRegistry,
-		currentFont:  defaultFont,
+		options:      opts,
 	}
+	// Get default font from registry
+	interp.textState.Font = fontRegistry.MustLookup("DefaultFont")
+	return interp
+}
+
+// Reset clears everything extracted and all graphics, text and
+// marked-content state, so the interpreter can process another stream as
+// if it were new. The font registry, resource provider, options and
+// registered handlers are kept; use SetFontRegistry and SetResources to
+// switch them for the next page. Internal
+// buffers are retained to avoid reallocating them.
+//
+// Reset makes an Interpreter suitable for a sync.Pool when the same options
+// serve many pages:
+//
+//	pool := sync.Pool{New: func() any { return interpreter.NewInterpreterWithOptions(nil, opts) }}
+//
+//	interp := pool.Get().(*interpreter.Interpreter)
+//	interp.Reset()
+//	interp.SetFontRegistry(pageFonts)
+//	err := interp.ProcessStream(content)
+//	text, runs := interp.GetText(), interp.GetRuns()
+//	pool.Put(interp)
+//
+// The Get methods return copies, so results stay valid after the
+// interpreter is reset.
+func (interp *Interpreter) Reset() {
+	*interp = Interpreter{
+		textBuilder:    interp.textBuilder,
+		provenance:     interp.provenance[:0],
+		textState:      NewTextState(),
+		stateStack:     interp.stateStack[:0],
+		fontRegistry:   interp.fontRegistry,
+		formDepth:      interp.formDepth,
+		resources:      interp.resources,
+		markedContent:  interp.markedContent[:0],
+		runs:           interp.runs[:0],
+		checkboxes:     interp.checkboxes[:0],
+		checkboxPaths:  interp.checkboxPaths[:0],
+		paths:          interp.paths[:0],
+		images:         interp.images[:0],
+		warnings:       interp.warnings[:0],
+		handlers:       interp.handlers,
+		operatorCounts: interp.operatorCounts,
+		options:        interp.options,
+	}
+	interp.textState.Font = interp.fontRegistry.MustLookup("DefaultFont")
+	interp.textBuilder.Reset()
+	clear(interp.operatorCounts)
+}
+
+// SetFontRegistry switches the fonts used to resolve Tf resource names.
+// If fontRegistry is nil, a default registry with WinAnsi encoding is used.
+// Call it between streams, typically right after Reset.
+func (interp *Interpreter) SetFontRegistry(fontRegistry *font.FontRegistry) {
+	if fontRegistry == nil {
+		fontRegistry = font.NewFontRegistry()
+	}
+	interp.fontRegistry = fontRegistry
+	interp.formDepth = 0
+	interp.textState.Font = fontRegistry.MustLookup("DefaultFont")
+}
+
+// PushFontScope resolves Tf resource names against fonts, then against
+// the fonts in effect so far, until the matching PopFontScope. Callers
+// that interpret a form XObject's content push the fonts of its
+// /Resources around it, as /F1 may name a different font there than on
+// the page.
+func (interp *Interpreter) PushFontScope(fonts *font.FontRegistry) {
+	interp.fontRegistry = interp.fontRegistry.Push(fonts)
+	interp.formDepth++
+}
+
+// PopFontScope returns to the fonts in effect before the last
+// PushFontScope. It reports false, changing nothing, if no scope is
+// pushed. The current font is kept until the next Tf.
+func (interp *Interpreter) PopFontScope() bool {
+	parent := interp.fontRegistry.Pop()
+	if parent == nil {
+		return false
+	}
+	interp.fontRegistry = parent
+	interp.formDepth--
+	return true
 }
 
 // ProcessStream reads from an io.Reader, parses the content stream,
 // and interprets the operations.
 func (interp *Interpreter) ProcessStream(r io.Reader) error {
-	interp.parser = parser.NewParser(r)
-	operations, err := interp.parser.Parse()
-	if err != nil {
-		return fmt.Errorf("parser failed: %w", err)
-	}
+	return interp.ProcessStreamContext(context.Background(), r)
+}
 
-	for _, op := range operations {
-		if err := interp.processOperation(op); err != nil {
-			// Log warnings but continue processing
-			log.Printf("Warning: error processing op '%s': %v", op.Name, err)
+// ProcessStreamContext is like ProcessStream but stops when ctx is done,
+// returning ctx.Err(). Text extracted before that point remains available
+// through GetText and GetRuns.
+//
+// Operations are interpreted as they are tokenized, so memory use depends
+// on the extracted output, not on the size of the stream. If the stream
+// turns out to be malformed beyond recovery, the error is returned and the
+// text extracted up to that point remains available.
+func (interp *Interpreter) ProcessStreamContext(ctx context.Context, r io.Reader) error {
+	if limit := interp.options.MaxFormDepth; limit > 0 && interp.formDepth > limit {
+		return &LimitError{Limit: "MaxFormDepth", Max: limit, Offset: -1}
+	}
+	interp.parser = parser.NewParser(r)
+	interp.parser.SetMaxArrayLength(interp.options.MaxArrayLength)
+	interp.parser.SetMaxWarnings(interp.options.MaxWarnings)
+	defer func(p *parser.Parser) {
+		interp.suppressedWarnings += p.SuppressedWarnings()
+	}(interp.parser)
+	relayed := 0 // tokenizer warnings relayed so far
+	i := 0
+	for op, err := range interp.parser.Operations(ctx) {
+		// Tokenizer warnings precede the operation in the stream
+		for _, malformed := range interp.parser.Warnings()[relayed:] {
+			interp.warn("", malformed.Position, malformed)
+			relayed++
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("parser failed: %w", err)
+		}
+		if i%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if limit := interp.options.MaxOperations; limit > 0 && i == limit {
+			return &LimitError{Limit: "MaxOperations", Max: limit, Offset: op.Offset}
+		}
+		before, textLen := interp.textState, interp.textBuilder.Len()
+		interp.opPos, interp.opIndex, interp.opElement = op.Position, i, 0
+		inImageDict := interp.inInlineImage // BI entries are not operators
+		if interp.options.CountOperators && !inImageDict {
+			if interp.operatorCounts == nil {
+				interp.operatorCounts = make(map[string]int)
+			}
+			interp.operatorCounts[op.Name]++
+		}
+		err := interp.processOperation(op)
+		if len(interp.handlers) > 0 && !inImageDict {
+			err = errors.Join(err, interp.runHandlers(op))
+		}
+		if interp.options.Trace != nil {
+			emitted := interp.textBuilder.Slice(textLen)
+			traceOperation(interp.options.Trace, i, op, before, interp.textState, emitted, err)
+		}
+		if err != nil {
+			// Record the warning but continue processing
+			interp.warn(op.Name, op.Position, err)
+		}
+		if interp.textBuilder.Truncated() {
+			return &LimitError{Limit: "MaxOutputBytes", Max: interp.options.MaxOutputBytes, Offset: op.Offset}
 		}
+		i++
+	}
+	for _, malformed := range interp.parser.Warnings()[relayed:] {
+		interp.warn("", malformed.Position, malformed)
+	}
+	if interp.inTextObject {
+		interp.warn("BT", interp.textObjectPos, fmt.Errorf("%w: missing ET at end of stream", ErrUnbalancedTextObject))
 	}
 	return nil
 }
 
+// contextCheckInterval is how many operations are interpreted between
+// checks for cancellation.
+const contextCheckInterval = 256
+
 // GetText returns the accumulated text extracted from the stream.
 func (interp *Interpreter) GetText() string {
 	// Trim leading/trailing whitespace and normalize newlines
@@ -72,11 +291,36 @@ func (interp *Interpreter) GetText() string {
 	return s
 }
 
+// GetOperatorCounts returns how many operations of each operator were
+// interpreted, with Options.CountOperators; inline image dictionary
+// entries are not operations.
+func (interp *Interpreter) GetOperatorCounts() map[string]int {
+	return maps.Clone(interp.operatorCounts)
+}
+
+// ShowedText reports whether the stream showed any text with Tj, TJ, '
+// or ", including text the options dropped, such as invisible text. A
+// page that paints images and shows no text is likely scanned.
+func (interp *Interpreter) ShowedText() bool {
+	return interp.showedText
+}
+
+// OutputBytes returns the size of the text extracted so far, in bytes,
+// before GetText trims it. It can be polled to monitor memory use.
+func (interp *Interpreter) OutputBytes() int {
+	return interp.textBuilder.Len()
+}
+
 // processOperation handles a single PDF operation.
-func (interp *Interpreter) processOperation(op parser.Operation) error {
+func (interp *Interpreter) processOperation(op parser.Operation) (err error) {
+	if interp.inInlineImage && op.Name != "ID" {
+		interp.collectInlineImageEntry(op.Operands)
+		return nil
+	}
+
 	// Text can only be drawn inside a BT/ET block.
 	if !interp.inTextObject && isTextShowingOp(op.Name) {
-		return fmt.Errorf("text showing op '%s' outside BT/ET block", op.Name)
+		return fmt.Errorf("%w: %s", ErrTextOutsideBT, op.Name)
 	}
 
 	switch op.Name {
@@ -87,47 +331,130 @@ func (interp *Interpreter) processOperation(op parser.Operation) error {
 	case "Q":
 		// Restore graphics state
 		if len(interp.stateStack) == 0 {
-			return errors.New("unbalanced 'Q' operator")
+			return ErrUnbalancedQ
 		}
 		interp.textState = interp.stateStack[len(interp.stateStack)-1]
 		interp.stateStack = interp.stateStack[:len(interp.stateStack)-1]
+	case "cm":
+		if len(op.Operands) != 6 {
+			return errOperandCount("cm", 6, len(op.Operands))
+		}
+		var m geom.Matrix
+		for i, operand := range op.Operands {
+			v, err := operandToFloat(operand)
+			if err != nil {
+				return fmt.Errorf("%w: cm operand %d not a number", ErrInvalidOperand, i)
+			}
+			m[i] = v
+		}
+		interp.textState.CTM = m.Multiply(interp.textState.CTM)
+	case "w":
+		if len(op.Operands) != 1 {
+			return errOperandCount("w", 1, len(op.Operands))
+		}
+		width, err := operandToFloat(op.Operands[0])
+		if err != nil {
+			return fmt.Errorf("%w: w line width not a number", ErrInvalidOperand)
+		}
+		interp.textState.LineWidth = width
+
+	case "gs":
+		return interp.setGraphicsState(op.Operands)
+
+	// --- Path Construction and Painting ---
+	case "m", "l", "c", "v", "y", "h", "re",
+		"S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n":
+		return interp.processPathOperation(op.Name, op.Operands)
+
+	// --- XObjects and Inline Images ---
+	case "Do", "BI", "ID", "EI":
+		return interp.processImageOperation(op.Name, op.Operands)
 
 	// --- Text Object ---
 	case "BT":
+		if interp.inTextObject {
+			err = fmt.Errorf("%w: BT inside text object", ErrUnbalancedTextObject)
+		}
 		interp.inTextObject = true
-		// Reset text matrices (not fully implemented)
-		interp.textState = NewTextState()
+		interp.textObjectPos = op.Position
+		// Reset text matrices; other text state parameters persist
+		// across text objects.
+		interp.textState.TextMatrix = geom.Identity()
+		interp.textState.LineMatrix = geom.Identity()
 		interp.textState.LastY = 0 // Assume start at Y=0
 	case "ET":
+		if !interp.inTextObject {
+			return fmt.Errorf("%w: ET without BT", ErrUnbalancedTextObject)
+		}
 		interp.inTextObject = false
 
+	// --- Marked Content ---
+	case "BMC":
+		// Begin marked content. e.g., /Artifact BMC
+		if len(op.Operands) < 1 {
+			return errOperandCount("BMC", 1, len(op.Operands))
+		}
+		tag, ok := op.Operands[0].(string)
+		if !ok {
+			return fmt.Errorf("%w: BMC tag not a name", ErrInvalidOperand)
+		}
+		interp.beginMarkedContent(tag, nil)
+	case "BDC":
+		// Begin marked content with properties. e.g., /Artifact <</Type /Pagination>> BDC
+		if len(op.Operands) < 2 {
+			return errOperandCount("BDC", 2, len(op.Operands))
+		}
+		tag, ok := op.Operands[0].(string)
+		if !ok {
+			return fmt.Errorf("%w: BDC tag not a name", ErrInvalidOperand)
+		}
+		interp.beginMarkedContent(tag, op.Operands[1])
+	case "EMC":
+		if !interp.endMarkedContent() {
+			return ErrUnbalancedEMC
+		}
+
 	// --- Text State ---
 	case "Tf":
 		// Set font and size. e.g., /F1 12 Tf
 		if len(op.Operands) < 2 {
-			return fmt.Errorf("Tf expects 2 operands, got %d", len(op.Operands))
+			return errOperandCount("Tf", 2, len(op.Operands))
 		}
 		fontName, ok := op.Operands[0].(string)
 		if !ok {
-			return fmt.Errorf("Tf font name not a string")
+			return fmt.Errorf("%w: Tf font name not a string", ErrInvalidOperand)
 		}
 		fontSize, err := operandToFloat(op.Operands[1])
 		if err != nil {
-			return fmt.Errorf("Tf font size not a number")
+			return fmt.Errorf("%w: Tf font size not a number", ErrInvalidOperand)
 		}
 		interp.textState.FontName = fontName
 		interp.textState.FontSize = fontSize
 
 		// Look up font in registry
-		interp.currentFont = interp.fontRegistry.MustLookup(fontName)
+		interp.textState.Font = interp.fontRegistry.MustLookup(fontName)
+		if err := interp.textState.Font.CheckDecodable(); err != nil {
+			return fmt.Errorf("Tf: %w", err)
+		}
 		// DEBUG: uncomment to see font lookups
-		// log.Printf("DEBUG: Set font to %q, found: %v", fontName, interp.currentFont.Name)
+		// log.Printf("DEBUG: Set font to %q, found: %v", fontName, interp.textState.Font.Name)
+
+	case "Tr":
+		// Set text rendering mode. e.g., 3 Tr
+		if len(op.Operands) < 1 {
+			return errOperandCount("Tr", 1, len(op.Operands))
+		}
+		mode, err := operandToFloat(op.Operands[0])
+		if err != nil || mode < 0 || mode > 7 {
+			return fmt.Errorf("%w: Tr rendering mode invalid: %v", ErrInvalidOperand, op.Operands[0])
+		}
+		interp.textState.RenderMode = RenderMode(mode)
 
 	// --- Text Showing ---
 	case "Tj":
 		// Show text
 		if len(op.Operands) < 1 {
-			return fmt.Errorf("Tj expects 1 operand, got %d", len(op.Operands))
+			return errOperandCount("Tj", 1, len(op.Operands))
 		}
 		if err := interp.showText(op.Operands[0]); err != nil {
 			return fmt.Errorf("Tj: %w", err)
@@ -136,44 +463,83 @@ func (interp *Interpreter) processOperation(op parser.Operation) error {
 	case "TJ":
 		// Show text with spacing
 		if len(op.Operands) < 1 {
-			return fmt.Errorf("TJ expects 1 operand, got %d", len(op.Operands))
+			return errOperandCount("TJ", 1, len(op.Operands))
 		}
 		arr, ok := op.Operands[0].([]any)
 		if !ok {
-			return fmt.Errorf("TJ operand not an array")
+			return fmt.Errorf("%w: TJ operand not an array", ErrInvalidOperand)
 		}
-		for _, val := range arr {
+		for i, val := range arr {
 			switch v := val.(type) {
 			case string, []byte:
+				interp.opElement = i
 				if err := interp.showText(v); err != nil {
 					return fmt.Errorf("TJ: %w", err)
 				}
 			case float64:
-				// Spacing adjustment in thousandths of an em.
-				// Negative values tighten spacing, positive values add space.
-				// Only add space for significantly large positive values.
-				// Threshold: ~100 = noticeable space (roughly 1/10 em)
-				if v > 100 {
-					interp.textBuilder.WriteString(" ")
-				}
+				// Spacing adjustment in thousandths of an em, subtracted from
+				// the horizontal position: positive values tighten spacing,
+				// negative values add space. Whether the resulting gap is a
+				// word break is decided when the next string is shown.
+				interp.adjustTextPosition(v)
 			}
 		}
 
 	case "T*":
-		// Move to start of next line
-		interp.textBuilder.WriteString("\n")
-		// Simulate a line break (font size is a decent guess)
-		interp.textState.LastY -= interp.textState.FontSize
+		interp.nextLine()
+
+	case "'":
+		// Move to the next line and show text
+		if len(op.Operands) < 1 {
+			return errOperandCount("'", 1, len(op.Operands))
+		}
+		interp.nextLine()
+		if err := interp.showText(op.Operands[0]); err != nil {
+			return fmt.Errorf("': %w", err)
+		}
+
+	case "\"":
+		// Set word and character spacing, move to the next line, and show
+		// text. Generators use it with an empty string just for the
+		// spacing side effects, which persist.
+		if len(op.Operands) < 3 {
+			return errOperandCount("\"", 3, len(op.Operands))
+		}
+		aw, err1 := operandToFloat(op.Operands[0])
+		ac, err2 := operandToFloat(op.Operands[1])
+		if err := errors.Join(err1, err2); err != nil {
+			return fmt.Errorf("\": %w", err)
+		}
+		interp.textState.WordSpacing = aw
+		interp.textState.CharSpacing = ac
+		interp.nextLine()
+		if err := interp.showText(op.Operands[2]); err != nil {
+			return fmt.Errorf("\": %w", err)
+		}
 
 	// --- Other common ops to ignore gracefully ---
 	case "Tm": // Set text matrix [a b c d e f]
 		if len(op.Operands) < 6 {
 			break // Ignore malformed op
 		}
+		var m geom.Matrix
+		valid := true
+		for i := range m {
+			v, err := operandToFloat(op.Operands[i])
+			if err != nil {
+				valid = false
+				break
+			}
+			m[i] = v
+		}
+		if valid {
+			interp.textState.TextMatrix = m
+			interp.textState.LineMatrix = m
+		}
 		if f, err := operandToFloat(op.Operands[5]); err == nil {
 			// Check if Y position (f) has changed significantly
 			if math.Abs(f-interp.textState.LastY) > interp.textState.FontSize*0.5 {
-				interp.textBuilder.WriteString("\n")
+				interp.emit("\n")
 			}
 			interp.textState.LastY = f
 		}
@@ -184,46 +550,171 @@ func (interp *Interpreter) processOperation(op parser.Operation) error {
 		tx, err1 := operandToFloat(op.Operands[0])
 		ty, err2 := operandToFloat(op.Operands[1])
 		if err1 == nil && err2 == nil {
+			if op.Name == "TD" {
+				interp.textState.Leading = -ty
+			}
+			interp.moveTextPosition(tx, ty)
 			if ty != 0 {
 				// Vertical move
-				interp.textBuilder.WriteString("\n")
+				interp.emit("\n")
 				interp.textState.LastY += ty
-			} else if tx > 1.0 {
-				// Horizontal move - add space only if movement is significant
-				// tx is in text space units (unscaled user space units).
-				// Typical character widths are 0.5-1.0, so movements > 1.0 indicate word spacing.
-				// This is a heuristic that may need tuning for specific PDFs.
-				interp.textBuilder.WriteString(" ")
 			}
+			// Horizontal moves are compared against the end of the previous
+			// run when the next string is shown (see wordBreak)
+		}
+	case "Tc", "Tw", "Tz", "TL", "Ts":
+		// Set character spacing, word spacing, horizontal scaling (in
+		// percent), leading or rise
+		if len(op.Operands) < 1 {
+			return errOperandCount(op.Name, 1, len(op.Operands))
+		}
+		v, err := operandToFloat(op.Operands[0])
+		if err != nil {
+			return fmt.Errorf("%w: %s operand not a number", ErrInvalidOperand, op.Name)
+		}
+		switch op.Name {
+		case "Tc":
+			interp.textState.CharSpacing = v
+		case "Tw":
+			interp.textState.WordSpacing = v
+		case "Tz":
+			interp.textState.HorizontalScaling = v
+		case "TL":
+			interp.textState.Leading = v
+		case "Ts":
+			interp.textState.Rise = v
 		}
-	case "rg", "RG", "g", "G", "Tc", "Tw", "re", "W", "n", "gs", "cm", "Do":
-		// Ignore graphics operations - we only care about text content
+
+	case "W", "W*":
+		interp.setClip()
+
+	case "g", "G", "rg", "RG", "k", "K", "cs", "CS", "sc", "SC", "scn", "SCN":
+		err = interp.setColor(op.Name, op.Operands)
 
 	default:
 		// log.Printf("Ignoring unhandled operator: %s", op.Name)
 	}
-	return nil
+	return err
+}
+
+// emit appends extracted text unless output is currently suppressed
+// (e.g., inside an artifact when SkipArtifacts is set).
+func (interp *Interpreter) emit(s string) {
+	if interp.suppressed() {
+		return
+	}
+	if interp.afterCheckbox && s != "" {
+		if !strings.ContainsAny(s[:1], " \t\r\n") {
+			interp.write(" ")
+		}
+		interp.afterCheckbox = false
+	}
+	if s == "\n" {
+		// A line break supersedes any pending word separator
+		interp.pendingSpace = false
+		if interp.droppedLine {
+			// Lines of dropped runs leave no blank line
+			interp.droppedLine = false
+			return
+		}
+	} else if s != "" {
+		interp.droppedLine = false
+	}
+	interp.write(s)
+}
+
+// emitText writes decoded text for a run, first resolving the separator
+// between it and the previous run.
+func (interp *Interpreter) emitText(text string, run TextRun) {
+	if interp.suppressed() || text == "" {
+		return
+	}
+	if interp.hasLastRun {
+		sep, ok := "", false
+		if interp.options.RejoinNumbers {
+			sep, ok = interp.numberSeparator(text, run)
+		}
+		if ok {
+			interp.pendingSpace = false
+			interp.write(sep)
+		} else if interp.wordBreak(run) {
+			interp.pendingSpace = true
+		}
+	}
+	// Only the first piece of a run borders the previous run
+	interp.hasLastRun = false
+	if interp.pendingSpace {
+		interp.pendingSpace = false
+		interp.emit(" ")
+	}
+	interp.emit(text)
+}
+
+// nextLine moves to the start of the next line (T*, ', ").
+func (interp *Interpreter) nextLine() {
+	interp.emit("\n")
+	interp.textState.LastY -= interp.textState.Leading
+	interp.moveTextPosition(0, -interp.textState.Leading)
 }
 
 // showText is a helper to append text.
 // It handles simple string/byte conversion and uses the current font's encoding.
 func (interp *Interpreter) showText(val any) error {
+	var data []byte
 	switch s := val.(type) {
 	case string:
 		// This comes from a Literal String ( ... )
 		// For literal strings, we typically use the font's encoding directly
-		// Convert string to bytes and decode
-		decoded := interp.currentFont.DecodeText([]byte(s))
-		interp.textBuilder.WriteString(decoded)
+		data = []byte(s)
 	case []byte:
 		// This comes from a Hex String < ... >
-		// Decode using current font's encoding/ToUnicode CMap
-		decoded := interp.currentFont.DecodeText(s)
-		interp.textBuilder.WriteString(decoded)
+		data = s
 	default:
 		// This will catch operands that are not text, e.g., numbers.
-		return fmt.Errorf("operand not a string or []byte, got %T", val)
+		return fmt.Errorf("%w: not a string or []byte, got %T", ErrInvalidOperand, val)
+	}
+
+	if len(data) == 0 {
+		// An empty string shows no glyphs and does not move the text
+		// position. It must not become the previous run either: the gap
+		// to the next string is measured from the last glyphs drawn, so
+		// a Td between them still reads as a word break.
+		return nil
+	}
+	interp.showedText = true
+	if interp.options.GlyphFunc != nil {
+		interp.reportGlyphs(data)
+	}
+	if !interp.options.Region.IsEmpty() {
+		// Show only the glyphs inside the region, moving past the others
+		start, end := interp.regionSpan(data)
+		interp.skipText(data[:start])
+		defer interp.skipText(data[end:])
+		if data = data[start:end]; len(data) == 0 {
+			return nil
+		}
+	}
+
+	// Decode using current font's encoding/ToUnicode CMap
+	decoded := interp.decodeText(data)
+	run := interp.recordRun(decoded, data)
+	if interp.options.MarkScripts {
+		decoded = markScript(decoded, run.Script)
+	}
+
+	if interp.options.RecognizeCheckboxes {
+		interp.showCheckboxText(data, decoded, run)
+	} else {
+		interp.emitText(decoded, run)
+	}
+	if interp.droppedRun {
+		b := &interp.textBuilder
+		interp.droppedLine = b.Len() == 0 || b.Tail(1) == "\n"
+		interp.droppedRun = false
 	}
+	interp.lastRun = run
+	interp.lastRunEnd, interp.runDirection = interp.writingPosition()
+	interp.hasLastRun = true
 	return nil
 }
 
@@ -243,5 +734,5 @@ func operandToFloat(val any) (float64, error) {
 			return f, nil
 		}
 	}
-	return 0, fmt.Errorf("cannot convert %v to float", val)
+	return 0, fmt.Errorf("%w: cannot convert %v to float", ErrInvalidOperand, val)
 }
@@ -0,0 +1,208 @@
+package interpreter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/internal/streamgen"
+)
+
+// showStream interprets stream with /F1 a WinAnsi font without widths,
+// so each glyph advances 500 units and the space is assumed 250 wide.
+func showStream(t *testing.T, stream string) *Interpreter {
+	t.Helper()
+	registry := font.NewFontRegistry()
+	registry.RegisterSimple("F1", font.EncodingWinAnsi)
+	interp := NewInterpreter(registry)
+	if err := interp.ProcessStream(strings.NewReader(stream)); err != nil {
+		t.Fatal(err)
+	}
+	return interp
+}
+
+func TestShowEmptyString(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   string
+		runs   int
+	}{
+		{"Tj", "BT /F1 10 Tf () Tj ET", "", 0},
+		{"TJ", "BT /F1 10 Tf [] TJ ET", "", 0},
+		{"TJ of empty strings", "BT /F1 10 Tf [() -500 <>] TJ ET", "", 0},
+		{"Tj between moved words", "BT /F1 10 Tf (one) Tj 20 0 Td () Tj (two) Tj ET", "one two", 2},
+		{"TJ adjustment between empty strings", "BT /F1 10 Tf (one) Tj [() -500 ()] TJ (two) Tj ET", "one two", 2},
+		{"TJ empty string within a word", "BT /F1 10 Tf [(o) () (ne)] TJ ET", "one", 2},
+		{"'", "BT /F1 10 Tf 12 TL (one) Tj () ' (two) Tj ET", "one\ntwo", 2},
+		{"\"", "BT /F1 10 Tf 12 TL (one) Tj 0 0 () \" (two) Tj ET", "one\ntwo", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := showStream(t, tt.stream)
+			if got := interp.GetText(); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+			if got := len(interp.GetRuns()); got != tt.runs {
+				t.Errorf("%d runs, want %d", got, tt.runs)
+			}
+			if got := interp.ShowedText(); got != (tt.runs > 0) {
+				t.Errorf("ShowedText() = %v, want %v", got, tt.runs > 0)
+			}
+		})
+	}
+}
+
+func TestTdWordBreak(t *testing.T) {
+	// "Hel" is 15pt wide: a move of 15pt continues the word.
+	tests := []struct {
+		name   string
+		stream string
+		want   string
+	}{
+		{"move to the end of the run", "BT /F1 10 Tf (Hel) Tj 15 0 Td (lo) Tj ET", "Hello"},
+		{"move within a space", "BT /F1 10 Tf (Hel) Tj 16 0 Td (lo) Tj ET", "Hello"},
+		{"move past a space", "BT /F1 10 Tf (one) Tj 20 0 Td (two) Tj ET", "one two"},
+		{"move back", "BT /F1 10 Tf (one) Tj 0 0 Td (two) Tj ET", "onetwo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := showStream(t, tt.stream).GetText(); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreFont(t *testing.T) {
+	// /F2 maps A to X, so the text shows which font decoded each string.
+	cmap, err := font.ParseToUnicodeCMap(strings.NewReader("begincmap\n" +
+		"1 begincodespacerange\n<00> <FF>\nendcodespacerange\n" +
+		"1 beginbfchar\n<41> <0058>\nendbfchar\nendcmap\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := font.NewFontRegistry()
+	registry.RegisterSimple("F1", font.EncodingWinAnsi)
+	f2 := font.NewFont("F2")
+	f2.ToUnicode = cmap
+	registry.Register(f2)
+	interp := NewInterpreter(registry)
+	stream := "BT /F1 10 Tf (A) Tj q /F2 10 Tf (A) Tj Q (A) Tj ET"
+	if err := interp.ProcessStream(strings.NewReader(stream)); err != nil {
+		t.Fatal(err)
+	}
+	if got := interp.GetText(); got != "AXA" {
+		t.Errorf("text = %q, want %q", got, "AXA")
+	}
+}
+
+func TestDoubleQuoteSetsSpacing(t *testing.T) {
+	// 3 glyphs of 5pt, Tc after each and Tw after the space.
+	interp := showStream(t, "BT /F1 10 Tf 12 TL 4 2 () \" ET BT (a b) Tj ET")
+	ts := interp.textState
+	if ts.WordSpacing != 4 || ts.CharSpacing != 2 {
+		t.Errorf("Tw, Tc = %g, %g; want 4, 2", ts.WordSpacing, ts.CharSpacing)
+	}
+	runs := interp.GetRuns()
+	if len(runs) != 1 {
+		t.Fatalf("runs = %+v, want one", runs)
+	}
+	if want := 3*(5+2) + 4.0; runs[0].Width != want {
+		t.Errorf("width = %g, want %g", runs[0].Width, want)
+	}
+
+	// The spacing applies to the string " shows, too.
+	interp = showStream(t, "BT /F1 10 Tf 12 TL 4 2 (a b) \" ET")
+	if runs := interp.GetRuns(); len(runs) != 1 || runs[0].Width != 25 || runs[0].Y != -12 {
+		t.Errorf("runs = %+v, want one 25pt wide on the next line", runs)
+	}
+}
+
+func TestMaxWarnings(t *testing.T) {
+	// Six malformed tokens (each "<zz>" leaves a stray "<" and ">") and
+	// three unbalanced Qs: nine warnings.
+	stream := "<zz> Q <zz> Q <zz> Q BT /F1 10 Tf (text) Tj ET"
+	interp := NewInterpreterWithOptions(nil, Options{MaxWarnings: 4, SilenceWarnings: true})
+	if err := interp.ProcessStream(strings.NewReader(stream)); err != nil {
+		t.Fatal(err)
+	}
+	if got := interp.GetText(); got != "text" {
+		t.Errorf("text = %q, want %q", got, "text")
+	}
+	warnings := interp.GetWarnings()
+	if len(warnings) != 5 {
+		t.Fatalf("warnings = %v, want 4 and a summary", warnings)
+	}
+	last := warnings[4]
+	if !errors.Is(last.Err, ErrWarningsSuppressed) || !strings.Contains(last.Message, "5 more") {
+		t.Errorf("last warning = %v, want 5 suppressed", last)
+	}
+
+	interp.Reset()
+	if err := interp.ProcessStream(strings.NewReader("Q")); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(interp.GetWarnings()); got != 1 {
+		t.Errorf("%d warnings after Reset, want 1", got)
+	}
+}
+
+// BenchmarkInterpret measures interpretation of a large synthetic content
+// stream, parsing included, with the generator's fonts.
+func BenchmarkInterpret(b *testing.B) {
+	cfg := streamgen.Config{Size: 4 << 20, Seed: 7}
+	data := streamgen.Generate(cfg)
+	registry := streamgen.Registry(cfg)
+	interp := NewInterpreter(registry)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		interp.Reset()
+		if err := interp.ProcessStream(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// FuzzInterpret checks that interpretation terminates without panicking
+// on any stream, with the options that keep extra state enabled, and that
+// the text stays valid UTF-8 within the output cap.
+func FuzzInterpret(f *testing.F) {
+	for seed := range uint64(4) {
+		f.Add(streamgen.Generate(streamgen.Config{Size: 2 << 10, Seed: seed}))
+		f.Add(streamgen.Generate(streamgen.Config{Size: 2 << 10, Seed: seed, Pathological: streamgen.Pathological{
+			DeepArrays:    true,
+			UnbalancedOps: true,
+			GarbageTokens: true,
+			EscapeHeavy:   true,
+			InlineDicts:   true,
+		}}))
+	}
+	registry := streamgen.Registry(streamgen.Config{})
+	opts := Options{
+		SkipArtifacts:       true,
+		RecognizeCheckboxes: true,
+		RejoinNumbers:       true,
+		SkipDuplicates:      true,
+		RecordProvenance:    true,
+		MaxOutputBytes:      1 << 12,
+		MaxArrayLength:      1 << 12,
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		interp := NewInterpreterWithOptions(registry, opts)
+		_ = interp.ProcessStream(bytes.NewReader(data))
+		text := interp.GetText()
+		if !utf8.ValidString(text) {
+			t.Errorf("text is not valid UTF-8: %q", text)
+		}
+		if len(text) > opts.MaxOutputBytes {
+			t.Errorf("%d bytes of text, cap %d", len(text), opts.MaxOutputBytes)
+		}
+		interp.GetCheckboxes()
+		interp.GetProvenance()
+	})
+}
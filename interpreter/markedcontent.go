@@ -0,0 +1,63 @@
+package interpreter
+
+// MarkedContent describes an open marked-content sequence (BMC/BDC ... EMC).
+type MarkedContent struct {
+	// Tag is the sequence tag without the leading slash (e.g., "Artifact", "P", "Span").
+	Tag string
+
+	// Properties is the BDC property list operand: either an inline
+	// parser.Dict or the name (a string) of a /Properties resource.
+	// It is nil for sequences opened with BMC.
+	Properties any
+
+	hidden bool // on a layer hidden by Options.Layers
+}
+
+// IsArtifact reports whether the sequence marks pagination or layout artifacts.
+func (mc MarkedContent) IsArtifact() bool {
+	return mc.Tag == "Artifact"
+}
+
+// beginMarkedContent pushes a new sequence for BMC/BDC.
+func (interp *Interpreter) beginMarkedContent(tag string, properties any) {
+	mc := MarkedContent{Tag: tag, Properties: properties, hidden: interp.hiddenLayer(tag, properties)}
+	interp.markedContent = append(interp.markedContent, mc)
+	if mc.IsArtifact() {
+		interp.artifactDepth++
+	}
+	if mc.hidden {
+		interp.hiddenDepth++
+	}
+}
+
+// endMarkedContent pops the innermost sequence for EMC.
+func (interp *Interpreter) endMarkedContent() bool {
+	if len(interp.markedContent) == 0 {
+		return false
+	}
+	mc := interp.markedContent[len(interp.markedContent)-1]
+	interp.markedContent = interp.markedContent[:len(interp.markedContent)-1]
+	if mc.IsArtifact() {
+		interp.artifactDepth--
+	}
+	if mc.hidden {
+		interp.hiddenDepth--
+	}
+	return true
+}
+
+// MarkedContentStack returns a copy of the currently open marked-content
+// sequences, outermost first.
+func (interp *Interpreter) MarkedContentStack() []MarkedContent {
+	stack := make([]MarkedContent, len(interp.markedContent))
+	copy(stack, interp.markedContent)
+	return stack
+}
+
+// suppressed reports whether text output is currently being discarded.
+func (interp *Interpreter) suppressed() bool {
+	if interp.options.SkipArtifacts && interp.artifactDepth > 0 || interp.hiddenDepth > 0 || interp.droppedRun {
+		return true
+	}
+	return interp.options.SkipInvisible && !interp.textState.RenderMode.IsVisible()
+}
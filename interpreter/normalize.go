@@ -0,0 +1,52 @@
+package interpreter
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ligatures maps the Latin presentation-form ligatures to their letters.
+var ligatures = map[rune]string{
+	'ﬀ': "ff",
+	'ﬁ': "fi",
+	'ﬂ': "fl",
+	'ﬃ': "ffi",
+	'ﬄ': "ffl",
+	'ﬅ': "st", // long s + t
+	'ﬆ': "st",
+}
+
+// isLigature reports whether r is a Latin presentation-form ligature.
+func isLigature(r rune) bool {
+	return r >= 'ﬀ' && r <= 'ﬆ'
+}
+
+// expandLigatures replaces the ligatures of s with their letters.
+func expandLigatures(s string) string {
+	if !strings.ContainsFunc(s, isLigature) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 4)
+	for _, r := range s {
+		if letters, ok := ligatures[r]; ok {
+			b.WriteString(letters)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// normalizeText applies the ExpandLigatures and NormalizeNFC options to
+// decoded text.
+func (interp *Interpreter) normalizeText(s string) string {
+	if interp.options.ExpandLigatures {
+		s = expandLigatures(s)
+	}
+	if interp.options.NormalizeNFC && !norm.NFC.IsNormalString(s) {
+		s = norm.NFC.String(s)
+	}
+	return s
+}
@@ -0,0 +1,28 @@
+package interpreter
+
+import (
+	"math"
+
+	"github.com/apex-woot/pdf-stream-engine/numbers"
+)
+
+// numberTailBytes is how much already-emitted text is inspected when
+// deciding whether a run boundary falls inside a number.
+const numberTailBytes = 16
+
+// numberSeparator decides how to join text to the previous run when the
+// boundary between them falls inside a number. It returns ok == false
+// when the normal spacing rules should apply.
+func (interp *Interpreter) numberSeparator(text string, run TextRun) (string, bool) {
+	prev := interp.lastRun
+	if prev.FontSize <= 0 || math.Abs(run.Y-prev.Y) > 0.2*prev.FontSize {
+		return "", false // Not on the same baseline
+	}
+	gap := (run.X - prev.EndX()) / prev.FontSize
+	if gap < -0.2 {
+		return "", false // Overlapping or moving backwards
+	}
+
+	emitted := interp.textBuilder.Tail(numberTailBytes)
+	return numbers.JoinSeparator(emitted, text, math.Max(gap, 0))
+}
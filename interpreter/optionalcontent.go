@@ -0,0 +1,115 @@
+package interpreter
+
+import (
+	"slices"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// LayerFunc reports whether the optional content group (layer) named name
+// is visible.
+type LayerFunc func(name string) bool
+
+// HideLayers returns a LayerFunc under which the named layers are hidden
+// and all others visible.
+func HideLayers(names ...string) LayerFunc {
+	return func(name string) bool { return !slices.Contains(names, name) }
+}
+
+// ShowLayers returns a LayerFunc under which only the named layers are
+// visible.
+func ShowLayers(names ...string) LayerFunc {
+	return func(name string) bool { return slices.Contains(names, name) }
+}
+
+// OptionalContent is the optional content that an /OC marked-content
+// sequence belongs to: an optional content group (ISO 32000-1, 8.11.2)
+// or a membership dictionary combining several.
+type OptionalContent struct {
+	// Groups are the names (/Name) of the optional content groups.
+	Groups []string
+
+	// Policy is the visibility policy (/P) of a membership dictionary:
+	// "AnyOn", "AllOn", "AnyOff" or "AllOff". It is "" for a single
+	// group, which is visible if the group is, like "AnyOn".
+	Policy string
+}
+
+// Visible reports whether the content is visible when layers decides the
+// visibility of the groups. Visibility expressions (/VE) are not
+// evaluated; a membership dictionary without groups is visible.
+func (oc OptionalContent) Visible(layers LayerFunc) bool {
+	if len(oc.Groups) == 0 {
+		return true
+	}
+	on := 0
+	for _, name := range oc.Groups {
+		if layers(name) {
+			on++
+		}
+	}
+	switch oc.Policy {
+	case "AllOn":
+		return on == len(oc.Groups)
+	case "AnyOff":
+		return on < len(oc.Groups)
+	case "AllOff":
+		return on == 0
+	default:
+		return on > 0
+	}
+}
+
+// ParseOptionalContent reads an optional content group or membership
+// dictionary, with indirect objects already resolved. It reports false
+// if d is neither.
+func ParseOptionalContent(d parser.Dict) (OptionalContent, bool) {
+	switch typ, _ := d.String("Type"); typ {
+	case "OCG":
+		name, _ := d.String("Name")
+		return OptionalContent{Groups: []string{name}}, true
+	case "OCMD":
+		oc := OptionalContent{Policy: "AnyOn"}
+		if p, ok := d.String("P"); ok {
+			oc.Policy = p
+		}
+		groups := d["OCGs"]
+		if group, ok := groups.(parser.Dict); ok {
+			groups = []any{group}
+		}
+		list, _ := groups.([]any)
+		for _, g := range list {
+			if group, ok := g.(parser.Dict); ok {
+				name, _ := group.String("Name")
+				oc.Groups = append(oc.Groups, name)
+			}
+		}
+		return oc, true
+	}
+	return OptionalContent{}, false
+}
+
+// optionalContent resolves the property list of an /OC sequence: an
+// inline dictionary or the name of a /Properties resource.
+func (interp *Interpreter) optionalContent(properties any) (OptionalContent, bool) {
+	switch p := properties.(type) {
+	case parser.Dict:
+		return ParseOptionalContent(p)
+	case string:
+		if interp.resources != nil {
+			return interp.resources.OptionalContent(p)
+		}
+	}
+	return OptionalContent{}, false
+}
+
+// hiddenLayer reports whether a sequence opened by BDC with tag and
+// properties marks content on a layer that Options.Layers hides.
+// Content whose optional content cannot be resolved is visible.
+func (interp *Interpreter) hiddenLayer(tag string, properties any) bool {
+	if tag != "OC" || interp.options.Layers == nil {
+		return false
+	}
+	oc, ok := interp.optionalContent(properties)
+	return ok && !oc.Visible(interp.options.Layers)
+}
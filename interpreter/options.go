@@ -0,0 +1,163 @@
+package interpreter
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+)
+
+// Options controls optional interpreter behavior.
+// The zero value matches the default extraction behavior.
+type Options struct {
+	// SkipArtifacts suppresses text inside /Artifact marked-content
+	// sequences, which tagged PDFs use for running headers, footers,
+	// and page numbers.
+	SkipArtifacts bool
+
+	// RecognizeCheckboxes replaces checkbox and radio-button glyphs
+	// (ZapfDingbats, Wingdings, Unicode ballot boxes) with "[x]"/"[ ]"
+	// style markers and records them for GetCheckboxes. Squares drawn
+	// with path operators just before a label are recognized as well,
+	// checked if a mark is drawn inside them.
+	RecognizeCheckboxes bool
+
+	// RejoinNumbers uses run geometry to reassemble numbers that producers
+	// split into fragments (a detached minus sign, digits positioned
+	// separately, thin-space group separators drawn as kerning), so amounts
+	// like "1 234,56" or "-1,234.56" come out as single tokens.
+	RejoinNumbers bool
+
+	// SkipInvisible drops text drawn with an invisible rendering mode
+	// (Tr 3, or 7 which only clips), which is how OCR text layers and
+	// hidden text are embedded.
+	SkipInvisible bool
+
+	// SkipTextMatchingPageBackground drops text painted in the color of
+	// what lies behind it, such as white text on a white page used for
+	// keyword stuffing. The background is the fill color of the last
+	// filled path whose bounding box contains the text, or white; images
+	// are not looked at, so white text on a dark image is dropped too.
+	SkipTextMatchingPageBackground bool
+
+	// MinFontSize drops runs whose rendered font size (TextRun.FontSize)
+	// is below it, such as fine print. Zero keeps all runs.
+	MinFontSize float64
+
+	// RunFilter, if set, is called with each run not otherwise dropped,
+	// before it is recorded, and the run is dropped if it returns false:
+	// a hook for excluding text such as watermarks or machine-readable
+	// zones by font, position or color. The run's Text is the decoded
+	// text, before MarkScripts.
+	RunFilter func(TextRun) bool
+
+	// GlyphFunc, if set, is called with every glyph the text-showing
+	// operators show, in stream order, whether or not the filters above
+	// keep its run: a hook for tools that edit the stream glyph by glyph,
+	// such as redaction.
+	GlyphFunc func(Glyph)
+
+	// SkipDuplicates drops runs that repeat the text of a run already
+	// shown at nearly the same position, with boxes mostly overlapping:
+	// the copies some producers draw a fraction of a point apart to fake
+	// bold, and OCR text layered over the text it was recognized from.
+	// The first run drawn is kept.
+	SkipDuplicates bool
+
+	// Layers, if set, decides which optional content groups (layers) are
+	// visible, and text in /OC marked-content sequences whose optional
+	// content is hidden under it is dropped, such as an alternate-language
+	// or watermark layer. Property lists named by BDC are resolved
+	// through the ResourceProvider. If nil, text on all layers is
+	// extracted. See HideLayers and ShowLayers.
+	Layers LayerFunc
+
+	// SkipClipped drops text whose bounding box lies entirely outside the
+	// clipping path (W, W*), which producers use to hide stale text. The
+	// clip is tracked as the bounding box of the clipping paths, so text
+	// inside that box is kept even where the path itself excludes it.
+	SkipClipped bool
+
+	// Region, if not empty, restricts extraction to glyphs inside it, a
+	// rectangle in device space (default user space when the page has no
+	// CTM; the coordinates of TextRun): a glyph counts if the center of
+	// its box does. Glyphs outside still advance the text position. Line
+	// breaks are emitted as for the whole stream, so the runs, or the
+	// layout package, give the most compact text.
+	Region geom.Rect
+
+	// PUARemap replaces private-use code points in decoded text, for
+	// legacy symbol fonts whose glyphs decode to the Private Use Area.
+	// It is applied before checkbox recognition, so remapping to ballot
+	// box characters makes them recognizable.
+	PUARemap PUATable
+
+	// PUARemappers replace the private-use code points PUARemap leaves,
+	// trying each remapper in order, e.g. GlyphNamePUARemapper to recover
+	// text from glyph names.
+	PUARemappers []PUARemapper
+
+	// ExpandLigatures replaces the Latin presentation-form ligatures
+	// (U+FB00-U+FB06: ﬀ, ﬁ, ﬂ, ﬃ, ﬄ, ﬅ, ﬆ) in decoded text with their
+	// letters, so search indexes and diff tools see "fi" rather than "ﬁ".
+	ExpandLigatures bool
+
+	// NormalizeNFC normalizes decoded text to Unicode Normalization Form
+	// C, composing base letters and combining marks that fonts map to
+	// separately (e + U+0301 becomes é). Each show-string is normalized
+	// on its own, after ligature expansion and PUA remapping.
+	NormalizeNFC bool
+
+	// MarkScripts writes superscript runs as ^text and subscript runs as
+	// _text in the extracted text, with braces around more than one
+	// character (x^2, H_2O, note^{12}). Runs report their script in
+	// TextRun.Script either way.
+	MarkScripts bool
+
+	// CountOperators counts the operations interpreted by operator, for
+	// GetOperatorCounts.
+	CountOperators bool
+
+	// RecordProvenance records which operation emitted each part of the
+	// extracted text, for GetProvenance, so that tools can go from a word
+	// of the text back to the operands that showed it.
+	RecordProvenance bool
+
+	// MaxOutputBytes caps the size of the extracted text. When it is
+	// reached, processing stops with a *LimitError; the text up to the
+	// cap remains available. Zero means no limit.
+	MaxOutputBytes int
+
+	// MaxOperations caps the number of operations interpreted in one
+	// stream, MaxArrayLength the elements of an array operand and the
+	// operands of one operation (see parser.Parser.SetMaxArrayLength),
+	// and MaxFormDepth the font scopes pushed for nested form XObjects
+	// (see PushFontScope) within which a stream may be processed.
+	// Exceeding one stops processing with a *LimitError, keeping the text
+	// extracted so far. They guard servers against content crafted to
+	// exhaust them. Zero means no limit.
+	MaxOperations  int
+	MaxArrayLength int
+	MaxFormDepth   int
+
+	// MaxWarnings caps the warnings collected and logged until Reset.
+	// Further warnings are only counted, and GetWarnings ends with one
+	// more that says how many were suppressed; processing goes on. Zero
+	// means no limit.
+	MaxWarnings int
+
+	// Logger receives warnings about malformed content, and at debug
+	// level the decoding source (see font.DecodeSource) of each run of
+	// decoded text. If nil, slog.Default() is used, which writes through
+	// the standard log package unless reconfigured.
+	Logger *slog.Logger
+
+	// SilenceWarnings stops warnings from being logged at all. They are
+	// still collected and available from GetWarnings.
+	SilenceWarnings bool
+
+	// Trace, if set, receives one line per operator with the text state
+	// before and after it and the text it emitted, for debugging
+	// positioning regressions.
+	Trace io.Writer
+}
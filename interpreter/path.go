@@ -0,0 +1,219 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+)
+
+// SegmentKind identifies the path construction operator a segment came from.
+type SegmentKind int
+
+const (
+	MoveTo  SegmentKind = iota // m, or the start of re
+	LineTo                     // l, or an edge of re
+	CurveTo                    // c, v, y
+	Close                      // h, or the end of re
+)
+
+func (k SegmentKind) String() string {
+	switch k {
+	case MoveTo:
+		return "MoveTo"
+	case LineTo:
+		return "LineTo"
+	case CurveTo:
+		return "CurveTo"
+	case Close:
+		return "Close"
+	default:
+		return fmt.Sprintf("SegmentKind(%d)", int(k))
+	}
+}
+
+// PathSegment is one step of a path. MoveTo and LineTo have one point (the
+// end point), CurveTo has three (two control points and the end point),
+// and Close has none.
+type PathSegment struct {
+	Kind   SegmentKind
+	Points []geom.Point
+}
+
+// Path is a painted path. Coordinates are in device space: the CTM in
+// effect when the path was constructed is applied, like text runs.
+type Path struct {
+	Segments []PathSegment
+
+	// Stroke and Fill report how the path was painted.
+	Stroke, Fill bool
+
+	// EvenOdd reports the even-odd fill rule (f*, B*, b*).
+	EvenOdd bool
+
+	// LineWidth is the stroke width in device space units.
+	LineWidth float64
+
+	// FillColor and StrokeColor are the colors the path was painted with.
+	FillColor   Color
+	StrokeColor Color
+}
+
+// Bounds returns the bounding box of the path's points, including curve
+// control points.
+func (p Path) Bounds() geom.Rect {
+	var r geom.Rect
+	first := true
+	for _, seg := range p.Segments {
+		for _, pt := range seg.Points {
+			if first {
+				r = geom.NewRect(pt.X, pt.Y, pt.X, pt.Y)
+				first = false
+				continue
+			}
+			r = r.Union(geom.NewRect(pt.X, pt.Y, pt.X, pt.Y))
+		}
+	}
+	return r
+}
+
+// Subpaths splits the path at MoveTo segments.
+func (p Path) Subpaths() [][]PathSegment {
+	var subpaths [][]PathSegment
+	for _, seg := range p.Segments {
+		if seg.Kind == MoveTo || len(subpaths) == 0 {
+			subpaths = append(subpaths, nil)
+		}
+		subpaths[len(subpaths)-1] = append(subpaths[len(subpaths)-1], seg)
+	}
+	return subpaths
+}
+
+// GetPaths returns the stroked and filled paths drawn so far. Paths ended
+// with n (typically clipping paths) are not included.
+func (interp *Interpreter) GetPaths() []Path {
+	paths := make([]Path, len(interp.paths))
+	copy(paths, interp.paths)
+	return paths
+}
+
+// processPathOperation handles path construction (m, l, c, v, y, h, re)
+// and painting (S, s, f, F, f*, B, B*, b, b*, n) operators.
+func (interp *Interpreter) processPathOperation(name string, operands []any) error {
+	var nums []float64
+	if want := pathOperandCount(name); want > 0 {
+		if len(operands) != want {
+			return errOperandCount(name, want, len(operands))
+		}
+		nums = make([]float64, want)
+		for i, operand := range operands {
+			v, err := operandToFloat(operand)
+			if err != nil {
+				return fmt.Errorf("%w: %s operand %d not a number", ErrInvalidOperand, name, i)
+			}
+			nums[i] = v
+		}
+	}
+
+	ctm := interp.textState.CTM
+	point := func(x, y float64) geom.Point {
+		px, py := ctm.Apply(x, y)
+		return geom.Point{X: px, Y: py}
+	}
+	add := func(kind SegmentKind, pts ...geom.Point) {
+		interp.currentPath = append(interp.currentPath, PathSegment{Kind: kind, Points: pts})
+	}
+
+	switch name {
+	case "m":
+		p := point(nums[0], nums[1])
+		add(MoveTo, p)
+		interp.currentPoint, interp.subpathStart = p, p
+	case "l":
+		p := point(nums[0], nums[1])
+		add(LineTo, p)
+		interp.currentPoint = p
+	case "c":
+		p := point(nums[4], nums[5])
+		add(CurveTo, point(nums[0], nums[1]), point(nums[2], nums[3]), p)
+		interp.currentPoint = p
+	case "v":
+		// The first control point is the current point
+		p := point(nums[2], nums[3])
+		add(CurveTo, interp.currentPoint, point(nums[0], nums[1]), p)
+		interp.currentPoint = p
+	case "y":
+		// The second control point is the end point
+		p := point(nums[2], nums[3])
+		add(CurveTo, point(nums[0], nums[1]), p, p)
+		interp.currentPoint = p
+	case "h":
+		add(Close)
+		interp.currentPoint = interp.subpathStart
+	case "re":
+		x, y, w, h := nums[0], nums[1], nums[2], nums[3]
+		start := point(x, y)
+		add(MoveTo, start)
+		add(LineTo, point(x+w, y))
+		add(LineTo, point(x+w, y+h))
+		add(LineTo, point(x, y+h))
+		add(Close)
+		interp.currentPoint, interp.subpathStart = start, start
+
+	case "S":
+		interp.paintPath(true, false, false)
+	case "s":
+		add(Close)
+		interp.paintPath(true, false, false)
+	case "f", "F":
+		interp.paintPath(false, true, false)
+	case "f*":
+		interp.paintPath(false, true, true)
+	case "B":
+		interp.paintPath(true, true, false)
+	case "B*":
+		interp.paintPath(true, true, true)
+	case "b":
+		add(Close)
+		interp.paintPath(true, true, false)
+	case "b*":
+		add(Close)
+		interp.paintPath(true, true, true)
+	case "n":
+		interp.applyClip()
+		interp.currentPath = nil
+	}
+	return nil
+}
+
+// paintPath records the current path, applies it as a clipping path if W
+// or W* preceded, and starts a new one.
+func (interp *Interpreter) paintPath(stroke, fill, evenOdd bool) {
+	interp.applyClip()
+	if len(interp.currentPath) > 0 {
+		ts := interp.textState
+		interp.paths = append(interp.paths, Path{
+			Segments:    interp.currentPath,
+			Stroke:      stroke,
+			Fill:        fill,
+			EvenOdd:     evenOdd,
+			LineWidth:   ts.LineWidth * ts.CTM.ScaleY(),
+			FillColor:   ts.FillColor,
+			StrokeColor: ts.StrokeColor,
+		})
+	}
+	interp.currentPath = nil
+}
+
+// pathOperandCount returns the number of numeric operands a path operator
+// takes.
+func pathOperandCount(name string) int {
+	switch name {
+	case "m", "l":
+		return 2
+	case "v", "y", "re":
+		return 4
+	case "c":
+		return 6
+	}
+	return 0
+}
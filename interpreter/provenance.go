@@ -0,0 +1,94 @@
+package interpreter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// Provenance maps a span of the extracted text to the operation that
+// emitted it, with Options.RecordProvenance. Separators the interpreter
+// inserts belong to the operation that caused them: a line break to Td,
+// a word space to the string shown after it.
+type Provenance struct {
+	// Start and End are the byte offsets of the span in the text
+	// GetText returns, End exclusive.
+	Start, End int
+
+	// Op is the 0-based index of the operation in the stream, and Source
+	// its position, whose Offset is the byte offset in the stream.
+	Op     int
+	Source parser.Position
+
+	// Element is the index of the string in the TJ array operand, and 0
+	// for the other operators.
+	Element int
+}
+
+// write appends s to the extracted text, recording its provenance.
+func (interp *Interpreter) write(s string) {
+	start := interp.textBuilder.Len()
+	interp.textBuilder.WriteString(s)
+	end := interp.textBuilder.Len()
+	if !interp.options.RecordProvenance || end == start {
+		return
+	}
+	if n := len(interp.provenance); n > 0 {
+		last := &interp.provenance[n-1]
+		if last.End == start && last.Op == interp.opIndex && last.Element == interp.opElement {
+			last.End = end
+			return
+		}
+	}
+	interp.provenance = append(interp.provenance, Provenance{
+		Start:   start,
+		End:     end,
+		Op:      interp.opIndex,
+		Source:  interp.opPos,
+		Element: interp.opElement,
+	})
+}
+
+// GetProvenance returns the provenance of the extracted text, in text
+// order, if Options.RecordProvenance is set: every byte of the text
+// GetText returns lies in exactly one span.
+func (interp *Interpreter) GetProvenance() []Provenance {
+	raw := interp.textBuilder.String()
+	// GetText trims the text and replaces CR LF with LF; shift the spans
+	// to match
+	leading := len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+	text := strings.TrimSpace(raw)
+	trimmed := raw[:leading+len(text)]
+	var crlf []int // raw offsets of the LFs that are dropped
+	for i := leading; i+1 < len(trimmed); i++ {
+		if trimmed[i] == '\r' && trimmed[i+1] == '\n' {
+			crlf = append(crlf, i+1)
+		}
+	}
+	offset := func(o int) int {
+		o = min(max(o, leading), len(trimmed))
+		dropped := sort.SearchInts(crlf, o)
+		return o - leading - dropped
+	}
+
+	spans := make([]Provenance, 0, len(interp.provenance))
+	for _, p := range interp.provenance {
+		p.Start, p.End = offset(p.Start), offset(p.End)
+		if p.Start < p.End {
+			spans = append(spans, p)
+		}
+	}
+	return spans
+}
+
+// FindProvenance returns the span of spans, as GetProvenance returns
+// them, that holds byte offset of the text.
+func FindProvenance(spans []Provenance, offset int) (Provenance, bool) {
+	i := sort.Search(len(spans), func(i int) bool { return spans[i].End > offset })
+	if i < len(spans) && spans[i].Start <= offset {
+		return spans[i], true
+	}
+	return Provenance{}, false
+}
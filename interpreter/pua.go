@@ -0,0 +1,175 @@
+package interpreter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// PUATable maps private-use code points to replacement text. Legacy symbol
+// fonts (legal symbols, bank glyph fonts) often decode to the Private Use
+// Area, and organizations keep tables of what those code points mean.
+type PUATable map[rune]string
+
+// ParsePUATable reads a table with one mapping per line: a private-use
+// code point and the replacement code points, in hex, optionally written
+// as U+XXXX. Blank lines and text after '#' are ignored.
+//
+//	# Bank glyph font
+//	U+F0A7  U+2022        # bullet
+//	F0FC    2713          # check mark
+//	F6C3    0066 0066 0069  # ffi ligature
+func ParsePUATable(r io.Reader) (PUATable, error) {
+	table := make(PUATable)
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("PUA table line %d: expected code point and replacement", lineNo)
+		}
+		runes := make([]rune, len(fields))
+		for i, field := range fields {
+			v, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(field), "U+"), 16, 32)
+			if err != nil || v > unicode.MaxRune {
+				return nil, fmt.Errorf("PUA table line %d: invalid code point %q", lineNo, field)
+			}
+			runes[i] = rune(v)
+		}
+		if !font.IsPrivateUse(runes[0]) {
+			return nil, fmt.Errorf("PUA table line %d: U+%04X is not a private-use code point", lineNo, runes[0])
+		}
+		table[runes[0]] = string(runes[1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// Apply replaces the private-use code points of s found in the table.
+func (t PUATable) Apply(s string) string {
+	if len(t) == 0 || !strings.ContainsFunc(s, font.IsPrivateUse) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if repl, ok := t[r]; ok && font.IsPrivateUse(r) {
+			b.WriteString(repl)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// RemapPUA implements PUARemapper with the table, ignoring the glyph.
+func (t PUATable) RemapPUA(r rune, _ font.GlyphInfo, _ *font.Font) (string, bool) {
+	s, ok := t[r]
+	return s, ok
+}
+
+// PUARemapper replaces the private-use code points that fonts from some
+// producers (Indic and legacy CJK fonts among them) map their glyphs to.
+// RemapPUA is called for each private-use code point r in the text of a
+// glyph decoded with font f; ok is false to leave r unchanged.
+type PUARemapper interface {
+	RemapPUA(r rune, glyph font.GlyphInfo, f *font.Font) (text string, ok bool)
+}
+
+// GlyphNamePUARemapper is a PUARemapper that replaces a private-use code
+// point with the text of the glyph's name (see font.Font.GlyphName and
+// font.GlyphNameToUnicode), for fonts whose glyph names are meaningful
+// even though their Unicode mappings are not: "uni0915" gives क, "fi"
+// gives fi. Names that also map to the Private Use Area are ignored.
+type GlyphNamePUARemapper struct{}
+
+// RemapPUA implements PUARemapper.
+func (GlyphNamePUARemapper) RemapPUA(r rune, glyph font.GlyphInfo, f *font.Font) (string, bool) {
+	name, ok := f.GlyphName(glyph.Code, glyph.Length)
+	if !ok {
+		return "", false
+	}
+	text, ok := font.GlyphNameToUnicode(name)
+	if !ok || text == "" || strings.ContainsFunc(text, font.IsPrivateUse) {
+		return "", false
+	}
+	return text, true
+}
+
+// decodeText decodes data with the current font and applies the PUA
+// remapping table.
+func (interp *Interpreter) decodeText(data []byte) string {
+	text, glyphs, _ := interp.textState.Font.DecodeShowString(data)
+	interp.logDecodeSources(glyphs)
+	return interp.normalizeText(interp.remapPUA(text, glyphs))
+}
+
+// remapPUA replaces the private-use code points of text through the
+// PUARemap table, then the PUARemappers in order.
+func (interp *Interpreter) remapPUA(text string, glyphs []font.GlyphInfo) string {
+	if len(interp.options.PUARemappers) == 0 {
+		return interp.options.PUARemap.Apply(text)
+	}
+	if !strings.ContainsFunc(text, font.IsPrivateUse) {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, g := range glyphs {
+		for _, r := range g.Text {
+			if font.IsPrivateUse(r) {
+				if s, ok := interp.remapRune(r, g); ok {
+					b.WriteString(s)
+					continue
+				}
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// remapRune returns the replacement for the private-use code point r of
+// glyph g.
+func (interp *Interpreter) remapRune(r rune, g font.GlyphInfo) (string, bool) {
+	if s, ok := interp.options.PUARemap[r]; ok {
+		return s, true
+	}
+	for _, remapper := range interp.options.PUARemappers {
+		if s, ok := remapper.RemapPUA(r, g, interp.textState.Font); ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// logDecodeSources logs, at debug level, each run of glyphs decoded by
+// the same source (ToUnicode, encoding, embedded cmap, ...), for
+// debugging garbled extractions.
+func (interp *Interpreter) logDecodeSources(glyphs []font.GlyphInfo) {
+	logger := interp.logger()
+	if len(glyphs) == 0 || !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	var run strings.Builder
+	for i, g := range glyphs {
+		run.WriteString(g.Text)
+		if i+1 < len(glyphs) && glyphs[i+1].Source == g.Source {
+			continue
+		}
+		logger.Debug("decoded text", "font", interp.textState.Font.Name, "source", g.Source.String(), "text", run.String())
+		run.Reset()
+	}
+}
@@ -0,0 +1,43 @@
+package interpreter
+
+import "github.com/apex-woot/pdf-stream-engine/geom"
+
+// regionSpan returns the byte range of data whose glyphs lie inside
+// Options.Region: from the first glyph inside to the last, which for text
+// along a line and a rectangular region are all the glyphs inside. A
+// glyph is inside if the center of its box, as TextRun.Bounds estimates
+// it, is. start == end if no glyph is.
+func (interp *Interpreter) regionSpan(data []byte) (start, end int) {
+	region := interp.options.Region
+	ts := &interp.textState
+	trm := ts.RenderingMatrix()
+	found := false
+	pos := 0.0
+	for i := 0; i < len(data); {
+		code, n := interp.textState.Font.NextCode(data[i:])
+		advance := interp.codeAdvance(code, n)
+		x, y := pos+advance/2, 0.3*ts.FontSize
+		if interp.textState.Font.Vertical {
+			x, y = 0, -(pos + advance/2)
+		}
+		cx, cy := trm.Apply(x, y)
+		if region.Contains(geom.Point{X: cx, Y: cy}) {
+			if !found {
+				start, found = i, true
+			}
+			end = i + n
+		}
+		pos += advance
+		i += n
+	}
+	return start, end
+}
+
+// skipText advances the text position past data without showing it.
+func (interp *Interpreter) skipText(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	tx, ty := interp.advanceVector(interp.textAdvance(data))
+	interp.textState.TextMatrix = geom.Translate(tx, ty).Multiply(interp.textState.TextMatrix)
+}
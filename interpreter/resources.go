@@ -0,0 +1,92 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// ResourceProvider resolves the resources, other than fonts, that a
+// content stream refers to by name: the graphics state parameter
+// dictionaries of the /ExtGState resources, set with gs, the optional
+// content of the /Properties resources, named by /OC marked content, and
+// the /ColorSpace resources, set with cs and CS.
+type ResourceProvider interface {
+	// ExtGState returns the graphics state parameter dictionary named
+	// name, or false if the resources define none.
+	ExtGState(name string) (ExtGState, bool)
+
+	// OptionalContent returns the optional content group or membership
+	// dictionary that the property list named name is, or false if the
+	// resources define no such property list or it is neither.
+	OptionalContent(name string) (OptionalContent, bool)
+
+	// ColorSpace returns the family name of the color space named name
+	// (e.g. "ICCBased", "Separation") and its number of color
+	// components, or false if the resources define none.
+	ColorSpace(name string) (family string, components int, ok bool)
+}
+
+// ExtGState holds the entries of a graphics state parameter dictionary
+// (ISO 32000-1, 8.4.5) that the interpreter applies.
+type ExtGState struct {
+	// Font and FontSize are the /Font entry, which sets the font like Tf.
+	// Font is nil if the dictionary has no /Font entry.
+	Font     *font.Font
+	FontSize float64
+
+	// StrokeAlpha (/CA) and FillAlpha (/ca) are the constant opacities,
+	// nil if the dictionary does not set them.
+	StrokeAlpha *float64
+	FillAlpha   *float64
+
+	// BlendMode is the /BM blend mode name (the first one, for an array),
+	// or "" if the dictionary does not set it.
+	BlendMode string
+}
+
+// SetResources sets the provider that resolves the resources named by
+// operators such as gs, BDC and cs. Without one, gs is ignored, /OC
+// sequences that name their property list are visible and colors in
+// color spaces named by resource are unknown. Like SetFontRegistry, call
+// it between streams.
+func (interp *Interpreter) SetResources(resources ResourceProvider) {
+	interp.resources = resources
+}
+
+// setGraphicsState applies the graphics state parameter dictionary named
+// by a gs operator.
+func (interp *Interpreter) setGraphicsState(operands []any) error {
+	if len(operands) != 1 {
+		return errOperandCount("gs", 1, len(operands))
+	}
+	name, ok := operands[0].(string)
+	if !ok {
+		return fmt.Errorf("%w: gs dictionary name not a name", ErrInvalidOperand)
+	}
+	if interp.resources == nil {
+		return nil
+	}
+	gs, ok := interp.resources.ExtGState(name)
+	if !ok {
+		return fmt.Errorf("%w: no ExtGState %s", ErrMissingResource, name)
+	}
+	if gs.StrokeAlpha != nil {
+		interp.textState.StrokeAlpha = *gs.StrokeAlpha
+	}
+	if gs.FillAlpha != nil {
+		interp.textState.FillAlpha = *gs.FillAlpha
+	}
+	if gs.BlendMode != "" {
+		interp.textState.BlendMode = gs.BlendMode
+	}
+	if gs.Font != nil {
+		interp.textState.FontName = gs.Font.Name
+		interp.textState.FontSize = gs.FontSize
+		interp.textState.Font = gs.Font
+		if err := gs.Font.CheckDecodable(); err != nil {
+			return fmt.Errorf("gs: %w", err)
+		}
+	}
+	return nil
+}
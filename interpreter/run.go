@@ -0,0 +1,273 @@
+package interpreter
+
+import (
+	"math"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// TextRun is a piece of text shown by a single string operand of Tj, TJ, ' or ".
+// Coordinates are in device space: both the text matrix and the current
+// transformation matrix (cm) are applied, like path coordinates.
+type TextRun struct {
+	Text     string
+	FontName string
+
+	// BaseFont is the font's /BaseFont name, and Bold and Italic its
+	// style, as font.Font.IsBold and IsItalic judge it.
+	BaseFont     string
+	Bold, Italic bool
+
+	// FontSize is the rendered font size (Tf size scaled by the text matrix
+	// and the CTM).
+	FontSize float64
+
+	// X, Y is the baseline origin of the first glyph.
+	X, Y float64
+
+	// Width is the advance of the whole run along its writing direction:
+	// horizontal, or downward for Vertical runs.
+	Width float64
+
+	// Vertical is set for runs shown with a vertical-mode font.
+	Vertical bool
+
+	// RenderMode is the text rendering mode the run was shown with.
+	// Runs in invisible modes (e.g., OCR text layers) report IsVisible() == false.
+	RenderMode RenderMode
+
+	// FillColor and StrokeColor are the colors the run was shown with;
+	// which of them paints the glyphs depends on RenderMode.
+	FillColor   Color
+	StrokeColor Color
+
+	// Script reports whether the run is a superscript or subscript: shifted
+	// by the text rise, or set smaller and off the baseline of the text
+	// before it.
+	Script Script
+
+	// Source is the location of the text-showing operator in the content
+	// stream, for tracing text back to the producer's output.
+	Source parser.Position
+}
+
+// EndX returns the baseline x coordinate just after the last glyph.
+// Vertical runs end where they start horizontally.
+func (r TextRun) EndX() float64 {
+	if r.Vertical {
+		return r.X
+	}
+	return r.X + r.Width
+}
+
+// EndY returns the y coordinate just after the last glyph, which differs
+// from Y only for vertical runs.
+func (r TextRun) EndY() float64 {
+	if r.Vertical {
+		return r.Y - r.Width
+	}
+	return r.Y
+}
+
+// Bounds returns an approximate bounding box of the run, assuming glyphs
+// extend from 20% of the font size below the baseline to 80% above it,
+// or, for vertical runs, half the font size either side of the origin.
+func (r TextRun) Bounds() geom.Rect {
+	if r.Vertical {
+		return geom.NewRect(r.X-0.5*r.FontSize, r.EndY(), r.X+0.5*r.FontSize, r.Y)
+	}
+	return geom.NewRect(r.X, r.Y-0.2*r.FontSize, r.EndX(), r.Y+0.8*r.FontSize)
+}
+
+// GetRuns returns the positioned text runs shown so far.
+func (interp *Interpreter) GetRuns() []TextRun {
+	runs := make([]TextRun, len(interp.runs))
+	copy(runs, interp.runs)
+	return runs
+}
+
+// defaultGlyphWidth is the advance assumed for character codes the font
+// has no width for, in thousandths of an em.
+const defaultGlyphWidth = 500.0
+
+// defaultVerticalAdvance is the vertical displacement of each glyph in
+// vertical writing mode (the /DW2 default), in thousandths of an em.
+const defaultVerticalAdvance = 1000.0
+
+// textAdvance returns the advance of data along the writing direction in
+// unscaled text space units (before the text matrix is applied), using the
+// font's glyph widths where available plus character and word spacing,
+// scaled horizontally by Tz in horizontal writing mode. Word spacing
+// applies to single-byte code 32 only, as the spec requires.
+func (interp *Interpreter) textAdvance(data []byte) float64 {
+	total := 0.0
+	for i := 0; i < len(data); {
+		code, n := interp.textState.Font.NextCode(data[i:])
+		i += n
+		total += interp.codeAdvance(code, n)
+	}
+	return total
+}
+
+// codeAdvance returns the advance of one n-byte character code, like
+// textAdvance.
+func (interp *Interpreter) codeAdvance(code uint32, n int) float64 {
+	ts := &interp.textState
+	f := interp.textState.Font
+	width, ok := f.CodeWidth(code, n)
+	if f.Vertical {
+		width, ok = defaultVerticalAdvance, true
+	}
+	if !ok {
+		width = defaultGlyphWidth
+	}
+	advance := width/1000*ts.FontSize + ts.CharSpacing
+	if n == 1 && code == ' ' {
+		advance += ts.WordSpacing
+	}
+	if !f.Vertical {
+		advance *= ts.horizontalScale()
+	}
+	return advance
+}
+
+// defaultSpaceWidth is the space glyph width assumed when the font has
+// none, in thousandths of an em.
+const defaultSpaceWidth = 250.0
+
+// wordBreak reports whether the gap between the previous run and run is
+// wide enough to be a word break. The gap comes from real glyph advances,
+// so it reflects Td/Tm moves, TJ adjustments, and Tc/Tw spacing alike.
+//
+// The gap is measured along the writing direction of the text (downward
+// for vertical fonts, leftward under a mirroring text matrix), so word
+// boundaries come out the same whichever way the glyphs advance.
+//
+// A gap counts as a break when it exceeds half the font's space width,
+// which separates words set with narrow spaces at small sizes while
+// ignoring kerning inside words. Large backward moves on the same
+// baseline (e.g., a second column) also break.
+func (interp *Interpreter) wordBreak(run TextRun) bool {
+	prev := interp.lastRun
+	if prev.FontSize <= 0 {
+		return false
+	}
+	dx, dy := run.X-interp.lastRunEnd.X, run.Y-interp.lastRunEnd.Y
+	dir := interp.runDirection
+	gap := dx*dir.X + dy*dir.Y
+	if math.Abs(dy*dir.X-dx*dir.Y) > 0.2*prev.FontSize {
+		return false // Different baselines are handled as line breaks
+	}
+	if strings.HasSuffix(prev.Text, " ") || strings.HasPrefix(run.Text, " ") {
+		return false // The text already carries a space
+	}
+
+	spaceWidth, ok := interp.textState.Font.GlyphWidth(' ')
+	if !ok || spaceWidth <= 0 {
+		spaceWidth = defaultSpaceWidth
+	}
+	threshold := 0.5 * spaceWidth / 1000 * math.Min(prev.FontSize, run.FontSize)
+
+	return gap > threshold || gap < -2*prev.FontSize
+}
+
+// writingPosition returns the current text position in device space and
+// the unit vector of the writing direction there.
+func (interp *Interpreter) writingPosition() (pos, dir geom.Point) {
+	trm := interp.textState.RenderingMatrix()
+	x, y := trm.Apply(0, 0)
+	ux, uy := interp.advanceVector(1)
+	dx, dy := trm.Apply(ux, uy)
+	if n := math.Hypot(dx-x, dy-y); n > 0 {
+		dir = geom.Point{X: (dx - x) / n, Y: (dy - y) / n}
+	}
+	return geom.Point{X: x, Y: y}, dir
+}
+
+// advanceVector returns the text-space displacement of advance units along
+// the current font's writing direction.
+func (interp *Interpreter) advanceVector(advance float64) (tx, ty float64) {
+	if interp.textState.Font.Vertical {
+		return 0, -advance
+	}
+	return advance, 0
+}
+
+// recordRun positions a run at the current text matrix and advances the
+// matrix past it.
+func (interp *Interpreter) recordRun(text string, data []byte) TextRun {
+	ts := &interp.textState
+	advance := interp.textAdvance(data)
+
+	trm := ts.RenderingMatrix()
+	tx, ty := interp.advanceVector(advance)
+	x, y := trm.Apply(0, 0)
+	endX, endY := trm.Apply(tx, ty)
+	run := TextRun{
+		Text:        text,
+		FontName:    ts.FontName,
+		BaseFont:    interp.textState.Font.BaseFont,
+		FontSize:    ts.RenderedFontSize(),
+		X:           x,
+		Y:           y,
+		Width:       math.Hypot(endX-x, endY-y),
+		Vertical:    interp.textState.Font.Vertical,
+		RenderMode:  ts.RenderMode,
+		FillColor:   ts.FillColor,
+		StrokeColor: ts.StrokeColor,
+		Source:      interp.opPos,
+	}
+	run.Bold, run.Italic = interp.fontStyle()
+	run.Script = interp.classifyScript(run)
+	if run.Script == ScriptNormal {
+		interp.scriptBase, interp.hasScriptBase = run, true
+	}
+	ts.TextMatrix = geom.Translate(tx, ty).Multiply(ts.TextMatrix)
+
+	opts := &interp.options
+	interp.droppedRun = opts.SkipClipped && interp.clippedAway(run.Bounds()) ||
+		opts.SkipTextMatchingPageBackground && interp.matchesBackground(run) ||
+		run.FontSize < opts.MinFontSize ||
+		opts.RunFilter != nil && !interp.suppressed() && !opts.RunFilter(run)
+	interp.droppedRun = interp.droppedRun ||
+		opts.SkipDuplicates && !interp.suppressed() && interp.duplicate(run)
+	if !interp.suppressed() {
+		interp.runs = append(interp.runs, run)
+	}
+	return run
+}
+
+// fontStyle returns whether the current font is bold and italic,
+// remembering the answer for the font, since judging it parses its name.
+func (interp *Interpreter) fontStyle() (bold, italic bool) {
+	if interp.styleFont != interp.textState.Font {
+		interp.styleFont = interp.textState.Font
+		interp.bold, interp.italic = interp.textState.Font.IsBold(), interp.textState.Font.IsItalic()
+	}
+	return interp.bold, interp.italic
+}
+
+// adjustTextPosition moves the text matrix by a TJ adjustment expressed in
+// thousandths of an em. The adjustment is subtracted from the horizontal
+// coordinate, or from the vertical one for vertical fonts. Since vertical
+// text advances downward, a positive adjustment tightens horizontal text
+// but widens the gap in vertical text. Horizontal adjustments are scaled
+// by Tz.
+func (interp *Interpreter) adjustTextPosition(adjustment float64) {
+	d := -adjustment / 1000 * interp.textState.FontSize
+	tx, ty := d*interp.textState.horizontalScale(), 0.0
+	if interp.textState.Font.Vertical {
+		tx, ty = 0, d
+	}
+	interp.textState.TextMatrix = geom.Translate(tx, ty).Multiply(interp.textState.TextMatrix)
+}
+
+// moveTextPosition starts a new line offset by (tx, ty) from the start of
+// the current line (Td, TD, T*).
+func (interp *Interpreter) moveTextPosition(tx, ty float64) {
+	ts := &interp.textState
+	ts.LineMatrix = geom.Translate(tx, ty).Multiply(ts.LineMatrix)
+	ts.TextMatrix = ts.LineMatrix
+}
@@ -0,0 +1,105 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// tjInterpreter returns an interpreter with /F1 a WinAnsi font, /H a
+// two-byte Identity-H font and /V an Identity-V one, both mapping the
+// codes of the Japanese and Arabic letters below to the same Unicode
+// values. None has widths: the space of /F1 is assumed 250 units wide and
+// that of the CID fonts the default 1000, so a gap counts as a word break
+// past 125 and 500 units respectively.
+func tjInterpreter(t *testing.T) *Interpreter {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("begincmap\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	letters := "日本語سلامعيك"
+	fmt.Fprintf(&b, "%d beginbfchar\n", utf8.RuneCountInString(letters))
+	for _, r := range letters {
+		fmt.Fprintf(&b, "<%04X> <%04X>\n", r, r)
+	}
+	b.WriteString("endbfchar\nendcmap\n")
+	cmap, err := font.ParseToUnicodeCMap(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := font.NewFontRegistry()
+	registry.RegisterSimple("F1", font.EncodingWinAnsi)
+	for _, name := range []string{"H", "V"} {
+		f := font.NewFont(name)
+		f.Encoding = font.EncodingIdentity
+		f.IsMultiByte = true
+		f.ToUnicode = cmap
+		f.Vertical = name == "V"
+		registry.Register(f)
+	}
+	return NewInterpreter(registry)
+}
+
+func TestTJAdjustment(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   string
+	}{
+		// Horizontal text: negative adjustments move right, widening the gap.
+		{"horizontal space", "/F1 10 Tf [(one) -200 (two)] TJ", "one two"},
+		{"horizontal kerning", "/F1 10 Tf [(one) 200 (two)] TJ", "onetwo"},
+		{"horizontal below threshold", "/F1 10 Tf [(one) -125 (two)] TJ", "onetwo"},
+		{"horizontal above threshold", "/F1 10 Tf [(one) -130 (two)] TJ", "one two"},
+		{"horizontal scaled by Tz", "/F1 10 Tf 50 Tz [(one) -200 (two)] TJ", "onetwo"},
+		// Vertical text advances downward: positive adjustments move down,
+		// widening the gap.
+		{"vertical Japanese space", "/V 10 Tf [<65E5672C> 600 <8A9E>] TJ", "日本 語"},
+		{"vertical Japanese tightened", "/V 10 Tf [<65E5672C> -600 <8A9E>] TJ", "日本語"},
+		{"vertical below threshold", "/V 10 Tf [<65E5672C> 500 <8A9E>] TJ", "日本語"},
+		{"vertical above threshold", "/V 10 Tf [<65E5672C> 510 <8A9E>] TJ", "日本 語"},
+		{"vertical ignores Tz", "/V 10 Tf 50 Tz [<65E5672C> 600 <8A9E>] TJ", "日本 語"},
+		// Justified Arabic: words stretched apart, letters kerned within them.
+		{
+			"justified Arabic",
+			"/H 10 Tf [<0633> 30 <0644> -40 <0627> <0645> -600 <0639> 20 <0644> <064A> <0643> <0645>] TJ",
+			"سلام عليكم",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := tjInterpreter(t)
+			if err := interp.ProcessStream(strings.NewReader("BT " + tt.stream + " ET")); err != nil {
+				t.Fatal(err)
+			}
+			if got := interp.GetText(); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTJAdjustmentMovesTextPosition(t *testing.T) {
+	tests := []struct {
+		font   string
+		tx, ty float64
+	}{
+		{"F1", -2, 0},
+		{"V", 0, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.font, func(t *testing.T) {
+			interp := tjInterpreter(t)
+			stream := "BT /" + tt.font + " 10 Tf [200] TJ ET"
+			if err := interp.ProcessStream(strings.NewReader(stream)); err != nil {
+				t.Fatal(err)
+			}
+			tm := interp.textState.TextMatrix
+			if tm[4] != tt.tx || tm[5] != tt.ty {
+				t.Errorf("text position = (%g, %g), want (%g, %g)", tm[4], tm[5], tt.tx, tt.ty)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Script tells superscript and subscript runs from text on the baseline.
+type Script int
+
+const (
+	// ScriptNormal is text on the baseline.
+	ScriptNormal Script = iota
+	// ScriptSuperscript is text raised above the baseline, such as
+	// exponents and footnote references.
+	ScriptSuperscript
+	// ScriptSubscript is text lowered below the baseline, such as chemical
+	// formula indices.
+	ScriptSubscript
+)
+
+// String returns the script name.
+func (s Script) String() string {
+	switch s {
+	case ScriptNormal:
+		return "Normal"
+	case ScriptSuperscript:
+		return "Superscript"
+	case ScriptSubscript:
+		return "Subscript"
+	default:
+		return fmt.Sprintf("Script(%d)", int(s))
+	}
+}
+
+// minScriptShift and maxScriptShift bound the baseline shift of
+// superscripts and subscripts, as a fraction of the font size of the text
+// around them. Smaller shifts are jitter (OCR text layers position words
+// to a fraction of a point); larger ones are another line.
+const (
+	minScriptShift = 0.15
+	maxScriptShift = 0.6
+)
+
+// maxScriptSizeRatio is the largest font size, relative to the text
+// around it, that a run shifted off the baseline without a rise may have
+// to count as a superscript or subscript.
+const maxScriptSizeRatio = 0.9
+
+// classifyScript decides whether run, just recorded, is a superscript or
+// subscript: either the text rise (Ts) shifts it, or it is set smaller
+// than the last run on the baseline and shifted off that baseline by a
+// fraction of its font size.
+func (interp *Interpreter) classifyScript(run TextRun) Script {
+	ts := &interp.textState
+	if math.Abs(ts.Rise) > minScriptShift*math.Abs(ts.FontSize) {
+		if ts.Rise > 0 {
+			return ScriptSuperscript
+		}
+		return ScriptSubscript
+	}
+	base := interp.scriptBase
+	if !interp.hasScriptBase || run.Vertical || base.Vertical || run.FontSize > maxScriptSizeRatio*base.FontSize {
+		return ScriptNormal
+	}
+	// Distance from the base run's baseline, perpendicular to the writing
+	// direction
+	dir := interp.runDirection
+	shift := (run.Y-base.Y)*dir.X - (run.X-base.X)*dir.Y
+	if math.Abs(shift) < minScriptShift*base.FontSize || math.Abs(shift) > maxScriptShift*base.FontSize {
+		return ScriptNormal
+	}
+	if shift > 0 {
+		return ScriptSuperscript
+	}
+	return ScriptSubscript
+}
+
+// markScript writes text shown as a superscript or subscript with a
+// marker: ^ or _ before it, and braces around more than one character.
+// Surrounding whitespace is left outside the marker, and text that is all
+// whitespace is not marked.
+func markScript(text string, script Script) string {
+	marker := ""
+	switch script {
+	case ScriptSuperscript:
+		marker = "^"
+	case ScriptSubscript:
+		marker = "_"
+	default:
+		return text
+	}
+	body := strings.TrimLeftFunc(text, unicode.IsSpace)
+	lead := text[:len(text)-len(body)]
+	body = strings.TrimRightFunc(body, unicode.IsSpace)
+	if body == "" {
+		return text
+	}
+	trail := text[len(lead)+len(body):]
+	if utf8.RuneCountInString(body) > 1 {
+		body = "{" + body + "}"
+	}
+	return lead + marker + body + trail
+}
@@ -0,0 +1,110 @@
+package interpreter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// textChunkSize is the largest capacity of a chunk of a textBuffer;
+// chunks start at minTextChunkSize and double up to it.
+const (
+	minTextChunkSize = 1 << 10
+	textChunkSize    = 64 << 10
+)
+
+// textBuffer accumulates extracted text in fixed-size chunks. Unlike a
+// strings.Builder it never copies what was written before when it grows,
+// so memory stays close to the output size even for streams that emit a
+// lot of text, and an optional limit bounds it.
+type textBuffer struct {
+	chunks [][]byte
+	size   int
+
+	limit     int // maximum size in bytes; 0 means unlimited
+	truncated bool
+}
+
+// Reset empties the buffer, keeping its largest chunk for reuse.
+func (b *textBuffer) Reset() {
+	if n := len(b.chunks); n > 0 {
+		b.chunks = append(b.chunks[:0], b.chunks[n-1][:0])
+	}
+	b.size = 0
+	b.truncated = false
+}
+
+// Len returns the number of bytes written.
+func (b *textBuffer) Len() int {
+	return b.size
+}
+
+// Truncated reports whether a write was cut short by the limit.
+func (b *textBuffer) Truncated() bool {
+	return b.truncated
+}
+
+// WriteString appends s, or as much of it as fits under the limit without
+// splitting a UTF-8 sequence.
+func (b *textBuffer) WriteString(s string) {
+	if b.truncated {
+		return
+	}
+	if b.limit > 0 && b.size+len(s) > b.limit {
+		n := b.limit - b.size
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		s = s[:n]
+		b.truncated = true
+	}
+	for len(s) > 0 {
+		last := len(b.chunks) - 1
+		if last < 0 || len(b.chunks[last]) == cap(b.chunks[last]) {
+			b.chunks = append(b.chunks, make([]byte, 0, b.nextChunkSize()))
+			last++
+		}
+		chunk := b.chunks[last]
+		n := min(len(s), cap(chunk)-len(chunk))
+		b.chunks[last] = append(chunk, s[:n]...)
+		b.size += n
+		s = s[n:]
+	}
+}
+
+// nextChunkSize returns the capacity of the next chunk: twice the last
+// one, so short streams don't allocate a full chunk.
+func (b *textBuffer) nextChunkSize() int {
+	if len(b.chunks) == 0 {
+		return minTextChunkSize
+	}
+	return min(2*cap(b.chunks[len(b.chunks)-1]), textChunkSize)
+}
+
+// String returns the whole text.
+func (b *textBuffer) String() string {
+	return b.Slice(0)
+}
+
+// Slice returns the text from byte offset from to the end, copying only
+// that part.
+func (b *textBuffer) Slice(from int) string {
+	if from >= b.size {
+		return ""
+	}
+	var sb strings.Builder
+	sb.Grow(b.size - from)
+	pos := 0
+	for _, chunk := range b.chunks {
+		if end := pos + len(chunk); end > from {
+			start := max(from-pos, 0)
+			sb.Write(chunk[start:])
+		}
+		pos += len(chunk)
+	}
+	return sb.String()
+}
+
+// Tail returns the last n bytes of text (fewer if less was written).
+func (b *textBuffer) Tail(n int) string {
+	return b.Slice(max(b.size-n, 0))
+}
@@ -1,28 +1,148 @@
 package interpreter
 
-// TextState holds the current state relevant to text rendering.
-// A full implementation would include matrices, spacing, and more.
+import (
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/geom"
+)
+
+// TextState holds the current state relevant to text rendering, plus the
+// parts of the graphics state that q/Q save and restore with it.
 type TextState struct {
 	FontName string
 	FontSize float64
 	LastY    float64 // Track the last Y position
-	// We would also track TextMatrix, LineMatrix, WordSpacing, CharSpacing, etc.
+
+	// Font decodes shown strings: the font FontName selects, or that of a
+	// gs font entry.
+	Font *font.Font
+
+	// TextMatrix (Tm) and LineMatrix (Tlm) position glyphs within a text object.
+	TextMatrix geom.Matrix
+	LineMatrix geom.Matrix
+
+	// RenderMode is the text rendering mode (Tr).
+	RenderMode RenderMode
+
+	// CharSpacing (Tc) and WordSpacing (Tw) in unscaled text space units.
+	CharSpacing float64
+	WordSpacing float64
+
+	// HorizontalScaling (Tz) stretches glyph advances, spacing and TJ
+	// adjustments horizontally, in percent (100 is normal).
+	HorizontalScaling float64
+
+	// Leading (TL, or TD's negated vertical offset) is the distance
+	// between baselines that T*, ' and " move down by, and Rise (Ts) the
+	// baseline shift of superscripts and subscripts, both in unscaled
+	// text space units.
+	Leading float64
+	Rise    float64
+
+	// CTM is the current transformation matrix (cm), mapping user space
+	// to device space.
+	CTM geom.Matrix
+
+	// LineWidth is the stroke width (w) in user space units.
+	LineWidth float64
+
+	// StrokeAlpha and FillAlpha are the constant opacities and BlendMode
+	// the blend mode, set through graphics state parameter dictionaries
+	// (gs).
+	StrokeAlpha float64
+	FillAlpha   float64
+	BlendMode   string
+
+	// FillColor and StrokeColor are the nonstroking and stroking colors,
+	// which fill and stroke text and paths.
+	FillColor   Color
+	StrokeColor Color
+
+	// Clip is the device-space bounding box of the clipping path (W, W*),
+	// intersected over the paths set so far; Clipped is false until one is
+	// set.
+	Clip    geom.Rect
+	Clipped bool
 }
 
 // NewTextState creates a new, default text state.
 func NewTextState() TextState {
 	return TextState{
-		FontName: "default",
-		FontSize: 1.0,
-		LastY:    0,
+		FontName:          "default",
+		FontSize:          1.0,
+		LastY:             0,
+		TextMatrix:        geom.Identity(),
+		LineMatrix:        geom.Identity(),
+		HorizontalScaling: 100,
+		CTM:               geom.Identity(),
+		LineWidth:         1.0,
+		StrokeAlpha:       1.0,
+		FillAlpha:         1.0,
+		BlendMode:         "Normal",
+		FillColor:         black,
+		StrokeColor:       black,
 	}
 }
 
 // Copy creates a deep copy of the TextState.
 func (ts TextState) Copy() TextState {
 	return TextState{
-		FontName: ts.FontName,
-		FontSize: ts.FontSize,
-		LastY:    ts.LastY,
+		FontName:          ts.FontName,
+		FontSize:          ts.FontSize,
+		Font:              ts.Font,
+		LastY:             ts.LastY,
+		TextMatrix:        ts.TextMatrix,
+		LineMatrix:        ts.LineMatrix,
+		RenderMode:        ts.RenderMode,
+		CharSpacing:       ts.CharSpacing,
+		WordSpacing:       ts.WordSpacing,
+		HorizontalScaling: ts.HorizontalScaling,
+		Leading:           ts.Leading,
+		Rise:              ts.Rise,
+		CTM:               ts.CTM,
+		LineWidth:         ts.LineWidth,
+		StrokeAlpha:       ts.StrokeAlpha,
+		FillAlpha:         ts.FillAlpha,
+		BlendMode:         ts.BlendMode,
+		FillColor:         ts.FillColor,
+		StrokeColor:       ts.StrokeColor,
+		Clip:              ts.Clip,
+		Clipped:           ts.Clipped,
 	}
 }
+
+// RenderingMatrix returns the text matrix combined with the CTM, which maps
+// text space to device space, with the baseline shifted by the rise.
+func (ts TextState) RenderingMatrix() geom.Matrix {
+	return geom.Translate(0, ts.Rise).Multiply(ts.TextMatrix).Multiply(ts.CTM)
+}
+
+// horizontalScale returns the horizontal scaling as a factor.
+func (ts TextState) horizontalScale() float64 {
+	return ts.HorizontalScaling / 100
+}
+
+// RenderedFontSize returns the font size after applying the text matrix
+// and the CTM.
+func (ts TextState) RenderedFontSize() float64 {
+	return ts.FontSize * ts.RenderingMatrix().ScaleY()
+}
+
+// RenderMode is the text rendering mode set by the Tr operator.
+type RenderMode int
+
+const (
+	RenderFill           RenderMode = 0 // Fill text (default)
+	RenderStroke         RenderMode = 1 // Stroke text
+	RenderFillStroke     RenderMode = 2 // Fill, then stroke text
+	RenderInvisible      RenderMode = 3 // Neither fill nor stroke (used by OCR layers)
+	RenderFillClip       RenderMode = 4 // Fill text and add to clipping path
+	RenderStrokeClip     RenderMode = 5 // Stroke text and add to clipping path
+	RenderFillStrokeClip RenderMode = 6 // Fill, stroke, and add to clipping path
+	RenderClip           RenderMode = 7 // Add text to clipping path only
+)
+
+// IsVisible reports whether glyphs drawn in this mode are painted.
+// Modes 3 and 7 produce no marks on the page.
+func (m RenderMode) IsVisible() bool {
+	return m != RenderInvisible && m != RenderClip
+}
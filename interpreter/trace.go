@@ -0,0 +1,106 @@
+package interpreter
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// String returns a compact one-line summary of the text state, as used
+// in trace output.
+func (ts TextState) String() string {
+	return fmt.Sprintf("font=%s size=%g mode=%d tm=%v tlm=%v",
+		ts.FontName, ts.FontSize, ts.RenderMode, ts.TextMatrix, ts.LineMatrix)
+}
+
+// traceOperation writes one trace line for an interpreted operation:
+// index, line:column, operator with operands, text state before and
+// after, and the text it emitted.
+func traceOperation(w io.Writer, index int, op parser.Operation, before, after TextState, emitted string, err error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%d %d:%d %s", index, op.Line, op.Column, op.Name)
+	if len(op.Operands) > 0 {
+		b.WriteString(" ")
+		b.WriteString(formatOperands(op.Operands, isTextShowingOp(op.Name)))
+	}
+	fmt.Fprintf(&b, " | before: %v", before)
+	if after != before {
+		fmt.Fprintf(&b, " | after: %v", after)
+	}
+	if emitted != "" {
+		fmt.Fprintf(&b, " | text: %q", emitted)
+	}
+	if err != nil {
+		fmt.Fprintf(&b, " | error: %v", err)
+	}
+	b.WriteString("\n")
+	_, _ = io.WriteString(w, b.String())
+}
+
+// formatOperands renders operands in a content-stream-like notation:
+// names as /Name, literal strings in (), hex strings as <...>, arrays in [],
+// dictionaries in << >>.
+// The parser returns names and literal strings alike as Go strings, so
+// literal reports whether string operands are show-text strings.
+func formatOperands(operands []any, literal bool) string {
+	parts := make([]string, len(operands))
+	for i, operand := range operands {
+		parts[i] = formatOperand(operand, literal)
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatOperand(operand any, literal bool) string {
+	switch v := operand.(type) {
+	case string:
+		if literal {
+			return fmt.Sprintf("(%s)", strings.NewReplacer("\\", "\\\\", "(", "\\(", ")", "\\)").Replace(v))
+		}
+		return formatName(v)
+	case []byte:
+		return fmt.Sprintf("<%X>", v)
+	case float64:
+		return fmt.Sprintf("%g", v)
+	case []any:
+		return "[" + formatOperands(v, literal) + "]"
+	case parser.Dict:
+		return formatDict(v)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// formatDict renders a dictionary with its keys sorted. Which string
+// values were literal strings rather than names is lost in parsing;
+// they are shown as names.
+func formatDict(d parser.Dict) string {
+	var b strings.Builder
+	b.WriteString("<<")
+	for _, key := range slices.Sorted(maps.Keys(d)) {
+		fmt.Fprintf(&b, " %s %s", formatName(key), formatOperand(d[key], false))
+	}
+	b.WriteString(" >>")
+	return b.String()
+}
+
+// formatName renders a name with the bytes that cannot appear in a name
+// token written as #xx escapes, the reverse of the parser's decoding.
+func formatName(name string) string {
+	var b strings.Builder
+	b.WriteByte('/')
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < '!' || c > '~' || c == '#' || strings.IndexByte("()<>[]{}/%", c) >= 0 {
+			fmt.Fprintf(&b, "#%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
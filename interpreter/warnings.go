@@ -0,0 +1,79 @@
+package interpreter
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// Warning is a recoverable problem found while processing a stream:
+// a malformed token the parser skipped, or an operator the interpreter
+// could not apply. Processing continues after a warning.
+type Warning struct {
+	// Operator is the operator being processed, or empty for problems
+	// found by the tokenizer.
+	Operator string
+
+	// Position is the location in the content stream the warning refers
+	// to: the operator, or the malformed token.
+	parser.Position
+
+	// Message describes the problem.
+	Message string
+
+	// Err is the underlying error, for use with errors.Is and errors.As.
+	Err error
+}
+
+// String formats the warning for logs.
+func (w Warning) String() string {
+	if w.Operator == "" {
+		return fmt.Sprintf("%v: %s", w.Position, w.Message)
+	}
+	return fmt.Sprintf("%v: %s: %s", w.Position, w.Operator, w.Message)
+}
+
+// GetWarnings returns the warnings collected so far, from the tokenizer
+// and from interpretation, in stream order. If Options.MaxWarnings
+// suppressed some, a last warning wrapping ErrWarningsSuppressed counts
+// them.
+func (interp *Interpreter) GetWarnings() []Warning {
+	warnings := make([]Warning, len(interp.warnings), len(interp.warnings)+1)
+	copy(warnings, interp.warnings)
+	if n := interp.suppressedWarnings; n > 0 {
+		err := fmt.Errorf("%w: %d more (MaxWarnings is %d)", ErrWarningsSuppressed, n, interp.options.MaxWarnings)
+		warnings = append(warnings, Warning{Message: err.Error(), Err: err})
+	}
+	return warnings
+}
+
+// logger returns the logger set in the options, or slog.Default().
+func (interp *Interpreter) logger() *slog.Logger {
+	if interp.options.Logger != nil {
+		return interp.options.Logger
+	}
+	return slog.Default()
+}
+
+// warn records a warning and, unless the options silence them, logs it.
+// Past Options.MaxWarnings, it only counts the warning.
+func (interp *Interpreter) warn(operator string, pos parser.Position, err error) {
+	if limit := interp.options.MaxWarnings; limit > 0 && len(interp.warnings) >= limit {
+		interp.suppressedWarnings++
+		return
+	}
+	w := Warning{Operator: operator, Position: pos, Message: err.Error(), Err: err}
+	interp.warnings = append(interp.warnings, w)
+	if interp.options.SilenceWarnings {
+		return
+	}
+	logger := interp.logger()
+	if operator == "" {
+		logger.Warn("malformed content stream",
+			"offset", pos.Offset, "line", pos.Line, "column", pos.Column, "err", err)
+	} else {
+		logger.Warn("malformed content stream", "operator", operator,
+			"offset", pos.Offset, "line", pos.Line, "column", pos.Column, "err", err)
+	}
+}
@@ -0,0 +1,206 @@
+package layout
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/bidi"
+	"golang.org/x/text/unicode/norm"
+)
+
+// LogicalOrder reorders a line of text from visual order, left to right
+// as drawn, which is how many producers store Arabic and Hebrew text, to
+// logical order. It undoes the reordering of the Unicode Bidirectional
+// Algorithm (UAX #9) for a single paragraph without explicit embeddings:
+// right-to-left runs are reversed, with their brackets mirrored, while
+// numbers and left-to-right runs inside them keep their order. The
+// paragraph direction is that of the majority of strong characters.
+// Text without right-to-left characters is returned unchanged.
+//
+// Visual order loses some information: a number between left-to-right
+// and right-to-left text may belong to either.
+func LogicalOrder(visual string) string {
+	runes := []rune(visual)
+	classes := make([]bidi.Class, len(runes))
+	ltr, rtl := 0, 0
+	for i, r := range runes {
+		props, _ := bidi.LookupRune(r)
+		classes[i] = props.Class()
+		switch classes[i] {
+		case bidi.L:
+			ltr++
+		case bidi.R, bidi.AL:
+			rtl++
+		}
+	}
+	if rtl == 0 {
+		return visual
+	}
+	base := 0
+	if rtl >= ltr {
+		base = 1
+	}
+
+	// A right-to-left line reversed is close to logical order, which is
+	// what the levels are resolved from: a number's preceding strong
+	// character is then the one on its right, as the reader sees it.
+	lowest := 1
+	if base == 1 {
+		slices.Reverse(runes)
+		slices.Reverse(classes)
+		lowest = 2
+	}
+	levels := resolveLevels(classes, base)
+	for i, level := range levels {
+		if level%2 == 1 {
+			runes[i] = mirror(runes[i])
+		}
+	}
+	// Undo rule L2, which reverses every maximal sequence at or above
+	// each level from the highest down to the lowest odd one, by
+	// reversing them from the lowest up
+	highest := slices.Max(levels)
+	for level := lowest; level <= highest; level++ {
+		for i := 0; i < len(runes); {
+			if levels[i] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(runes) && levels[j] >= level {
+				j++
+			}
+			slices.Reverse(runes[i:j])
+			slices.Reverse(levels[i:j])
+			i = j
+		}
+	}
+	return string(runes)
+}
+
+// resolveLevels assigns embedding levels to characters of the given bidi
+// classes in a paragraph of level base (0 left to right, 1 right to
+// left), following the weak, neutral and implicit rules of UAX #9 that
+// apply without explicit embeddings: European numbers after left-to-right
+// text become left to right (W7), neutrals take the direction of the
+// text on both sides if it agrees and the paragraph's otherwise (N1, N2),
+// and levels are raised for right-to-left characters and numbers (I1,
+// I2).
+func resolveLevels(classes []bidi.Class, base int) []int {
+	baseClass := bidi.L
+	if base == 1 {
+		baseClass = bidi.R
+	}
+	types := make([]bidi.Class, len(classes))
+	strong := baseClass
+	for i, c := range classes {
+		switch c {
+		case bidi.L, bidi.R, bidi.AN:
+			types[i] = c
+		case bidi.AL:
+			types[i] = bidi.R
+		case bidi.EN:
+			types[i] = bidi.EN
+			if strong == bidi.L {
+				types[i] = bidi.L
+			}
+		default:
+			types[i] = bidi.ON
+		}
+		if c == bidi.L || c == bidi.R || c == bidi.AL {
+			strong = types[i]
+		}
+	}
+
+	// Numbers count as right to left for the neutrals around them
+	direction := func(c bidi.Class) bidi.Class {
+		if c == bidi.L {
+			return bidi.L
+		}
+		return bidi.R
+	}
+	for i := 0; i < len(types); {
+		if types[i] != bidi.ON {
+			i++
+			continue
+		}
+		j := i
+		for j < len(types) && types[j] == bidi.ON {
+			j++
+		}
+		before, after := baseClass, baseClass
+		if i > 0 {
+			before = direction(types[i-1])
+		}
+		if j < len(types) {
+			after = direction(types[j])
+		}
+		resolved := baseClass
+		if before == after {
+			resolved = before
+		}
+		for k := i; k < j; k++ {
+			types[k] = resolved
+		}
+		i = j
+	}
+
+	levels := make([]int, len(types))
+	for i, t := range types {
+		switch {
+		case base == 0 && t == bidi.R:
+			levels[i] = 1
+		case base == 0 && (t == bidi.EN || t == bidi.AN):
+			levels[i] = 2
+		case base == 1 && t != bidi.R:
+			levels[i] = 2
+		default:
+			levels[i] = base
+		}
+	}
+	return levels
+}
+
+// mirrored pairs the brackets whose glyphs are mirrored in right-to-left
+// text.
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+	'‹': '›', '›': '‹',
+}
+
+func mirror(r rune) rune {
+	if m, ok := mirrored[r]; ok {
+		return m
+	}
+	return r
+}
+
+// ArabicBaseForms replaces Arabic presentation forms (U+FB50-U+FDFF,
+// U+FE70-U+FEFF), the contextual glyph shapes some fonts map codes to,
+// with the base letters they show, so that "ﻣﺤﻤﺪ" reads as "محمد".
+// Ligature forms expand to their letters.
+func ArabicBaseForms(s string) string {
+	if !strings.ContainsFunc(s, isArabicPresentationForm) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if !isArabicPresentationForm(r) {
+			b.WriteRune(r)
+			continue
+		}
+		var buf [utf8.UTFMax]byte
+		b.Write(norm.NFKC.Bytes(buf[:utf8.EncodeRune(buf[:], r)]))
+	}
+	return b.String()
+}
+
+func isArabicPresentationForm(r rune) bool {
+	return r >= 0xFB50 && r <= 0xFDFF || r >= 0xFE70 && r <= 0xFEFF && r != 0xFEFF
+}
@@ -0,0 +1,100 @@
+package layout
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordFunc reports whether word is a known word, for telling a word
+// hyphenated at a line break ("exam-" "ple") from a hyphenated compound
+// ("well-" "known").
+type WordFunc func(word string) bool
+
+// Dehyphenate joins the words of text that are broken across lines at a
+// hyphen: a line ending in a letter and a hyphen ('-' or U+2010), followed
+// by a line starting with a lowercase letter. The completed word stays on
+// the first line, with any punctuation attached to it, and the second
+// keeps the rest. Without isWord the hyphen
+// is dropped ("exam-\nple is" becomes "example\nis"); with it, the hyphen
+// is dropped only if isWord knows the joined word, and kept otherwise
+// ("well-known").
+func Dehyphenate(text string, isWord WordFunc) string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		for i+1 < len(lines) {
+			trimmed := strings.TrimRightFunc(line, unicode.IsSpace)
+			keepHyphen, ok := brokenWord(trimmed, lines[i+1], isWord)
+			if !ok {
+				break
+			}
+			line = trimmed
+			if !keepHyphen {
+				line = trimHyphen(line)
+			}
+			// The end of the word moves up with whatever is attached to
+			// it (punctuation, another hyphen)
+			next := strings.TrimLeftFunc(lines[i+1], unicode.IsSpace)
+			end := strings.IndexFunc(next, unicode.IsSpace)
+			if end < 0 {
+				end = len(next)
+			}
+			line += next[:end]
+			rest := strings.TrimLeftFunc(next[end:], unicode.IsSpace)
+			if rest != "" {
+				lines[i+1] = rest
+				break
+			}
+			i++ // the next line held only the end of the word
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// brokenWord reports whether line, which must not end in whitespace, ends
+// with a word broken at a hyphen that next continues. keepHyphen reports
+// whether the hyphen belongs to the word, because isWord does not know it
+// without.
+func brokenWord(line, next string, isWord WordFunc) (keepHyphen, ok bool) {
+	hyphen, _ := utf8.DecodeLastRuneInString(line)
+	if hyphen != '-' && hyphen != '\u2010' {
+		return false, false
+	}
+	prefix := trailingWord(trimHyphen(line))
+	suffix := leadingWord(strings.TrimLeftFunc(next, unicode.IsSpace))
+	if prefix == "" || suffix == "" {
+		return false, false
+	}
+	if first, _ := utf8.DecodeRuneInString(suffix); !unicode.IsLower(first) {
+		return false, false
+	}
+	return isWord != nil && !isWord(prefix+suffix), true
+}
+
+// trimHyphen removes the last rune of line, its hyphen.
+func trimHyphen(line string) string {
+	_, size := utf8.DecodeLastRuneInString(line)
+	return line[:len(line)-size]
+}
+
+// trailingWord returns the letters at the end of s.
+func trailingWord(s string) string {
+	i := strings.LastIndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if i < 0 {
+		return s
+	}
+	_, size := utf8.DecodeRuneInString(s[i:])
+	return s[i+size:]
+}
+
+// leadingWord returns the letters at the start of s.
+func leadingWord(s string) string {
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if i < 0 {
+		return s
+	}
+	return s[:i]
+}
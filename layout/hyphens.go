@@ -0,0 +1,94 @@
+package layout
+
+import (
+	"strings"
+)
+
+// softHyphen is U+00AD SOFT HYPHEN, the discretionary hyphen that is only
+// drawn where a word is broken across lines.
+const softHyphen = "\u00ad"
+
+// SoftHyphenPolicy decides what happens to soft hyphens when the lines of
+// a paragraph are joined. Hard hyphens ('-', U+2010, U+2011) are left to
+// ParagraphOptions.Dehyphenate: without it, a line ending in one is
+// joined with a space as usual, since the hyphen may belong to a
+// compound.
+type SoftHyphenPolicy int
+
+const (
+	// SoftHyphenRemove drops soft hyphens. A word broken at a line-end
+	// soft hyphen is joined back together ("exam-" "ple" -> "example").
+	SoftHyphenRemove SoftHyphenPolicy = iota
+
+	// SoftHyphenKeep leaves U+00AD in the text, joining a word broken at
+	// one without a space, so downstream renderers can still hyphenate.
+	SoftHyphenKeep
+
+	// SoftHyphenConvert turns a line-end soft hyphen into "-" as it was
+	// drawn ("exam-ple") and drops soft hyphens elsewhere.
+	SoftHyphenConvert
+)
+
+func (p SoftHyphenPolicy) String() string {
+	switch p {
+	case SoftHyphenRemove:
+		return "remove"
+	case SoftHyphenKeep:
+		return "keep"
+	case SoftHyphenConvert:
+		return "convert"
+	default:
+		return "unknown"
+	}
+}
+
+// normalizeSoftHyphens rewrites the producer-specific soft hyphen markers
+// in marks to U+00AD, collapsing a soft hyphen adjacent to a drawn hyphen
+// glyph (U+00AD followed or preceded by "-", which some producers emit
+// for one discretionary break) into one.
+func normalizeSoftHyphens(s string, marks []string) string {
+	for _, mark := range marks {
+		if mark != "" {
+			s = strings.ReplaceAll(s, mark, softHyphen)
+		}
+	}
+	s = strings.ReplaceAll(s, softHyphen+"-", softHyphen)
+	return strings.ReplaceAll(s, "-"+softHyphen, softHyphen)
+}
+
+// joinLines joins the trimmed texts of a paragraph's lines with spaces,
+// applying the soft hyphen policy at line ends and inside lines, and
+// joining words broken at a hard hyphen if opts.Dehyphenate is set.
+func joinLines(texts []string, opts ParagraphOptions) string {
+	var b strings.Builder
+	for i, text := range texts {
+		text = normalizeSoftHyphens(text, opts.SoftHyphenMarks)
+		broken := i < len(texts)-1 && strings.HasSuffix(text, softHyphen)
+		if broken {
+			text = strings.TrimSuffix(text, softHyphen)
+		}
+		if opts.SoftHyphens != SoftHyphenKeep {
+			text = strings.ReplaceAll(text, softHyphen, "")
+		}
+		hyphenated := false
+		if !broken && opts.Dehyphenate && i < len(texts)-1 {
+			var keepHyphen bool
+			keepHyphen, hyphenated = brokenWord(text, texts[i+1], opts.IsWord)
+			if hyphenated && !keepHyphen {
+				text = trimHyphen(text)
+			}
+		}
+		b.WriteString(text)
+
+		switch {
+		case i == len(texts)-1, hyphenated:
+		case !broken:
+			b.WriteByte(' ')
+		case opts.SoftHyphens == SoftHyphenKeep:
+			b.WriteString(softHyphen)
+		case opts.SoftHyphens == SoftHyphenConvert:
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+// Package layout reconstructs reading structure (lines, paragraphs) from
+// the positioned text runs produced by the interpreter.
+package layout
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// Line is a sequence of runs sharing a baseline, in x order.
+type Line struct {
+	// Text is the line's text, with spaces inserted at word gaps.
+	Text string
+
+	// Bounds is the union of the runs' bounding boxes.
+	Bounds geom.Rect
+
+	// Baseline is the y coordinate of the line's baseline (the first run's).
+	Baseline float64
+
+	// FontSize is the dominant font size: the one covering the most width.
+	FontSize float64
+
+	// Runs are the constituent runs in x order.
+	Runs []interpreter.TextRun
+}
+
+// LineOptions tunes line assembly. Zero fields select the defaults.
+type LineOptions struct {
+	// BaselineTolerance is the maximum baseline distance, as a fraction of
+	// the smaller font size, for two runs to share a line. Default 0.3.
+	BaselineTolerance float64
+
+	// MaxFontSizeRatio is the largest ratio between font sizes on one line.
+	// Runs differing more (e.g., a heading beside body text) start new
+	// lines. Default 2.0.
+	MaxFontSizeRatio float64
+
+	// WordGap is the minimum horizontal gap, as a fraction of the font
+	// size, that is rendered as a space between runs. Default 0.15.
+	WordGap float64
+
+	// Bidi reorders the text of lines containing right-to-left characters
+	// from visual order, as runs are laid out, to logical order (see
+	// LogicalOrder). Arabic and Hebrew text stored in visual order then
+	// reads correctly; text stored in logical order is drawn as several
+	// runs and may come out reversed, so it is off by default.
+	Bidi bool
+
+	// ArabicBaseForms replaces Arabic presentation forms in line text
+	// with their base letters (see ArabicBaseForms).
+	ArabicBaseForms bool
+}
+
+func (o LineOptions) withDefaults() LineOptions {
+	if o.BaselineTolerance <= 0 {
+		o.BaselineTolerance = 0.3
+	}
+	if o.MaxFontSizeRatio <= 0 {
+		o.MaxFontSizeRatio = 2.0
+	}
+	if o.WordGap <= 0 {
+		o.WordGap = 0.15
+	}
+	return o
+}
+
+// AssembleLines groups runs into lines by baseline proximity and font size,
+// merging the runs of each line in x order. Lines are returned top to
+// bottom (descending y). Runs with empty text are ignored.
+func AssembleLines(runs []interpreter.TextRun, opts LineOptions) []Line {
+	opts = opts.withDefaults()
+
+	sorted := make([]interpreter.TextRun, 0, len(runs))
+	for _, run := range runs {
+		if run.Text != "" {
+			sorted = append(sorted, run)
+		}
+	}
+	// Top to bottom, then left to right
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Y != sorted[j].Y {
+			return sorted[i].Y > sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	var lines []*Line
+	for _, run := range sorted {
+		if line := findLine(lines, run, opts); line != nil {
+			line.Runs = append(line.Runs, run)
+			continue
+		}
+		lines = append(lines, &Line{Baseline: run.Y, Runs: []interpreter.TextRun{run}})
+	}
+
+	result := make([]Line, len(lines))
+	for i, line := range lines {
+		line.finish(opts)
+		result[i] = *line
+	}
+	return result
+}
+
+// findLine returns the most recent line run belongs to, or nil.
+// Runs arrive sorted by descending y, so only lines whose baseline is
+// still within tolerance need to be checked.
+func findLine(lines []*Line, run interpreter.TextRun, opts LineOptions) *Line {
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := lines[i]
+		ref := line.Runs[0]
+		size := math.Min(ref.FontSize, run.FontSize)
+		if line.Baseline-run.Y > opts.BaselineTolerance*math.Max(ref.FontSize, run.FontSize) {
+			return nil // Lines further up are even further away
+		}
+		if math.Abs(line.Baseline-run.Y) > opts.BaselineTolerance*size {
+			continue
+		}
+		if ratio := math.Max(ref.FontSize, run.FontSize) / math.Max(size, 1e-9); ratio > opts.MaxFontSizeRatio {
+			continue
+		}
+		return line
+	}
+	return nil
+}
+
+// finish orders the line's runs and computes its text and geometry.
+func (l *Line) finish(opts LineOptions) {
+	sort.SliceStable(l.Runs, func(i, j int) bool { return l.Runs[i].X < l.Runs[j].X })
+
+	var b strings.Builder
+	widthBySize := make(map[float64]float64)
+	for i, run := range l.Runs {
+		box := run.Bounds()
+		if i == 0 {
+			l.Bounds = box
+		} else {
+			l.Bounds = l.Bounds.Union(box)
+			prev := l.Runs[i-1]
+			gap := run.X - prev.EndX()
+			size := math.Min(prev.FontSize, run.FontSize)
+			if gap > opts.WordGap*size && !strings.HasSuffix(b.String(), " ") && !strings.HasPrefix(run.Text, " ") {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(run.Text)
+		widthBySize[run.FontSize] += math.Max(run.Width, 1e-9)
+	}
+	l.Text = b.String()
+	if opts.Bidi {
+		l.Text = LogicalOrder(l.Text)
+	}
+	if opts.ArabicBaseForms {
+		l.Text = ArabicBaseForms(l.Text)
+	}
+
+	// The dominant size is the one covering the most width
+	best := 0.0
+	for size, width := range widthBySize {
+		if width > best || (width == best && size > l.FontSize) {
+			best, l.FontSize = width, size
+		}
+	}
+}
+
+// Text joins lines with newlines.
+func Text(lines []Line) string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.Text
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,188 @@
+package layout
+
+import (
+	"math"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+)
+
+// Paragraph is a block of consecutive lines forming one paragraph.
+type Paragraph struct {
+	// Text is the paragraph's lines joined with spaces: line breaks inside
+	// a paragraph are soft and do not survive, paragraph breaks do. Words
+	// broken at a soft hyphen are joined according to the SoftHyphens
+	// policy.
+	Text string
+
+	// Bounds is the union of the lines' bounding boxes.
+	Bounds geom.Rect
+
+	// Lines are the constituent lines, top to bottom.
+	Lines []Line
+}
+
+// ParagraphOptions tunes paragraph detection. Zero fields select the defaults.
+type ParagraphOptions struct {
+	// SpacingFactor is how much larger than the paragraph's regular line
+	// spacing a gap must be to start a new paragraph. Default 1.3.
+	SpacingFactor float64
+
+	// MaxLineSpacing is the largest baseline distance, as a multiple of the
+	// font size, between two lines of one paragraph. Default 1.8.
+	MaxLineSpacing float64
+
+	// IndentFactor is the first-line indentation, as a multiple of the font
+	// size, that marks the start of a new paragraph. Default 0.8.
+	IndentFactor float64
+
+	// MaxFontSizeRatio is the largest font size ratio between consecutive
+	// lines of one paragraph. Default 1.2.
+	MaxFontSizeRatio float64
+
+	// SoftHyphens is the policy for soft hyphens (U+00AD) when lines are
+	// joined. The zero value removes them.
+	SoftHyphens SoftHyphenPolicy
+
+	// SoftHyphenMarks lists additional sequences that a producer uses for
+	// discretionary hyphens (e.g., a private-use code point its subset
+	// font maps the hyphen glyph to); they are treated like U+00AD.
+	SoftHyphenMarks []string
+
+	// Dehyphenate joins words broken across lines at a hard hyphen: a line
+	// ending in a letter and a hyphen, followed by one starting with a
+	// lowercase letter ("exam-" "ple" -> "example"). See Dehyphenate.
+	Dehyphenate bool
+
+	// IsWord, if set, decides whether Dehyphenate drops the hyphen: only
+	// if it knows the joined word. Otherwise the hyphen is kept and the
+	// word joined with it ("well-" "known" -> "well-known").
+	IsWord WordFunc
+}
+
+func (o ParagraphOptions) withDefaults() ParagraphOptions {
+	if o.SpacingFactor <= 0 {
+		o.SpacingFactor = 1.3
+	}
+	if o.MaxLineSpacing <= 0 {
+		o.MaxLineSpacing = 1.8
+	}
+	if o.IndentFactor <= 0 {
+		o.IndentFactor = 0.8
+	}
+	if o.MaxFontSizeRatio <= 0 {
+		o.MaxFontSizeRatio = 1.2
+	}
+	return o
+}
+
+// DetectParagraphs clusters lines (as returned by AssembleLines, top to
+// bottom) into paragraphs using inter-line spacing, indentation, alignment,
+// and font size cues.
+func DetectParagraphs(lines []Line, opts ParagraphOptions) []Paragraph {
+	opts = opts.withDefaults()
+
+	var paragraphs []Paragraph
+	var current []Line
+	spacing := 0.0 // regular baseline distance of the current paragraph
+
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, newParagraph(current, opts))
+		}
+		current = nil
+		spacing = 0
+	}
+
+	for _, line := range lines {
+		if len(current) == 0 {
+			current = append(current, line)
+			continue
+		}
+		prev := current[len(current)-1]
+		if breaksParagraph(current, line, spacing, opts) {
+			flush()
+			current = append(current, line)
+			continue
+		}
+		if spacing == 0 {
+			spacing = prev.Baseline - line.Baseline
+		}
+		current = append(current, line)
+	}
+	flush()
+	return paragraphs
+}
+
+// breaksParagraph reports whether line starts a new paragraph after the
+// lines collected so far.
+func breaksParagraph(current []Line, line Line, spacing float64, opts ParagraphOptions) bool {
+	prev := current[len(current)-1]
+	size := math.Max(math.Max(prev.FontSize, line.FontSize), 1e-9)
+	gap := prev.Baseline - line.Baseline
+
+	// Font size change (e.g., heading followed by body text)
+	if math.Max(prev.FontSize, line.FontSize)/math.Max(math.Min(prev.FontSize, line.FontSize), 1e-9) > opts.MaxFontSizeRatio {
+		return true
+	}
+
+	// Vertical spacing: too far apart, or noticeably wider than the
+	// paragraph's established line spacing
+	if gap <= 0 || gap > opts.MaxLineSpacing*size {
+		return true
+	}
+	if spacing > 0 && gap > opts.SpacingFactor*spacing {
+		return true
+	}
+
+	// Lines must overlap horizontally (guards against column jumps)
+	block := current[0].Bounds
+	for _, l := range current[1:] {
+		block = block.Union(l.Bounds)
+	}
+	if line.Bounds.X1 < block.X0 || line.Bounds.X0 > block.X1 {
+		return true
+	}
+
+	// First-line indentation relative to the paragraph's left edge
+	left := block.X0
+	indent := line.Bounds.X0 - left
+	if len(current) > 1 && indent > opts.IndentFactor*size {
+		firstIndent := current[0].Bounds.X0 - left
+		if firstIndent < opts.IndentFactor*size {
+			// Body lines are flush, so an indented line opens a new paragraph
+			return true
+		}
+	}
+
+	// Alignment: a short previous line that ends a paragraph, followed by
+	// a line back at the left margin
+	if len(current) > 1 && prev.Bounds.Width() < 0.6*block.Width() && indent < opts.IndentFactor*size {
+		return true
+	}
+
+	return false
+}
+
+// newParagraph builds a paragraph from its lines.
+func newParagraph(lines []Line, opts ParagraphOptions) Paragraph {
+	p := Paragraph{Lines: lines, Bounds: lines[0].Bounds}
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		p.Bounds = p.Bounds.Union(line.Bounds)
+		if t := strings.TrimSpace(line.Text); t != "" {
+			texts = append(texts, t)
+		}
+	}
+	p.Text = joinLines(texts, opts)
+	return p
+}
+
+// ParagraphsText joins paragraphs with blank lines.
+func ParagraphsText(paragraphs []Paragraph) string {
+	texts := make([]string, len(paragraphs))
+	for i, p := range paragraphs {
+		texts[i] = p.Text
+	}
+	return strings.Join(texts, "\n\n")
+}
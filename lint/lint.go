@@ -0,0 +1,399 @@
+// Package lint checks content streams for the spec violations the
+// interpreter tolerates: unbalanced q/Q, BT/ET and marked-content
+// operators, text operators outside text objects, operands of the wrong
+// number or type, oversized operand stacks, undefined resource names and
+// unknown operators. It helps producers find out why a viewer renders
+// their output differently from another.
+package lint
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// Rule identifies a check.
+type Rule string
+
+const (
+	// Syntax reports tokens the parser skipped, and streams it could not
+	// parse to the end.
+	Syntax Rule = "syntax"
+
+	// UnbalancedSave reports Q without a matching q, q never restored,
+	// and q nested deeper than Options.MaxNesting.
+	UnbalancedSave Rule = "unbalanced-save"
+
+	// UnbalancedText reports BT inside a text object, ET outside one,
+	// and text objects left open.
+	UnbalancedText Rule = "unbalanced-text"
+
+	// UnbalancedMarkedContent reports EMC without a matching BMC or BDC,
+	// and sequences left open.
+	UnbalancedMarkedContent Rule = "unbalanced-marked-content"
+
+	// TextOutsideTextObject reports text-positioning and text-showing
+	// operators outside BT ... ET.
+	TextOutsideTextObject Rule = "text-outside-text-object"
+
+	// OperandCount reports operators with the wrong number of operands.
+	OperandCount Rule = "operand-count"
+
+	// OperandType reports operands of the wrong type.
+	OperandType Rule = "operand-type"
+
+	// OperandStack reports operations with more operands than
+	// Options.MaxOperands.
+	OperandStack Rule = "operand-stack"
+
+	// UndefinedResource reports resource names missing from
+	// Options.Resources.
+	UndefinedResource Rule = "undefined-resource"
+
+	// UnknownOperator reports operators the PDF specification does not
+	// define, outside BX ... EX compatibility sections.
+	UnknownOperator Rule = "unknown-operator"
+)
+
+// Issue is one violation found.
+type Issue struct {
+	Rule Rule
+
+	// Position is the location of the offending operator or token, and
+	// Operator its name, if an operator is at fault.
+	Position parser.Position
+	Operator string
+
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Position, i.Rule, i.Message)
+}
+
+// Report is the outcome of Check.
+type Report struct {
+	// Issues are the violations found, in stream order, except that those
+	// about sequences left open at the end of the stream come last.
+	Issues []Issue
+
+	// Operations is the number of operations checked.
+	Operations int
+}
+
+// OK reports whether no issues were found.
+func (r Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Count returns the number of issues found for rule.
+func (r Report) Count(rule Rule) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Rule == rule {
+			n++
+		}
+	}
+	return n
+}
+
+// String lists the issues, one per line.
+func (r Report) String() string {
+	var b strings.Builder
+	for _, i := range r.Issues {
+		b.WriteString(i.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Resources lists the names a stream's resource dictionary defines, by
+// resource category, the keys of the dictionary: Font, XObject,
+// ExtGState, ColorSpace, Pattern, Shading and Properties.
+type Resources map[string][]string
+
+func (r Resources) defines(category, name string) bool {
+	return slices.Contains(r[category], name)
+}
+
+// Options tunes Check. Zero fields select the defaults.
+type Options struct {
+	// Resources, if not nil, are the resources of the stream, and names
+	// they do not define are reported.
+	Resources Resources
+
+	// MaxOperands is the most operands an operation may have. Default
+	// 100, beyond what any operator takes, which only malformed streams
+	// pile up.
+	MaxOperands int
+
+	// MaxNesting is the deepest q nesting allowed. Default 28, the limit
+	// of ISO 32000-1, Annex C.
+	MaxNesting int
+}
+
+// Check parses stream and reports the violations found.
+func Check(stream []byte, opts Options) Report {
+	if opts.MaxOperands <= 0 {
+		opts.MaxOperands = 100
+	}
+	if opts.MaxNesting <= 0 {
+		opts.MaxNesting = 28
+	}
+	c := checker{opts: opts}
+	p := parser.NewParserBytes(stream)
+	relayed := 0 // parser warnings reported so far
+	for op, err := range p.Operations(context.Background()) {
+		for _, malformed := range p.Warnings()[relayed:] {
+			c.report(Syntax, malformed.Position, "", fmt.Sprintf("skipped malformed token %q: %v", malformed.Token, malformed.Err))
+			relayed++
+		}
+		if err != nil {
+			c.report(Syntax, parser.Position{}, "", fmt.Sprintf("parsing stopped: %v", err))
+			break
+		}
+		c.check(op)
+		c.result.Operations++
+	}
+	for _, malformed := range p.Warnings()[relayed:] {
+		c.report(Syntax, malformed.Position, "", fmt.Sprintf("skipped malformed token %q: %v", malformed.Token, malformed.Err))
+	}
+	c.finish()
+	return c.result
+}
+
+// checker holds the state of Check.
+type checker struct {
+	opts   Options
+	result Report
+
+	saves         []parser.Position  // positions of the open q
+	textObject    *parser.Position   // position of the open BT
+	markedContent []parser.Operation // the open BMC and BDC
+	compatibility int                // BX nesting
+}
+
+func (c *checker) report(rule Rule, pos parser.Position, operator, message string) {
+	c.result.Issues = append(c.result.Issues, Issue{Rule: rule, Position: pos, Operator: operator, Message: message})
+}
+
+func (c *checker) check(op parser.Operation) {
+	pos := op.Position
+	switch op.Name {
+	case "q":
+		c.saves = append(c.saves, pos)
+		if len(c.saves) == c.opts.MaxNesting+1 {
+			c.report(UnbalancedSave, pos, op.Name, fmt.Sprintf("q nested deeper than %d levels", c.opts.MaxNesting))
+		}
+	case "Q":
+		if len(c.saves) == 0 {
+			c.report(UnbalancedSave, pos, op.Name, "Q without matching q")
+		} else {
+			c.saves = c.saves[:len(c.saves)-1]
+		}
+	case "BT":
+		if c.textObject != nil {
+			c.report(UnbalancedText, pos, op.Name, fmt.Sprintf("BT inside the text object opened at %s", c.textObject))
+		}
+		c.textObject = &pos
+	case "ET":
+		if c.textObject == nil {
+			c.report(UnbalancedText, pos, op.Name, "ET without matching BT")
+		}
+		c.textObject = nil
+	case "BMC", "BDC":
+		c.markedContent = append(c.markedContent, parser.Operation{Name: op.Name, Position: pos})
+	case "EMC":
+		if len(c.markedContent) == 0 {
+			c.report(UnbalancedMarkedContent, pos, op.Name, "EMC without matching BMC or BDC")
+		} else {
+			c.markedContent = c.markedContent[:len(c.markedContent)-1]
+		}
+	case "BX":
+		c.compatibility++
+	case "EX":
+		c.compatibility = max(c.compatibility-1, 0)
+	}
+	if textOperators[op.Name] && c.textObject == nil {
+		c.report(TextOutsideTextObject, pos, op.Name, op.Name+" outside a text object")
+	}
+
+	if len(op.Operands) > c.opts.MaxOperands {
+		c.report(OperandStack, pos, op.Name, fmt.Sprintf("%d operands on the stack, more than %d", len(op.Operands), c.opts.MaxOperands))
+	}
+	sig, known := signatures[op.Name]
+	switch {
+	case op.Name == "ID" || op.Name == "EI":
+		return
+	case !known:
+		if c.compatibility == 0 {
+			c.report(UnknownOperator, pos, op.Name, fmt.Sprintf("unknown operator %s", op.Name))
+		}
+		return
+	case sig.variadic:
+		c.checkColorOperands(op)
+	default:
+		c.checkOperands(op, sig.operands)
+	}
+	if c.opts.Resources != nil {
+		c.checkResources(op)
+	}
+}
+
+// checkOperands checks the operands of op against the kinds of its
+// signature.
+func (c *checker) checkOperands(op parser.Operation, kinds string) {
+	if len(op.Operands) != len(kinds) {
+		c.report(OperandCount, op.Position, op.Name, fmt.Sprintf("%s takes %s, got %d", op.Name, plural(len(kinds), "operand"), len(op.Operands)))
+		return
+	}
+	for i, operand := range op.Operands {
+		if !isKind(operand, kinds[i]) {
+			c.report(OperandType, op.Position, op.Name, fmt.Sprintf("operand %d of %s is %s, want %s", i+1, op.Name, describe(operand), kindName(kinds[i])))
+		}
+	}
+}
+
+// checkColorOperands checks the operands of SC, sc, SCN and scn: numbers,
+// for SCN and scn optionally followed by a pattern name.
+func (c *checker) checkColorOperands(op parser.Operation) {
+	operands := op.Operands
+	if n := len(operands); n > 0 && (op.Name == "SCN" || op.Name == "scn") && isKind(operands[n-1], name) && !isKind(operands[n-1], number) {
+		operands = operands[:n-1]
+		if len(operands) == 0 {
+			return // Colored pattern
+		}
+	}
+	limit := 4
+	if op.Name == "SCN" || op.Name == "scn" {
+		limit = maxColorOperands - 1
+	}
+	if len(operands) == 0 || len(operands) > limit {
+		c.report(OperandCount, op.Position, op.Name, fmt.Sprintf("%s takes 1 to %d color components, got %d", op.Name, limit, len(operands)))
+		return
+	}
+	for i, operand := range operands {
+		if !isKind(operand, number) {
+			c.report(OperandType, op.Position, op.Name, fmt.Sprintf("operand %d of %s is %s, want number", i+1, op.Name, describe(operand)))
+		}
+	}
+}
+
+// checkResources reports the resource names op uses that
+// Options.Resources does not define.
+func (c *checker) checkResources(op parser.Operation) {
+	if len(op.Operands) < len(signatures[op.Name].operands) {
+		return // Reported as OperandCount
+	}
+	var category string
+	var operand any
+	switch op.Name {
+	case "Tf":
+		category, operand = "Font", op.Operands[0]
+	case "Do":
+		category, operand = "XObject", op.Operands[0]
+	case "gs":
+		category, operand = "ExtGState", op.Operands[0]
+	case "sh":
+		category, operand = "Shading", op.Operands[0]
+	case "CS", "cs":
+		if s, _ := op.Operands[0].(string); deviceColorSpaces[s] {
+			return
+		}
+		category, operand = "ColorSpace", op.Operands[0]
+	case "SCN", "scn":
+		if len(op.Operands) == 0 {
+			return
+		}
+		category, operand = "Pattern", op.Operands[len(op.Operands)-1]
+	case "BDC", "DP":
+		category, operand = "Properties", op.Operands[1]
+	default:
+		return
+	}
+	resource, ok := operand.(string)
+	if !ok {
+		return // Not a name: numbers or an inline dictionary
+	}
+	if !c.opts.Resources.defines(category, resource) {
+		c.report(UndefinedResource, op.Position, op.Name, fmt.Sprintf("%s /%s is not defined in the resources", category, resource))
+	}
+}
+
+// finish reports the sequences left open at the end of the stream.
+func (c *checker) finish() {
+	for _, pos := range c.saves {
+		c.report(UnbalancedSave, pos, "q", "q without matching Q")
+	}
+	if c.textObject != nil {
+		c.report(UnbalancedText, *c.textObject, "BT", "text object not closed by ET")
+	}
+	for _, op := range c.markedContent {
+		c.report(UnbalancedMarkedContent, op.Position, op.Name, op.Name+" not closed by EMC")
+	}
+}
+
+// plural formats a count of things.
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// isKind reports whether operand is of the kind. Names and literal
+// strings are both Go strings, and cannot be told apart.
+func isKind(operand any, kind byte) bool {
+	switch operand.(type) {
+	case float64, int:
+		return kind == number
+	case string:
+		return kind == text || kind == name || kind == dictOrName
+	case []byte:
+		return kind == text
+	case []any:
+		return kind == array
+	case parser.Dict:
+		return kind == dictOrName
+	}
+	return false
+}
+
+func kindName(kind byte) string {
+	switch kind {
+	case number:
+		return "number"
+	case text:
+		return "string"
+	case name:
+		return "name"
+	case array:
+		return "array"
+	default:
+		return "dictionary or name"
+	}
+}
+
+// describe names the type of operand for messages.
+func describe(operand any) string {
+	switch operand.(type) {
+	case float64, int:
+		return "a number"
+	case string:
+		return "a name or string"
+	case []byte:
+		return "a hex string"
+	case []any:
+		return "an array"
+	case parser.Dict:
+		return "a dictionary"
+	case bool:
+		return "a boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", operand)
+}
@@ -0,0 +1,70 @@
+package lint
+
+// Operand kinds of an operator signature
+const (
+	number     = 'n' // float64 or int
+	text       = 's' // literal or hex string
+	name       = 'N'
+	array      = 'a'
+	dictOrName = 'D' // property list: inline dictionary or resource name
+)
+
+// signature describes the operands an operator takes.
+type signature struct {
+	// operands holds the kind of each operand.
+	operands string
+
+	// variadic operators (SC, sc, SCN, scn) take 1 to maxColorOperands
+	// numbers instead, SCN and scn optionally followed by a pattern name.
+	variadic bool
+}
+
+// maxColorOperands is the most color components an operator takes: the
+// 32 of a DeviceN space, plus a pattern name.
+const maxColorOperands = 33
+
+// signatures are the operators of ISO 32000-1, Table A.1, except the
+// inline image operators ID and EI, whose operands the parser makes up.
+var signatures = map[string]signature{
+	// General graphics state
+	"w": {operands: "n"}, "J": {operands: "n"}, "j": {operands: "n"}, "M": {operands: "n"},
+	"d": {operands: "an"}, "ri": {operands: "N"}, "i": {operands: "n"}, "gs": {operands: "N"},
+	// Special graphics state
+	"q": {}, "Q": {}, "cm": {operands: "nnnnnn"},
+	// Path construction and painting
+	"m": {operands: "nn"}, "l": {operands: "nn"}, "c": {operands: "nnnnnn"},
+	"v": {operands: "nnnn"}, "y": {operands: "nnnn"}, "h": {}, "re": {operands: "nnnn"},
+	"S": {}, "s": {}, "f": {}, "F": {}, "f*": {}, "B": {}, "B*": {}, "b": {}, "b*": {}, "n": {},
+	"W": {}, "W*": {},
+	// Color
+	"CS": {operands: "N"}, "cs": {operands: "N"},
+	"SC": {variadic: true}, "sc": {variadic: true}, "SCN": {variadic: true}, "scn": {variadic: true},
+	"G": {operands: "n"}, "g": {operands: "n"}, "RG": {operands: "nnn"}, "rg": {operands: "nnn"},
+	"K": {operands: "nnnn"}, "k": {operands: "nnnn"},
+	// Shading, XObjects and inline images
+	"sh": {operands: "N"}, "Do": {operands: "N"}, "BI": {},
+	// Text objects, state, positioning and showing
+	"BT": {}, "ET": {},
+	"Tc": {operands: "n"}, "Tw": {operands: "n"}, "Tz": {operands: "n"}, "TL": {operands: "n"},
+	"Tf": {operands: "Nn"}, "Tr": {operands: "n"}, "Ts": {operands: "n"},
+	"Td": {operands: "nn"}, "TD": {operands: "nn"}, "Tm": {operands: "nnnnnn"}, "T*": {},
+	"Tj": {operands: "s"}, "TJ": {operands: "a"}, "'": {operands: "s"}, "\"": {operands: "nns"},
+	// Type 3 fonts
+	"d0": {operands: "nn"}, "d1": {operands: "nnnnnn"},
+	// Marked content
+	"MP": {operands: "N"}, "DP": {operands: "ND"}, "BMC": {operands: "N"}, "BDC": {operands: "ND"}, "EMC": {},
+	// Compatibility
+	"BX": {}, "EX": {},
+}
+
+// textOperators are the operators allowed only inside text objects.
+var textOperators = map[string]bool{
+	"Td": true, "TD": true, "Tm": true, "T*": true,
+	"Tj": true, "TJ": true, "'": true, "\"": true,
+}
+
+// deviceColorSpaces are the color space names that are not resources.
+var deviceColorSpaces = map[string]bool{
+	"DeviceGray": true, "DeviceRGB": true, "DeviceCMYK": true, "Pattern": true,
+	"G": true, "RGB": true, "CMYK": true, // inline image abbreviations
+}
@@ -1,15 +1,65 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"log"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/parser"
 	"github.com/apex-woot/pdf-stream-engine/streamengine"
+	"github.com/apex-woot/pdf-stream-engine/textdiff"
 )
 
 func main() {
-	fmt.Println("=== PDF Stream Engine - ToUnicode CMap Support ===\n")
+	if len(os.Args) > 1 && os.Args[1] == "dump" {
+		if len(os.Args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: dump <decoded content stream file>")
+			os.Exit(2)
+		}
+		if err := dumpStream(os.Args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	streamPath := flag.String("stream", "", "decoded content stream file to extract (runs the examples if empty)")
+	trace := flag.Bool("trace", false, "print one trace line per operator to stderr")
+	htmlPath := flag.String("html", "", "write an HTML view of extracted run boxes to this file")
+	imageURL := flag.String("image", "", "page image to show under the run boxes in the HTML view")
+	pageWidth := flag.Float64("page-width", 612, "page width in points for the HTML view")
+	pageHeight := flag.Float64("page-height", 792, "page height in points for the HTML view")
+	watch := flag.Bool("watch", false, "re-extract when the stream file changes and print a diff of the text")
+	interval := flag.Duration("interval", 500*time.Millisecond, "how often to check the stream file in watch mode")
+	flag.Parse()
+
+	if *watch {
+		if *streamPath == "" {
+			fmt.Fprintln(os.Stderr, "error: -watch requires -stream")
+			os.Exit(2)
+		}
+		if err := watchStream(*streamPath, *trace, *interval); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *streamPath != "" {
+		if err := runStream(*streamPath, *trace, *htmlPath, *imageURL, *pageWidth, *pageHeight); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	fmt.Println("=== PDF Stream Engine - ToUnicode CMap Support ===")
+	fmt.Println()
 
 	// Example 1: Simple extraction (default WinAnsi encoding)
 	runSimpleExample()
@@ -20,6 +70,108 @@ func main() {
 	runAdvancedExample()
 }
 
+// dumpStream prints the operators of a decoded content stream file, one
+// per line, indented by nesting.
+func dumpStream(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	p := parser.NewParserBytes(data)
+	ops, err := p.Parse()
+	if err != nil {
+		return err
+	}
+	for _, w := range p.Warnings() {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", w)
+	}
+	return parser.Dump(os.Stdout, ops)
+}
+
+// runStream extracts text from a decoded content stream file, optionally
+// tracing every operator and writing an HTML view of the run boxes.
+func runStream(path string, trace bool, htmlPath, imageURL string, pageWidth, pageHeight float64) error {
+	interp, err := extractFile(path, trace)
+	if err != nil {
+		return err
+	}
+	fmt.Println(interp.GetText())
+
+	if htmlPath == "" {
+		return nil
+	}
+	f, err := os.Create(htmlPath)
+	if err != nil {
+		return err
+	}
+	if err := writeRunsHTML(f, interp.GetRuns(), imageURL, pageWidth, pageHeight); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// extractFile interprets the content stream in path.
+func extractFile(path string, trace bool) (*interpreter.Interpreter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts interpreter.Options
+	if trace {
+		opts.Trace = os.Stderr
+	}
+	interp := interpreter.NewInterpreterWithOptions(nil, opts)
+	if err := interp.ProcessStream(bytes.NewReader(data)); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	return interp, nil
+}
+
+// watchStream prints the text of the stream in path, then polls the file
+// and prints a unified diff of the text each time it changes. It runs until
+// the process is interrupted.
+func watchStream(path string, trace bool, interval time.Duration) error {
+	interp, err := extractFile(path, trace)
+	if err != nil {
+		return err
+	}
+	text := interp.GetText()
+	fmt.Println(text)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	for {
+		time.Sleep(interval)
+		current, err := os.Stat(path)
+		if err != nil {
+			// The file may be mid-rewrite; try again next tick
+			continue
+		}
+		if current.ModTime().Equal(info.ModTime()) && current.Size() == info.Size() {
+			continue
+		}
+		info = current
+
+		interp, err := extractFile(path, trace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			continue
+		}
+		next := interp.GetText()
+		stamp := current.ModTime().Format(time.RFC3339)
+		if diff := textdiff.Unified(text, next, path+" (previous)", path+" ("+stamp+")", 3); diff != "" {
+			fmt.Print(diff)
+		} else {
+			fmt.Printf("%s changed, text unchanged\n", path)
+		}
+		text = next
+	}
+}
+
 // runSimpleExample demonstrates basic text extraction using default WinAnsi encoding.
 func runSimpleExample() {
 	fmt.Println("EXAMPLE 1: Simple Text Extraction (WinAnsi encoding)")
@@ -112,7 +264,7 @@ ET
 `
 
 	// Extract text using the font registry
-	text := streamengine.ExtractTextWithFonts([]byte(contentStream), fontRegistry)
+	text := streamengine.ExtractText([]byte(contentStream), streamengine.WithFonts(fontRegistry))
 
 	fmt.Println("Content Stream:")
 	fmt.Println(contentStream)
@@ -132,52 +284,22 @@ ET
 // Integration with pdfcpu
 // ===================================================================
 //
-// To use this with pdfcpu for extracting text from real PDFs:
-//
-// 1. Read the PDF file and get page content:
-//    ```go
-//    ctx, err := pdfcpu.ReadContext("document.pdf", pdfConf)
-//    page := ctx.Pages[0]
-//    streamDict := page.StreamDict
-//    streamData, err := streamDict.Decode()
-//    ```
-//
-// 2. Extract font resources and ToUnicode CMaps:
-//    ```go
-//    fontRegistry := font.NewFontRegistry()
+// The adapters/pdfcpu package extracts text from documents loaded with
+// github.com/pdfcpu/pdfcpu: it walks the page's Font resources, parses
+// ToUnicode CMaps, detects base encodings and widths, and runs the
+// interpreter over the page's content streams:
 //
-//    resources := page.Resources
-//    if fonts, ok := resources["Font"].(map[string]any); ok {
-//        for fontName, fontObj := range fonts {
-//            fontDict := fontObj.(pdfcpu.Dict)
+//    ctx, err := api.ReadContextFile("document.pdf")
+//    text, err := pdfcpu.ExtractPageText(ctx, 1)
 //
-//            // Check for ToUnicode CMap
-//            if toUnicode, ok := fontDict["ToUnicode"]; ok {
-//                cmapStream := toUnicode.(pdfcpu.StreamDict)
-//                cmapData, _ := cmapStream.Decode()
+// To combine the registry with other options, build it separately:
 //
-//                // Parse the CMap
-//                cmap, err := font.ParseToUnicodeCMap(bytes.NewReader(cmapData))
-//                if err == nil {
-//                    fontRegistry.RegisterWithToUnicode(fontName, cmap)
-//                }
-//            } else {
-//                // No ToUnicode - use standard encoding
-//                encoding := font.EncodingWinAnsi // or detect from Encoding dict
-//                fontRegistry.RegisterSimple(fontName, encoding)
-//            }
-//        }
-//    }
-//    ```
-//
-// 3. Extract text with proper encoding:
-//    ```go
-//    text := streamengine.ExtractTextWithFonts(streamData, fontRegistry)
-//    ```
+//    content, fontRegistry, err := pdfcpu.LoadPage(ctx, 1)
+//    text := streamengine.ExtractText(content, streamengine.WithFonts(fontRegistry),
+//        streamengine.WithSkipArtifacts(true))
 //
 // This approach ensures that:
 //   - CID fonts are decoded using their ToUnicode CMaps
 //   - Standard fonts use appropriate encodings (WinAnsi, MacRoman, etc.)
 //   - Custom font encodings are handled correctly
 //   - Multi-byte character codes are properly supported
-
@@ -0,0 +1,276 @@
+package numbers
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Format describes the separators used by a locale's number format.
+type Format struct {
+	// Group is the thousands separator (0 if the number has no grouping).
+	Group rune
+	// Decimal is the decimal separator (0 if the number has no fraction).
+	Decimal rune
+}
+
+// Common locale formats.
+var (
+	FormatEnglish = Format{Group: ',', Decimal: '.'}  // 1,234.56
+	FormatGerman  = Format{Group: '.', Decimal: ','}  // 1.234,56
+	FormatFrench  = Format{Group: ' ', Decimal: ','}  // 1 234,56
+	FormatSwiss   = Format{Group: '\'', Decimal: '.'} // 1'234.56
+)
+
+// Number is a numeric amount recognized in extracted text.
+type Number struct {
+	// Value is the parsed value, negative for leading minus signs
+	// and accounting-style parentheses.
+	Value float64
+
+	// Format holds the separators the text was written with.
+	Format Format
+
+	// Currency is the currency symbol attached to the amount, if any.
+	Currency string
+
+	// Percent reports a trailing percent sign.
+	Percent bool
+}
+
+// currencySymbols are recognized as amount prefixes or suffixes.
+const currencySymbols = "$€£¥₹₽₩₺¢"
+
+// IsSign reports whether r is a plus or minus sign (including U+2212 MINUS SIGN
+// and the dashes producers commonly substitute for it).
+func IsSign(r rune) bool {
+	return r == '-' || r == '+' || r == '\u2212' || r == '\u2013' || r == '\ufe63' || r == '\uff0d'
+}
+
+// IsCurrency reports whether r is a recognized currency symbol.
+func IsCurrency(r rune) bool {
+	return strings.ContainsRune(currencySymbols, r)
+}
+
+// isGroupRune reports whether r can be a thousands separator.
+// Spaces include NO-BREAK SPACE, THIN SPACE, and NARROW NO-BREAK SPACE.
+func isGroupRune(r rune) bool {
+	switch r {
+	case ',', '.', '\'', '\u2019', ' ', '\u00a0', '\u2009', '\u202f':
+		return true
+	}
+	return false
+}
+
+// isSpaceGroup reports whether r is one of the space-like group separators.
+func isSpaceGroup(r rune) bool {
+	return r == ' ' || r == '\u00a0' || r == '\u2009' || r == '\u202f'
+}
+
+// Parse recognizes a numeric amount written in any of the common locale
+// formats, with an optional sign, currency symbol, or percent sign.
+//
+// A single ',' or '.' followed by exactly three digits is ambiguous;
+// it is read as a group separator for ',' and as a decimal point for '.',
+// which matches the most common producers.
+//
+// Examples:
+//
+//	Parse("1 234,56")  // 1234.56, FormatFrench
+//	Parse("-1,234.50") // -1234.5, FormatEnglish
+//	Parse("(€12.00)")  // -12, Currency "€"
+func Parse(s string) (Number, bool) {
+	var n Number
+	s = strings.TrimSpace(s)
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	if r, size := utf8.DecodeRuneInString(s); IsSign(r) {
+		negative = negative || r != '+'
+		s = strings.TrimSpace(s[size:])
+	}
+	if r, size := utf8.DecodeRuneInString(s); IsCurrency(r) {
+		n.Currency = string(r)
+		s = strings.TrimSpace(s[size:])
+		// Sign after the symbol, e.g. "$-5"
+		if r, size := utf8.DecodeRuneInString(s); IsSign(r) {
+			negative = negative || r != '+'
+			s = s[size:]
+		}
+	}
+	if strings.HasSuffix(s, "%") {
+		n.Percent = true
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	}
+	if r, size := utf8.DecodeLastRuneInString(s); n.Currency == "" && IsCurrency(r) {
+		n.Currency = string(r)
+		s = strings.TrimSpace(s[:len(s)-size])
+	}
+
+	digits, format, ok := normalize(s)
+	if !ok {
+		return Number{}, false
+	}
+	value, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return Number{}, false
+	}
+	if negative {
+		value = -value
+	}
+	n.Value = value
+	n.Format = format
+	return n, true
+}
+
+// normalize strips group separators and converts the decimal separator
+// to '.', returning the detected format.
+func normalize(s string) (string, Format, bool) {
+	if s == "" {
+		return "", Format{}, false
+	}
+
+	// Split into digit groups and the separators between them
+	var groups []string
+	var seps []rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			continue
+		case isGroupRune(r):
+			if i == start {
+				return "", Format{}, false // empty group (e.g. "1,,2" or ",5")
+			}
+			groups = append(groups, s[start:i])
+			seps = append(seps, r)
+			start = i + utf8.RuneLen(r)
+		default:
+			return "", Format{}, false
+		}
+	}
+	if start == len(s) {
+		return "", Format{}, false // trailing separator
+	}
+	groups = append(groups, s[start:])
+
+	if len(seps) == 0 {
+		return s, Format{}, true
+	}
+
+	var format Format
+	last := seps[len(seps)-1]
+	lastGroup := groups[len(groups)-1]
+	integerGroups := groups
+
+	// Decide whether the final separator is a decimal point
+	decimal := false
+	switch {
+	case last != ',' && last != '.':
+		decimal = false
+	case len(seps) > 1 && seps[0] != last:
+		decimal = true // 1.234,56 or 1,234.56
+	case len(lastGroup) != 3:
+		decimal = true // 12,5 or 3.14
+	case len(seps) == 1:
+		decimal = last == '.'
+	}
+	if decimal {
+		format.Decimal = last
+		seps = seps[:len(seps)-1]
+		integerGroups = groups[:len(groups)-1]
+	}
+
+	// Remaining separators must be one consistent group separator
+	// with 1-3 leading digits and exactly three digits per group.
+	if len(seps) > 0 {
+		format.Group = seps[0]
+		for _, sep := range seps {
+			if sep != format.Group && !(isSpaceGroup(sep) && isSpaceGroup(format.Group)) {
+				return "", Format{}, false
+			}
+		}
+		if len(integerGroups[0]) > 3 {
+			return "", Format{}, false
+		}
+		for _, g := range integerGroups[1:] {
+			if len(g) != 3 {
+				return "", Format{}, false
+			}
+		}
+	}
+
+	digits := strings.Join(integerGroups, "")
+	if decimal {
+		digits += "." + lastGroup
+	}
+	return digits, format, true
+}
+
+// JoinSeparator decides how two adjacent fragments should be joined when
+// the boundary between them falls inside a number. gap is the horizontal
+// distance between the fragments in em (fractions of the font size).
+//
+// It returns ok == false when the boundary is not numeric or the gap is too
+// wide to be part of one token; callers then apply their usual spacing rules.
+// Otherwise sep is "" (join directly) or " " (a thin-space group separator).
+func JoinSeparator(left, right string, gap float64) (sep string, ok bool) {
+	if left == "" || right == "" || gap > 0.6 {
+		return "", false
+	}
+	l, _ := utf8.DecodeLastRuneInString(left)
+	r, _ := utf8.DecodeRuneInString(right)
+
+	switch {
+	case (IsSign(l) || IsCurrency(l)) && isDigit(r):
+		// "−" "12" or "$" "5": only a standalone sign, not "a-" "1"
+		prefix := strings.TrimRightFunc(left[:len(left)-utf8.RuneLen(l)], IsCurrency)
+		if prefix != "" && !unicode.IsSpace(lastRune(prefix)) && lastRune(prefix) != '(' {
+			return "", false
+		}
+		return "", gap < 0.5
+	case isDigit(l) && (r == ',' || r == '.') && len(right) > 1 && isDigit(rune(right[1])):
+		return "", gap < 0.5 // "1 234" ",56"
+	case (l == ',' || l == '.') && isDigit(r) && len(left) > 1 && isDigit(rune(left[len(left)-2])):
+		return "", gap < 0.5 // "1 234," "56"
+	case isDigit(l) && isDigit(r):
+		if gap < 0.1 {
+			return "", true // kerned digits of the same token
+		}
+		if leadingDigits(right) == 3 && trailingDigits(left) <= 3 {
+			return " ", true // space-grouped thousands
+		}
+	}
+	return "", false
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// leadingDigits counts the ASCII digits at the start of s.
+func leadingDigits(s string) int {
+	n := 0
+	for n < len(s) && isDigit(rune(s[n])) {
+		n++
+	}
+	return n
+}
+
+// trailingDigits counts the ASCII digits at the end of s.
+func trailingDigits(s string) int {
+	n := 0
+	for n < len(s) && isDigit(rune(s[len(s)-1-n])) {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Dict is a dictionary operand, such as the property list of BDC or a
+// nested dictionary in an inline image (e.g. /DecodeParms). Keys are the
+// names without the leading slash. Values use the operand types: float64
+// for numbers, string for names and literal strings, []byte for hex
+// strings, []any for arrays, Dict for dictionaries, bool for true and
+// false, and nil for null.
+type Dict map[string]any
+
+// Number returns the numeric value of key.
+func (d Dict) Number(key string) (float64, bool) {
+	f, ok := d[key].(float64)
+	return f, ok
+}
+
+// String returns the name or literal string value of key.
+func (d Dict) String(key string) (string, bool) {
+	s, ok := d[key].(string)
+	return s, ok
+}
+
+// Dict returns the dictionary value of key.
+func (d Dict) Dict(key string) (Dict, bool) {
+	v, ok := d[key].(Dict)
+	return v, ok
+}
+
+// Array returns the array value of key.
+func (d Dict) Array(key string) ([]any, bool) {
+	a, ok := d[key].([]any)
+	return a, ok
+}
+
+// ParseDict parses a dictionary token such as "<</MCID 0>>".
+func ParseDict(token []byte) (Dict, error) {
+	var p Parser
+	return p.parseDict(token)
+}
+
+// parseDict parses a "<<...>>" token as delimited by tokenEnd, which
+// keeps nested dictionaries and strings inside the token.
+func (p *Parser) parseDict(token []byte) (Dict, error) {
+	if len(token) < 4 || !bytes.HasPrefix(token, []byte("<<")) || !bytes.HasSuffix(token, []byte(">>")) {
+		return nil, fmt.Errorf("%w: missing '>>'", ErrInvalidDict)
+	}
+	s := dictScanner{p: p, data: token[2 : len(token)-2]}
+	d := make(Dict)
+	for {
+		key := s.next()
+		if key == nil {
+			return d, nil
+		}
+		if key[0] != '/' {
+			return nil, fmt.Errorf("%w: key %q is not a name", ErrInvalidDict, key)
+		}
+		token := s.next()
+		if token == nil {
+			return nil, fmt.Errorf("%w: no value for key %s", ErrInvalidDict, key)
+		}
+		v, err := s.value(token)
+		if err != nil {
+			return nil, err
+		}
+		d[decodeName(key[1:])] = v
+	}
+}
+
+// dictScanner reads the tokens of a dictionary's contents.
+type dictScanner struct {
+	p    *Parser
+	data []byte
+}
+
+// next returns the next token, or nil at the end of the contents.
+func (s *dictScanner) next() []byte {
+	advance, token, _ := pdfTokenSplit(s.data, true)
+	s.data = s.data[advance:]
+	return token
+}
+
+// value parses the value starting with token, reading the rest of an
+// array from the scanner.
+func (s *dictScanner) value(token []byte) (any, error) {
+	switch string(token) {
+	case "[":
+		arr := []any{}
+		for {
+			token := s.next()
+			if token == nil {
+				return nil, ErrUnclosedArray
+			}
+			if string(token) == "]" {
+				return arr, nil
+			}
+			v, err := s.value(token)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+	case "]":
+		return nil, ErrUnexpectedArrayEnd
+	}
+	if bytes.HasPrefix(token, []byte("<<")) {
+		return s.p.parseDict(token)
+	}
+	return s.p.parseOperand(token)
+}
@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Dump writes a readable listing of operations to w, one per line: the
+// line and column of the operator, then the operator followed by its
+// operands, indented by nesting, and what the operator does. The q ... Q,
+// BT ... ET, BMC/BDC ... EMC, BX ... EX and BI ... EI sequences each
+// indent what they enclose. Operands are written as Serialize writes
+// them, except the inline image data of EI, which is summarized by its
+// size.
+//
+//	2:1      BT                                 begin text object
+//	2:11       Tf /F1 12                        set font and size
+//	2:32       Tj (Hello)                       show string
+//
+// Unbalanced sequences do not stop the listing: a closing operator
+// without an opening one is written unindented.
+func Dump(w io.Writer, ops []Operation) error {
+	var b bytes.Buffer
+	depth := 0
+	for i, op := range ops {
+		if closesNesting(op.Name) {
+			depth = max(depth-1, 0)
+		}
+
+		b.Reset()
+		fmt.Fprintf(&b, "%5d:%-4d ", op.Position.Line, op.Position.Column)
+		for range depth {
+			b.WriteString("  ")
+		}
+		b.WriteString(op.Name)
+		if op.Name == "EI" {
+			fmt.Fprintf(&b, " <%d bytes of image data>", inlineImageSize(op))
+		} else if len(op.Operands) > 0 {
+			b.WriteByte(' ')
+			if err := writeOperands(&b, op); err != nil {
+				return fmt.Errorf("operation %d (%s): %w", i, op.Name, err)
+			}
+			b.Truncate(b.Len() - 1) // The space after the last operand
+		}
+		if desc, ok := operatorDescriptions[op.Name]; ok {
+			const column = 48 // Where descriptions start, unless pushed right
+			for n := b.Len(); n < column-2; n++ {
+				b.WriteByte(' ')
+			}
+			b.WriteString("  ")
+			b.WriteString(desc)
+		}
+		b.WriteByte('\n')
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+
+		if opensNesting(op.Name) {
+			depth++
+		}
+	}
+	return nil
+}
+
+func opensNesting(name string) bool {
+	switch name {
+	case "q", "BT", "BMC", "BDC", "BX", "BI":
+		return true
+	}
+	return false
+}
+
+func closesNesting(name string) bool {
+	switch name {
+	case "Q", "ET", "EMC", "EX", "EI":
+		return true
+	}
+	return false
+}
+
+// inlineImageSize returns the length of the image data of an EI operation.
+func inlineImageSize(op Operation) int {
+	if len(op.Operands) == 1 {
+		if data, ok := op.Operands[0].([]byte); ok {
+			return len(data)
+		}
+	}
+	return 0
+}
+
+// IsStandardOperator reports whether name is an operator of ISO 32000-1,
+// Table A.1, as opposed to a vendor extension or garbage.
+func IsStandardOperator(name string) bool {
+	_, ok := operatorDescriptions[name]
+	return ok
+}
+
+// operatorDescriptions summarize the operators of ISO 32000-1, Table A.1.
+var operatorDescriptions = map[string]string{
+	// General graphics state
+	"w":  "set line width",
+	"J":  "set line cap",
+	"j":  "set line join",
+	"M":  "set miter limit",
+	"d":  "set dash pattern",
+	"ri": "set rendering intent",
+	"i":  "set flatness",
+	"gs": "set graphics state parameters",
+
+	// Special graphics state
+	"q":  "save graphics state",
+	"Q":  "restore graphics state",
+	"cm": "concatenate matrix",
+
+	// Path construction
+	"m":  "move to",
+	"l":  "line to",
+	"c":  "curve to",
+	"v":  "curve to, first control point current",
+	"y":  "curve to, second control point final",
+	"h":  "close subpath",
+	"re": "rectangle",
+
+	// Path painting and clipping
+	"S":  "stroke",
+	"s":  "close and stroke",
+	"f":  "fill (nonzero)",
+	"F":  "fill (nonzero)",
+	"f*": "fill (even-odd)",
+	"B":  "fill (nonzero) and stroke",
+	"B*": "fill (even-odd) and stroke",
+	"b":  "close, fill (nonzero) and stroke",
+	"b*": "close, fill (even-odd) and stroke",
+	"n":  "end path",
+	"W":  "clip (nonzero)",
+	"W*": "clip (even-odd)",
+
+	// Color
+	"CS":  "set stroking color space",
+	"cs":  "set nonstroking color space",
+	"SC":  "set stroking color",
+	"sc":  "set nonstroking color",
+	"SCN": "set stroking color",
+	"scn": "set nonstroking color",
+	"G":   "set stroking gray",
+	"g":   "set nonstroking gray",
+	"RG":  "set stroking RGB color",
+	"rg":  "set nonstroking RGB color",
+	"K":   "set stroking CMYK color",
+	"k":   "set nonstroking CMYK color",
+
+	// Shading, XObjects and inline images
+	"sh": "paint shading",
+	"Do": "paint XObject",
+	"BI": "begin inline image",
+	"ID": "inline image data",
+	"EI": "end inline image",
+
+	// Text objects and state
+	"BT": "begin text object",
+	"ET": "end text object",
+	"Tc": "set character spacing",
+	"Tw": "set word spacing",
+	"Tz": "set horizontal scaling",
+	"TL": "set leading",
+	"Tf": "set font and size",
+	"Tr": "set text rendering mode",
+	"Ts": "set text rise",
+
+	// Text positioning and showing
+	"Td": "move text position",
+	"TD": "move text position and set leading",
+	"Tm": "set text matrix",
+	"T*": "move to next line",
+	"Tj": "show string",
+	"TJ": "show strings with spacing",
+	"'":  "move to next line and show string",
+	"\"": "set spacing, move to next line and show string",
+
+	// Type 3 fonts
+	"d0": "set glyph width",
+	"d1": "set glyph width and bounding box",
+
+	// Marked content
+	"MP":  "marked-content point",
+	"DP":  "marked-content point with properties",
+	"BMC": "begin marked content",
+	"BDC": "begin marked content with properties",
+	"EMC": "end marked content",
+
+	// Compatibility
+	"BX": "begin compatibility section",
+	"EX": "end compatibility section",
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMalformedToken is matched (via errors.Is) by every *MalformedTokenError.
+var ErrMalformedToken = errors.New("malformed token")
+
+// ErrUnserializable reports an operation Serialize cannot write: an
+// operand of a type the parser does not produce, a number that is not
+// finite, or an operator name that would not read back as one.
+var ErrUnserializable = errors.New("cannot serialize operation")
+
+// ErrLimitExceeded reports input exceeding a size limit, such as a token
+// larger than the tokenizer's buffer. It is matched by every *LimitError.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Errors wrapped by MalformedTokenError (via Err) and returned by Parse,
+// for telling failure classes apart with errors.Is.
+var (
+	// ErrUnclosedArray reports a '[' without a matching ']' at the end of
+	// the stream.
+	ErrUnclosedArray = errors.New("unclosed array")
+
+	// ErrUnexpectedArrayEnd reports a ']' outside of any array.
+	ErrUnexpectedArrayEnd = errors.New("unexpected ']' outside of array")
+
+	// ErrInvalidHexString reports a hex string with non-hex digits.
+	ErrInvalidHexString = errors.New("invalid hex string")
+
+	// ErrInvalidLiteralString reports a literal string without its
+	// enclosing parentheses.
+	ErrInvalidLiteralString = errors.New("invalid literal string")
+
+	// ErrInvalidDict reports a dictionary that is unterminated or whose
+	// entries are not name-value pairs.
+	ErrInvalidDict = errors.New("invalid dictionary")
+
+	// ErrUnknownOperand reports a token that is neither an operator nor a
+	// known operand type.
+	ErrUnknownOperand = errors.New("unrecognized operand type")
+)
+
+// MalformedTokenError describes a token that could not be parsed.
+type MalformedTokenError struct {
+	// Position is the location of the token in the content stream.
+	Position
+
+	// Token is the raw token text.
+	Token string
+
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+func (e *MalformedTokenError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("malformed token %q at %v: %v", e.Token, e.Position, e.Err)
+	}
+	return fmt.Sprintf("malformed token %q at %v", e.Token, e.Position)
+}
+
+// LimitError reports input exceeding a configured processing limit, such
+// as the size of a token or the number of operations in a stream, which
+// stops processing.
+type LimitError struct {
+	// Limit names the limit after the setting that sets it, such as
+	// "MaxTokenSize", and Max is its value.
+	Limit string
+	Max   int
+
+	// Offset is the byte offset in the stream where the limit was
+	// exceeded, or -1 for limits not tied to a stream position.
+	Offset int64
+}
+
+func (e *LimitError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("%v: %s is %d", ErrLimitExceeded, e.Limit, e.Max)
+	}
+	return fmt.Sprintf("%v at offset %d: %s is %d", ErrLimitExceeded, e.Offset, e.Limit, e.Max)
+}
+
+// Unwrap returns ErrLimitExceeded.
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// Is reports whether target is ErrMalformedToken.
+func (e *MalformedTokenError) Is(target error) bool {
+	return target == ErrMalformedToken
+}
+
+// Unwrap returns the underlying cause.
+func (e *MalformedTokenError) Unwrap() error {
+	return e.Err
+}
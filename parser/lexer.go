@@ -0,0 +1,389 @@
+package parser
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+)
+
+// Byte classes of the PDF lexer (ISO 32000-1, 7.2.2), looked up in a table
+// so the tokenizer handles each byte with one load instead of a chain of
+// comparisons.
+const (
+	classSpace     byte = 1 << iota // NUL, TAB, LF, FF, CR, SP
+	classDelimiter                  // ( ) < > [ ] { } / %
+	classHexDigit                   // 0-9 a-f A-F
+	classOperator                   // letters, '*', '\'', '"'
+	classNumeric                    // 0-9 + - .
+)
+
+var byteClass = func() (t [256]byte) {
+	for _, b := range []byte{0, '\t', '\n', '\f', '\r', ' '} {
+		t[b] |= classSpace
+	}
+	for _, b := range []byte("()<>[]{}/%") {
+		t[b] |= classDelimiter
+	}
+	for _, b := range []byte("0123456789abcdefABCDEF") {
+		t[b] |= classHexDigit
+	}
+	for b := 'a'; b <= 'z'; b++ {
+		t[b] |= classOperator
+		t[b-'a'+'A'] |= classOperator
+	}
+	for _, b := range []byte("*'\"") {
+		t[b] |= classOperator
+	}
+	for _, b := range []byte("0123456789+-.") {
+		t[b] |= classNumeric
+	}
+	return t
+}()
+
+// isWhitespace reports whether b is a PDF whitespace character.
+func isWhitespace(b byte) bool {
+	return byteClass[b]&classSpace != 0
+}
+
+func isDelimiter(b byte) bool {
+	return byteClass[b]&classDelimiter != 0
+}
+
+// isTokenEnd reports whether b ends a regular token (a name, number or
+// operator).
+func isTokenEnd(b byte) bool {
+	return byteClass[b]&(classSpace|classDelimiter) != 0
+}
+
+// hexDigits holds the value of each hex digit, and 0xFF for other bytes.
+var hexDigits = func() (t [256]byte) {
+	for i := range t {
+		t[i] = 0xFF
+		if byteClass[i]&classHexDigit != 0 {
+			t[i] = hexValue(byte(i))
+		}
+	}
+	return t
+}()
+
+// hexValue returns the value of a hex digit.
+func hexValue(b byte) byte {
+	switch {
+	case b >= 'a':
+		return b - 'a' + 10
+	case b >= 'A':
+		return b - 'A' + 10
+	default:
+		return b - '0'
+	}
+}
+
+// standardOperators are the operators of ISO 32000-1, Annex A.
+var standardOperators = []string{
+	"b", "B", "b*", "B*", "BDC", "BI", "BMC", "BT", "BX",
+	"c", "cm", "CS", "cs", "d", "d0", "d1", "Do", "DP",
+	"EI", "EMC", "ET", "EX", "f", "F", "f*", "G", "g", "gs",
+	"h", "i", "ID", "j", "J", "K", "k", "l", "m", "M", "MP",
+	"n", "q", "Q", "re", "RG", "rg", "ri", "s", "S", "SC", "sc",
+	"SCN", "scn", "sh", "T*", "Tc", "Td", "TD", "Tf", "Tj", "TJ",
+	"TL", "Tm", "Tr", "Ts", "Tw", "Tz", "v", "w", "W", "W*", "y",
+	"'", "\"",
+}
+
+// operatorTable interns the standard operator names, so that the parser
+// does not allocate a string for each operation. It is an open-addressing
+// hash table keyed by operatorKey, which for these short keys is several
+// times faster than a map lookup.
+var operatorTable = func() (t [256]struct {
+	key  uint32
+	name string
+}) {
+	for _, name := range standardOperators {
+		key := operatorKey([]byte(name))
+		h := operatorHash(key)
+		for t[h].name != "" {
+			h++ // Wraps around the 256 entries
+		}
+		t[h].key, t[h].name = key, name
+	}
+	return t
+}()
+
+// operatorKey packs a token of at most three bytes and its length into an
+// integer, distinct for distinct tokens.
+func operatorKey(token []byte) uint32 {
+	key := uint32(len(token))
+	for i, b := range token {
+		key |= uint32(b) << (8 * (i + 1))
+	}
+	return key
+}
+
+func operatorHash(key uint32) uint8 {
+	return uint8(key * 0x9E3779B1 >> 24)
+}
+
+// operatorName returns the operator name of token, interned if it is a
+// standard operator.
+func operatorName(token []byte) string {
+	if len(token) <= 3 {
+		key := operatorKey(token)
+		for h := operatorHash(key); operatorTable[h].name != ""; h++ {
+			if operatorTable[h].key == key {
+				return operatorTable[h].name
+			}
+		}
+	}
+	return string(token)
+}
+
+// maxInternedNames bounds the per-parser table of interned names.
+const maxInternedNames = 1024
+
+// internName returns the name token body b as a string operand, reusing an
+// earlier allocation for names seen before. Content streams repeat a
+// handful of resource names (fonts, marked-content tags) many times.
+func (p *Parser) internName(b []byte) any {
+	if name, ok := p.names[string(b)]; ok {
+		return name
+	}
+	var name any = decodeName(b)
+	if len(p.names) < maxInternedNames {
+		if p.names == nil {
+			p.names = make(map[string]any)
+		}
+		p.names[string(b)] = name
+	}
+	return name
+}
+
+// decodeName returns the name token body b with its #xx escapes decoded
+// (ISO 32000-1, 7.3.5), e.g. "F#20one" as "F one". A '#' not followed by
+// two hex digits is kept as is, as PDF 1.1 names could contain it.
+func decodeName(b []byte) string {
+	i := bytes.IndexByte(b, '#')
+	if i < 0 {
+		return string(b)
+	}
+	out := make([]byte, i, len(b))
+	copy(out, b)
+	for ; i < len(b); i++ {
+		if b[i] == '#' && i+2 < len(b) && byteClass[b[i+1]]&byteClass[b[i+2]]&classHexDigit != 0 {
+			out = append(out, hexValue(b[i+1])<<4|hexValue(b[i+2]))
+			i += 2
+			continue
+		}
+		out = append(out, b[i])
+	}
+	return string(out)
+}
+
+// parseNumber parses a PDF number: an optional sign, digits, and at most
+// one decimal point. Plain decimals are converted directly; anything else
+// (e.g. exponents, which PDF does not define but some producers write) is
+// left to strconv.
+func parseNumber(token []byte) (float64, bool) {
+	i := 0
+	neg := false
+	if i < len(token) && (token[i] == '+' || token[i] == '-') {
+		neg = token[i] == '-'
+		i++
+	}
+	var mantissa uint64
+	digits, scale := 0, 0
+	seenPoint := false
+	for ; i < len(token); i++ {
+		c := token[i]
+		switch {
+		case c >= '0' && c <= '9':
+			if digits >= 15 {
+				// Beyond 15 digits the mantissa may not be exact
+				return parseFloatSlow(token)
+			}
+			mantissa = mantissa*10 + uint64(c-'0')
+			digits++
+			if seenPoint {
+				scale++
+			}
+		case c == '.' && !seenPoint:
+			seenPoint = true
+		default:
+			return parseFloatSlow(token)
+		}
+	}
+	if digits == 0 {
+		return parseFloatSlow(token)
+	}
+	f := float64(mantissa)
+	if scale > 0 {
+		f /= pow10[scale]
+	}
+	if neg {
+		f = -f
+	}
+	return f, true
+}
+
+// pow10 holds the powers of ten parseNumber divides by. Both operands of
+// the division are exact, so the result is correctly rounded, the same as
+// strconv.ParseFloat.
+var pow10 = [...]float64{1, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15}
+
+// boxedInts holds the non-negative integers below len(boxedInts) as
+// operands. Coordinates and sizes are mostly small integers, and storing a
+// float64 in an interface otherwise allocates.
+var boxedInts = func() (t [1024]any) {
+	for i := range t {
+		t[i] = float64(i)
+	}
+	return t
+}()
+
+// operandCacheSize is the number of entries of each table of an
+// operandCache.
+const operandCacheSize = 256
+
+// maxCachedString is the length of the longest literal string an
+// operandCache holds.
+const maxCachedString = 32
+
+// operandCache holds recently boxed numbers and short strings, so that an
+// operand repeating one shares its allocation. Content streams repeat a
+// handful of color components, widths and kerned glyph strings many times.
+// Each table is direct-mapped: a value replaces the one it collides with.
+type operandCache struct {
+	numbers [operandCacheSize]struct {
+		bits  uint64
+		boxed any
+	}
+	strings [operandCacheSize]struct {
+		s     string
+		boxed any
+	}
+}
+
+// cacheIndex spreads the bits of h over the entries of an operandCache
+// (Fibonacci hashing).
+func cacheIndex(h uint64) uint8 {
+	return uint8(h * 0x9E3779B97F4A7C15 >> 56)
+}
+
+// parseNumberOperand parses a number token as an operand.
+func (p *Parser) parseNumberOperand(token []byte) (any, bool) {
+	f, ok := parseNumber(token)
+	if !ok {
+		return nil, false
+	}
+	return p.numberOperand(f), true
+}
+
+// smallIntOperand reads a token of one to three digits, the most common
+// numbers, directly as one of boxedInts.
+func smallIntOperand(token []byte) (any, bool) {
+	if len(token) == 0 || len(token) > 3 {
+		return nil, false
+	}
+	i := 0
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return nil, false
+		}
+		i = i*10 + int(c-'0')
+	}
+	return boxedInts[i], true
+}
+
+// numberOperand returns f as an operand, without allocating for small
+// integers or for a value boxed recently.
+func (p *Parser) numberOperand(f float64) any {
+	if i := int(f); float64(i) == f && i >= 0 && i < len(boxedInts) && !math.Signbit(f) {
+		return boxedInts[i]
+	}
+	if p.cache == nil {
+		p.cache = new(operandCache)
+	}
+	bits := math.Float64bits(f)
+	e := &p.cache.numbers[cacheIndex(bits)]
+	if e.boxed == nil || e.bits != bits {
+		e.bits, e.boxed = bits, f
+	}
+	return e.boxed
+}
+
+// stringOperand returns the literal string b as an operand, without
+// allocating for a short string boxed recently.
+func (p *Parser) stringOperand(b []byte) any {
+	if len(b) == 0 || len(b) > maxCachedString {
+		return p.stringSlab(b)
+	}
+	if p.cache == nil {
+		p.cache = new(operandCache)
+	}
+	h := uint64(len(b)) | uint64(b[0])<<8 | uint64(b[len(b)/2])<<16 | uint64(b[len(b)-1])<<24
+	e := &p.cache.strings[cacheIndex(h)]
+	if e.boxed == nil || e.s != string(b) {
+		e.s = p.stringSlab(b)
+		e.boxed = e.s
+	}
+	return e.boxed
+}
+
+func parseFloatSlow(token []byte) (float64, bool) {
+	if f, err := strconv.ParseFloat(string(token), 64); err == nil {
+		return f, true
+	}
+	return parseMalformedNumber(token)
+}
+
+// parseMalformedNumber reads the malformed numbers some producers write
+// and viewers accept: repeated leading signs ("--5"), a sign or point on
+// its own (read as 0), and minus signs after the first digit or point
+// ("1.-5"), which are ignored.
+func parseMalformedNumber(token []byte) (float64, bool) {
+	i := 0
+	neg := false
+	for i < len(token) && (token[i] == '+' || token[i] == '-') {
+		neg = neg || token[i] == '-'
+		i++
+	}
+	digits := make([]byte, 0, len(token))
+	seenPoint, seenDigit := false, false
+	for ; i < len(token); i++ {
+		switch c := token[i]; {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+			seenDigit = true
+		case c == '.' && !seenPoint:
+			digits = append(digits, c)
+			seenPoint = true
+		case c == '-':
+		default:
+			return 0, false
+		}
+	}
+	if !seenDigit {
+		return 0, true
+	}
+	f, err := strconv.ParseFloat(string(digits), 64)
+	if err != nil {
+		return 0, false
+	}
+	if neg {
+		f = -f
+	}
+	return f, true
+}
+
+// keywordOperand returns the value of the keywords true, false and null.
+func keywordOperand(token []byte) (any, bool) {
+	switch string(token) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+	return nil, false
+}
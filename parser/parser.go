@@ -1,126 +1,491 @@
 package parser
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"strconv"
+	"iter"
+	"log/slog"
 	"strings"
-	"unicode"
 )
 
 // Operation represents a PDF operator and its operands.
 type Operation struct {
 	Name     string
 	Operands []any
+
+	// Position is the location of the operator in the content stream.
+	Position
 }
 
 // Parser tokenizes a PDF content stream.
 // This is a simplified parser; a production-parser would need to be
 // more robust, especially around string parsing and error handling.
 type Parser struct {
-	scanner *bufio.Scanner
+	in window // the stream being tokenized
+
+	lines    lineTracker // line breaks in the tokenized bytes
+	tokenPos Position    // position of the current token
+
+	// Inline images (BI ... ID data EI): after ID the tokenizer switches
+	// to reading raw image data up to EI.
+	inlineData bool // the next token is inline image data
+	imageToken bool // the current token is inline image data
+
+	// Operands of the operator being parsed, and the elements of the
+	// arrays being built, innermost last, with where each array starts
+	operands    []any
+	arrayItems  []any
+	arrayStarts []int
+
+	maxArrayLength int // 0 means no limit
+
+	names   map[string]any  // interned names, see internName
+	cache   *operandCache   // boxed numbers and strings, see numberOperand
+	bytes   []byte          // slab for hex strings, see byteSlab
+	arrays  []any           // slab for arrays, see arraySlab
+	text    strings.Builder // slab for literal strings, see stringSlab
+	escaped []byte          // scratch for decoding escaped literal strings
+
+	tokens int             // tokens scanned, for cancellation checks
+	ctx    context.Context // set while iterating with Operations
+	err    error           // sticky error returned by Next
+
+	warnings    []*MalformedTokenError // operands skipped so far
+	maxWarnings int                    // 0 means no limit
+	suppressed  int                    // operands skipped past maxWarnings
+	logger      *slog.Logger           // if set, skipped operands are logged
 }
 
-// NewParser creates a new parser for a given reader.
+// NewParser creates a new parser for a given reader. The stream is read
+// into a buffer as parsing proceeds, so memory stays bounded by the
+// largest token.
 func NewParser(r io.Reader) *Parser {
-	scanner := bufio.NewScanner(r)
-	scanner.Split(pdfTokenSplit)
-	return &Parser{scanner: scanner}
+	return &Parser{in: window{r: r}}
+}
+
+// NewParserBytes creates a new parser for a stream held in memory. It
+// tokenizes data in place, without copying it; data must not be modified
+// while parsing. Operands do not refer to data.
+func NewParserBytes(data []byte) *Parser {
+	return &Parser{in: window{buf: data, eof: true}}
+}
+
+// scan returns the next token as a view of the window, valid until the
+// next call, or nil at the end of the stream or on an error of the
+// window.
+func (p *Parser) scan() []byte {
+	w := &p.in
+	for {
+		if p.inlineData {
+			advance, token, _ := splitInlineImageData(w.buf[w.pos:], w.eof)
+			if token == nil {
+				if !w.more() {
+					return nil
+				}
+				continue
+			}
+			start := w.pos + advance - len(token)
+			p.lines.advance(w.buf[w.pos:start], w.offset(w.pos))
+			p.tokenPos = p.lines.position(w.offset(start))
+			p.lines.advance(token, w.offset(start))
+			w.pos += advance
+			p.inlineData = false
+			p.imageToken = true
+			return token
+		}
+
+		buf, start := w.buf, w.pos
+		for start < len(buf) {
+			c := buf[start]
+			if byteClass[c]&classSpace != 0 {
+				if c == '\n' || c == '\r' {
+					p.lines.lineBreak(c, w.offset(start))
+				}
+				start++
+				continue
+			}
+			if c != '%' {
+				break
+			}
+			// A comment runs up to the end of the line
+			end := start + 1
+			for end < len(buf) && buf[end] != '\n' && buf[end] != '\r' {
+				end++
+			}
+			if end == len(buf) && !w.eof {
+				break // Read the rest of the comment first
+			}
+			start = end
+		}
+		w.pos = start
+
+		var end int
+		complete := false
+		if start < len(buf) {
+			if byteClass[buf[start]]&classDelimiter == 0 {
+				// A regular token (a number, operator or keyword), the
+				// most common kind, without the dispatch of tokenEnd
+				end = start + 1
+				for end < len(buf) && byteClass[buf[end]]&(classSpace|classDelimiter) == 0 {
+					end++
+				}
+				complete = end < len(buf)
+			} else if c := buf[start]; c == '[' || c == ']' {
+				end, complete = start+1, true
+			} else if c != '%' {
+				end, complete = tokenEnd(buf, start)
+			}
+		}
+		if !complete {
+			if !w.eof {
+				if !w.more() {
+					return nil
+				}
+				continue
+			}
+			if start == len(buf) {
+				return nil
+			}
+			end = len(buf) // The stream ends within the token
+		}
+		if w.maxTokenSize > 0 && end-start > w.maxTokenSize {
+			w.err = errTokenTooLong
+			return nil
+		}
+		token := buf[start:end]
+		p.tokenPos = p.lines.position(w.offset(start))
+		if c := token[0]; c == '(' || c == '<' {
+			p.lines.advance(token, w.offset(start))
+		}
+		w.pos = end
+		return token
+	}
+}
+
+// SetMaxTokenSize caps the size of a single token (a string, inline
+// dictionary or inline image) at n bytes; a longer token stops parsing
+// with a *LimitError. By default, tokens may be of any size. Call it
+// before parsing.
+func (p *Parser) SetMaxTokenSize(n int) {
+	p.in.maxTokenSize = n
+}
+
+// SetMaxArrayLength caps the number of elements of an array operand, and
+// of operands collected for one operator, at n; more stop parsing with a
+// *LimitError. By default, arrays may be of any length. Call it before
+// parsing.
+func (p *Parser) SetMaxArrayLength(n int) {
+	p.maxArrayLength = n
+}
+
+// SetMaxWarnings caps the skipped operands recorded for Warnings and
+// logged at n; later ones are skipped all the same but only counted, see
+// SuppressedWarnings. By default, all are recorded. Call it before
+// parsing.
+func (p *Parser) SetMaxWarnings(n int) {
+	p.maxWarnings = n
+}
+
+// SetLogger makes the parser log each operand it skips, in addition to
+// recording it for Warnings. By default nothing is logged.
+func (p *Parser) SetLogger(logger *slog.Logger) {
+	p.logger = logger
 }
 
 // Parse processes the entire stream and returns a list of operations.
 func (p *Parser) Parse() ([]Operation, error) {
+	return p.ParseContext(context.Background())
+}
+
+// contextCheckInterval is how many tokens are scanned between checks for
+// cancellation.
+const contextCheckInterval = 1024
+
+// ParseContext is like Parse but stops when ctx is done. On cancellation it
+// returns the operations parsed so far together with ctx.Err().
+func (p *Parser) ParseContext(ctx context.Context) ([]Operation, error) {
 	var operations []Operation
-	var operands []any
-	var arrayStack [][]any // stack of arrays being built
-	arrayLevel := 0
+	for op, err := range p.Operations(ctx) {
+		if err != nil {
+			if ctx.Err() != nil {
+				return operations, err
+			}
+			return nil, err
+		}
+		operations = append(operations, op)
+	}
+	return operations, nil
+}
 
-	for p.scanner.Scan() {
-		token := p.scanner.Bytes()
-		if len(token) == 0 {
-			continue
+// Operations returns an iterator over the operations of the stream, parsed
+// as the iteration proceeds, so memory stays bounded by the largest
+// operation rather than the stream. If parsing fails, or ctx is done, the
+// last pair yielded carries the error.
+func (p *Parser) Operations(ctx context.Context) iter.Seq2[Operation, error] {
+	return func(yield func(Operation, error) bool) {
+		p.ctx = ctx
+		defer func() { p.ctx = nil }()
+		for {
+			op, err := p.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(op, err) || err != nil {
+				return
+			}
 		}
+	}
+}
 
-		// Check if it's an operator (alphabetic)
-		if arrayLevel == 0 && isOperator(token) {
-			op := Operation{
-				Name:     string(token),
-				Operands: make([]any, len(operands)),
+// Next parses and returns the next operation. It returns io.EOF at the end
+// of the stream. After any other error the stream cannot be resumed, and
+// Next keeps returning that error.
+func (p *Parser) Next() (Operation, error) {
+	if p.err != nil {
+		return Operation{}, p.err
+	}
+	name, err := p.next()
+	if err != nil {
+		p.err = err
+		return Operation{}, err
+	}
+	return Operation{Name: name, Operands: p.takeOperands(), Position: p.tokenPos}, nil
+}
+
+// next parses the next operation, returning its name; its operands are
+// left in p.operands and its position in p.tokenPos.
+func (p *Parser) next() (string, error) {
+	for {
+		token := p.scan()
+		if token == nil {
+			break
+		}
+		if p.ctx != nil {
+			if p.tokens++; p.tokens%contextCheckInterval == 0 {
+				if err := p.ctx.Err(); err != nil {
+					return "", err
+				}
 			}
-			copy(op.Operands, operands)
-			operations = append(operations, op)
-			operands = operands[:0] // Clear the operand stack
-		} else {
-			// It's an operand, or we are inside an array
-			operand, err := parseOperand(token)
-			if err != nil {
-				// For now, we'll just skip bad operands
-				fmt.Printf("Warning: skipping unparsable operand '%s': %v\n", string(token), err)
+		}
+		if p.imageToken {
+			// Raw inline image data becomes the operand of EI
+			p.imageToken = false
+			p.operands = append(p.operands[:0], inlineImageData(token))
+			return "EI", nil
+		}
+		// Numbers are the most common operands
+		if byteClass[token[0]]&classNumeric != 0 {
+			v, ok := smallIntOperand(token)
+			if !ok {
+				v, ok = p.parseNumberOperand(token)
+			}
+			if ok {
+				if err := p.addOperand(v); err != nil {
+					return "", err
+				}
 				continue
 			}
+		}
 
-			if s, ok := operand.(string); ok {
-				if s == "[" {
-					// Start new array
-					arrayStack = append(arrayStack, make([]any, 0))
-					arrayLevel++
-					continue // Don't add "[" to operand stack
-				} else if s == "]" {
-					// Close current array
-					if arrayLevel == 0 {
-						return nil, errors.New("unexpected ']' outside of array")
-					}
-					arrayLevel--
-					closedArray := arrayStack[len(arrayStack)-1]
-					arrayStack = arrayStack[:len(arrayStack)-1] // pop
-
-					if arrayLevel == 0 {
-						// Top-level array finished, add to main operands
-						operands = append(operands, closedArray)
-					} else {
-						// Nested array finished, add to parent array
-						parentArray := arrayStack[len(arrayStack)-1]
-						arrayStack[len(arrayStack)-1] = append(parentArray, closedArray)
-					}
-					continue // Don't add "]" to operand stack
+		// The keywords true, false and null look like operators but are
+		// operands
+		if v, ok := keywordOperand(token); ok {
+			if err := p.addOperand(v); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		// Check if it's an operator (alphabetic)
+		if len(p.arrayStarts) == 0 && isOperator(token) {
+			name := operatorName(token)
+			if name == "ID" {
+				p.inlineData = true
+			}
+			return name, nil
+		}
+
+		// Array delimiters
+		if len(token) == 1 && token[0] == '[' {
+			p.arrayStarts = append(p.arrayStarts, len(p.arrayItems))
+			continue
+		}
+		if len(token) == 1 && token[0] == ']' {
+			if len(p.arrayStarts) == 0 {
+				return "", &MalformedTokenError{
+					Position: p.tokenPos,
+					Token:    "]",
+					Err:      ErrUnexpectedArrayEnd,
 				}
 			}
+			if err := p.addOperand(p.closeArray()); err != nil {
+				return "", err
+			}
+			continue
+		}
 
-			// Add operand
-			if arrayLevel > 0 {
-				// Add to current array
-				currentArray := arrayStack[len(arrayStack)-1]
-				arrayStack[len(arrayStack)-1] = append(currentArray, operand)
-			} else {
-				// Add to main operand stack
-				operands = append(operands, operand)
+		// It's an operand, or we are inside an array
+		operand, err := p.parseOperand(token)
+		if err != nil {
+			// Skip bad operands, recording them for Warnings
+			if p.maxWarnings > 0 && len(p.warnings) >= p.maxWarnings {
+				p.suppressed++
+				continue
 			}
+			malformed := &MalformedTokenError{Position: p.tokenPos, Token: string(token), Err: err}
+			p.warnings = append(p.warnings, malformed)
+			if p.logger != nil {
+				p.logger.Warn("skipping unparsable operand", "offset", malformed.Offset, "err", malformed)
+			}
+			continue
+		}
+
+		if err := p.addOperand(operand); err != nil {
+			return "", err
 		}
 	}
 
-	if err := p.scanner.Err(); err != nil {
-		return nil, fmt.Errorf("scanner error: %w", err)
+	if err := p.in.err; err != nil {
+		if errors.Is(err, errTokenTooLong) {
+			return "", &LimitError{Limit: "MaxTokenSize", Max: p.in.maxTokenSize, Offset: p.in.offset(p.in.pos)}
+		}
+		return "", fmt.Errorf("read error: %w", err)
 	}
 
-	if arrayLevel > 0 {
-		return nil, errors.New("unclosed array at end of stream")
+	if len(p.arrayStarts) > 0 {
+		return "", fmt.Errorf("%w at end of stream", ErrUnclosedArray)
 	}
 
-	return operations, nil
+	return "", io.EOF
+}
+
+// operandSlabSize is how many operands are allocated at once.
+const operandSlabSize = 256
+
+// byteSlabSize is how many bytes are allocated at once for hex strings.
+const byteSlabSize = 4 << 10
+
+// byteSlab returns an empty slice with room for n bytes, carved from a
+// shared slab like the operand lists (see takeOperands).
+func (p *Parser) byteSlab(n int) []byte {
+	if n > byteSlabSize/8 {
+		return make([]byte, 0, n)
+	}
+	if n > len(p.bytes) {
+		p.bytes = make([]byte, byteSlabSize)
+	}
+	b := p.bytes[:0:n]
+	p.bytes = p.bytes[n:]
+	return b
+}
+
+// arraySlab returns a slice of n operands, carved from a shared slab like
+// the operand lists.
+func (p *Parser) arraySlab(n int) []any {
+	if n > operandSlabSize/8 {
+		return make([]any, n)
+	}
+	if n > len(p.arrays) {
+		p.arrays = make([]any, operandSlabSize)
+	}
+	a := p.arrays[:n:n]
+	p.arrays = p.arrays[n:]
+	return a
+}
+
+// stringSlab returns b as a string, carved from a shared buffer like the
+// hex strings: a strings.Builder never moves the bytes of the strings it
+// has returned, so a substring of one stays valid as it grows within its
+// capacity.
+func (p *Parser) stringSlab(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if len(b) > byteSlabSize/8 {
+		return string(b)
+	}
+	if p.text.Cap()-p.text.Len() < len(b) {
+		p.text = strings.Builder{}
+		p.text.Grow(byteSlabSize)
+	}
+	p.text.Write(b)
+	s := p.text.String()
+	return s[len(s)-len(b):]
+}
+
+// closeArray pops the innermost open array and returns it as an operand.
+func (p *Parser) closeArray() []any {
+	start := p.arrayStarts[len(p.arrayStarts)-1]
+	p.arrayStarts = p.arrayStarts[:len(p.arrayStarts)-1]
+	arr := p.arraySlab(len(p.arrayItems) - start)
+	copy(arr, p.arrayItems[start:])
+	clear(p.arrayItems[start:])
+	p.arrayItems = p.arrayItems[:start]
+	return arr
+}
+
+// takeOperands returns the operands collected for the current operator and
+// starts a new list. Operands are collected in place in a shared slab, so
+// most operations need no allocation or copy of their own; each returned
+// slice has its capacity capped, so appending to one cannot clobber the
+// next.
+func (p *Parser) takeOperands() []any {
+	n := len(p.operands)
+	if n == 0 {
+		return nil
+	}
+	operands := p.operands[:n:n]
+	p.operands = p.operands[n:n]
+	return operands
+}
+
+// addOperand adds an operand to the innermost open array, or to the
+// operand stack outside arrays, failing if that exceeds the maximum array
+// length.
+func (p *Parser) addOperand(operand any) error {
+	if n := len(p.arrayStarts); n > 0 {
+		if p.maxArrayLength > 0 && len(p.arrayItems)-p.arrayStarts[n-1] == p.maxArrayLength {
+			return &LimitError{Limit: "MaxArrayLength", Max: p.maxArrayLength, Offset: p.tokenPos.Offset}
+		}
+		p.arrayItems = append(p.arrayItems, operand)
+		return nil
+	}
+	if p.maxArrayLength > 0 && len(p.operands) == p.maxArrayLength {
+		return &LimitError{Limit: "MaxArrayLength", Max: p.maxArrayLength, Offset: p.tokenPos.Offset}
+	}
+	if len(p.operands) == cap(p.operands) {
+		// Start a new slab, moving the operands collected so far
+		slab := make([]any, len(p.operands), max(operandSlabSize, 2*len(p.operands)))
+		copy(slab, p.operands)
+		p.operands = slab
+	}
+	p.operands = append(p.operands, operand)
+	return nil
+}
+
+// Warnings returns the malformed operands that parsing skipped, in stream
+// order.
+func (p *Parser) Warnings() []*MalformedTokenError {
+	return p.warnings
+}
+
+// SuppressedWarnings returns how many skipped operands were not recorded
+// for Warnings because of SetMaxWarnings.
+func (p *Parser) SuppressedWarnings() int {
+	return p.suppressed
 }
 
 // isOperator checks if a token is a PDF operator.
-// This is a simplification: valid operators can contain '*' or "'"
+// This is a simplification: operators are letters plus '*', "'" and '"'.
 func isOperator(token []byte) bool {
 	if len(token) == 0 {
 		return false
 	}
-	// Check if all characters are letters (or special operator chars)
 	for _, b := range token {
-		if !((b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b == '*' || b == '\'') {
+		if byteClass[b]&classOperator == 0 {
 			return false
 		}
 	}
@@ -128,74 +493,78 @@ func isOperator(token []byte) bool {
 }
 
 // parseOperand converts a token into a Go type.
-func parseOperand(token []byte) (any, error) {
+func (p *Parser) parseOperand(token []byte) (any, error) {
 	if len(token) == 0 {
 		return nil, errors.New("empty token")
 	}
 
 	switch token[0] {
 	case '(':
-		return parseLiteralString(token)
+		return p.parseLiteralString(token)
 	case '<':
 		if len(token) > 1 && token[1] == '<' {
-			// Dictionary token, e.g., <</MCID 0>>
-			// For text extraction, we don't need to parse its contents,
-			// so we just return it as a string to be consumed by an operator.
-			return string(token), nil
+			return p.parseDict(token) // Dictionary, e.g. <</MCID 0>>
 		}
-		return parseHexString(token) // This now returns ([]byte, error)
+		return parseHexString(p.byteSlab(len(token)/2), token)
 	case '/':
-		return string(token[1:]), nil // Name
-	case '[', ']':
-		return string(token[0]), nil
+		return p.internName(token[1:]), nil // Name
+	case '[':
+		return "[", nil
+	case ']':
+		return "]", nil
 	default:
 		// Try to parse as a number (float or int)
-		if f, err := strconv.ParseFloat(string(token), 64); err == nil {
-			return f, nil
+		if v, ok := p.parseNumberOperand(token); ok {
+			return v, nil
+		}
+		if v, ok := keywordOperand(token); ok {
+			return v, nil
 		}
 		// If not a number, it might be an inline operator we missed,
 		// but for operands, we'll error out.
-		return nil, fmt.Errorf("unrecognized operand type: %s", string(token))
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOperand, string(token))
 	}
 }
 
 // parseLiteralString handles (string) with escapes.
-func parseLiteralString(token []byte) (string, error) {
+func (p *Parser) parseLiteralString(token []byte) (any, error) {
 	if len(token) < 2 || token[0] != '(' || token[len(token)-1] != ')' {
-		return "", fmt.Errorf("invalid literal string: %s", string(token))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLiteralString, string(token))
 	}
 	// Trim parens
 	s := token[1 : len(token)-1]
-	var b strings.Builder
+	if bytes.IndexByte(s, '\\') < 0 {
+		return p.stringOperand(s), nil // No escapes: the common case
+	}
+	b := p.escaped[:0]
 	escaping := false
 	for i := 0; i < len(s); i++ {
 		c := s[i]
 		if escaping {
 			switch c {
 			case 'n':
-				b.WriteByte('\n')
+				b = append(b, '\n')
 			case 'r':
-				b.WriteByte('\r')
+				b = append(b, '\r')
 			case 't':
-				b.WriteByte('\t')
+				b = append(b, '\t')
 			case 'b':
-				b.WriteByte('\b')
+				b = append(b, '\b')
 			case 'f':
-				b.WriteByte('\f')
+				b = append(b, '\f')
 			case '(', ')', '\\':
-				b.WriteByte(c)
+				b = append(b, c)
 			default:
 				// Octal escape (e.g., \123)
 				if c >= '0' && c <= '7' {
-					octal := []byte{c}
+					val := c - '0'
 					j := 1
 					for j < 3 && i+j < len(s) && s[i+j] >= '0' && s[i+j] <= '7' {
-						octal = append(octal, s[i+j])
+						val = val<<3 | (s[i+j] - '0') // High-order overflow is ignored
 						j++
 					}
 					i += (j - 1)
-					val, _ := strconv.ParseInt(string(octal), 8, 32)
-					b.WriteByte(byte(val))
+					b = append(b, val)
 				} else {
 					// Ignored escape (e.g. \g)
 				}
@@ -204,61 +573,72 @@ func parseLiteralString(token []byte) (string, error) {
 		} else if c == '\\' {
 			escaping = true
 		} else {
-			b.WriteByte(c)
+			b = append(b, c)
 		}
 	}
-	return b.String(), nil
+	p.escaped = b
+	return p.stringOperand(b), nil
 }
 
-// parseHexString handles <hexstring>.
-func parseHexString(token []byte) ([]byte, error) {
+// parseHexString handles <hexstring>, appending the bytes to out.
+func parseHexString(out, token []byte) ([]byte, error) {
 	if len(token) < 2 || token[0] != '<' || token[len(token)-1] != '>' {
-		return nil, fmt.Errorf("invalid hex string: %s", string(token))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHexString, string(token))
 	}
 	// Trim angle brackets
 	s := token[1 : len(token)-1]
 
-	// PDF hex strings can contain whitespace, so let's strip it.
-	s = bytes.Map(func(r rune) rune {
-		if unicode.IsSpace(r) {
-			return -1 // Remove whitespace
+	// PDF hex strings can contain whitespace, which is skipped. An odd
+	// final digit is padded with 0.
+	i := 0
+	for ; i+1 < len(s); i += 2 {
+		// Pairs of digits, the common case
+		hi, lo := hexDigits[s[i]], hexDigits[s[i+1]]
+		if hi|lo > 0xF {
+			break
 		}
-		return r
-	}, s)
-
-	// Ensure even length, pad with 0 if not
-	if len(s)%2 != 0 {
-		s = append(s, '0')
+		out = append(out, hi<<4|lo)
 	}
-
-	var b bytes.Buffer
-	for i := 0; i < len(s); i += 2 {
-		hexByte := string(s[i : i+2])
-		val, err := strconv.ParseUint(hexByte, 16, 8)
-		if err != nil {
+	var hi byte
+	half := false
+	for ; i < len(s); i++ {
+		c := s[i]
+		if isWhitespace(c) {
+			continue
+		}
+		if byteClass[c]&classHexDigit == 0 {
 			// PDF spec says to ignore bad hex chars, but we'll be strict
-			return nil, fmt.Errorf("invalid hex byte '%s': %w", hexByte, err)
+			return nil, fmt.Errorf("%w: invalid hex digit %q at %d", ErrInvalidHexString, c, i+1)
+		}
+		if !half {
+			hi = hexValue(c)
+		} else {
+			out = append(out, hi<<4|hexValue(c))
 		}
-		b.WriteByte(byte(val))
+		half = !half
+	}
+	if half {
+		out = append(out, hi<<4)
 	}
 	// Note: This returns the raw bytes.
 	// The interpreter will need to handle encoding.
-	return b.Bytes(), nil
+	return out, nil
 }
 
-// pdfTokenSplit is a custom bufio.SplitFunc for PDF content streams.
-// This is a *major* simplification. A real implementation is much more complex.
+// pdfTokenSplit is a bufio.SplitFunc for PDF content streams, used to read
+// the contents of dictionaries. The parser itself tokenizes in place; see
+// Parser.scan.
 func pdfTokenSplit(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	start := 0
 	// Skip leading whitespace and comments
 	for start < len(data) {
-		r := rune(data[start])
-		if unicode.IsSpace(r) {
+		c := data[start]
+		if isWhitespace(c) {
 			start++
 			continue
 		}
 
-		if r == '%' {
+		if c == '%' {
 			// Skip comment
 			start++ // consume the '%'
 			for start < len(data) {
@@ -276,7 +656,19 @@ func pdfTokenSplit(data []byte, atEOF bool) (advance int, token []byte, err erro
 	if start == len(data) {
 		return start, nil, nil // Need more data
 	}
+	if end, ok := tokenEnd(data, start); ok {
+		return end, data[start:end], nil
+	}
+	if atEOF {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil // Need more data
+}
 
+// tokenEnd returns the end of the token starting at data[start], and
+// whether the token ends within data; if not, the end is len(data).
+// This is a *major* simplification. A real implementation is much more complex.
+func tokenEnd(data []byte, start int) (int, bool) {
 	pos := start
 	switch data[pos] {
 	case '(': // Literal String
@@ -295,7 +687,7 @@ func pdfTokenSplit(data []byte, atEOF bool) (advance int, token []byte, err erro
 				parenLevel--
 				if parenLevel == 0 {
 					pos++
-					return pos, data[start:pos], nil
+					return pos, true
 				}
 			}
 			pos++
@@ -314,7 +706,7 @@ func pdfTokenSplit(data []byte, atEOF bool) (advance int, token []byte, err erro
 						dictLevel--
 						pos += 2
 						if dictLevel == 0 {
-							return pos, data[start:pos], nil
+							return pos, true
 						}
 						continue
 					} else if data[pos] == '<' && data[pos+1] == '<' {
@@ -354,47 +746,88 @@ func pdfTokenSplit(data []byte, atEOF bool) (advance int, token []byte, err erro
 				b := data[pos]
 				if b == '>' {
 					pos++
-					return pos, data[start:pos], nil
+					return pos, true
 				}
 				// Allow whitespace and hex chars
-				if (b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F') || unicode.IsSpace(rune(b)) {
+				if byteClass[b]&(classHexDigit|classSpace) != 0 {
 					pos++
 				} else {
 					// Invalid char, treat as end of token
-					return pos, data[start:pos], nil
+					return pos, true
 				}
 			}
 		}
 	case '[': // Array Start
-		return pos + 1, data[start : pos+1], nil
+		return pos + 1, true
 	case ']': // Array End
-		return pos + 1, data[start : pos+1], nil
+		return pos + 1, true
 	case '/': // Name
 		pos++
 		for pos < len(data) {
-			if unicode.IsSpace(rune(data[pos])) || isDelimiter(data[pos]) {
-				return pos, data[start:pos], nil
+			if isTokenEnd(data[pos]) {
+				return pos, true
 			}
 			pos++
 		}
+	case ')', '>', '{', '}': // Stray delimiter
+		// Emit it as its own token so it is reported as malformed
+		// instead of stalling the tokenizer with an empty token.
+		return pos + 1, true
 	default: // Number or Operator
 		for pos < len(data) {
-			if unicode.IsSpace(rune(data[pos])) || isDelimiter(data[pos]) {
-				return pos, data[start:pos], nil
+			if isTokenEnd(data[pos]) {
+				return pos, true
 			}
 			pos++
 		}
 	}
 
-	if atEOF && len(data) > start {
+	return len(data), false
+}
+
+// splitInlineImageData returns the raw data of an inline image following
+// the ID operator, up to and including the EI operator. The data starts
+// after the single whitespace byte that ends ID and ends before the
+// whitespace preceding an EI that is followed by whitespace, a delimiter,
+// or the end of the stream.
+func splitInlineImageData(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) == 0 {
+		return 0, nil, nil
+	}
+	start := 0
+	if isWhitespace(data[0]) {
+		start = 1
+	}
+	for i := start; i+1 < len(data); i++ {
+		if data[i] != 'E' || data[i+1] != 'I' || (i > start && !isWhitespace(data[i-1])) {
+			continue
+		}
+		if i+2 == len(data) {
+			if !atEOF {
+				return 0, nil, nil // Need more data to see what follows EI
+			}
+			return i + 2, data[start : i+2], nil
+		}
+		if isWhitespace(data[i+2]) || isDelimiter(data[i+2]) {
+			return i + 2, data[start : i+2], nil
+		}
+	}
+	if atEOF {
+		// Unterminated image: take the rest of the stream
 		return len(data), data[start:], nil
 	}
-
-	return start, nil, nil // Need more data
+	return 0, nil, nil
 }
 
-func isDelimiter(b byte) bool {
-	return b == '(' || b == ')' || b == '<' || b == '>' ||
-		b == '[' || b == ']' || b == '{' || b == '}' ||
-		b == '/' || b == '%'
+// inlineImageData strips the trailing EI operator and the whitespace
+// before it from a token returned by splitInlineImageData, and copies the
+// data out of the window.
+func inlineImageData(token []byte) []byte {
+	if bytes.HasSuffix(token, []byte("EI")) {
+		token = token[:len(token)-2]
+		if n := len(token); n > 0 && isWhitespace(token[n-1]) {
+			token = token[:n-1]
+		}
+	}
+	return bytes.Clone(token)
 }
@@ -0,0 +1,87 @@
+package parser_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+
+	"github.com/apex-woot/pdf-stream-engine/internal/streamgen"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// seedStreams adds synthetic streams to the corpus of f: the default mix
+// and every pathological construct, for a few seeds.
+func seedStreams(f *testing.F) {
+	for seed := range uint64(4) {
+		f.Add(streamgen.Generate(streamgen.Config{Size: 2 << 10, Seed: seed}))
+		f.Add(streamgen.Generate(streamgen.Config{Size: 2 << 10, Seed: seed, Pathological: streamgen.Pathological{
+			HugeStrings:   seed == 0,
+			DeepArrays:    true,
+			UnbalancedOps: true,
+			GarbageTokens: true,
+			EscapeHeavy:   true,
+			InlineDicts:   true,
+		}}))
+	}
+}
+
+// FuzzParse checks that the parser terminates without panicking on any
+// input, reporting operations in stream order, and that tokenizing the
+// input in place gives what reading it in small pieces does.
+func FuzzParse(f *testing.F) {
+	seedStreams(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p := parser.NewParserBytes(data)
+		p.SetMaxArrayLength(1 << 12)
+		r := parser.NewParser(iotest.HalfReader(bytes.NewReader(data)))
+		r.SetMaxArrayLength(1 << 12)
+		last := int64(-1)
+		for {
+			op, err := p.Next()
+			rop, rerr := r.Next()
+			if !reflect.DeepEqual(op, rop) || !reflect.DeepEqual(err, rerr) {
+				t.Fatalf("in place: %+v, %v; from a reader: %+v, %v", op, err, rop, rerr)
+			}
+			if err != nil {
+				return
+			}
+			if op.Offset <= last || op.Offset >= int64(len(data)) {
+				t.Fatalf("operation %s at offset %d after %d, in %d bytes", op.Name, op.Offset, last, len(data))
+			}
+			last = op.Offset
+		}
+	})
+}
+
+// BenchmarkParse measures the parser's throughput on a large synthetic
+// content stream with the default operator mix, tokenized in place and
+// read through an io.Reader.
+func BenchmarkParse(b *testing.B) {
+	data := streamgen.Generate(streamgen.Config{Size: 4 << 20, Seed: 7})
+	parsers := []struct {
+		name string
+		new  func() *parser.Parser
+	}{
+		{"Bytes", func() *parser.Parser { return parser.NewParserBytes(data) }},
+		{"Reader", func() *parser.Parser { return parser.NewParser(bytes.NewReader(data)) }},
+	}
+	for _, pp := range parsers {
+		b.Run(pp.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				p := pp.new()
+				for {
+					if _, err := p.Next(); err != nil {
+						if err != io.EOF {
+							b.Fatal(err)
+						}
+						break
+					}
+				}
+			}
+		})
+	}
+}
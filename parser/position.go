@@ -0,0 +1,57 @@
+package parser
+
+import "fmt"
+
+// Position is a location in a content stream.
+type Position struct {
+	// Offset is the byte offset from the start of the stream.
+	Offset int64
+
+	// Line and Column are 1-based. Columns count bytes; CR, LF and CR LF
+	// each end a line.
+	Line, Column int
+}
+
+// String formats the position as "line L, column C (offset O)".
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d (offset %d)", p.Line, p.Column, p.Offset)
+}
+
+// lineTracker follows line breaks through the tokenized part of a stream.
+// The tokenizer reports the breaks in whitespace as it skips it, and
+// passes the tokens that may contain breaks (strings, dictionaries and
+// inline images) to advance.
+type lineTracker struct {
+	line      int   // current line, 0-based
+	lineStart int64 // offset of the first byte of the current line
+	crEnd     int64 // offset after the last CR, where an LF continues its break
+}
+
+// lineBreak records the CR or LF c at offset.
+func (t *lineTracker) lineBreak(c byte, offset int64) {
+	if c == '\n' && offset == t.crEnd && offset > 0 {
+		t.lineStart = offset + 1 // CR LF
+		return
+	}
+	t.line++
+	t.lineStart = offset + 1
+	if c == '\r' {
+		t.crEnd = offset + 1
+	}
+}
+
+// advance records the line breaks in b, which starts at offset in the
+// stream.
+func (t *lineTracker) advance(b []byte, offset int64) {
+	for i, c := range b {
+		if c == '\n' || c == '\r' {
+			t.lineBreak(c, offset+int64(i))
+		}
+	}
+}
+
+// position returns the position of offset, which must be on the current
+// line.
+func (t *lineTracker) position(offset int64) Position {
+	return Position{Offset: offset, Line: t.line + 1, Column: int(offset-t.lineStart) + 1}
+}
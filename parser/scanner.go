@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"errors"
+	"io"
+)
+
+// initialBufferSize is the starting size of a window's buffer when it
+// reads from an io.Reader.
+const initialBufferSize = 4 << 10
+
+// maxEmptyReads is how many reads returning no data and no error are
+// tolerated before giving up, as in bufio.Scanner.
+const maxEmptyReads = 100
+
+// errTokenTooLong is returned when a token exceeds maxTokenSize.
+var errTokenTooLong = errors.New("token too long")
+
+// window is the part of a content stream the parser tokenizes in place:
+// the whole stream for NewParserBytes, or a buffer refilled from a reader
+// for NewParser. Tokens are views into buf, valid until the next refill.
+//
+// A reader's buffer is filled completely before tokenizing resumes, and
+// grows as long as a token needs it, up to maxTokenSize if that is set, so
+// a token that spans many reads is rescanned only when the buffer grows,
+// keeping tokenization linear in the token size. Hex strings, inline
+// dictionaries and inline images can be arbitrarily large.
+type window struct {
+	r    io.Reader // nil if buf holds the whole stream
+	buf  []byte    // the data read; more is read into buf[len(buf):cap(buf)]
+	pos  int       // next byte to tokenize
+	base int64     // stream offset of buf[0]
+	eof  bool      // buf ends at the end of the stream
+	err  error     // read error, or errTokenTooLong
+
+	maxTokenSize int // 0 means no limit
+}
+
+// offset returns the stream offset of buf[i].
+func (w *window) offset(i int) int64 {
+	return w.base + int64(i)
+}
+
+// more reads more of the stream, keeping buf[pos:], and reports whether
+// tokenizing can go on. It returns false at the end of the stream and on
+// an error, which it records in err. Views of buf taken before the call
+// are invalid after it.
+func (w *window) more() bool {
+	if w.r == nil || w.eof || w.err != nil {
+		return false
+	}
+	if w.pos > 0 {
+		n := copy(w.buf, w.buf[w.pos:])
+		w.buf = w.buf[:n]
+		w.base += int64(w.pos)
+		w.pos = 0
+	}
+	if len(w.buf) == cap(w.buf) {
+		if w.maxTokenSize > 0 && len(w.buf) >= w.maxTokenSize {
+			w.err = errTokenTooLong
+			return false
+		}
+		size := max(2*cap(w.buf), initialBufferSize)
+		if w.maxTokenSize > 0 {
+			size = min(size, w.maxTokenSize)
+		}
+		buf := make([]byte, len(w.buf), size)
+		copy(buf, w.buf)
+		w.buf = buf
+	}
+	for empty := 0; len(w.buf) < cap(w.buf); {
+		n, err := w.r.Read(w.buf[len(w.buf):cap(w.buf)])
+		w.buf = w.buf[:len(w.buf)+n]
+		if n == 0 && err == nil {
+			if empty++; empty >= maxEmptyReads {
+				w.err = io.ErrNoProgress
+				return false
+			}
+		}
+		if err == io.EOF {
+			w.eof = true
+			break
+		}
+		if err != nil {
+			w.err = err
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// Serialize writes operations back as a content stream, one operation per
+// line, such that parsing the result yields the same operators and
+// operands. It is the inverse of Parse for rewriting streams.
+//
+// Operands are typed as Parse returns them: float64 (or int) numbers,
+// bool, nil for null, []byte for hex strings, []any arrays and Dict
+// dictionaries. Names and literal strings are both Go strings, so a
+// string is written as a literal string where the operator takes one
+// (the operands of Tj, ' and ", and the strings in a TJ array) and in the
+// dictionary entries holding text (such as /ActualText), and as a name
+// everywhere else.
+//
+// Inline images are written as the parser splits them: BI, then ID with
+// the image dictionary's keys and values as operands, then EI with the
+// image data as its only operand. As in the PDF syntax, data holding
+// whitespace followed by EI cannot be read back.
+func Serialize(ops []Operation) ([]byte, error) {
+	var b bytes.Buffer
+	for i, op := range ops {
+		if err := writeOperation(&b, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op.Name, err)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func writeOperation(b *bytes.Buffer, op Operation) error {
+	if !isOperator([]byte(op.Name)) || keywordOperator(op.Name) {
+		return fmt.Errorf("%w: invalid operator name %q", ErrUnserializable, op.Name)
+	}
+	if op.Name == "EI" {
+		return writeInlineImageData(b, op.Operands)
+	}
+	if err := writeOperands(b, op); err != nil {
+		return err
+	}
+	b.WriteString(op.Name)
+	b.WriteByte('\n')
+	return nil
+}
+
+// writeOperands writes the operands of op, each followed by a space.
+func writeOperands(b *bytes.Buffer, op Operation) error {
+	textOperand := func(int) bool { return false }
+	switch op.Name {
+	case "Tj", "'", "\"":
+		// The string is the last operand (" takes two spacings first)
+		textOperand = func(i int) bool { return i == len(op.Operands)-1 }
+	case "TJ":
+		textOperand = func(int) bool { return true }
+	}
+	for i, operand := range op.Operands {
+		if err := writeOperand(b, operand, textOperand(i)); err != nil {
+			return err
+		}
+		b.WriteByte(' ')
+	}
+	return nil
+}
+
+// keywordOperator reports whether name is one of the keywords the parser
+// reads as operands.
+func keywordOperator(name string) bool {
+	_, ok := keywordOperand([]byte(name))
+	return ok
+}
+
+// writeInlineImageData writes the data operand of EI, which follows the
+// newline Serialize writes after ID.
+func writeInlineImageData(b *bytes.Buffer, operands []any) error {
+	if len(operands) != 1 {
+		return fmt.Errorf("%w: EI takes the image data as its only operand", ErrUnserializable)
+	}
+	data, ok := operands[0].([]byte)
+	if !ok {
+		return fmt.Errorf("%w: EI data of type %T", ErrUnserializable, operands[0])
+	}
+	b.Write(data)
+	b.WriteString("\nEI\n")
+	return nil
+}
+
+// textStringKeys are the dictionary keys whose string values are text
+// strings rather than names, in marked-content property lists (ISO
+// 32000-1, 14.9).
+var textStringKeys = []string{"ActualText", "Alt", "E", "Lang", "T", "TU", "Contents"}
+
+// writeOperand writes operand, writing strings as literal strings if text
+// is set and as names otherwise. Strings in arrays inherit text, as TJ
+// arrays hold text and other arrays names.
+func writeOperand(b *bytes.Buffer, operand any, text bool) error {
+	switch v := operand.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int:
+		b.WriteString(strconv.Itoa(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: number %v", ErrUnserializable, v)
+		}
+		b.WriteString(formatNumber(v))
+	case string:
+		if text {
+			writeLiteralString(b, v)
+		} else {
+			writeName(b, v)
+		}
+	case []byte:
+		writeHexString(b, v)
+	case []any:
+		b.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			if err := writeOperand(b, elem, text); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case Dict:
+		// Sort the keys so that the output is deterministic
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		b.WriteString("<<")
+		for _, key := range keys {
+			writeName(b, key)
+			b.WriteByte(' ')
+			if err := writeOperand(b, v[key], slices.Contains(textStringKeys, key)); err != nil {
+				return err
+			}
+		}
+		b.WriteString(">>")
+	default:
+		return fmt.Errorf("%w: operand of type %T", ErrUnserializable, operand)
+	}
+	return nil
+}
+
+// formatNumber formats f in the PDF number syntax, which has no exponent
+// form: integers without a decimal point, and other numbers with as many
+// digits as needed to read back the same float64.
+func formatNumber(f float64) string {
+	if f == 0 {
+		return "0" // Also for -0
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// writeLiteralString writes s as a literal string, escaping backslashes,
+// parentheses, and bytes outside printable ASCII (as three-digit octal
+// escapes, so that a following digit is not read as part of them).
+func writeLiteralString(b *bytes.Buffer, s string) {
+	b.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' || c == '(' || c == ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(b, `\%03o`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+}
+
+func writeHexString(b *bytes.Buffer, data []byte) {
+	const digits = "0123456789ABCDEF"
+	b.WriteByte('<')
+	for _, c := range data {
+		b.WriteByte(digits[c>>4])
+		b.WriteByte(digits[c&0xF])
+	}
+	b.WriteByte('>')
+}
+
+// writeName writes name as a name token, with #xx escapes for the bytes
+// that cannot appear in one literally (ISO 32000-1, 7.3.5).
+func writeName(b *bytes.Buffer, name string) {
+	b.WriteByte('/')
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < 0x21 || c > 0x7e || c == '#' || isDelimiter(c) {
+			fmt.Fprintf(b, "#%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+}
@@ -0,0 +1,234 @@
+// Package redact removes text from content streams: the glyphs to redact
+// are taken out of the strings the text-showing operators show, rather
+// than covered, so that the text can no longer be extracted or copied.
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// Options selects the text to redact. A glyph is redacted if any of the
+// regions or patterns selects it.
+type Options struct {
+	// Regions are rectangles in device space (the coordinates of
+	// interpreter.TextRun): glyphs whose box center lies in one are
+	// redacted.
+	Regions []geom.Rect
+
+	// Patterns are matched against the text each Tj, TJ, ' or "
+	// operation shows, and the glyphs of the matches redacted, including
+	// glyphs only partly in a match, such as ligatures. Text split across
+	// operations is not matched; use Regions for it.
+	Patterns []*regexp.Regexp
+
+	// Collapse closes up the space of the redacted glyphs, moving the
+	// text after them on the line. By default a TJ adjustment takes
+	// their place, so they leave a blank of their width.
+	Collapse bool
+}
+
+// Removal is text removed from the stream: consecutive redacted glyphs of
+// one operation.
+type Removal struct {
+	// Op is the index of the text-showing operation in the stream, and
+	// Position its position.
+	Op       int
+	Position parser.Position
+
+	// Text is the removed text, and Bounds the approximate box of its
+	// glyphs in device space.
+	Text   string
+	Bounds geom.Rect
+}
+
+// Result is the outcome of Apply.
+type Result struct {
+	// Content is the redacted content stream.
+	Content []byte
+
+	// Removed is what was removed, in stream order.
+	Removed []Removal
+}
+
+// Apply returns stream with the glyphs opts selects removed, the fonts of
+// registry decoding and positioning them. Operations losing glyphs are
+// written as TJ: ' and " become the operators they stand for followed by
+// TJ.
+//
+// Only the text of the stream itself is redacted: form XObjects it paints
+// are separate streams, and images are left as they are.
+func Apply(stream []byte, registry *font.FontRegistry, opts Options) (Result, error) {
+	ops, err := parser.NewParserBytes(stream).Parse()
+	if err != nil {
+		return Result{}, fmt.Errorf("parsing stream: %w", err)
+	}
+
+	// Position the glyphs; Glyph.Op indexes ops, parsed the same way
+	glyphs := make(map[int][]interpreter.Glyph)
+	interp := interpreter.NewInterpreterWithOptions(registry, interpreter.Options{
+		SilenceWarnings: true,
+		GlyphFunc: func(g interpreter.Glyph) {
+			glyphs[g.Op] = append(glyphs[g.Op], g)
+		},
+	})
+	if err := interp.ProcessStream(bytes.NewReader(stream)); err != nil {
+		return Result{}, fmt.Errorf("interpreting stream: %w", err)
+	}
+
+	var result Result
+	out := make([]parser.Operation, 0, len(ops))
+	for i, op := range ops {
+		redacted := opts.selected(glyphs[i])
+		if !slices.Contains(redacted, true) {
+			out = append(out, op)
+			continue
+		}
+		out = append(out, redactOperation(op, glyphs[i], redacted, opts.Collapse)...)
+		result.Removed = append(result.Removed, removals(i, op.Position, glyphs[i], redacted)...)
+	}
+	result.Content, err = parser.Serialize(out)
+	if err != nil {
+		return Result{}, fmt.Errorf("writing stream: %w", err)
+	}
+	return result, nil
+}
+
+// selected reports which of the glyphs of one operation opts redacts.
+func (opts Options) selected(glyphs []interpreter.Glyph) []bool {
+	redacted := make([]bool, len(glyphs))
+	for i, g := range glyphs {
+		center := geom.Point{X: (g.Bounds.X0 + g.Bounds.X1) / 2, Y: (g.Bounds.Y0 + g.Bounds.Y1) / 2}
+		redacted[i] = slices.ContainsFunc(opts.Regions, func(r geom.Rect) bool { return r.Contains(center) })
+	}
+	if len(opts.Patterns) == 0 {
+		return redacted
+	}
+
+	var text strings.Builder
+	starts := make([]int, len(glyphs)+1) // starts[i] is the offset of glyph i's text
+	for i, g := range glyphs {
+		starts[i] = text.Len()
+		text.WriteString(g.Text)
+	}
+	starts[len(glyphs)] = text.Len()
+	for _, re := range opts.Patterns {
+		for _, m := range re.FindAllStringIndex(text.String(), -1) {
+			for i := range glyphs {
+				start, end := starts[i], starts[i+1]
+				if start < m[1] && end > m[0] || start == end && start >= m[0] && start < m[1] {
+					redacted[i] = true
+				}
+			}
+		}
+	}
+	return redacted
+}
+
+// redactOperation returns the operations replacing op, a text-showing
+// operation showing glyphs, with the redacted glyphs removed.
+func redactOperation(op parser.Operation, glyphs []interpreter.Glyph, redacted []bool, collapse bool) []parser.Operation {
+	last := len(op.Operands) - 1
+	values := []any{op.Operands[last]}
+	if op.Name == "TJ" {
+		values, _ = op.Operands[last].([]any)
+	}
+
+	var array []any
+	addAdjustment := func(n float64) {
+		n = math.Round(n*1000) / 1000 // Keep the sums of glyph widths short
+		if k := len(array) - 1; k >= 0 {
+			if prev, ok := array[k].(float64); ok {
+				array[k] = prev + n
+				return
+			}
+		}
+		array = append(array, n)
+	}
+	g := 0
+	for i, v := range values {
+		var raw []byte
+		switch v := v.(type) {
+		case string:
+			raw = []byte(v)
+		case []byte:
+			raw = v
+		case float64:
+			addAdjustment(v)
+			continue
+		default:
+			array = append(array, v)
+			continue
+		}
+		// Split the string around its redacted glyphs, keeping its type
+		_, isString := v.(string)
+		var kept []byte
+		flush := func() {
+			if len(kept) == 0 {
+				return
+			}
+			if isString {
+				array = append(array, string(kept))
+			} else {
+				array = append(array, kept)
+			}
+			kept = nil
+		}
+		for ; g < len(glyphs) && glyphs[g].Element == i; g++ {
+			code := raw[glyphs[g].Offset : glyphs[g].Offset+glyphs[g].Length]
+			if !redacted[g] {
+				kept = append(kept, code...)
+				continue
+			}
+			flush()
+			if !collapse {
+				addAdjustment(glyphs[g].Adjustment)
+			}
+		}
+		flush()
+	}
+
+	show := parser.Operation{Name: "TJ", Operands: []any{array}, Position: op.Position}
+	switch op.Name {
+	case "'":
+		return []parser.Operation{{Name: "T*", Position: op.Position}, show}
+	case "\"":
+		if len(op.Operands) == 3 {
+			return []parser.Operation{
+				{Name: "Tw", Operands: op.Operands[:1], Position: op.Position},
+				{Name: "Tc", Operands: op.Operands[1:2], Position: op.Position},
+				{Name: "T*", Position: op.Position},
+				show,
+			}
+		}
+	}
+	return []parser.Operation{show}
+}
+
+// removals reports the runs of consecutive redacted glyphs of operation
+// op.
+func removals(op int, pos parser.Position, glyphs []interpreter.Glyph, redacted []bool) []Removal {
+	var out []Removal
+	for i, g := range glyphs {
+		if !redacted[i] {
+			continue
+		}
+		if i > 0 && redacted[i-1] {
+			r := &out[len(out)-1]
+			r.Text += g.Text
+			r.Bounds = r.Bounds.Union(g.Bounds)
+			continue
+		}
+		out = append(out, Removal{Op: op, Position: pos, Text: g.Text, Bounds: g.Bounds})
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package rewrite
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// ErrUnencodable reports text that a font has no character code for.
+var ErrUnencodable = errors.New("text not encodable in font")
+
+// Encoder encodes text as character codes of a font: the inverse of
+// font.Font.DecodeShowString, for the codes whose decoded text is known.
+type Encoder struct {
+	font   *font.Font
+	codes  map[string][]byte // decoded text to code
+	maxLen int               // longest text in codes, in runes
+}
+
+// NewEncoder returns an encoder for f. It knows the codes of the
+// ToUnicode CMap and, for simple fonts, the 256 single-byte codes, each
+// as it decodes: text encoded with it extracts as the same text. Where
+// several codes decode to the same text, the shortest code with the
+// lowest value is used, preferring codes the font gives a nonzero width,
+// as subset fonts leave the codes of glyphs they do not embed at zero.
+func NewEncoder(f *font.Font) *Encoder {
+	e := &Encoder{font: f, codes: make(map[string][]byte)}
+	zeroWidth := make(map[string]bool)
+	add := func(code []byte) {
+		text, glyphs, _ := f.DecodeShowString(code)
+		if len(glyphs) != 1 || glyphs[0].Length != len(code) || text == "" || text == "\uFFFD" {
+			return
+		}
+		zero := glyphs[0].HasWidth && glyphs[0].Width == 0
+		if _, seen := e.codes[text]; seen && (zero || !zeroWidth[text]) {
+			return
+		}
+		e.codes[text] = code
+		zeroWidth[text] = zero
+		e.maxLen = max(e.maxLen, utf8.RuneCountInString(text))
+	}
+	if f.ToUnicode != nil {
+		for code := range f.ToUnicode.Mappings() {
+			add(code)
+		}
+	}
+	if f.CodeLength() == 1 {
+		for c := range 256 {
+			add([]byte{byte(c)})
+		}
+	}
+	return e
+}
+
+// Encode returns text as a show-string in the encoder's font, taking the
+// longest text with a code at each position, so that a ligature glyph is
+// used where the font has one. It returns an error wrapping
+// ErrUnencodable if a character has no code.
+func (e *Encoder) Encode(text string) ([]byte, error) {
+	var out []byte
+	for text != "" {
+		code, n := e.next(text)
+		if n == 0 {
+			r, _ := utf8.DecodeRuneInString(text)
+			return nil, fmt.Errorf("%w: %q in %s", ErrUnencodable, r, e.font.Name)
+		}
+		out = append(out, code...)
+		text = text[n:]
+	}
+	return out, nil
+}
+
+// next returns the code of the longest prefix of text that has one, and
+// the prefix length in bytes; n is 0 if none has.
+func (e *Encoder) next(text string) (code []byte, n int) {
+	prefix := text
+	if runes := utf8.RuneCountInString(text); runes > e.maxLen {
+		end := 0
+		for range e.maxLen {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		prefix = text[:end]
+	}
+	for prefix != "" {
+		if code, ok := e.codes[prefix]; ok {
+			return code, len(prefix)
+		}
+		_, size := utf8.DecodeLastRuneInString(prefix)
+		prefix = prefix[:len(prefix)-size]
+	}
+	return nil, 0
+}
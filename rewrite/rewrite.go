@@ -0,0 +1,330 @@
+// Package rewrite replaces text in content streams: the strings shown by
+// the text-showing operators are decoded with their fonts, matched
+// against rules, and re-encoded in the same fonts, so that documents can
+// be generated from existing PDFs used as templates.
+package rewrite
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// Rule replaces the text one operation shows.
+type Rule struct {
+	// Match is the text to replace, as extracted.
+	Match string
+
+	// Regexp, if set, is matched instead of Match, and Replacement may
+	// refer to its submatches as in regexp.Regexp.Expand ($1, ${name}).
+	Regexp *regexp.Regexp
+
+	// Replacement is the text that replaces each match.
+	Replacement string
+}
+
+// Replacement is one replacement made.
+type Replacement struct {
+	// Op is the index of the text-showing operation in the stream, and
+	// Position its position.
+	Op       int
+	Position parser.Position
+
+	// Font is the resource name of the font the text is shown in.
+	Font string
+
+	// Old is the matched text and New the text that replaced it.
+	Old, New string
+}
+
+// Result is the outcome of Rewrite.
+type Result struct {
+	// Content is the rewritten content stream.
+	Content []byte
+
+	// Replacements are the replacements made, in stream order.
+	Replacements []Replacement
+}
+
+// Rewrite returns stream with the text matching rules replaced, the fonts
+// of registry decoding and encoding the shown strings (see NewEncoder).
+//
+// Matches are found in the text each Tj, TJ, ' or " operation shows,
+// including across the strings of a TJ array, whose spacing adjustments
+// inside a match are dropped; text split across operations is not
+// matched. Matches that start or end inside a glyph (e.g. in a
+// ligature) are skipped, as are operations in fonts the registry does not
+// hold. Where matches overlap, the one starting first wins, and of those
+// starting together the one of the earlier rule. The replacement text
+// takes the place of the matched glyphs; the rest of the line is not
+// reflowed.
+//
+// It returns an error wrapping ErrUnencodable if a replacement holds a
+// character that the font of the match has no code for.
+func Rewrite(stream []byte, registry *font.FontRegistry, rules []Rule) (Result, error) {
+	ops, err := parser.NewParserBytes(stream).Parse()
+	if err != nil {
+		return Result{}, fmt.Errorf("parsing stream: %w", err)
+	}
+	ops, replacements, err := RewriteOperations(ops, registry, rules)
+	if err != nil {
+		return Result{}, err
+	}
+	content, err := parser.Serialize(ops)
+	if err != nil {
+		return Result{}, fmt.Errorf("writing stream: %w", err)
+	}
+	return Result{Content: content, Replacements: replacements}, nil
+}
+
+// RewriteOperations is like Rewrite for parsed operations. It returns new
+// operations, leaving ops and their operands unchanged.
+func RewriteOperations(ops []parser.Operation, registry *font.FontRegistry, rules []Rule) ([]parser.Operation, []Replacement, error) {
+	out := make([]parser.Operation, len(ops))
+	var replacements []Replacement
+	var fontName string
+	var fontStack []string // for q/Q
+	encoders := make(map[*font.Font]*Encoder)
+	for i, op := range ops {
+		out[i] = op
+		switch op.Name {
+		case "q":
+			fontStack = append(fontStack, fontName)
+		case "Q":
+			if n := len(fontStack); n > 0 {
+				fontName, fontStack = fontStack[n-1], fontStack[:n-1]
+			}
+		case "Tf":
+			if len(op.Operands) > 0 {
+				fontName, _ = op.Operands[0].(string)
+			}
+		case "Tj", "TJ", "'", "\"":
+			f, ok := registry.Lookup(fontName)
+			if !ok {
+				continue
+			}
+			shown := newShownText(op, f)
+			if shown == nil {
+				continue
+			}
+			edits := shown.edits(rules)
+			if len(edits) == 0 {
+				continue
+			}
+			if encoders[f] == nil {
+				encoders[f] = NewEncoder(f)
+			}
+			rewritten, err := shown.apply(edits, encoders[f])
+			if err != nil {
+				return nil, nil, fmt.Errorf("operation %d at %s: %w", i, op.Position, err)
+			}
+			out[i] = rewritten
+			for _, e := range edits {
+				replacements = append(replacements, Replacement{
+					Op:       i,
+					Position: op.Position,
+					Font:     fontName,
+					Old:      shown.text[e.start:e.end],
+					New:      e.replacement,
+				})
+			}
+		}
+	}
+	return out, replacements, nil
+}
+
+// shownText is the text a text-showing operation shows, glyph by glyph.
+type shownText struct {
+	op     parser.Operation
+	array  bool  // the strings are the elements of a TJ array
+	values []any // the strings, with the TJ adjustments between them
+	glyphs []glyph
+	text   string
+}
+
+// glyph is a character code of a shown string.
+type glyph struct {
+	value      int    // index of the string in values
+	code       []byte // the code's bytes in the string
+	start, end int    // offsets of the code's text in shownText.text
+}
+
+// newShownText decodes the strings op shows in f, or returns nil if its
+// operands are malformed.
+func newShownText(op parser.Operation, f *font.Font) *shownText {
+	if len(op.Operands) == 0 {
+		return nil
+	}
+	s := &shownText{op: op}
+	switch last := op.Operands[len(op.Operands)-1]; {
+	case op.Name != "TJ":
+		s.values = []any{last}
+	default:
+		arr, ok := last.([]any)
+		if !ok {
+			return nil
+		}
+		s.array, s.values = true, arr
+	}
+	var text strings.Builder
+	for i, v := range s.values {
+		raw, ok := showString(v)
+		if !ok {
+			continue
+		}
+		_, glyphs, _ := f.DecodeShowString(raw)
+		for _, g := range glyphs {
+			start := text.Len()
+			text.WriteString(g.Text)
+			s.glyphs = append(s.glyphs, glyph{value: i, code: raw[g.Offset : g.Offset+g.Length], start: start, end: text.Len()})
+		}
+	}
+	if len(s.glyphs) == 0 {
+		return nil
+	}
+	s.text = text.String()
+	return s
+}
+
+// showString returns the bytes of a string operand.
+func showString(v any) ([]byte, bool) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), true
+	case []byte:
+		return v, true
+	}
+	return nil, false
+}
+
+// edit replaces text[start:end].
+type edit struct {
+	start, end  int
+	replacement string
+	rule        int
+}
+
+// edits returns the matches of rules in the text, in text order, without
+// overlaps and only where they fall on glyph boundaries.
+func (s *shownText) edits(rules []Rule) []edit {
+	var edits []edit
+	for i, rule := range rules {
+		switch {
+		case rule.Regexp != nil:
+			for _, m := range rule.Regexp.FindAllStringSubmatchIndex(s.text, -1) {
+				replacement := rule.Regexp.ExpandString(nil, rule.Replacement, s.text, m)
+				edits = append(edits, edit{start: m[0], end: m[1], replacement: string(replacement), rule: i})
+			}
+		case rule.Match != "":
+			for offset := 0; ; {
+				j := strings.Index(s.text[offset:], rule.Match)
+				if j < 0 {
+					break
+				}
+				start := offset + j
+				edits = append(edits, edit{start: start, end: start + len(rule.Match), replacement: rule.Replacement, rule: i})
+				offset = start + len(rule.Match)
+			}
+		}
+	}
+	slices.SortFunc(edits, func(a, b edit) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		return a.rule - b.rule
+	})
+
+	boundary := make(map[int]bool, len(s.glyphs)+1)
+	for _, g := range s.glyphs {
+		boundary[g.start] = true
+	}
+	boundary[len(s.text)] = true
+	kept := edits[:0]
+	end := 0
+	for _, e := range edits {
+		if e.start == e.end || e.start < end || !boundary[e.start] || !boundary[e.end] {
+			continue
+		}
+		kept = append(kept, e)
+		end = e.end
+	}
+	return kept
+}
+
+// apply returns the operation with the edits made, the replacements
+// encoded with enc into the string holding the first matched glyph.
+func (s *shownText) apply(edits []edit, enc *Encoder) (parser.Operation, error) {
+	// editOf[i] is the edit covering glyph i, or -1
+	editOf := make([]int, len(s.glyphs))
+	k := 0
+	for i, g := range s.glyphs {
+		for k < len(edits) && edits[k].end <= g.start {
+			k++
+		}
+		editOf[i] = -1
+		if k < len(edits) && edits[k].start <= g.start && g.start < edits[k].end {
+			editOf[i] = k
+		}
+	}
+
+	strs := make([][]byte, len(s.values))
+	emitted := make([]bool, len(edits))
+	for i, g := range s.glyphs {
+		e := editOf[i]
+		if e < 0 {
+			strs[g.value] = append(strs[g.value], g.code...)
+			continue
+		}
+		if !emitted[e] {
+			code, err := enc.Encode(edits[e].replacement)
+			if err != nil {
+				return parser.Operation{}, err
+			}
+			strs[g.value] = append(strs[g.value], code...)
+			emitted[e] = true
+		}
+	}
+
+	// The values keep their string type; adjustments between two glyphs
+	// of the same match and strings the edits emptied are dropped
+	values := make([]any, 0, len(s.values))
+	for i, v := range s.values {
+		raw, ok := showString(v)
+		switch {
+		case !ok:
+			if !s.array || !s.insideEdit(i, editOf) {
+				values = append(values, v)
+			}
+		case len(strs[i]) == 0 && len(raw) > 0 && s.array:
+		default:
+			if _, isString := v.(string); isString {
+				values = append(values, string(strs[i]))
+			} else {
+				values = append(values, strs[i])
+			}
+		}
+	}
+
+	op := s.op
+	op.Operands = slices.Clone(op.Operands)
+	if s.array {
+		op.Operands[len(op.Operands)-1] = values
+	} else {
+		op.Operands[len(op.Operands)-1] = values[0]
+	}
+	return op, nil
+}
+
+// insideEdit reports whether values[i] lies between two glyphs of the
+// same edit.
+func (s *shownText) insideEdit(i int, editOf []int) bool {
+	next := slices.IndexFunc(s.glyphs, func(g glyph) bool { return g.value > i })
+	if next <= 0 {
+		return false
+	}
+	return editOf[next-1] >= 0 && editOf[next-1] == editOf[next]
+}
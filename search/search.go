@@ -0,0 +1,306 @@
+// Package search finds text in extraction results and locates the hits on
+// the page, for highlighting them in a viewer.
+package search
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/layout"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+// Options tunes Find. The zero value matches case-insensitively anywhere
+// in words and returns all matches.
+type Options struct {
+	// CaseSensitive matches letter case exactly.
+	CaseSensitive bool
+
+	// WholeWord only matches where the query is not directly preceded or
+	// followed by a letter or digit.
+	WholeWord bool
+
+	// MaxMatches stops the search after that many matches. Zero means no
+	// limit.
+	MaxMatches int
+}
+
+// Match is one occurrence of the query.
+type Match struct {
+	// Page is the 1-based page number.
+	Page int
+
+	// Start and End are the rune offsets of the match in the page's
+	// text as PageText returns it, End exclusive.
+	Start, End int
+
+	// Text is the matched text, with the line breaks it spans.
+	Text string
+
+	// Rects are the boxes of the matched characters in page space, one
+	// per line the match spans. Character boxes divide each run's box
+	// evenly between its characters, as runs do not record glyph
+	// widths, so they are approximate for proportional fonts.
+	Rects []geom.Rect
+
+	// Runs are the parts of the runs the match covers, in text order,
+	// which lead back to the text-showing operators in the content
+	// stream through TextRun.Source.
+	Runs []RunMatch
+}
+
+// RunMatch is the part of a run that a match covers.
+type RunMatch struct {
+	// Run is the run, whose Source is the position (with the byte
+	// offset) of the operator that showed it.
+	Run interpreter.TextRun
+
+	// Start and End are the rune offsets of the covered part in
+	// Run.Text, End exclusive.
+	Start, End int
+
+	// Bounds is the box of the covered characters, approximate like
+	// Match.Rects.
+	Bounds geom.Rect
+}
+
+// Find returns the matches of query in the pages of doc, in page and
+// text order. Whitespace in the query matches any run of whitespace in
+// the text, including line breaks, so phrases broken across lines are
+// found; text is searched line by line in layout order (see PageText).
+// Matches do not overlap.
+func Find(doc streamengine.DocumentText, query string, opts Options) []Match {
+	needle := []rune(strings.Join(strings.Fields(string(normalize([]rune(query), opts.CaseSensitive))), " "))
+	if len(needle) == 0 {
+		return nil
+	}
+	return findAll(doc, opts, func(p *pageIndex) []Match { return p.find(needle, opts) })
+}
+
+// FindRegexp is like Find for the matches of re in each page's text (see
+// PageText), which holds line breaks as "\n": \s matches them, so
+// patterns can span lines. Options.CaseSensitive does not apply; use the
+// (?i) flag instead. Empty matches are skipped.
+func FindRegexp(doc streamengine.DocumentText, re *regexp.Regexp, opts Options) []Match {
+	return findAll(doc, opts, func(p *pageIndex) []Match { return p.findRegexp(re, opts) })
+}
+
+// findAll collects the matches that find returns for the pages of doc.
+func findAll(doc streamengine.DocumentText, opts Options, find func(p *pageIndex) []Match) []Match {
+	var matches []Match
+	for _, result := range doc.Pages {
+		for _, m := range find(newPageIndex(result.Runs)) {
+			if opts.MaxMatches > 0 && len(matches) == opts.MaxMatches {
+				return matches
+			}
+			m.Page = result.Number
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// PageText returns the text that Find searches and its offsets refer to:
+// the runs assembled into lines with the layout package's defaults, one
+// line per row.
+func PageText(result streamengine.PageResult) string {
+	return layout.Text(layout.AssembleLines(result.Runs, layout.LineOptions{}))
+}
+
+// pageIndex holds a page's text with the position and run of each
+// character.
+type pageIndex struct {
+	text  []rune
+	boxes []geom.Rect // boxes[i] is the box of text[i]
+	lines []int       // lines[i] is the line of text[i]
+
+	runs     []interpreter.TextRun // in text order
+	runOf    []int                 // runOf[i] is the run of text[i], or -1 between runs
+	runStart []int                 // runStart[i] is the offset of runs[i] in text
+}
+
+func newPageIndex(runs []interpreter.TextRun) *pageIndex {
+	p := &pageIndex{}
+	for i, line := range layout.AssembleLines(runs, layout.LineOptions{}) {
+		if i > 0 {
+			p.add('\n', geom.Rect{}, i-1, -1)
+		}
+		text := line.Text
+		var prev *interpreter.TextRun
+		for j := range line.Runs {
+			run := &line.Runs[j]
+			// Line.Text is the run texts with spaces inserted at gaps
+			if !strings.HasPrefix(text, run.Text) && strings.HasPrefix(text, " ") {
+				gap := run.Bounds()
+				if prev != nil {
+					gap = geom.Rect{X0: prev.EndX(), Y0: gap.Y0, X1: run.X, Y1: gap.Y1}
+				}
+				p.add(' ', gap, i, -1)
+				text = text[1:]
+			}
+			p.addRun(*run, i)
+			text = strings.TrimPrefix(text, run.Text)
+			prev = run
+		}
+	}
+	return p
+}
+
+func (p *pageIndex) add(r rune, box geom.Rect, line, run int) {
+	p.text = append(p.text, r)
+	p.boxes = append(p.boxes, box)
+	p.lines = append(p.lines, line)
+	p.runOf = append(p.runOf, run)
+}
+
+// addRun adds the characters of run, dividing its box between them.
+func (p *pageIndex) addRun(run interpreter.TextRun, line int) {
+	index := len(p.runs)
+	p.runs = append(p.runs, run)
+	p.runStart = append(p.runStart, len(p.text))
+	box := run.Bounds()
+	n := float64(utf8.RuneCountInString(run.Text))
+	i := 0.0
+	for _, r := range run.Text {
+		b := box
+		if run.Vertical {
+			b.Y1 = box.Y1 - box.Height()*i/n
+			b.Y0 = box.Y1 - box.Height()*(i+1)/n
+		} else {
+			b.X0 = box.X0 + box.Width()*i/n
+			b.X1 = box.X0 + box.Width()*(i+1)/n
+		}
+		p.add(r, b, line, index)
+		i++
+	}
+}
+
+// find returns the matches of needle, which is normalized already.
+func (p *pageIndex) find(needle []rune, opts Options) []Match {
+	// Search a normalized copy in which every run of whitespace is a
+	// single space, mapping its characters back to the text
+	var hay []rune
+	var origin []int
+	folded := normalize(p.text, opts.CaseSensitive)
+	for i, r := range folded {
+		if r == ' ' && len(hay) > 0 && hay[len(hay)-1] == ' ' {
+			continue
+		}
+		hay = append(hay, r)
+		origin = append(origin, i)
+	}
+
+	var matches []Match
+	for i := 0; i+len(needle) <= len(hay); {
+		if !hasPrefix(hay[i:], needle) || opts.WholeWord && !p.wordBounded(origin[i], origin[i+len(needle)-1]+1) {
+			i++
+			continue
+		}
+		matches = append(matches, p.match(origin[i], origin[i+len(needle)-1]+1))
+		i += len(needle)
+	}
+	return matches
+}
+
+// findRegexp returns the matches of re.
+func (p *pageIndex) findRegexp(re *regexp.Regexp, opts Options) []Match {
+	text := string(p.text)
+	// runeOffset[b] is the rune offset of byte offset b, for the offsets
+	// where runes start, which are the only ones matches start or end at
+	runeOffset := make([]int, len(text)+1)
+	n := 0
+	for b := range text {
+		runeOffset[b] = n
+		n++
+	}
+	runeOffset[len(text)] = n
+
+	var matches []Match
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		start, end := runeOffset[loc[0]], runeOffset[loc[1]]
+		if start == end || opts.WholeWord && !p.wordBounded(start, end) {
+			continue
+		}
+		matches = append(matches, p.match(start, end))
+	}
+	return matches
+}
+
+// match returns the match of text[start:end].
+func (p *pageIndex) match(start, end int) Match {
+	m := Match{Start: start, End: end, Text: string(p.text[start:end]), Rects: p.rects(start, end)}
+	last := -1
+	for i := start; i < end; i++ {
+		run := p.runOf[i]
+		switch {
+		case run < 0:
+			continue
+		case run == last:
+			covered := &m.Runs[len(m.Runs)-1]
+			covered.End++
+			covered.Bounds = covered.Bounds.Union(p.boxes[i])
+		default:
+			offset := i - p.runStart[run]
+			m.Runs = append(m.Runs, RunMatch{Run: p.runs[run], Start: offset, End: offset + 1, Bounds: p.boxes[i]})
+		}
+		last = run
+	}
+	return m
+}
+
+// wordBounded reports whether text[start:end] is neither preceded nor
+// followed by a letter or digit.
+func (p *pageIndex) wordBounded(start, end int) bool {
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	return (start == 0 || !isWord(p.text[start-1])) && (end == len(p.text) || !isWord(p.text[end]))
+}
+
+// rects returns the boxes of text[start:end], united per line.
+func (p *pageIndex) rects(start, end int) []geom.Rect {
+	var rects []geom.Rect
+	line := -1
+	for i := start; i < end; i++ {
+		if p.text[i] == '\n' {
+			continue
+		}
+		if p.lines[i] != line {
+			rects = append(rects, p.boxes[i])
+			line = p.lines[i]
+			continue
+		}
+		rects[len(rects)-1] = rects[len(rects)-1].Union(p.boxes[i])
+	}
+	return rects
+}
+
+// normalize maps whitespace to spaces and, unless caseSensitive, folds
+// letters to lower case, keeping one rune per rune.
+func normalize(text []rune, caseSensitive bool) []rune {
+	out := make([]rune, len(text))
+	for i, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			r = ' '
+		case !caseSensitive:
+			r = unicode.ToLower(r)
+		}
+		out[i] = r
+	}
+	return out
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
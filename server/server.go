@@ -0,0 +1,202 @@
+// Package server exposes the stream engine over HTTP, so that it can run
+// as a sidecar service:
+//
+//	POST /extract
+//
+// takes a PDF or a decoded content stream, as the request body or as the
+// "file" field of a multipart form, and returns its text as text/plain,
+// or with ?format=json the document model of the formats package.
+// ?layout=true rebuilds the text from the positioned runs (see
+// streamengine.WithLayout).
+//
+// Uploads are recognized as PDFs by the %PDF- header in their first 1024
+// bytes, as viewers do; anything else is taken as a content stream and
+// decoded with the default WinAnsi fonts.
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apex-woot/pdf-stream-engine/formats"
+	"github.com/apex-woot/pdf-stream-engine/streamengine"
+)
+
+// Config sets the limits of a Server. Zero fields select the defaults.
+type Config struct {
+	// MaxUploadBytes is the largest request body accepted; larger ones
+	// are answered with 413 Request Entity Too Large. Default 32 MiB.
+	MaxUploadBytes int64
+
+	// MaxPages is the most pages a PDF may have; PDFs with more are
+	// answered with 422 Unprocessable Entity. Zero means no limit.
+	MaxPages int
+
+	// Timeout limits the time spent extracting one request; requests
+	// that exceed it are answered with 504 Gateway Timeout. Default 30
+	// seconds. Use streamengine.WithPageTimeout in Options to return
+	// partial text for slow pages instead.
+	Timeout time.Duration
+
+	// MaxConcurrent is the most requests extracted at once; further ones
+	// are answered with 503 Service Unavailable. Zero means no limit.
+	MaxConcurrent int
+
+	// Limits bound the work spent on each page; a page exceeding one is
+	// reported in the JSON format's page error, its text cut short.
+	Limits streamengine.Limits
+
+	// Options are applied to every extraction, after Limits and before
+	// the options the request selects.
+	Options []streamengine.Option
+}
+
+// Server serves the extraction endpoint. Create it with New.
+type Server struct {
+	cfg   Config
+	mux   *http.ServeMux
+	slots chan struct{} // held by the requests being extracted, if limited
+}
+
+// New returns a server with the limits of cfg.
+func New(cfg Config) *Server {
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = 32 << 20
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	s := &Server{cfg: cfg, mux: http.NewServeMux()}
+	if cfg.MaxConcurrent > 0 {
+		s.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	s.mux.HandleFunc("POST /extract", s.handleExtract)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleExtract(w http.ResponseWriter, r *http.Request) {
+	asJSON := false
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "text":
+	case "json":
+		asJSON = true
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q: want text or json", format), http.StatusBadRequest)
+		return
+	}
+	opts := append([]streamengine.Option{streamengine.WithLimits(s.cfg.Limits)}, s.cfg.Options...)
+	if v := r.URL.Query().Get("layout"); v != "" {
+		layout, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid layout %q", v), http.StatusBadRequest)
+			return
+		}
+		opts = append(opts, streamengine.WithLayout(layout))
+	}
+
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		default:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many extractions in progress", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	data, err := s.readUpload(w, r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("upload larger than %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var session *streamengine.Session
+	if isPDF(data) {
+		session, err = streamengine.OpenDocumentReader(bytes.NewReader(data), opts...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if s.cfg.MaxPages > 0 && session.PageCount() > s.cfg.MaxPages {
+			http.Error(w, fmt.Sprintf("PDF has %d pages, more than %d", session.PageCount(), s.cfg.MaxPages), http.StatusUnprocessableEntity)
+			return
+		}
+	} else {
+		session = streamengine.NewSession(streamengine.Pages{{Content: data}}, opts...)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.Timeout)
+	defer cancel()
+	pages, err := session.ExtractAll(ctx)
+	timedOut := errors.Is(err, context.DeadlineExceeded)
+	for _, page := range pages {
+		// A page interrupted by the deadline has truncated text
+		timedOut = timedOut || errors.Is(page.Err, context.DeadlineExceeded)
+	}
+	switch {
+	case timedOut:
+		http.Error(w, fmt.Sprintf("extraction took longer than %v", s.cfg.Timeout), http.StatusGatewayTimeout)
+		return
+	case err != nil:
+		return // The client went away
+	}
+
+	doc := streamengine.DocumentText{Pages: pages}
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json")
+		formats.WriteJSON(w, doc)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, doc.Text())
+}
+
+// readUpload returns the uploaded file: the "file" field of a multipart
+// form, or else the request body.
+func (s *Server) readUpload(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.MaxUploadBytes)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return io.ReadAll(r.Body)
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, errors.New(`multipart form without a "file" field`)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return io.ReadAll(part)
+		}
+	}
+}
+
+// isPDF reports whether data starts with a PDF header, which may follow
+// up to 1024 bytes of garbage.
+func isPDF(data []byte) bool {
+	return bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-"))
+}
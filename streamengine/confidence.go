@@ -0,0 +1,101 @@
+package streamengine
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// Confidence estimates how well the text of a page was decoded, so that
+// pipelines can send the pages that score low to OCR. Each ratio is in
+// [0, 1], 0 being clean; Score combines them.
+type Confidence struct {
+	// Score is the product of one minus each ratio: 1 for text with no
+	// sign of a decoding problem, down to 0. A page without text scores 0,
+	// as it is likely scanned.
+	Score float64
+
+	// Replacement is the fraction of the characters that are U+FFFD,
+	// standing for codes no source could map to Unicode.
+	Replacement float64
+
+	// PrivateUse is the fraction of the characters in the Private Use
+	// Areas, which symbol and subset fonts decode to when their ToUnicode
+	// CMap is missing (see WithPUARemap).
+	PrivateUse float64
+
+	// Fallback is the fraction of the runs shown with a font that has
+	// neither a ToUnicode CMap nor a known encoding (see
+	// font.CapabilityFallback), whose text is raw bytes.
+	Fallback float64
+
+	// Mojibake is the fraction of the characters that look like text
+	// decoded with the wrong encoding: control characters, and UTF-8
+	// sequences read as Latin-1 or WinAnsi, such as "Ã©" for "é".
+	Mojibake float64
+}
+
+// WithConfidence scores the decoding quality of each page in
+// PageResult.Confidence. It applies to sessions only.
+func WithConfidence(enabled bool) Option {
+	return func(c *config) {
+		c.confidence = enabled
+	}
+}
+
+// ScoreConfidence estimates the decoding quality of the text and runs of
+// a page, whose fonts resolve the runs' font names; with fonts nil, the
+// Fallback ratio is 0.
+func ScoreConfidence(text string, runs []interpreter.TextRun, fonts *font.FontRegistry) Confidence {
+	var c Confidence
+	total, replacement, privateUse, mojibake := 0, 0, 0, 0
+	var prev rune
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			prev = r
+			continue
+		}
+		total++
+		switch {
+		case r == '\uFFFD':
+			replacement++
+		case font.IsPrivateUse(r):
+			privateUse++
+		case unicode.IsControl(r), isMisreadUTF8(prev, r):
+			mojibake++
+		}
+		prev = r
+	}
+	if total == 0 {
+		return c
+	}
+	c.Replacement = float64(replacement) / float64(total)
+	c.PrivateUse = float64(privateUse) / float64(total)
+	c.Mojibake = float64(mojibake) / float64(total)
+
+	if fonts != nil && len(runs) > 0 {
+		fallback := 0
+		for _, run := range runs {
+			if f, ok := fonts.Lookup(run.FontName); ok && f.Capability() == font.CapabilityFallback {
+				fallback++
+			}
+		}
+		c.Fallback = float64(fallback) / float64(len(runs))
+	}
+
+	c.Score = (1 - c.Replacement) * (1 - c.PrivateUse) * (1 - c.Fallback) * (1 - c.Mojibake)
+	return c
+}
+
+// isMisreadUTF8 reports whether r after prev is the continuation byte of
+// a UTF-8 sequence decoded as Latin-1 or WinAnsi: a lead byte read as Â,
+// Ã or â, followed by a byte of 0x80-0xBF read as a Latin-1 symbol or a
+// WinAnsi punctuation mark (â€™ for ’).
+func isMisreadUTF8(prev, r rune) bool {
+	if prev != 'Â' && prev != 'Ã' && prev != 'â' {
+		return false
+	}
+	return (r >= 0xA0 && r <= 0xBF) || strings.ContainsRune("€‚ƒ„…†‡ˆ‰Š‹ŒŽ‘’“”•–—˜™š›œžŸ", r)
+}
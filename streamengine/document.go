@@ -0,0 +1,100 @@
+package streamengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	"github.com/apex-woot/pdf-stream-engine/adapters/pdfcpu"
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// DocumentText is the text of a whole document, page by page.
+type DocumentText struct {
+	// Pages holds one result per page, in page order.
+	Pages []PageResult
+}
+
+// Text joins the text of all pages, separating pages with a blank line.
+func (d DocumentText) Text() string {
+	texts := make([]string, len(d.Pages))
+	for i, page := range d.Pages {
+		texts[i] = page.Text
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// ExtractDocument opens the PDF file at path and extracts the text of
+// every page, building each page's font registry from its resources.
+// It returns an error only if the file cannot be read; per-page failures
+// are reported in each PageResult.
+func ExtractDocument(path string, opts ...Option) (DocumentText, error) {
+	session, err := OpenDocument(path, opts...)
+	if err != nil {
+		return DocumentText{}, err
+	}
+	pages, err := session.ExtractAll(context.Background())
+	return DocumentText{Pages: pages}, err
+}
+
+// OpenDocument reads the PDF file at path and returns a session over its
+// pages, for callers that want to analyze fonts or extract pages
+// selectively.
+func OpenDocument(path string, opts ...Option) (*Session, error) {
+	ctx, err := api.ReadContextFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return NewSession(newDocumentPages(ctx, newConfig(opts)), opts...), nil
+}
+
+// OpenDocumentReader is like OpenDocument for a PDF read from rs, such as
+// an upload held in memory. Objects are read from rs as pages are loaded,
+// so rs must remain readable while the session is in use.
+func OpenDocumentReader(rs io.ReadSeeker, opts ...Option) (*Session, error) {
+	ctx, err := api.ReadContext(rs, model.NewDefaultConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("reading PDF: %w", err)
+	}
+	if err := api.ValidateContext(ctx); err != nil {
+		return nil, fmt.Errorf("reading PDF: %w", err)
+	}
+	return NewSession(newDocumentPages(ctx, newConfig(opts)), opts...), nil
+}
+
+// documentPages is a PageSource over a document loaded with pdfcpu. Fonts
+// are parsed once and shared between pages.
+type documentPages struct {
+	ctx   *model.Context
+	fonts *pdfcpu.FontCache
+}
+
+func newDocumentPages(ctx *model.Context, cfg config) documentPages {
+	fonts := pdfcpu.NewFontCache()
+	fonts.SetMaxCMapMappings(cfg.maxCMapMappings)
+	return documentPages{ctx: ctx, fonts: fonts}
+}
+
+func (d documentPages) PageCount() int {
+	return d.ctx.PageCount
+}
+
+func (d documentPages) Page(number int) (Page, error) {
+	content, fonts, err := d.fonts.LoadPage(d.ctx, number)
+	if err != nil {
+		return Page{}, err
+	}
+	resources, err := d.fonts.PageResources(d.ctx, number)
+	if err != nil {
+		return Page{}, err
+	}
+	return Page{Number: number, Content: content, Fonts: fonts, Resources: resources}, nil
+}
+
+func (d documentPages) PageFonts(number int) (*font.FontRegistry, error) {
+	return d.fonts.PageFontRegistry(d.ctx, number)
+}
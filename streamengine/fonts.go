@@ -0,0 +1,136 @@
+package streamengine
+
+import (
+	"context"
+	"sort"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+// FontSource is implemented by page sources that can supply a page's fonts
+// without loading its content. Session.AnalyzeFonts uses it when available.
+type FontSource interface {
+	// PageFonts returns the font registry of the 1-based page number.
+	PageFonts(number int) (*font.FontRegistry, error)
+}
+
+// FontReport describes one font of a document.
+type FontReport struct {
+	// Name is the font's resource name and BaseFont its PostScript name.
+	Name     string
+	BaseFont string
+
+	// Capability reports how the font's codes are mapped to Unicode.
+	Capability font.Capability
+
+	// Pages lists the pages whose resources include the font, ascending.
+	Pages []int
+}
+
+// FontAnalysis is the result of Session.AnalyzeFonts.
+type FontAnalysis struct {
+	// Fonts lists every font found, sorted by name and base font.
+	Fonts []FontReport
+
+	// PageErrors holds the pages whose fonts could not be loaded.
+	PageErrors map[int]error
+}
+
+// Reliable reports whether every font can be decoded through a ToUnicode
+// CMap or a known encoding. If not, extracted text is likely to contain
+// garbage and OCR may be the better option.
+func (a FontAnalysis) Reliable() bool {
+	if len(a.PageErrors) > 0 {
+		return false
+	}
+	for _, f := range a.Fonts {
+		if f.Capability == font.CapabilityFallback {
+			return false
+		}
+	}
+	return true
+}
+
+// AnalyzeFonts scans the font resources of every page and reports how
+// well each font can be decoded, before any text is extracted. Sources
+// that cache parsed fonts (such as the one behind ExtractDocument) parse
+// each font once, so later extraction reuses the work.
+//
+// It stops early, returning the analysis so far and ctx.Err(), only if
+// ctx is canceled.
+func (s *Session) AnalyzeFonts(ctx context.Context) (FontAnalysis, error) {
+	reports := make(map[fontKey]*FontReport)
+	var analysis FontAnalysis
+
+	for n := 1; n <= s.PageCount(); n++ {
+		if err := ctx.Err(); err != nil {
+			analysis.Fonts = sortedReports(reports)
+			return analysis, err
+		}
+		fonts, err := s.pageFonts(n)
+		if err != nil {
+			if analysis.PageErrors == nil {
+				analysis.PageErrors = make(map[int]error)
+			}
+			analysis.PageErrors[n] = err
+			continue
+		}
+		if fonts == nil {
+			continue
+		}
+		fonts.Each(func(name string, f *font.Font) bool {
+			k := fontKey{name, f.BaseFont}
+			r, ok := reports[k]
+			if !ok {
+				r = &FontReport{Name: name, BaseFont: f.BaseFont, Capability: f.Capability()}
+				reports[k] = r
+			}
+			r.Pages = append(r.Pages, n)
+			return true
+		})
+	}
+	analysis.Fonts = sortedReports(reports)
+	return analysis, nil
+}
+
+// pageFonts returns the fonts of a page, without loading its content if
+// the source allows it. Pages without fonts of their own use those of
+// WithFonts, as in ExtractPage.
+func (s *Session) pageFonts(number int) (*font.FontRegistry, error) {
+	s.sourceMu.Lock()
+	defer s.sourceMu.Unlock()
+	var fonts *font.FontRegistry
+	if fs, ok := s.source.(FontSource); ok {
+		var err error
+		if fonts, err = fs.PageFonts(number); err != nil {
+			return nil, err
+		}
+	} else {
+		page, err := s.source.Page(number)
+		if err != nil {
+			return nil, err
+		}
+		fonts = page.Fonts
+	}
+	if fonts == nil {
+		fonts = s.cfg.fonts
+	}
+	return fonts, nil
+}
+
+// fontKey identifies a font across pages.
+type fontKey struct{ name, baseFont string }
+
+func sortedReports(reports map[fontKey]*FontReport) []FontReport {
+	out := make([]FontReport, 0, len(reports))
+	for _, r := range reports {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].BaseFont < out[j].BaseFont
+	})
+	return out
+}
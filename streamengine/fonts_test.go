@@ -0,0 +1,21 @@
+package streamengine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+func TestAnalyzeFontsWithFonts(t *testing.T) {
+	registry := font.NewFontRegistry()
+	registry.RegisterSimple("F1", font.EncodingWinAnsi)
+	session := NewSession(Pages{{Content: []byte("BT /F1 12 Tf (a) Tj ET")}}, WithFonts(registry))
+	analysis, err := session.AnalyzeFonts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analysis.Fonts) != 1 || analysis.Fonts[0].Name != "F1" || len(analysis.Fonts[0].Pages) != 1 {
+		t.Errorf("fonts = %+v, want F1 on page 1", analysis.Fonts)
+	}
+}
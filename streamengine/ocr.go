@@ -0,0 +1,71 @@
+package streamengine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apex-woot/pdf-stream-engine/images"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// OCRProvider recognizes the text of scanned pages, which paint images
+// but show no text (see PageResult.Scanned). Set it with WithOCR.
+type OCRProvider interface {
+	// Recognize returns the text in the images of page number, decoded
+	// and in painting order, with their placements on the page. Images
+	// that could not be decoded, such as JBIG2 or JPEG 2000 ones, are
+	// passed with Err set.
+	Recognize(ctx context.Context, number int, pageImages []images.Image) (string, error)
+}
+
+// WithOCR sends scanned pages to provider and merges the text it
+// recognizes into PageResult.Text. The provider is called with the
+// context of the extraction, outside the budget of WithPageTimeout; an
+// error fails the page. Images are resolved through Page.Resources, which
+// must implement images.ResourceProvider as those of OpenDocument do;
+// without it, only pages with inline images count as scanned. It applies
+// to sessions only.
+func WithOCR(provider OCRProvider) Option {
+	return func(c *config) {
+		c.ocr = provider
+	}
+}
+
+// isScanned reports whether placements, the images painted by a page that
+// showed no text, are all images: inline ones, or XObjects resources
+// resolves to images. Form XObjects may hold text.
+func isScanned(placements []interpreter.ImagePlacement, resources interpreter.ResourceProvider) bool {
+	if len(placements) == 0 {
+		return false
+	}
+	xobjects, _ := resources.(images.ResourceProvider)
+	for _, p := range placements {
+		if p.Inline != nil {
+			continue
+		}
+		if xobjects == nil {
+			return false
+		}
+		xobj, err := xobjects.XObject(p.Name)
+		if err != nil || xobj.Subtype != "Image" {
+			return false
+		}
+	}
+	return true
+}
+
+// recognize merges the text the OCR provider finds in the images of a
+// scanned page into result.
+func (s *Session) recognize(ctx context.Context, result *PageResult, placements []interpreter.ImagePlacement, resources interpreter.ResourceProvider) {
+	xobjects, _ := resources.(images.ResourceProvider)
+	text, err := s.cfg.ocr.Recognize(ctx, result.Number, images.Extract(placements, xobjects))
+	if err != nil {
+		result.Err = fmt.Errorf("OCR of page %d: %w", result.Number, err)
+		return
+	}
+	if result.Text != "" && text != "" {
+		result.Text += "\n"
+	}
+	result.Text += text
+	result.OCR = text != ""
+}
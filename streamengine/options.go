@@ -0,0 +1,451 @@
+package streamengine
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/layout"
+)
+
+// Option configures ExtractText and Session. Options are applied in
+// order, so a later option overrides an earlier one setting the same
+// thing; in particular, WithInterpreterOptions replaces every interpreter
+// setting made before it.
+type Option func(*config)
+
+// config holds the settings shared by the extraction APIs.
+type config struct {
+	interpreterOptions interpreter.Options
+	fonts              *font.FontRegistry
+	mode               Mode
+	layout             bool
+	lineOptions        layout.LineOptions
+	dehyphenate        bool
+	pageTimeout        time.Duration
+	parallel           ParallelOptions
+	progress           func(done, total int)
+	stats              bool
+	confidence         bool
+	ocr                OCRProvider
+	maxCMapMappings    int
+	streamProgress     func(page int, read, total int64)
+	logger             *slog.Logger
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// interpreterOptionsWithLogger returns the interpreter options with the
+// logger set by WithLogger applied, the operator counts WithStats needs,
+// and the deduplication ModeAccurate turns on.
+func (c config) interpreterOptionsWithLogger() interpreter.Options {
+	opts := c.interpreterOptions
+	if c.mode == ModeAccurate {
+		opts.SkipDuplicates = true
+	}
+	if c.logger != nil {
+		opts.Logger = c.logger
+	}
+	if c.stats {
+		opts.CountOperators = true
+	}
+	return opts
+}
+
+// text returns the text extracted by interp: the interpreter's output, or
+// the runs reassembled into lines with WithLayout or ModeAccurate.
+func (c config) text(interp *interpreter.Interpreter) string {
+	var text string
+	if !c.layout && c.mode != ModeAccurate {
+		text = interp.GetText()
+	} else {
+		text = layout.Text(layout.AssembleLines(interp.GetRuns(), c.lineOptions))
+	}
+	if c.dehyphenate {
+		text = layout.Dehyphenate(text, nil)
+	}
+	return text
+}
+
+// provenance returns the provenance of the text c.text returns, with
+// WithProvenance. Text reassembled from the runs has none: its offsets no
+// longer follow the stream.
+func (c config) provenance(interp *interpreter.Interpreter) []interpreter.Provenance {
+	if !c.interpreterOptions.RecordProvenance || c.layout || c.mode == ModeAccurate || c.dehyphenate {
+		return nil
+	}
+	return interp.GetProvenance()
+}
+
+// Mode trades extraction speed for quality.
+//
+// ModeFast, the default, writes text in content-stream order, breaking
+// lines on text-positioning operators (Td, Tm, T*) as they are seen.
+// ModeAccurate turns on the features that cost time for better text:
+// it positions every run in device space and reassembles the text from
+// the runs, as WithLayout does, so lines are formed by baseline
+// proximity and sorted top to bottom and left to right, and words are
+// separated by measured glyph gaps; and it drops text drawn again over
+// itself, as WithSkipDuplicates does. This costs sorting the runs and
+// comparing each with the ones before it.
+//
+// On the samples of TestModeAccuracy, ModeAccurate reads lines and words
+// drawn out of order, lines placed by the CTM and fake-bold text exactly,
+// where ModeFast is 1 to 12 edits off on each. Side-by-side columns go the
+// other way: ModeFast keeps them whole, while ModeAccurate interleaves
+// their lines, 16 edits off. On the synthetic stream of BenchmarkModes,
+// ModeAccurate takes about 2.2 times as long.
+//
+// Both modes track the full text and graphics matrices, space words by
+// glyph widths, and decode codes missing from a ToUnicode CMap through
+// the embedded font program.
+type Mode int
+
+const (
+	// ModeFast uses the cheap stream-order heuristics.
+	ModeFast Mode = iota
+
+	// ModeAccurate reassembles the text from positioned runs and drops
+	// duplicated text.
+	ModeAccurate
+)
+
+// String returns the mode name.
+func (m Mode) String() string {
+	switch m {
+	case ModeFast:
+		return "fast"
+	case ModeAccurate:
+		return "accurate"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// WithMode selects the extraction mode. ModeAccurate implies WithLayout
+// and WithSkipDuplicates.
+func WithMode(mode Mode) Option {
+	return func(c *config) {
+		c.mode = mode
+	}
+}
+
+// WithFonts resolves font resource names with registry. For a Session it
+// is used for pages whose Fonts is nil. Without it, a default registry
+// with WinAnsi encoding is used.
+func WithFonts(registry *font.FontRegistry) Option {
+	return func(c *config) {
+		c.fonts = registry
+	}
+}
+
+// WithLayout rebuilds the text from the positioned runs, grouped into
+// lines top to bottom, instead of returning it in content-stream order.
+// This helps with producers that draw text out of reading order.
+func WithLayout(enabled bool) Option {
+	return func(c *config) {
+		c.layout = enabled
+	}
+}
+
+// WithBidi reorders right-to-left text (Arabic, Hebrew) stored in visual
+// order to logical order. It applies with WithLayout or ModeAccurate. See
+// layout.LineOptions.Bidi.
+func WithBidi(enabled bool) Option {
+	return func(c *config) {
+		c.lineOptions.Bidi = enabled
+	}
+}
+
+// WithArabicBaseForms replaces Arabic presentation forms with their base
+// letters. It applies with WithLayout or ModeAccurate. See
+// layout.LineOptions.ArabicBaseForms.
+func WithArabicBaseForms(enabled bool) Option {
+	return func(c *config) {
+		c.lineOptions.ArabicBaseForms = enabled
+	}
+}
+
+// WithDehyphenate joins words broken across lines at a hyphen ("exam-"
+// "ple" becomes "example"). See layout.Dehyphenate.
+func WithDehyphenate(enabled bool) Option {
+	return func(c *config) {
+		c.dehyphenate = enabled
+	}
+}
+
+// WithSkipArtifacts suppresses headers, footers and page numbers marked
+// as artifacts in tagged PDFs. See interpreter.Options.SkipArtifacts.
+func WithSkipArtifacts(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.SkipArtifacts = enabled
+	}
+}
+
+// WithSkipInvisible drops text drawn invisibly, such as OCR layers.
+// See interpreter.Options.SkipInvisible.
+func WithSkipInvisible(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.SkipInvisible = enabled
+	}
+}
+
+// WithRegion extracts only the text inside rect, in page space. See
+// interpreter.Options.Region.
+func WithRegion(rect geom.Rect) Option {
+	return func(c *config) {
+		c.interpreterOptions.Region = rect
+	}
+}
+
+// WithSkipTextMatchingPageBackground drops text drawn in its background
+// color, such as white on white. See
+// interpreter.Options.SkipTextMatchingPageBackground.
+func WithSkipTextMatchingPageBackground(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.SkipTextMatchingPageBackground = enabled
+	}
+}
+
+// WithMinFontSize drops text rendered smaller than size. See
+// interpreter.Options.MinFontSize.
+func WithMinFontSize(size float64) Option {
+	return func(c *config) {
+		c.interpreterOptions.MinFontSize = size
+	}
+}
+
+// WithRunFilter drops the runs filter returns false for. See
+// interpreter.Options.RunFilter.
+func WithRunFilter(filter func(interpreter.TextRun) bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.RunFilter = filter
+	}
+}
+
+// WithSkipDuplicates drops text drawn again over itself, as for fake
+// bold. See interpreter.Options.SkipDuplicates.
+func WithSkipDuplicates(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.SkipDuplicates = enabled
+	}
+}
+
+// WithLayers drops text on the optional content layers that layers
+// hides, e.g. interpreter.HideLayers("Watermark") or, for a document's
+// own default configuration, pdfcpu.DocumentLayers. See
+// interpreter.Options.Layers.
+func WithLayers(layers interpreter.LayerFunc) Option {
+	return func(c *config) {
+		c.interpreterOptions.Layers = layers
+	}
+}
+
+// WithSkipClipped drops text drawn entirely outside the clipping path.
+// See interpreter.Options.SkipClipped.
+func WithSkipClipped(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.SkipClipped = enabled
+	}
+}
+
+// WithCheckboxes renders checkbox glyphs as "[x]"/"[ ]" markers.
+// See interpreter.Options.RecognizeCheckboxes.
+func WithCheckboxes(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.RecognizeCheckboxes = enabled
+	}
+}
+
+// WithRejoinNumbers reassembles numbers split into fragments.
+// See interpreter.Options.RejoinNumbers.
+func WithRejoinNumbers(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.RejoinNumbers = enabled
+	}
+}
+
+// WithPUARemap replaces private-use code points in decoded text.
+// See interpreter.Options.PUARemap.
+func WithPUARemap(table interpreter.PUATable) Option {
+	return func(c *config) {
+		c.interpreterOptions.PUARemap = table
+	}
+}
+
+// WithPUARemappers replaces private-use code points through remappers
+// such as interpreter.GlyphNamePUARemapper.
+// See interpreter.Options.PUARemappers.
+func WithPUARemappers(remappers ...interpreter.PUARemapper) Option {
+	return func(c *config) {
+		c.interpreterOptions.PUARemappers = remappers
+	}
+}
+
+// WithExpandLigatures replaces presentation-form ligatures such as ﬁ
+// with their letters. See interpreter.Options.ExpandLigatures.
+func WithExpandLigatures(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.ExpandLigatures = enabled
+	}
+}
+
+// WithNormalizeNFC normalizes decoded text to Unicode NFC.
+// See interpreter.Options.NormalizeNFC.
+func WithNormalizeNFC(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.NormalizeNFC = enabled
+	}
+}
+
+// WithMarkScripts writes superscripts and subscripts with ^ and _ markers
+// (x^2, H_2O). See interpreter.Options.MarkScripts.
+func WithMarkScripts(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.MarkScripts = enabled
+	}
+}
+
+// WithProvenance records which operation emitted each part of the text,
+// in PageResult.Provenance. It applies to the text written in stream
+// order: WithLayout, ModeAccurate and WithDehyphenate leave it empty.
+func WithProvenance(enabled bool) Option {
+	return func(c *config) {
+		c.interpreterOptions.RecordProvenance = enabled
+	}
+}
+
+// WithMaxOutputBytes caps the size of the extracted text of each stream
+// or page. See interpreter.Options.MaxOutputBytes.
+func WithMaxOutputBytes(n int) Option {
+	return func(c *config) {
+		c.interpreterOptions.MaxOutputBytes = n
+	}
+}
+
+// Limits bound the work and memory extraction may spend on one page,
+// guarding servers against content crafted to exhaust them, such as
+// decompression bombs. A page exceeding one fails with a
+// *parser.LimitError (matching ErrLimitExceeded) in PageResult.Err,
+// keeping the text extracted until then. Zero fields mean no limit.
+type Limits struct {
+	// MaxOperations caps the operations interpreted per content stream.
+	MaxOperations int
+
+	// MaxArrayLength caps the elements of an array operand, such as the
+	// array of TJ, and the operands of one operation.
+	MaxArrayLength int
+
+	// MaxCMapMappings caps the entries of each ToUnicode CMap a document
+	// loads; it applies to documents opened with OpenDocument and
+	// OpenDocumentReader.
+	MaxCMapMappings int
+
+	// MaxFormDepth caps the nesting of form XObjects interpreted through
+	// interpreter.PushFontScope.
+	MaxFormDepth int
+
+	// MaxOutputBytes caps the extracted text per page.
+	MaxOutputBytes int
+
+	// MaxWarnings caps the warnings collected and logged per page. Past
+	// it, warnings are only counted and the page does not fail; see
+	// interpreter.Options.MaxWarnings.
+	MaxWarnings int
+}
+
+// WithLimits sets the limits of extraction, overriding WithMaxOutputBytes.
+func WithLimits(limits Limits) Option {
+	return func(c *config) {
+		c.interpreterOptions.MaxOperations = limits.MaxOperations
+		c.interpreterOptions.MaxArrayLength = limits.MaxArrayLength
+		c.interpreterOptions.MaxFormDepth = limits.MaxFormDepth
+		c.interpreterOptions.MaxOutputBytes = limits.MaxOutputBytes
+		c.interpreterOptions.MaxWarnings = limits.MaxWarnings
+		c.maxCMapMappings = limits.MaxCMapMappings
+	}
+}
+
+// WithInterpreterOptions sets all interpreter options at once, replacing
+// those set by earlier options.
+func WithInterpreterOptions(opts interpreter.Options) Option {
+	return func(c *config) {
+		c.interpreterOptions = opts
+	}
+}
+
+// WithPageTimeout limits the processing time of each page. A page that
+// exceeds the budget is finalized with whatever was extracted, marked
+// Partial, and extraction continues with the next page. Zero means no limit.
+// It applies to sessions only.
+func WithPageTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.pageTimeout = d
+	}
+}
+
+// WithProgress reports the progress of ExtractAll: progress is called
+// with done 0 before the first page, then each time a page is finished,
+// with the number of pages finished so far and the page count. Calls are
+// serialized, also with WithParallel, and done only grows. It applies to
+// sessions only.
+func WithProgress(progress func(done, total int)) Option {
+	return func(c *config) {
+		c.progress = progress
+	}
+}
+
+// WithStreamProgress reports how much of each page's content stream has
+// been read: progress is called as the parser consumes the stream, with
+// the 1-based page number and the bytes read of total, the size of the
+// decoded stream. As the stream is read in chunks ahead of the operators
+// being interpreted, calls that stop coming before read reaches total
+// point to a stall in the page. With WithParallel, it is called
+// concurrently for different pages. It applies to sessions only.
+func WithStreamProgress(progress func(page int, read, total int64)) Option {
+	return func(c *config) {
+		c.streamProgress = progress
+	}
+}
+
+// WithLogger directs warnings to logger, overriding the Logger of the
+// interpreter options.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// ParallelOptions configures concurrent page extraction.
+type ParallelOptions struct {
+	// Workers is the number of pages processed at once. Zero selects
+	// runtime.GOMAXPROCS(0).
+	Workers int
+}
+
+func (o ParallelOptions) withDefaults() ParallelOptions {
+	if o.Workers <= 0 {
+		o.Workers = runtime.GOMAXPROCS(0)
+	}
+	return o
+}
+
+// WithParallel makes ExtractAll process pages concurrently. Results keep
+// page order. Pages are still loaded from the PageSource one at a time;
+// interpretation, which dominates for most documents, runs in parallel,
+// each page with its own interpreter and font registry.
+func WithParallel(opts ParallelOptions) Option {
+	return func(c *config) {
+		c.parallel = opts.withDefaults()
+	}
+}
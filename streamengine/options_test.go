@@ -0,0 +1,159 @@
+package streamengine
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apex-woot/pdf-stream-engine/internal/streamgen"
+)
+
+func TestMode(t *testing.T) {
+	// The second line is drawn first, and "Bold" twice, 0.3pt apart.
+	stream := []byte("BT /F1 12 Tf 72 680 Td (second) Tj ET " +
+		"BT /F1 12 Tf 72 700 Td (first) Tj ET " +
+		"BT /F1 12 Tf 72 660 Td (Bold) Tj ET BT /F1 12 Tf 72.3 660 Td (Bold) Tj ET")
+	tests := []struct {
+		mode Mode
+		want string
+	}{
+		{ModeFast, "second\nfirst\nBold\nBold"},
+		{ModeAccurate, "first\nsecond\nBold"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			if got := ExtractText(stream, WithMode(tt.mode)); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// modeSamples are streams with the text a reader sees on the page, and
+// how many edits away from it the text of each mode is.
+var modeSamples = []struct {
+	name           string
+	stream         string
+	want           string
+	fast, accurate int
+}{
+	{
+		"lines in order",
+		"BT /F1 12 Tf 72 700 Td (first line) Tj 0 -20 Td (second line) Tj 0 -20 Td (third line) Tj ET",
+		"first line\nsecond line\nthird line",
+		0, 0,
+	},
+	{
+		"lines out of order",
+		"BT /F1 12 Tf 72 680 Td (second line) Tj ET BT /F1 12 Tf 72 700 Td (first line) Tj ET",
+		"first line\nsecond line",
+		12, 0,
+	},
+	{
+		"words out of order",
+		"BT /F1 12 Tf 200 700 Td (world) Tj -100 0 Td (hello) Tj ET",
+		"hello world",
+		8, 0,
+	},
+	{
+		"fake bold",
+		"BT /F1 12 Tf 72 700 Td (Bold) Tj ET BT /F1 12 Tf 72.3 700 Td (Bold) Tj ET BT /F1 12 Tf 72 680 Td (text) Tj ET",
+		"Bold\ntext",
+		5, 0,
+	},
+	{
+		"lines placed by the CTM",
+		"q 1 0 0 1 72 700 cm BT /F1 12 Tf (first line) Tj ET Q q 1 0 0 1 72 680 cm BT /F1 12 Tf (second line) Tj ET Q",
+		"first line\nsecond line",
+		1, 0,
+	},
+	{
+		"two columns",
+		"BT /F1 12 Tf 72 700 Td (left one) Tj 0 -20 Td (left two) Tj ET BT /F1 12 Tf 300 700 Td (right one) Tj 0 -20 Td (right two) Tj ET",
+		"left one\nleft two\nright one\nright two",
+		0, 16,
+	},
+	{
+		"TJ spacing and kerning",
+		"BT /F1 12 Tf 72 700 Td [(word) -300 (spaced) 20 (kerned)] TJ ET",
+		"word spacedkerned",
+		0, 0,
+	},
+}
+
+// TestModeAccuracy measures how far the text of each mode is from what a
+// reader sees on modeSamples, which the Mode documentation sums up.
+func TestModeAccuracy(t *testing.T) {
+	for _, s := range modeSamples {
+		t.Run(s.name, func(t *testing.T) {
+			for mode, want := range map[Mode]int{ModeFast: s.fast, ModeAccurate: s.accurate} {
+				got := ExtractText([]byte(s.stream), WithMode(mode))
+				if d := editDistance(got, s.want); d != want {
+					t.Errorf("%s: %q is %d edits from %q, want %d", mode, got, d, s.want, want)
+				}
+			}
+		})
+	}
+}
+
+// editDistance returns the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range ra {
+		cur[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j]+cost, prev[j+1]+1, cur[j]+1)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// BenchmarkModes compares ModeFast and ModeAccurate on a synthetic
+// stream, where interpretation is all the work, and, if the environment
+// variable PDF_CORPUS names a directory, on each PDF in it.
+func BenchmarkModes(b *testing.B) {
+	cfg := streamgen.Config{Size: 4 << 20, Seed: 1}
+	data := streamgen.Generate(cfg)
+	registry := streamgen.Registry(cfg)
+	quiet := WithLogger(slog.New(slog.DiscardHandler))
+	for _, mode := range []Mode{ModeFast, ModeAccurate} {
+		b.Run("streamgen/"+mode.String(), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				ExtractText(data, WithFonts(registry), WithMode(mode), quiet)
+			}
+		})
+	}
+
+	dir := os.Getenv("PDF_CORPUS")
+	if dir == "" {
+		return
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pdf"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, path := range paths {
+		for _, mode := range []Mode{ModeFast, ModeAccurate} {
+			b.Run(filepath.Base(path)+"/"+mode.String(), func(b *testing.B) {
+				for b.Loop() {
+					if _, err := ExtractDocument(path, WithMode(mode), quiet); err != nil {
+						b.Skip(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package streamengine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/apex-woot/pdf-stream-engine/filters"
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// Page is the input for one page: its decoded content stream (all content
+// streams concatenated) and the fonts its resources define.
+type Page struct {
+	// Number is the 1-based page number.
+	Number int
+
+	// Content is the content stream (all content streams concatenated).
+	// It is decoded already unless Filters is set.
+	Content []byte
+
+	// Filters and DecodeParms describe how Content is encoded, as in the
+	// stream dictionary. If Filters is set, Content is decoded with the
+	// filters package before interpretation.
+	Filters     []string
+	DecodeParms []map[string]any
+
+	// Fonts resolves the page's font resource names. If nil, the registry
+	// set with WithFonts is used, or else a default registry with WinAnsi
+	// encoding.
+	Fonts *font.FontRegistry
+
+	// Resources resolves the page's other named resources, such as the
+	// graphics state parameter dictionaries set with gs. If nil, gs is
+	// ignored.
+	Resources interpreter.ResourceProvider
+}
+
+// PageSource supplies the pages of a document to a Session.
+type PageSource interface {
+	// PageCount returns the number of pages.
+	PageCount() int
+
+	// Page returns the 1-based page number.
+	Page(number int) (Page, error)
+}
+
+// Pages is a PageSource backed by an in-memory slice. Page numbers are
+// assigned from slice positions.
+type Pages []Page
+
+// PageCount returns the number of pages.
+func (p Pages) PageCount() int {
+	return len(p)
+}
+
+// Page returns the 1-based page number.
+func (p Pages) Page(number int) (Page, error) {
+	if number < 1 || number > len(p) {
+		return Page{}, fmt.Errorf("page %d out of range [1, %d]", number, len(p))
+	}
+	page := p[number-1]
+	page.Number = number
+	return page, nil
+}
+
+// PageResult is the extraction result for one page.
+type PageResult struct {
+	// Number is the 1-based page number.
+	Number int
+
+	// Text is the extracted text.
+	Text string
+
+	// Runs are the positioned text runs.
+	Runs []interpreter.TextRun
+
+	// Provenance maps the spans of Text to the operations that emitted
+	// them, with WithProvenance.
+	Provenance []interpreter.Provenance
+
+	// Partial reports that processing stopped early because the page
+	// exceeded its time budget; Text and Runs hold what was extracted
+	// until then.
+	Partial bool
+
+	// Warnings are the recoverable problems found in the page's content.
+	Warnings []interpreter.Warning
+
+	// Stats describe the work done extracting the page, with WithStats.
+	Stats *ExtractionStats
+
+	// Confidence estimates how well the text was decoded, with
+	// WithConfidence.
+	Confidence *Confidence
+
+	// Scanned reports that the page paints images and shows no text, as
+	// scanned pages do; see WithOCR.
+	Scanned bool
+
+	// OCR reports that Text includes text recognized by the OCRProvider
+	// of WithOCR.
+	OCR bool
+
+	// Err is set if the page could not be loaded or parsed, or its OCR
+	// failed.
+	Err error
+}
+
+// Session extracts text from the pages of one document.
+// It is safe for concurrent use: calls into the PageSource are serialized.
+type Session struct {
+	source PageSource
+	cfg    config
+
+	sourceMu sync.Mutex // serializes PageSource calls
+
+	interpreters sync.Pool // *interpreter.Interpreter, reused across pages
+}
+
+// NewSession creates a session over the given pages.
+func NewSession(source PageSource, opts ...Option) *Session {
+	return &Session{source: source, cfg: newConfig(opts)}
+}
+
+// PageCount returns the number of pages in the document.
+func (s *Session) PageCount() int {
+	return s.source.PageCount()
+}
+
+// ExtractPage extracts the text of the 1-based page number.
+func (s *Session) ExtractPage(ctx context.Context, number int) PageResult {
+	result := PageResult{Number: number}
+	var stats ExtractionStats
+	if s.cfg.stats {
+		result.Stats = &stats
+	}
+	start := time.Now()
+	page, err := s.loadPage(number)
+	stats.LoadTime = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	content := page.Content
+	if len(page.Filters) > 0 {
+		start = time.Now()
+		content, err = filters.Decode(content, page.Filters, page.DecodeParms)
+		stats.DecodeTime = time.Since(start)
+		if err != nil {
+			result.Err = fmt.Errorf("decoding page %d: %w", number, err)
+			return result
+		}
+	}
+	stats.BytesDecoded = len(content)
+
+	pageCtx := ctx
+	if s.cfg.pageTimeout > 0 {
+		var cancel context.CancelFunc
+		pageCtx, cancel = context.WithTimeout(ctx, s.cfg.pageTimeout)
+		defer cancel()
+	}
+
+	fonts := page.Fonts
+	if fonts == nil {
+		fonts = s.cfg.fonts
+	}
+	interp := s.getInterpreter(fonts)
+	interp.SetResources(page.Resources)
+	defer s.interpreters.Put(interp)
+	var r io.Reader = bytes.NewReader(content)
+	if s.cfg.streamProgress != nil {
+		r = &progressReader{r: r, total: int64(len(content)), report: func(read, total int64) {
+			s.cfg.streamProgress(number, read, total)
+		}}
+	}
+	start = time.Now()
+	err = interp.ProcessStreamContext(pageCtx, r)
+	stats.InterpretTime = time.Since(start)
+	switch {
+	case err == nil:
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+		// Only this page's budget ran out
+		result.Partial = true
+	default:
+		result.Err = err
+	}
+	start = time.Now()
+	result.Text = s.cfg.text(interp)
+	stats.TextTime = time.Since(start)
+	result.Runs = interp.GetRuns()
+	result.Provenance = s.cfg.provenance(interp)
+	result.Warnings = interp.GetWarnings()
+	if result.Err == nil && !result.Partial && !interp.ShowedText() {
+		placements := interp.GetImagePlacements()
+		result.Scanned = isScanned(placements, page.Resources)
+		if result.Scanned && s.cfg.ocr != nil {
+			start = time.Now()
+			s.recognize(ctx, &result, placements, page.Resources)
+			stats.OCRTime = time.Since(start)
+		}
+	}
+	if s.cfg.stats {
+		collectStats(&stats, interp, fonts, result)
+	}
+	if s.cfg.confidence {
+		confidence := ScoreConfidence(result.Text, result.Runs, fonts)
+		result.Confidence = &confidence
+	}
+	return result
+}
+
+// getInterpreter returns a pooled interpreter reset for a new page, or a
+// new one.
+func (s *Session) getInterpreter(fonts *font.FontRegistry) *interpreter.Interpreter {
+	interp, ok := s.interpreters.Get().(*interpreter.Interpreter)
+	if !ok {
+		return interpreter.NewInterpreterWithOptions(fonts, s.cfg.interpreterOptionsWithLogger())
+	}
+	interp.Reset()
+	interp.SetFontRegistry(fonts)
+	return interp
+}
+
+// loadPage fetches a page from the source, one call at a time.
+func (s *Session) loadPage(number int) (Page, error) {
+	s.sourceMu.Lock()
+	defer s.sourceMu.Unlock()
+	return s.source.Page(number)
+}
+
+// ExtractAll extracts every page in order. If ctx itself is done, it
+// stops early and returns the results so far and ctx.Err(), even if only
+// the last page was cut short; per-page failures are reported in each
+// PageResult.
+//
+// With WithParallel, pages are processed concurrently; the results are
+// the same as for sequential extraction. On cancellation, the results
+// cover the pages up to the first one that was not finished.
+func (s *Session) ExtractAll(ctx context.Context) ([]PageResult, error) {
+	if s.cfg.parallel.Workers > 1 {
+		return s.extractParallel(ctx)
+	}
+	results := make([]PageResult, 0, s.PageCount())
+	s.reportProgress(0)
+	for n := 1; n <= s.PageCount(); n++ {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		results = append(results, s.ExtractPage(ctx, n))
+		s.reportProgress(n)
+	}
+	// The last page may have been cut short
+	return results, ctx.Err()
+}
+
+// extractParallel implements ExtractAll with a pool of workers.
+func (s *Session) extractParallel(ctx context.Context) ([]PageResult, error) {
+	count := s.PageCount()
+	results := make([]PageResult, count)
+	done := make([]bool, count)
+
+	pages := make(chan int)
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+	completed := 0 // pages finished, for WithProgress
+	s.reportProgress(0)
+	for range min(s.cfg.parallel.Workers, count) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range pages {
+				results[n-1] = s.ExtractPage(ctx, n)
+				done[n-1] = true
+				progressMu.Lock()
+				completed++
+				s.reportProgress(completed)
+				progressMu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for n := 1; n <= count; n++ {
+		select {
+		case pages <- n:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(pages)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		finished := 0
+		for finished < count && done[finished] {
+			finished++
+		}
+		return results[:finished], err
+	}
+	return results, nil
+}
+
+// reportProgress calls the WithProgress callback, if set, with done pages
+// finished.
+func (s *Session) reportProgress(done int) {
+	if s.cfg.progress != nil {
+		s.cfg.progress(done, s.PageCount())
+	}
+}
+
+// progressReader reports the bytes read through it, for
+// WithStreamProgress.
+type progressReader struct {
+	r      io.Reader
+	read   int64
+	total  int64
+	report func(read, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.report(p.read, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,139 @@
+package streamengine
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/parser"
+)
+
+// ExtractionStats describe the work done extracting a page, or with Add
+// a document, for monitoring extraction in production: a rise in unknown
+// operators, replacement characters or fonts without ToUnicode points to
+// documents the engine handles poorly.
+type ExtractionStats struct {
+	// Operations is the number of operations interpreted.
+	Operations int
+
+	// Operators counts the operations by operator, including unknown ones.
+	Operators map[string]int
+
+	// UnknownOperators counts the operations whose operator is not one of
+	// ISO 32000-1 (see parser.IsStandardOperator), such as vendor
+	// extensions or garbage decoded as operators.
+	UnknownOperators map[string]int
+
+	// Runs is the number of text runs shown.
+	Runs int
+
+	// BytesDecoded is the size of the decoded content stream.
+	BytesDecoded int
+
+	// ReplacementChars counts the U+FFFD characters in the text, which
+	// stand for codes that could not be mapped to Unicode.
+	ReplacementChars int
+
+	// FontsMissingToUnicode are the resource names of the fonts that
+	// showed text without a ToUnicode CMap, sorted. Their text is decoded
+	// from the font's encoding, which for subset and symbolic fonts is
+	// often wrong.
+	FontsMissingToUnicode []string
+
+	// LoadTime is spent loading the page from the PageSource, DecodeTime
+	// decoding its content with Page.Filters, InterpretTime interpreting
+	// the content, TextTime assembling the text from the interpreter's
+	// output, as with WithLayout, and OCRTime recognizing the text of a
+	// scanned page with WithOCR.
+	LoadTime      time.Duration
+	DecodeTime    time.Duration
+	InterpretTime time.Duration
+	TextTime      time.Duration
+	OCRTime       time.Duration
+}
+
+// WithStats collects the ExtractionStats of each page in
+// PageResult.Stats. It applies to sessions only.
+func WithStats(enabled bool) Option {
+	return func(c *config) {
+		c.stats = enabled
+	}
+}
+
+// Add adds the counts and times of other to s; FontsMissingToUnicode
+// becomes the sorted union of both.
+func (s *ExtractionStats) Add(other ExtractionStats) {
+	s.Operations += other.Operations
+	s.Operators = addCounts(s.Operators, other.Operators)
+	s.UnknownOperators = addCounts(s.UnknownOperators, other.UnknownOperators)
+	s.Runs += other.Runs
+	s.BytesDecoded += other.BytesDecoded
+	s.ReplacementChars += other.ReplacementChars
+	for _, name := range other.FontsMissingToUnicode {
+		if i, found := slices.BinarySearch(s.FontsMissingToUnicode, name); !found {
+			s.FontsMissingToUnicode = slices.Insert(s.FontsMissingToUnicode, i, name)
+		}
+	}
+	s.LoadTime += other.LoadTime
+	s.DecodeTime += other.DecodeTime
+	s.InterpretTime += other.InterpretTime
+	s.TextTime += other.TextTime
+	s.OCRTime += other.OCRTime
+}
+
+// addCounts adds the counts of src to dst, allocating dst if needed.
+func addCounts(dst, src map[string]int) map[string]int {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]int, len(src))
+	}
+	for name, n := range src {
+		dst[name] += n
+	}
+	return dst
+}
+
+// Stats returns the sum of the stats of the pages, which are collected
+// with WithStats; pages without stats are skipped.
+func (d DocumentText) Stats() ExtractionStats {
+	var stats ExtractionStats
+	for _, page := range d.Pages {
+		if page.Stats != nil {
+			stats.Add(*page.Stats)
+		}
+	}
+	return stats
+}
+
+// collectStats fills in the counts of stats from the interpreter that
+// extracted result, with fonts the page's registry.
+func collectStats(stats *ExtractionStats, interp *interpreter.Interpreter, fonts *font.FontRegistry, result PageResult) {
+	stats.Operators = interp.GetOperatorCounts()
+	for name, n := range stats.Operators {
+		stats.Operations += n
+		if !parser.IsStandardOperator(name) {
+			if stats.UnknownOperators == nil {
+				stats.UnknownOperators = make(map[string]int)
+			}
+			stats.UnknownOperators[name] = n
+		}
+	}
+	stats.Runs = len(result.Runs)
+	stats.ReplacementChars = strings.Count(result.Text, "\uFFFD")
+
+	missing := make(map[string]bool)
+	for _, run := range result.Runs {
+		if fonts == nil || missing[run.FontName] {
+			continue
+		}
+		if f, ok := fonts.Lookup(run.FontName); ok && f.ToUnicode == nil {
+			missing[run.FontName] = true
+		}
+	}
+	stats.FontsMissingToUnicode = slices.Sorted(maps.Keys(missing))
+}
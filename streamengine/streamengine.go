@@ -2,8 +2,11 @@ package streamengine
 
 import (
 	"bytes"
+	"fmt"
 
+	"github.com/apex-woot/pdf-stream-engine/filters"
 	"github.com/apex-woot/pdf-stream-engine/font"
+	"github.com/apex-woot/pdf-stream-engine/geom"
 	"github.com/apex-woot/pdf-stream-engine/interpreter"
 )
 
@@ -11,8 +14,11 @@ import (
 // Input: raw decoded stream bytes from pdfcpu (after StreamDict.Decode())
 // Output: extracted text string
 //
-// This is the simple API that uses default WinAnsi encoding for all fonts.
-// For PDFs with custom font encodings or ToUnicode CMaps, use ExtractTextWithFonts.
+// Without options, every font uses the default WinAnsi encoding. For PDFs
+// with custom font encodings or ToUnicode CMaps, pass WithFonts; other
+// options select what is extracted and how:
+//
+//	text := ExtractText(data, WithFonts(registry), WithSkipArtifacts(true), WithLayout(true))
 //
 // The function parses PDF content stream operators (BT, ET, Tj, TJ, Td, Tm, etc.)
 // and extracts readable text while maintaining proper text positioning and reading order.
@@ -26,35 +32,72 @@ import (
 //	ET
 //
 // Returns: "Hello World"
-func ExtractText(streamData []byte) string {
-	return ExtractTextWithFonts(streamData, nil)
+func ExtractText(streamData []byte, opts ...Option) string {
+	cfg := newConfig(opts)
+	interp := interpreter.NewInterpreterWithOptions(cfg.fonts, cfg.interpreterOptionsWithLogger())
+	// On error, still return any text that was extracted; this follows the
+	// graceful degradation philosophy
+	_ = interp.ProcessStream(bytes.NewReader(streamData))
+	return cfg.text(interp)
 }
 
-// ExtractTextWithFonts processes decoded PDF content streams and extracts text
-// using a custom font registry for proper encoding handling.
-//
-// Input:
-//   - streamData: raw decoded stream bytes from pdfcpu (after StreamDict.Decode())
-//   - fontRegistry: FontRegistry with registered fonts and their ToUnicode CMaps
-//     If nil, a default registry with WinAnsi encoding will be used.
-//
-// Output: extracted text string
+// ExtractTextInRect is like ExtractText with WithFonts(registry), but
+// returns only the text whose glyphs lie inside rect, in page space (see
+// interpreter.Options.Region), for reading fixed form regions such as
+// invoice totals or header fields. The text is reassembled from the
+// positioned runs, as with WithLayout, so text around the region leaves
+// no blank lines.
+func ExtractTextInRect(streamData []byte, registry *font.FontRegistry, rect geom.Rect, opts ...Option) string {
+	opts = append(opts, WithFonts(registry), WithRegion(rect), WithLayout(true))
+	return ExtractText(streamData, opts...)
+}
+
+// ExtractTextWithFonts extracts text using a custom font registry for
+// proper encoding handling (ToUnicode CMaps, multi-byte encodings).
 //
-// Use this when you need to handle:
-//   - Custom font encodings
-//   - ToUnicode CMaps for CID fonts
-//   - Multi-byte character encodings
+// Deprecated: Use ExtractText with WithFonts.
 func ExtractTextWithFonts(streamData []byte, fontRegistry *font.FontRegistry) string {
-	// Create a reader from the byte slice
-	reader := bytes.NewReader(streamData)
-
-	// Create interpreter with font registry
-	interp := interpreter.NewInterpreter(fontRegistry)
-	if err := interp.ProcessStream(reader); err != nil {
-		// Log error but still return any text that was extracted
-		// This follows the graceful degradation philosophy
-		return interp.GetText()
-	}
+	return ExtractText(streamData, WithFonts(fontRegistry))
+}
 
-	return interp.GetText()
+// ExtractTextWithOptions is like ExtractTextWithFonts but also accepts
+// interpreter options controlling what is extracted.
+//
+// Deprecated: Use ExtractText with WithFonts and WithInterpreterOptions,
+// or the individual options such as WithSkipArtifacts.
+func ExtractTextWithOptions(streamData []byte, fontRegistry *font.FontRegistry, opts interpreter.Options) string {
+	return ExtractText(streamData, WithInterpreterOptions(opts), WithFonts(fontRegistry))
+}
+
+// Result is the outcome of ExtractTextResult.
+type Result struct {
+	// Text is the extracted text.
+	Text string
+
+	// Warnings are the recoverable problems found in the stream, such as
+	// malformed tokens or operators with missing operands.
+	Warnings []interpreter.Warning
+}
+
+// ExtractTextResult is like ExtractTextWithFonts but reports problems
+// instead of logging them: recoverable ones as Result.Warnings, and a
+// stream that could not be parsed at all as an error (with Result holding
+// whatever text was extracted).
+func ExtractTextResult(streamData []byte, fontRegistry *font.FontRegistry) (Result, error) {
+	interp := interpreter.NewInterpreterWithOptions(fontRegistry, interpreter.Options{SilenceWarnings: true})
+	err := interp.ProcessStream(bytes.NewReader(streamData))
+	return Result{Text: interp.GetText(), Warnings: interp.GetWarnings()}, err
+}
+
+// ExtractTextFromRawStream is like ExtractTextWithOptions for stream data
+// that is still encoded: the /Filter chain and /DecodeParms of the stream
+// dictionary are applied first, so callers need not pre-decode streams.
+// It returns an error only if decoding fails.
+func ExtractTextFromRawStream(rawData []byte, filterChain []string, decodeParms []map[string]any,
+	fontRegistry *font.FontRegistry, opts interpreter.Options) (string, error) {
+	streamData, err := filters.Decode(rawData, filterChain, decodeParms)
+	if err != nil {
+		return "", fmt.Errorf("decoding stream: %w", err)
+	}
+	return ExtractText(streamData, WithInterpreterOptions(opts), WithFonts(fontRegistry)), nil
 }
@@ -0,0 +1,147 @@
+package table
+
+import (
+	"sort"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// detectRuled builds grids from connected groups of horizontal and
+// vertical rulings and assigns runs to their cells. It returns the tables
+// and the runs not placed in any table.
+func detectRuled(runs []interpreter.TextRun, rulings []Ruling, opts Options) ([]Table, []interpreter.TextRun) {
+	tol := opts.Tolerance
+	var horizontal, vertical []Ruling
+	for _, r := range rulings {
+		r = r.normalized()
+		switch {
+		case r.Horizontal(tol):
+			horizontal = append(horizontal, r)
+		case r.Vertical(tol):
+			vertical = append(vertical, r)
+		}
+	}
+
+	used := make([]bool, len(runs))
+	var tables []Table
+	for _, group := range connectedGroups(horizontal, vertical, tol) {
+		var ys, xs []float64
+		for _, r := range group {
+			if r.Horizontal(tol) {
+				ys = append(ys, (r.Y0+r.Y1)/2)
+			} else {
+				xs = append(xs, (r.X0+r.X1)/2)
+			}
+		}
+		ys = clusterValues(ys, tol)
+		xs = clusterValues(xs, tol)
+		if len(ys)-1 < opts.MinRows || len(xs)-1 < opts.MinCols {
+			continue
+		}
+		// Rows top to bottom
+		sort.Sort(sort.Reverse(sort.Float64Slice(ys)))
+
+		t := Table{
+			Ruled:  true,
+			Bounds: geom.NewRect(xs[0], ys[len(ys)-1], xs[len(xs)-1], ys[0]),
+			Rows:   make([][]Cell, len(ys)-1),
+		}
+		for row := range t.Rows {
+			t.Rows[row] = make([]Cell, len(xs)-1)
+			for col := range t.Rows[row] {
+				t.Rows[row][col] = Cell{
+					Row:    row,
+					Col:    col,
+					Bounds: geom.NewRect(xs[col], ys[row+1], xs[col+1], ys[row]),
+				}
+			}
+		}
+		for i, run := range runs {
+			if used[i] || run.Text == "" {
+				continue
+			}
+			p := runCenter(run)
+			if !t.Bounds.Contains(p) {
+				continue
+			}
+			row := sort.Search(len(ys)-1, func(k int) bool { return ys[k+1] <= p.Y })
+			col := sort.Search(len(xs)-1, func(k int) bool { return xs[k+1] >= p.X })
+			if row >= len(t.Rows) || col >= len(t.Rows[row]) {
+				continue
+			}
+			cell := &t.Rows[row][col]
+			cell.Runs = append(cell.Runs, run)
+			used[i] = true
+		}
+		for row := range t.Rows {
+			for col := range t.Rows[row] {
+				cell := &t.Rows[row][col]
+				cell.Text = cellText(cell.Runs)
+			}
+		}
+		tables = append(tables, t)
+	}
+
+	var rest []interpreter.TextRun
+	for i, run := range runs {
+		if !used[i] {
+			rest = append(rest, run)
+		}
+	}
+	return tables, rest
+}
+
+// connectedGroups partitions rulings into groups connected by
+// intersections between horizontal and vertical rulings. Groups without
+// both orientations are dropped.
+func connectedGroups(horizontal, vertical []Ruling, tol float64) [][]Ruling {
+	n := len(horizontal) + len(vertical)
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i, h := range horizontal {
+		for j, v := range vertical {
+			if v.X0 >= h.X0-tol && v.X0 <= h.X1+tol && h.Y0 >= v.Y0-tol && h.Y0 <= v.Y1+tol {
+				parent[find(i)] = find(len(horizontal) + j)
+			}
+		}
+	}
+
+	groups := make(map[int][]Ruling)
+	var order []int
+	for i := 0; i < n; i++ {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			order = append(order, root)
+		}
+		if i < len(horizontal) {
+			groups[root] = append(groups[root], horizontal[i])
+		} else {
+			groups[root] = append(groups[root], vertical[i-len(horizontal)])
+		}
+	}
+
+	var result [][]Ruling
+	for _, root := range order {
+		group := groups[root]
+		var hasH, hasV bool
+		for _, r := range group {
+			hasH = hasH || r.Horizontal(tol)
+			hasV = hasV || r.Vertical(tol)
+		}
+		if hasH && hasV {
+			result = append(result, group)
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package table
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// Ruling is a horizontal or vertical line segment drawn on the page, such
+// as a table border.
+type Ruling struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Horizontal reports whether the ruling is horizontal within tol.
+func (r Ruling) Horizontal(tol float64) bool {
+	return math.Abs(r.Y1-r.Y0) <= tol && math.Abs(r.X1-r.X0) > tol
+}
+
+// Vertical reports whether the ruling is vertical within tol.
+func (r Ruling) Vertical(tol float64) bool {
+	return math.Abs(r.X1-r.X0) <= tol && math.Abs(r.Y1-r.Y0) > tol
+}
+
+// normalized orders the endpoints so that X0 <= X1 and Y0 <= Y1.
+func (r Ruling) normalized() Ruling {
+	if r.X0 > r.X1 {
+		r.X0, r.X1 = r.X1, r.X0
+	}
+	if r.Y0 > r.Y1 {
+		r.Y0, r.Y1 = r.Y1, r.Y0
+	}
+	return r
+}
+
+func (r Ruling) String() string {
+	return fmt.Sprintf("(%.2f,%.2f)-(%.2f,%.2f)", r.X0, r.Y0, r.X1, r.Y1)
+}
+
+// maxRulingThickness is the largest height or width of a filled shape
+// that is treated as a line. Producers often draw table borders as thin
+// filled rectangles instead of stroked lines.
+const maxRulingThickness = 3.0
+
+// RulingsFromPaths collects the straight line segments of stroked paths
+// and the center lines of thin filled subpaths. Curves are skipped.
+func RulingsFromPaths(paths []interpreter.Path) []Ruling {
+	var rulings []Ruling
+	for _, path := range paths {
+		for _, sub := range path.Subpaths() {
+			if path.Fill {
+				if r, ok := thinFill(sub); ok {
+					rulings = append(rulings, r)
+					continue
+				}
+			}
+			if path.Stroke {
+				rulings = append(rulings, lineSegments(sub)...)
+			}
+		}
+	}
+	return rulings
+}
+
+// RulingsFromStream interprets a decoded content stream and returns the
+// rulings of its paths (see RulingsFromPaths). When the runs come from an
+// interpreter anyway, call RulingsFromPaths(interp.GetPaths()) instead.
+func RulingsFromStream(data []byte) ([]Ruling, error) {
+	interp := interpreter.NewInterpreter(nil)
+	if err := interp.ProcessStream(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return RulingsFromPaths(interp.GetPaths()), nil
+}
+
+// lineSegments returns the straight segments of a subpath, including the
+// closing segment.
+func lineSegments(sub []interpreter.PathSegment) []Ruling {
+	var rulings []Ruling
+	var cur, start geom.Point
+	for _, seg := range sub {
+		switch seg.Kind {
+		case interpreter.MoveTo:
+			cur, start = seg.Points[0], seg.Points[0]
+		case interpreter.LineTo:
+			end := seg.Points[0]
+			rulings = append(rulings, Ruling{cur.X, cur.Y, end.X, end.Y})
+			cur = end
+		case interpreter.CurveTo:
+			cur = seg.Points[len(seg.Points)-1]
+		case interpreter.Close:
+			if cur != start {
+				rulings = append(rulings, Ruling{cur.X, cur.Y, start.X, start.Y})
+			}
+			cur = start
+		}
+	}
+	return rulings
+}
+
+// thinFill returns the center line of a filled subpath no thicker than
+// maxRulingThickness.
+func thinFill(sub []interpreter.PathSegment) (Ruling, bool) {
+	box := interpreter.Path{Segments: sub}.Bounds()
+	w, h := box.Width(), box.Height()
+	switch {
+	case h <= maxRulingThickness && w > h:
+		y := (box.Y0 + box.Y1) / 2
+		return Ruling{box.X0, y, box.X1, y}, true
+	case w <= maxRulingThickness && h > w:
+		x := (box.X0 + box.X1) / 2
+		return Ruling{x, box.Y0, x, box.Y1}, true
+	}
+	return Ruling{}, false
+}
@@ -0,0 +1,182 @@
+// Package table detects tables on a page and extracts their cells, using
+// ruling lines where the page draws them and column alignment of text runs
+// where it does not.
+package table
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/layout"
+)
+
+// Cell is one cell of a table.
+type Cell struct {
+	// Row and Col are the 0-based cell position, rows top to bottom.
+	Row, Col int
+
+	// Bounds is the cell area: the grid cell for ruled tables, the union
+	// of the cell's runs for unruled ones.
+	Bounds geom.Rect
+
+	// Text is the cell text, with wrapped lines joined by spaces.
+	Text string
+
+	// Runs are the runs assigned to the cell.
+	Runs []interpreter.TextRun
+}
+
+// Table is a detected table.
+type Table struct {
+	// Bounds is the table area.
+	Bounds geom.Rect
+
+	// Ruled reports whether the table was found from ruling lines rather
+	// than text alignment.
+	Ruled bool
+
+	// Rows holds the cells, Rows[row][col]. Every row has the same number
+	// of columns; empty cells have empty Text.
+	Rows [][]Cell
+}
+
+// NumRows returns the number of rows.
+func (t Table) NumRows() int {
+	return len(t.Rows)
+}
+
+// NumCols returns the number of columns.
+func (t Table) NumCols() int {
+	if len(t.Rows) == 0 {
+		return 0
+	}
+	return len(t.Rows[0])
+}
+
+// Records returns the cell texts, row by row.
+func (t Table) Records() [][]string {
+	records := make([][]string, len(t.Rows))
+	for i, row := range t.Rows {
+		records[i] = make([]string, len(row))
+		for j, cell := range row {
+			records[i][j] = cell.Text
+		}
+	}
+	return records
+}
+
+// WriteCSV writes the table as CSV.
+func (t Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(t.Records()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// Options tunes table detection. Zero fields select the defaults.
+type Options struct {
+	// Tolerance is the distance within which ruling coordinates are
+	// considered equal and rulings are considered to touch. Default 2.
+	Tolerance float64
+
+	// MinRows and MinCols are the smallest table dimensions reported.
+	// Default 2 each.
+	MinRows, MinCols int
+
+	// ColumnGap is the smallest horizontal gap between runs, as a multiple
+	// of the font size, that separates columns in unruled tables.
+	// Default 1.0.
+	ColumnGap float64
+
+	// MaxRowSpacing is the largest baseline distance, as a multiple of the
+	// font size, between consecutive rows of an unruled table. Default 2.5.
+	MaxRowSpacing float64
+
+	// RulingsOnly disables detection of unruled tables from text alignment.
+	RulingsOnly bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.Tolerance <= 0 {
+		o.Tolerance = 2
+	}
+	if o.MinRows <= 0 {
+		o.MinRows = 2
+	}
+	if o.MinCols <= 0 {
+		o.MinCols = 2
+	}
+	if o.ColumnGap <= 0 {
+		o.ColumnGap = 1.0
+	}
+	if o.MaxRowSpacing <= 0 {
+		o.MaxRowSpacing = 2.5
+	}
+	return o
+}
+
+// Detect finds tables among the page's runs and rulings (see
+// RulingsFromStream). Ruled tables are found first; the remaining runs are
+// then searched for aligned columns unless opts.RulingsOnly is set.
+// Tables are returned top to bottom.
+func Detect(runs []interpreter.TextRun, rulings []Ruling, opts Options) []Table {
+	opts = opts.withDefaults()
+
+	tables, rest := detectRuled(runs, rulings, opts)
+	if !opts.RulingsOnly {
+		tables = append(tables, detectUnruled(rest, opts)...)
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].Bounds.Y1 > tables[j].Bounds.Y1
+	})
+	return tables
+}
+
+// cellText assembles the runs of one cell into text.
+func cellText(runs []interpreter.TextRun) string {
+	lines := layout.AssembleLines(runs, layout.LineOptions{})
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = strings.TrimSpace(line.Text)
+	}
+	return strings.Join(texts, " ")
+}
+
+// runCenter returns the center of the run's bounding box, used to assign
+// runs to cells.
+func runCenter(run interpreter.TextRun) geom.Point {
+	box := run.Bounds()
+	return geom.Point{X: (box.X0 + box.X1) / 2, Y: (box.Y0 + box.Y1) / 2}
+}
+
+// clusterValues sorts values and merges those within tol of the previous
+// cluster's first value, returning each cluster's mean.
+func clusterValues(values []float64, tol float64) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var result []float64
+	start, sum, n := sorted[0], 0.0, 0
+	for _, v := range sorted {
+		if v-start > tol {
+			result = append(result, sum/float64(n))
+			start, sum, n = v, 0, 0
+		}
+		sum += v
+		n++
+	}
+	return append(result, sum/float64(n))
+}
+
+func overlaps(a0, a1, b0, b1, tol float64) bool {
+	return math.Min(a1, b1)-math.Max(a0, b0) >= -tol
+}
@@ -0,0 +1,129 @@
+package table
+
+import (
+	"math"
+	"sort"
+
+	"github.com/apex-woot/pdf-stream-engine/geom"
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+	"github.com/apex-woot/pdf-stream-engine/layout"
+)
+
+// segment is a group of runs on one line, separated from its neighbours
+// by a column-sized gap.
+type segment struct {
+	x0, x1 float64
+	runs   []interpreter.TextRun
+}
+
+// detectUnruled finds blocks of consecutive lines whose runs split into
+// the same columns at wide gaps.
+func detectUnruled(runs []interpreter.TextRun, opts Options) []Table {
+	lines := layout.AssembleLines(runs, layout.LineOptions{})
+
+	var tables []Table
+	var block []layout.Line
+	var blockSegments [][]segment
+	flush := func() {
+		if t, ok := buildUnruled(block, blockSegments, opts); ok {
+			tables = append(tables, t)
+		}
+		block, blockSegments = nil, nil
+	}
+
+	for _, line := range lines {
+		segs := splitSegments(line, opts)
+		if len(segs) < 2 {
+			flush()
+			continue
+		}
+		if len(block) > 0 {
+			prev := block[len(block)-1]
+			if prev.Baseline-line.Baseline > opts.MaxRowSpacing*math.Max(prev.FontSize, line.FontSize) {
+				flush()
+			}
+		}
+		block = append(block, line)
+		blockSegments = append(blockSegments, segs)
+	}
+	flush()
+	return tables
+}
+
+// splitSegments splits a line's runs at gaps of at least opts.ColumnGap
+// font sizes.
+func splitSegments(line layout.Line, opts Options) []segment {
+	var segs []segment
+	for i, run := range line.Runs {
+		if i > 0 {
+			prev := line.Runs[i-1]
+			gap := run.X - prev.EndX()
+			if gap < opts.ColumnGap*math.Min(prev.FontSize, run.FontSize) {
+				last := &segs[len(segs)-1]
+				last.runs = append(last.runs, run)
+				last.x1 = math.Max(last.x1, run.EndX())
+				continue
+			}
+		}
+		segs = append(segs, segment{x0: run.X, x1: run.EndX(), runs: []interpreter.TextRun{run}})
+	}
+	return segs
+}
+
+// buildUnruled derives columns from the union of the block's segment
+// extents and lays the segments out as cells. Segments spanning two
+// columns merge them, so headers spanning the table collapse it and it is
+// rejected as too narrow.
+func buildUnruled(lines []layout.Line, segs [][]segment, opts Options) (Table, bool) {
+	if len(lines) < opts.MinRows {
+		return Table{}, false
+	}
+
+	var all []segment
+	for _, row := range segs {
+		all = append(all, row...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].x0 < all[j].x0 })
+	var columns [][2]float64
+	for _, s := range all {
+		if n := len(columns); n > 0 && overlaps(columns[n-1][0], columns[n-1][1], s.x0, s.x1, 0) {
+			columns[n-1][1] = math.Max(columns[n-1][1], s.x1)
+			continue
+		}
+		columns = append(columns, [2]float64{s.x0, s.x1})
+	}
+	if len(columns) < opts.MinCols {
+		return Table{}, false
+	}
+
+	t := Table{Rows: make([][]Cell, len(lines))}
+	for row, line := range lines {
+		t.Rows[row] = make([]Cell, len(columns))
+		for col := range columns {
+			t.Rows[row][col] = Cell{Row: row, Col: col}
+		}
+		for _, s := range segs[row] {
+			col := sort.Search(len(columns), func(k int) bool { return columns[k][1] >= s.x0 })
+			cell := &t.Rows[row][col]
+			cell.Runs = append(cell.Runs, s.runs...)
+		}
+		for col := range t.Rows[row] {
+			cell := &t.Rows[row][col]
+			cell.Text = cellText(cell.Runs)
+			for i, run := range cell.Runs {
+				if i == 0 {
+					cell.Bounds = run.Bounds()
+				} else {
+					cell.Bounds = cell.Bounds.Union(run.Bounds())
+				}
+			}
+		}
+		if row == 0 {
+			t.Bounds = line.Bounds
+		} else {
+			t.Bounds = t.Bounds.Union(line.Bounds)
+		}
+	}
+	t.Bounds = t.Bounds.Union(geom.NewRect(columns[0][0], t.Bounds.Y0, columns[len(columns)-1][1], t.Bounds.Y1))
+	return t, true
+}
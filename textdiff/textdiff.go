@@ -0,0 +1,273 @@
+// Package textdiff compares extracted text line by line and renders the
+// differences as a unified diff. It backs the CLI watch mode and lets test
+// suites for PDF generators assert on extraction-level changes between
+// builds.
+package textdiff
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Op is the kind of an edit.
+type Op int
+
+const (
+	Equal  Op = iota // line present in both texts
+	Delete           // line only in the old text
+	Insert           // line only in the new text
+)
+
+func (o Op) String() string {
+	switch o {
+	case Equal:
+		return " "
+	case Delete:
+		return "-"
+	case Insert:
+		return "+"
+	default:
+		return fmt.Sprintf("Op(%d)", int(o))
+	}
+}
+
+// Edit is one line of an edit script.
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// Lines returns a minimal edit script turning old into new, line by line
+// (Myers' algorithm).
+func Lines(old, new string) []Edit {
+	return diff(splitLines(old), splitLines(new))
+}
+
+// Changes counts the inserted and deleted lines of an edit script.
+func Changes(edits []Edit) (inserted, deleted int) {
+	for _, e := range edits {
+		switch e.Op {
+		case Insert:
+			inserted++
+		case Delete:
+			deleted++
+		}
+	}
+	return inserted, deleted
+}
+
+// Unified renders the differences between old and new as a unified diff
+// with the given number of context lines, labelling the texts oldName and
+// newName. It returns "" when the texts have the same lines.
+func Unified(old, new, oldName, newName string, context int) string {
+	edits := Lines(old, new)
+	if ins, del := Changes(edits); ins == 0 && del == 0 {
+		return ""
+	}
+	if context < 0 {
+		context = 0
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Line numbers (0-based) in old and new before each edit
+	oldLine := make([]int, len(edits)+1)
+	newLine := make([]int, len(edits)+1)
+	for i, e := range edits {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if e.Op != Insert {
+			oldLine[i+1]++
+		}
+		if e.Op != Delete {
+			newLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+		// Extend the hunk while the next change is within 2*context lines
+		start := max(i-context, 0)
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].Op != Equal {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(end+context, len(edits))
+
+		oldCount := oldLine[end] - oldLine[start]
+		newCount := newLine[end] - newLine[start]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[start], oldCount), hunkRange(newLine[start], newCount))
+		for _, e := range edits[start:end] {
+			b.WriteString(e.Op.String())
+			b.WriteString(e.Line)
+			b.WriteByte('\n')
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats a hunk range: 1-based start and count, with an empty
+// range addressed by the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits text into lines, ignoring a final newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diff implements Myers' O(ND) difference algorithm in linear space: it
+// finds the middle snake of an optimal path and recurses on either side
+// of it, after trimming the lines the texts start and end with.
+func diff(a, b []string) []Edit {
+	size := 2*((len(a)+len(b)+1)/2) + 3 // as in middleSnake
+	d := differ{
+		edits: make([]Edit, 0, max(len(a), len(b))),
+		fwd:   make([]int, size),
+		bwd:   make([]int, size),
+	}
+	d.compare(a, b)
+	normalize(d.edits)
+	return d.edits
+}
+
+// differ holds the edit script being built and the furthest-reaching
+// paths of the middle snake search, shared by all recursion levels.
+type differ struct {
+	edits    []Edit
+	fwd, bwd []int
+}
+
+// compare appends the edits turning a into b.
+func (d *differ) compare(a, b []string) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	d.equal(a[:prefix])
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	tail := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			d.edits = append(d.edits, Edit{Insert, line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			d.edits = append(d.edits, Edit{Delete, line})
+		}
+	default:
+		x, y, u, v := d.middleSnake(a, b)
+		d.compare(a[:x], b[:y])
+		d.equal(a[x:u])
+		d.compare(a[u:], b[v:])
+	}
+	d.equal(tail)
+}
+
+// equal appends lines common to both texts.
+func (d *differ) equal(lines []string) {
+	for _, line := range lines {
+		d.edits = append(d.edits, Edit{Equal, line})
+	}
+}
+
+// middleSnake returns the middle snake of an optimal path from the start
+// of a and b to their ends, from (x, y) to (u, v), searching forward from
+// the start and backward from the end at once until the paths overlap. a
+// and b must differ in their first and last lines, so the snake lies
+// strictly inside and both sides of it are smaller problems.
+func (d *differ) middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+	fwd, bwd := d.fwd[:2*limit+3], d.bwd[:2*limit+3]
+	// The backward search runs over the reversed texts: bwd holds how far
+	// from the end of a each of its diagonals reaches.
+	fwd[offset+1], bwd[offset+1] = 0, 0
+	for e := 0; e <= limit; e++ {
+		for k := -e; k <= e; k += 2 {
+			var x int
+			if k == -e || (k != e && fwd[offset+k-1] < fwd[offset+k+1]) {
+				x = fwd[offset+k+1] // Down: insertion
+			} else {
+				x = fwd[offset+k-1] + 1 // Right: deletion
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			fwd[offset+k] = x
+			if c := delta - k; odd && c >= -(e-1) && c <= e-1 && x+bwd[offset+c] >= n {
+				return startX, startY, x, y
+			}
+		}
+		for c := -e; c <= e; c += 2 {
+			var x int
+			if c == -e || (c != e && bwd[offset+c-1] < bwd[offset+c+1]) {
+				x = bwd[offset+c+1]
+			} else {
+				x = bwd[offset+c-1] + 1
+			}
+			y := x - c
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x, y = x+1, y+1
+			}
+			bwd[offset+c] = x
+			if k := delta - c; !odd && k >= -e && k <= e && x+fwd[offset+k] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	panic("textdiff: no middle snake")
+}
+
+// normalize orders each run of changed lines with the deletions before
+// the insertions, as unified diffs show them.
+func normalize(edits []Edit) {
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != Equal {
+			j++
+		}
+		slices.SortStableFunc(edits[i:j], func(e, f Edit) int {
+			return cmp.Compare(e.Op, f.Op)
+		})
+		i = j
+	}
+}
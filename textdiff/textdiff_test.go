@@ -0,0 +1,57 @@
+package textdiff
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string // the edits, one per line
+	}{
+		{"same", "a\nb\n", "a\nb\n", " a\n b\n"},
+		{"old empty", "", "a\nb", "+a\n+b\n"},
+		{"new empty", "a\nb", "", "-a\n-b\n"},
+		{"insertion", "a\nc", "a\nb\nc", " a\n+b\n c\n"},
+		{"deletion", "a\nb\nc", "a\nc", " a\n-b\n c\n"},
+		{"replacement", "a\nb\nc\nd", "a\nx\ny\nd", " a\n-b\n-c\n+x\n+y\n d\n"},
+		{"all replaced", "a\nb\nc", "x\ny", "-a\n-b\n-c\n+x\n+y\n"},
+		{"moved line", "a\nb\nc\nd", "b\nc\nd\na", "-a\n b\n c\n d\n+a\n"},
+		{"repeated lines", "a\nb\na\nb\na", "b\na\nb\na\nb", "-a\n b\n a\n b\n a\n+b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			for _, e := range Lines(tt.old, tt.new) {
+				b.WriteString(e.Op.String() + e.Line + "\n")
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("edits:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLinesLarge checks that two long texts differing in every line are
+// compared in linear space.
+func TestLinesLarge(t *testing.T) {
+	var old, new strings.Builder
+	for i := range 4000 {
+		old.WriteString(strings.Repeat("a", i%7+1) + "\n")
+		new.WriteString(strings.Repeat("b", i%7+1) + "\n")
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	ins, del := Changes(Lines(old.String(), new.String()))
+	runtime.ReadMemStats(&after)
+	if ins != 4000 || del != 4000 {
+		t.Errorf("%d insertions and %d deletions, want 4000 each", ins, del)
+	}
+	// Keeping the paths for every edit distance would take about 1 GB
+	if n := after.TotalAlloc - before.TotalAlloc; n > 16<<20 {
+		t.Errorf("%d bytes allocated", n)
+	}
+}
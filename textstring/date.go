@@ -0,0 +1,116 @@
+package textstring
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate is returned by ParseDate for strings that are not PDF dates.
+var ErrInvalidDate = errors.New("invalid PDF date")
+
+// ParseDate parses a PDF date string of the form
+//
+//	D:YYYYMMDDHHmmSSOHH'mm'
+//
+// where everything after the year is optional and O is '+', '-', or 'Z'.
+// Missing month and day default to 1, missing time fields to 0, and a
+// missing offset to UTC. Common producer deviations are accepted: no "D:"
+// prefix, no apostrophes or only one ("+05'30"), a trailing apostrophe
+// after Z, and surrounding whitespace.
+//
+// Pass the decoded text string, e.g. ParseDate(DecodeString(raw)).
+func ParseDate(s string) (time.Time, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "D:")
+
+	fail := func(reason string) (time.Time, error) {
+		return time.Time{}, fmt.Errorf("%w %q: %s", ErrInvalidDate, orig, reason)
+	}
+
+	// Split the digits from the time zone designator
+	end := strings.IndexAny(s, "+-Zz")
+	digits, zone := s, ""
+	if end >= 0 {
+		digits, zone = s[:end], s[end:]
+	}
+	if len(digits) < 4 || len(digits) > 14 || len(digits)%2 != 0 {
+		return fail("expected 4 to 14 digits")
+	}
+
+	// year, month, day, hour, minute, second
+	fields := [6]int{0, 1, 1, 0, 0, 0}
+	widths := [6]int{4, 2, 2, 2, 2, 2}
+	for i, pos := 0, 0; pos < len(digits); i++ {
+		v, err := strconv.Atoi(digits[pos : pos+widths[i]])
+		if err != nil || v < 0 {
+			return fail("non-digit in date")
+		}
+		fields[i] = v
+		pos += widths[i]
+	}
+	year, month, day, hour, minute, second := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 59 {
+		return fail("field out of range")
+	}
+
+	loc, err := parseZone(zone)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, loc)
+	if t.Day() != day {
+		return fail("day out of range for month")
+	}
+	return t, nil
+}
+
+// parseZone parses the time zone part of a PDF date ("", "Z", "+05'30'").
+func parseZone(zone string) (*time.Location, error) {
+	zone = strings.TrimRight(zone, "'")
+	if zone == "" || zone == "Z" || zone == "z" {
+		return time.UTC, nil
+	}
+	sign := 1
+	if zone[0] == '-' {
+		sign = -1
+	}
+	rest := strings.ReplaceAll(zone[1:], "'", "")
+	if len(rest) != 2 && len(rest) != 4 {
+		return nil, fmt.Errorf("bad time zone %q", zone)
+	}
+	hours, err := strconv.Atoi(rest[:2])
+	if err != nil || hours > 23 {
+		return nil, fmt.Errorf("bad time zone %q", zone)
+	}
+	minutes := 0
+	if len(rest) == 4 {
+		minutes, err = strconv.Atoi(rest[2:])
+		if err != nil || minutes > 59 {
+			return nil, fmt.Errorf("bad time zone %q", zone)
+		}
+	}
+	offset := sign * (hours*3600 + minutes*60)
+	if offset == 0 {
+		return time.UTC, nil
+	}
+	return time.FixedZone("", offset), nil
+}
+
+// FormatDate formats t as a PDF date string with an explicit offset,
+// e.g. "D:20240131120000+01'00'".
+func FormatDate(t time.Time) string {
+	_, offset := t.Zone()
+	if offset == 0 {
+		return t.Format("D:20060102150405") + "Z"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%s%c%02d'%02d'", t.Format("D:20060102150405"), sign, offset/3600, offset%3600/60)
+}
@@ -0,0 +1,84 @@
+// Package textstring decodes PDF text strings and dates, the string values
+// found in the document Info dictionary, annotations (/Contents, /T, /M),
+// outlines, and form fields.
+package textstring
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/apex-woot/pdf-stream-engine/font"
+)
+
+var (
+	bomUTF16BE = []byte{0xFE, 0xFF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+)
+
+// Decode converts a PDF text string to UTF-8. Strings starting with a
+// UTF-16BE byte order mark are UTF-16BE, strings starting with the UTF-8
+// BOM (PDF 2.0) are UTF-8, and everything else is PDFDocEncoding.
+// Little-endian UTF-16 with a BOM, which some producers write despite the
+// specification, is accepted too. Language escape sequences
+// (0x1B lang 0x1B) in Unicode strings are removed.
+//
+// data is the string's bytes after literal/hex string unescaping, as
+// returned by the parser.
+func Decode(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return stripLanguageEscapes(decodeUTF16(data[2:], false))
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return stripLanguageEscapes(decodeUTF16(data[2:], true))
+	case bytes.HasPrefix(data, bomUTF8):
+		return stripLanguageEscapes(string(bytes.ToValidUTF8(data[3:], []byte("\uFFFD"))))
+	default:
+		return font.DecodePDFDoc(data)
+	}
+}
+
+// DecodeString is Decode for strings, such as literal string operands
+// returned by the parser.
+func DecodeString(s string) string {
+	return Decode([]byte(s))
+}
+
+// decodeUTF16 decodes UTF-16 code units, combining surrogate pairs. A
+// trailing odd byte is dropped.
+func decodeUTF16(data []byte, littleEndian bool) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if littleEndian {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		} else {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// stripLanguageEscapes removes ESC-delimited language tags such as
+// "\x1benUS\x1b" from decoded Unicode text.
+func stripLanguageEscapes(s string) string {
+	if strings.IndexByte(s, 0x1b) < 0 {
+		return s
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, 0x1b)
+		if start < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:start])
+		end := strings.IndexByte(s[start+1:], 0x1b)
+		if end < 0 {
+			// Unterminated escape: drop the ESC and keep the rest
+			b.WriteString(s[start+1:])
+			return b.String()
+		}
+		s = s[start+1+end+1:]
+	}
+}
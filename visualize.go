@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/apex-woot/pdf-stream-engine/interpreter"
+)
+
+// writeRunsHTML writes a self-contained HTML page that overlays the
+// bounding box of every extracted run on a user-supplied page image.
+// Boxes are positioned in percentages of the page size, so the image may
+// be rendered at any resolution as long as it covers the whole page.
+func writeRunsHTML(w io.Writer, runs []interpreter.TextRun, imageURL string, pageWidth, pageHeight float64) error {
+	const head = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Extracted runs</title>
+<style>
+body { font-family: sans-serif; margin: 16px; }
+#page { position: relative; width: 100%%; max-width: %gpx; aspect-ratio: %g / %g; border: 1px solid #888; }
+#page img { position: absolute; inset: 0; width: 100%%; height: 100%%; }
+.run { position: absolute; box-sizing: border-box; border: 1px solid rgba(220, 0, 0, 0.8); background: rgba(255, 0, 0, 0.12); }
+.run.invisible { border-color: rgba(0, 0, 220, 0.8); background: rgba(0, 0, 255, 0.12); }
+.run:hover { background: rgba(255, 200, 0, 0.4); }
+#info { margin-top: 8px; font-family: monospace; white-space: pre; }
+</style>
+</head>
+<body>
+<div id="page">
+`
+	if _, err := fmt.Fprintf(w, head, pageWidth*1.5, pageWidth, pageHeight); err != nil {
+		return err
+	}
+	if imageURL != "" {
+		if _, err := fmt.Fprintf(w, "<img src=\"%s\" alt=\"page\">\n", html.EscapeString(imageURL)); err != nil {
+			return err
+		}
+	}
+
+	for i, run := range runs {
+		box := run.Bounds()
+		class := "run"
+		if !run.RenderMode.IsVisible() {
+			class += " invisible"
+		}
+		title := fmt.Sprintf("#%d %q font=%s size=%.2f at (%.2f, %.2f)", i, run.Text, run.FontName, run.FontSize, run.X, run.Y)
+		_, err := fmt.Fprintf(w,
+			"<div class=\"%s\" style=\"left:%.4f%%;top:%.4f%%;width:%.4f%%;height:%.4f%%\" title=\"%s\"></div>\n",
+			class,
+			box.X0/pageWidth*100,
+			(pageHeight-box.Y1)/pageHeight*100, // PDF y grows upward
+			box.Width()/pageWidth*100,
+			box.Height()/pageHeight*100,
+			html.EscapeString(title))
+		if err != nil {
+			return err
+		}
+	}
+
+	const tail = `</div>
+<div id="info">Hover a box to see its run. Red: visible text, blue: invisible (Tr 3/7).</div>
+<script>
+document.querySelectorAll(".run").forEach(function (el) {
+  el.addEventListener("mouseenter", function () { document.getElementById("info").textContent = el.title; });
+});
+</script>
+</body>
+</html>
+`
+	_, err := io.WriteString(w, tail)
+	return err
+}